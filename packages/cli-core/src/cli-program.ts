@@ -1,34 +1,53 @@
-import { Command } from "@commander-js/extra-typings";
+import { Command, createOption, type CommandUnknownOpts } from "@commander-js/extra-typings";
 import { expandInputJson } from "./lib/input-json.ts";
-import { setLogLevel } from "./lib/log.ts";
+import { setLogLevel, setPlain } from "./lib/log.ts";
 import { setMode, type Mode } from "./mode.ts";
 import { registerInit } from "./commands/init/index.ts";
 import { registerAuth } from "./commands/auth/index.ts";
 import { registerLink } from "./commands/link/index.ts";
 import { registerUnlink } from "./commands/unlink/index.ts";
 import { registerWhoami } from "./commands/whoami/index.ts";
+import { registerUse } from "./commands/use/index.ts";
 import { registerOpen } from "./commands/open/index.ts";
 import { registerApps } from "./commands/apps/index.ts";
 import { registerUsers } from "./commands/users/index.ts";
+import { registerOrgs } from "./commands/orgs/index.ts";
+import { registerDomains } from "./commands/domains/index.ts";
+import { registerSsoConnections } from "./commands/sso-connections/index.ts";
+import { registerSessions } from "./commands/sessions/index.ts";
+import { registerClients } from "./commands/clients/index.ts";
+import { registerApiKeys } from "./commands/api-keys/index.ts";
+import { registerSecurity } from "./commands/security/index.ts";
+import { registerMetrics } from "./commands/metrics/index.ts";
+import { registerJwks } from "./commands/jwks/index.ts";
+import { registerRestrictions } from "./commands/restrictions/index.ts";
+import { registerInvitations } from "./commands/invitations/index.ts";
 import { registerImpersonate } from "./commands/impersonate/index.ts";
 import { registerEnv } from "./commands/env/index.ts";
 import { registerConfig } from "./commands/config/index.ts";
 import { registerToggles } from "./commands/toggles/index.ts";
+import { registerBilling } from "./commands/billing/index.ts";
 import { registerApi } from "./commands/api/index.ts";
 import { registerDoctor } from "./commands/doctor/index.ts";
+import { registerScan } from "./commands/scan/index.ts";
+import { registerBench } from "./commands/bench/index.ts";
 import { registerMcp } from "./commands/mcp/index.ts";
 import { registerSwitchEnv } from "./commands/switch-env/index.ts";
 import { registerCompletion } from "./commands/completion/index.ts";
+import { registerCommands } from "./commands/commands/index.ts";
+import { registerSettings } from "./commands/settings/index.ts";
+import { registerTelemetry } from "./commands/telemetry/index.ts";
 import { registerUpdate } from "./commands/update/index.ts";
+import { registerVersion } from "./commands/version/index.ts";
 import { registerDeploy } from "./commands/deploy/index.ts";
 import { registerWebhooks } from "./commands/webhooks/index.ts";
-import { getEnvironment } from "./lib/config.ts";
+import { getEnvironment, setConfigPath } from "./lib/config.ts";
 import {
   setCurrentEnv,
   isValidEnv,
   getCurrentEnvName,
   getAvailableEnvs,
-  getPlapiBaseUrl,
+  logActiveEnv,
 } from "./lib/environment.ts";
 import {
   CliError,
@@ -44,13 +63,45 @@ import { clerkHelpConfig, formatExamplesBlock, type Example } from "./lib/help.t
 import { isAgent } from "./mode.ts";
 import { log } from "./lib/log.ts";
 import { maybeNotifyUpdate, getCurrentVersion } from "./lib/update-check.ts";
+import { setReadOnly } from "./lib/read-only.ts";
+import { DryRunRequest, setRequestDryRun } from "./lib/dry-run.ts";
+import { setPagerEnabled } from "./lib/pager.ts";
+import { finishCommandTelemetry, startCommandTelemetry } from "./lib/telemetry.ts";
+import { OUTPUT_FORMATS, setOutputFormat, type OutputFormat } from "./lib/output-format.ts";
+import { setQuiet } from "./lib/quiet.ts";
+import { setRedactPii, setShowSecrets } from "./lib/redact.ts";
+import { parseColorOverrides, setTheme, THEMES, type Theme } from "./lib/color.ts";
+import { setDisplayLocale } from "./lib/locale.ts";
+import {
+  getBooleanSetting,
+  getIntegerSetting,
+  getSetting,
+  parseBoolean,
+} from "./lib/settings.ts";
+import { DEFAULT_MAX_DELAY_MS, DEFAULT_RETRIES, setRetryPolicy } from "./lib/http-retry.ts";
 import { registerExtras } from "@clerk/cli-extras";
 
 /**
  * The root `clerk` program with its global options applied, so registrants
  * can rely on the typed global option contract instead of a generic Command.
  */
-export type Program = Command<[], { inputJson?: string; mode?: string; verbose?: boolean }>;
+export type Program = Command<
+  [],
+  {
+    inputJson?: string;
+    mode?: string;
+    config?: string;
+    verbose?: boolean;
+    readOnly?: boolean;
+    dryRun?: boolean;
+    showSecrets?: boolean;
+    showPii?: boolean;
+    pager?: boolean;
+    outputFormat?: string;
+    quiet?: boolean;
+    plain?: boolean;
+  }
+>;
 
 type CommandRegistrant = (program: Program) => void;
 
@@ -60,24 +111,118 @@ const registrants: CommandRegistrant[] = [
   registerLink,
   registerUnlink,
   registerWhoami,
+  registerUse,
   registerOpen,
   registerApps,
   registerUsers,
+  registerOrgs,
+  registerDomains,
+  registerSsoConnections,
+  registerSessions,
+  registerClients,
+  registerApiKeys,
+  registerSecurity,
+  registerMetrics,
+  registerJwks,
+  registerRestrictions,
+  registerInvitations,
   registerImpersonate,
   registerEnv,
   registerConfig,
   registerToggles,
+  registerBilling,
   registerApi,
   registerDoctor,
+  registerScan,
+  registerBench,
   registerMcp,
   registerSwitchEnv,
   registerCompletion,
+  registerCommands,
+  registerSettings,
+  registerTelemetry,
   registerUpdate,
+  registerVersion,
   registerDeploy,
   registerWebhooks,
   registerExtras,
 ];
 
+/**
+ * Resolve read-only mode for this invocation. Precedence: `--read-only`, then
+ * `CLERK_READ_ONLY` (which can also force it off), then the `core.read-only`
+ * setting.
+ */
+async function resolveReadOnly(flag: boolean | undefined): Promise<void> {
+  const envValue = process.env.CLERK_READ_ONLY;
+  const fromEnv = envValue ? parseBoolean(envValue) : undefined;
+  let source: string | undefined;
+  if (flag) {
+    source = "--read-only";
+  } else if (fromEnv !== undefined) {
+    source = fromEnv ? "CLERK_READ_ONLY" : undefined;
+  } else if (await getBooleanSetting("core.read-only")) {
+    source = "core.read-only setting";
+  }
+  setReadOnly(source !== undefined);
+  if (source) log.debug(`config: read-only mode enabled via ${source}`);
+}
+
+/**
+ * `--dry-run` is global, so Commander hands it to the root program even when
+ * it's written after the subcommand. Commands that define their own dry run
+ * (a plan, a diff, a server-side check) get the flag back; every other command
+ * has its first mutating request printed instead of sent.
+ */
+function resolveDryRun(dryRun: boolean, actionCommand: CommandUnknownOpts): void {
+  const ownsDryRun = actionCommand.options.some((option) => option.long === "--dry-run");
+  if (dryRun && ownsDryRun) actionCommand.setOptionValue("dryRun", true);
+  setRequestDryRun(dryRun && !ownsDryRun);
+}
+
+/** The subcommand names leading to `command`, e.g. `users notes add`. Never its arguments. */
+function commandPath(command: CommandUnknownOpts): string {
+  const names: string[] = [];
+  let current = command;
+  while (current.parent) {
+    names.unshift(current.name());
+    current = current.parent;
+  }
+  return names.join(" ");
+}
+
+/** Mask personal data in tables when `display.redact-pii` is on, unless `--show-pii`. */
+async function resolvePiiRedaction(showPii: boolean | undefined): Promise<void> {
+  const redact = !showPii && (await getBooleanSetting("display.redact-pii"));
+  setRedactPii(redact);
+  if (redact) log.debug("config: masking emails and phone numbers via display.redact-pii setting");
+}
+
+/** Load the retry budget for Clerk API requests from the `http.*` settings. */
+async function resolveRetryPolicy(): Promise<void> {
+  const [retries, maxDelay] = await Promise.all([
+    getIntegerSetting("http.retries", DEFAULT_RETRIES),
+    getIntegerSetting("http.max-delay", DEFAULT_MAX_DELAY_MS / 1000),
+  ]);
+  setRetryPolicy({ retries, maxDelayMs: maxDelay * 1000 });
+  log.debug(`config: http.retries=${retries}, http.max-delay=${maxDelay}`);
+}
+
+/** Apply the `output.theme` palette and `output.colors` overrides for this invocation. */
+async function resolveOutputTheme(): Promise<void> {
+  const [theme, colors, locale] = await Promise.all([
+    getSetting("output.theme"),
+    getSetting("output.colors"),
+    getSetting("output.locale"),
+  ]);
+  setDisplayLocale(locale);
+  const parsed = colors ? parseColorOverrides(colors) : undefined;
+  setTheme(
+    (THEMES as readonly string[]).includes(theme ?? "") ? (theme as Theme) : "dark",
+    parsed && "overrides" in parsed ? parsed.overrides : {},
+  );
+}
+
 export function createProgram(): Program {
   const program = new Command()
     .name("clerk")
@@ -98,27 +243,57 @@ export function createProgram(): Program {
       "--mode <mode>",
       "Force interaction mode (human or agent). Defaults to auto-detect based on TTY.",
     )
-    .option("--verbose", "Show detailed output (enables debug messages)") as Program;
+    .option("--config <path>", "Use this config directory (or .json config file) for this run")
+    .option("--verbose", "Show debug output, including how keys and settings were resolved")
+    .option("--read-only", "Refuse to send mutating API requests (POST, PUT, PATCH, DELETE)")
+    .option("--dry-run", "Print the first mutating API request instead of sending it")
+    .option("--show-secrets", "Print secret keys and tokens instead of masking them")
+    .option("--show-pii", "Print emails and phone numbers even when display.redact-pii is on")
+    .option("--no-pager", "Do not pipe long output through a pager")
+    .addOption(
+      createOption(
+        "-o, --output-format <format>",
+        "Render tables and details in this format (default: table)",
+      ).choices(OUTPUT_FORMATS),
+    )
+    .option("-q, --quiet", "Print only resource IDs from list and create commands")
+    .option("--plain", "Print only data, without banners, spinners, or colors") as Program;
 
-  program.hook("preAction", async () => {
+  program.hook("preAction", async (_program, actionCommand) => {
     // Reset log level at the start of each command invocation so a previous
     // --verbose doesn't leak into subsequent runs.
     setLogLevel("info");
     const opts = program.opts();
+    setQuiet(Boolean(opts.quiet));
+    setPlain(Boolean(opts.plain));
     if (opts.verbose) {
       setLogLevel("debug");
+    } else if (opts.quiet || opts.plain) {
+      setLogLevel("error");
     }
+    setShowSecrets(Boolean(opts.showSecrets));
+    setPagerEnabled(opts.pager !== false);
+    // Before anything below reads settings or the saved environment.
+    if (opts.config) setConfigPath(opts.config);
     if (opts.mode) {
       if (opts.mode !== "human" && opts.mode !== "agent") {
         throwUsageError(`Invalid mode "${opts.mode}". Must be "human" or "agent".`);
       }
       setMode(opts.mode as Mode);
     }
+    setOutputFormat((opts.outputFormat ?? "table") as OutputFormat);
+
+    await resolveReadOnly(opts.readOnly);
+    resolveDryRun(Boolean(opts.dryRun), actionCommand);
+    startCommandTelemetry(commandPath(actionCommand));
+    await resolvePiiRedaction(opts.showPii);
+    await resolveRetryPolicy();
+    await resolveOutputTheme();
 
     // Initialize the active environment from persisted config
     const envName = await getEnvironment();
     if (envName && isValidEnv(envName)) {
-      setCurrentEnv(envName); // logs env + platformApiUrl
+      setCurrentEnv(envName); // logs env + API base URLs
     } else {
       if (envName) {
         log.warn(
@@ -126,22 +301,22 @@ export function createProgram(): Program {
         );
         log.warn(`Available environments: ${getAvailableEnvs().join(", ")}`);
       }
-      log.debug(`env: active environment is "production" (platformApiUrl=${getPlapiBaseUrl()})`);
+      logActiveEnv();
     }
 
     // Print environment banner to stderr when not on production,
     // so it doesn't pollute stdout for piped commands.
     const activeEnv = getCurrentEnvName();
-    if (activeEnv !== "production") {
+    if (activeEnv !== "production" && !opts.quiet && !opts.plain) {
       process.stderr.write(`[${activeEnv.toUpperCase()}]\n`);
     }
   });
 
   // Show update notification after each command, except for commands that
-  // already perform their own version check (doctor, update).
+  // already perform their own version check (doctor, update, version).
   program.hook("postAction", async (_thisCommand, actionCommand) => {
     const cmdName = actionCommand.name();
-    if (cmdName === "doctor" || cmdName === "update") return;
+    if (cmdName === "doctor" || cmdName === "update" || cmdName === "version") return;
     await maybeNotifyUpdate(getCurrentVersion());
   });
 
@@ -231,13 +406,23 @@ export async function runProgram(
   try {
     const { argv, from } = await resolveArgv(args, options?.from);
     await program.parseAsync(argv, { from });
+    await finishCommandTelemetry(!process.exitCode);
   } catch (error) {
     const verbose = program.opts().verbose ?? false;
+    // Telemetry counts a run as successful when it exits 0.
+    await finishCommandTelemetry(
+      error instanceof UserAbortError || isPromptExitError(error) || error instanceof DryRunRequest,
+    );
 
     if (error instanceof UserAbortError || isPromptExitError(error)) {
       process.exit(EXIT_CODE.SUCCESS);
     }
 
+    if (error instanceof DryRunRequest) {
+      printDryRunRequest(error);
+      process.exit(EXIT_CODE.SUCCESS);
+    }
+
     if (error instanceof CliError) {
       if (isAgent() && error.code) {
         outputJsonError(error.code, error.message, error.docsUrl, undefined, error.examples);
@@ -305,6 +490,23 @@ export async function runProgram(
   }
 }
 
+/**
+ * Agents get the request as JSON on stdout; people get it the way
+ * `clerk api --dry-run` shows it.
+ */
+/** The request plan is the command's output, so it goes to stdout in every mode. */
+function printDryRunRequest(request: DryRunRequest): void {
+  const { method, url, body } = request;
+  if (isAgent()) {
+    log.data(JSON.stringify({ dry_run: true, method, url, body }));
+    return;
+  }
+  log.data(`[dry-run] ${method} ${url}`);
+  if (body !== undefined) {
+    log.data(typeof body === "string" ? body : JSON.stringify(body, null, 2));
+  }
+}
+
 interface ApiErrorEntry {
   code?: string;
   message?: string;
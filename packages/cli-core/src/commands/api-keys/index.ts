@@ -0,0 +1,36 @@
+import type { Program } from "../../cli-program.ts";
+import { parseTimeOption } from "../../lib/option-parsers.ts";
+import { apiKeysList } from "./list.ts";
+
+export function registerApiKeys(program: Program): void {
+  const apiKeys = program
+    .command("api-keys")
+    .description("Inspect the API keys users and organizations use to call your backend")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  apiKeys
+    .command("list")
+    .description("List API keys with who created them and when they were last used")
+    .option("--subject <id>", "Only keys of this user or organization (user_... or org_...)")
+    .option(
+      "--stale <duration>",
+      "Only active keys unused for this long (e.g. 90d, 6mo), least recently used first",
+      (value) => parseTimeOption(value, "--stale"),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk api-keys list --stale 90d",
+        description: "Keys nobody has used in three months, candidates to revoke",
+      },
+      {
+        command: "clerk api-keys list --subject org_123 --json",
+        description: "An organization's keys as JSON",
+      },
+    ])
+    .action((_opts, cmd) =>
+      apiKeysList(cmd.optsWithGlobals() as Parameters<typeof apiKeysList>[0]),
+    );
+}
@@ -0,0 +1,100 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { apiKeysList, staleApiKeys } = await import("./list.ts");
+
+const DEPLOY = {
+  id: "ak_deploy",
+  name: "deploy",
+  subject: "org_1",
+  created_by: "user_1",
+  last_used_at: 9_000,
+  created_at: 1_000,
+};
+const BACKUP = { id: "ak_backup", name: "backup", subject: "org_1", created_at: 2_000 };
+const OLD = { ...BACKUP, id: "ak_old", revoked: true, created_at: 500 };
+
+type Call = { method: string; path: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+function ids(output: string): string[] {
+  return (JSON.parse(output) as { id: string }[]).map((key) => key.id);
+}
+
+describe("staleApiKeys", () => {
+  test("keeps active keys unused since the cutoff, counting never-used keys from creation", () => {
+    expect(staleApiKeys([DEPLOY, BACKUP, OLD], 5_000).map((key) => key.id)).toEqual([
+      "ak_backup",
+    ]);
+  });
+});
+
+describe("api-keys list", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockResolvedValue(respond({ data: [BACKUP, DEPLOY, OLD], total_count: 3 }));
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("lists every key, most recently used first", async () => {
+    await apiKeysList({ subject: "org_1", json: true });
+
+    const [{ path }] = mockBapiRequest.mock.calls[0] as [Call];
+    expect(path).toBe("/api_keys?limit=100&offset=0&include_invalid=true&subject=org_1");
+    expect(ids(captured.out)).toEqual(["ak_deploy", "ak_backup", "ak_old"]);
+  });
+
+  test("--stale keeps idle active keys, least recently used first", async () => {
+    await apiKeysList({ stale: 10_000, json: true });
+
+    expect(ids(captured.out)).toEqual(["ak_backup", "ak_deploy"]);
+  });
+
+  test("shows the creator, last use, and status", async () => {
+    await apiKeysList();
+
+    expect(captured.err).toContain("user_1");
+    expect(captured.err).toContain("never");
+    expect(captured.err).toContain("revoked");
+    expect(captured.err).toContain("3 API keys");
+  });
+
+  test("rejects a --subject that isn't a user or organization ID", async () => {
+    await expect(apiKeysList({ subject: "acme" })).rejects.toThrow(/org_/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,92 @@
+import {
+  apiKeyLastActivity,
+  apiKeyStatus,
+  listApiKeys,
+  type BapiApiKey,
+} from "../../lib/api-keys.ts";
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { printQuietIds } from "../../lib/quiet.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+
+export type ApiKeysListOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+  subject?: string;
+  /** Unix milliseconds: only active keys unused since then. */
+  stale?: number;
+  json?: boolean;
+};
+
+const API_KEY_COLUMNS: TableColumn<BapiApiKey>[] = [
+  { key: "id", header: "ID", value: (key) => key.id, style: cyan },
+  { key: "name", header: "NAME", value: (key) => key.name },
+  { key: "subject", header: "SUBJECT", value: (key) => key.subject },
+  { key: "created_by", header: "CREATED BY", value: (key) => key.created_by ?? undefined },
+  {
+    key: "last_used",
+    header: "LAST USED",
+    value: (key) => formatTimestamp(key.last_used_at) ?? "never",
+    style: dim,
+  },
+  { key: "created", header: "CREATED", value: (key) => formatTimestamp(key.created_at) },
+  { key: "status", header: "STATUS", value: apiKeyStatus },
+];
+
+/** Active keys not used, or created and never used, since `cutoff`. */
+export function staleApiKeys(keys: BapiApiKey[], cutoff: number): BapiApiKey[] {
+  return keys.filter((key) => apiKeyStatus(key) === "active" && apiKeyLastActivity(key) < cutoff);
+}
+
+export async function apiKeysList(options: ApiKeysListOptions = {}): Promise<void> {
+  const subject = options.subject;
+  if (subject !== undefined && !/^(user|org)_/.test(subject)) {
+    throwUsageError(
+      `--subject expects a user or organization ID (user_... or org_...), got \`${subject}\`.`,
+    );
+  }
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  const all = await withSpinner("Fetching API keys...", () =>
+    withApiContext(
+      listApiKeys(secretKey, subject),
+      subject ? `Failed to list API keys for ${subject}` : "Failed to list API keys",
+    ),
+  );
+  // Stale keys come least recently used first, since those are the first to revoke.
+  const keys =
+    options.stale === undefined
+      ? all.sort((a, b) => apiKeyLastActivity(b) - apiKeyLastActivity(a))
+      : staleApiKeys(all, options.stale).sort(
+          (a, b) => apiKeyLastActivity(a) - apiKeyLastActivity(b),
+        );
+
+  if (printQuietIds(keys.map((key) => key.id))) return;
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(keys, null, 2));
+    return;
+  }
+
+  if (keys.length === 0) {
+    log.warn(
+      options.stale === undefined
+        ? "No API keys found."
+        : `None of the ${all.length} API keys are stale.`,
+    );
+    return;
+  }
+
+  await printOutput(renderTable(keys, API_KEY_COLUMNS));
+  const filtered = keys.length < all.length ? ` (of ${all.length})` : "";
+  log.info(`\n${keys.length} API key${keys.length === 1 ? "" : "s"}${filtered}`);
+}
@@ -1,11 +1,16 @@
 import { test, expect, describe, beforeEach, afterEach, mock, spyOn } from "bun:test";
+import { mkdtemp, readFile, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
 import { useCaptureLog } from "../../test/lib/stubs.ts";
 
 const mockCreateApplication = mock();
 const mockFetchApplication = mock();
+const mockCreateProductionInstance = mock();
 mock.module("../../lib/plapi.ts", () => ({
   createApplication: (...args: unknown[]) => mockCreateApplication(...args),
   fetchApplication: (...args: unknown[]) => mockFetchApplication(...args),
+  createProductionInstance: (...args: unknown[]) => mockCreateProductionInstance(...args),
   PlapiError: class PlapiError extends Error {},
 }));
 
@@ -68,6 +73,7 @@ describe("apps create", () => {
   afterEach(() => {
     mockCreateApplication.mockReset();
     mockFetchApplication.mockReset();
+    mockCreateProductionInstance.mockReset();
     mockIsAgent.mockReset();
     logSpy.mockRestore();
     errorSpy.mockRestore();
@@ -165,6 +171,54 @@ describe("apps create", () => {
     });
   });
 
+  describe("--production-domain", () => {
+    const devOnly = { ...mockApp, instances: [mockApp.instances[0]!] };
+
+    test("clones the development instance into a new production instance", async () => {
+      mockFetchApplication.mockResolvedValueOnce(devOnly).mockResolvedValueOnce(mockApp);
+
+      await runCreate("My SaaS App", { productionDomain: "example.com", json: true });
+
+      expect(mockCreateProductionInstance).toHaveBeenCalledWith("app_abc123", {
+        domain: "example.com",
+        environment_type: "production",
+        clone_instance_id: "ins_dev1",
+      });
+      expect(JSON.parse(captured.out).instances).toHaveLength(2);
+    });
+
+    test("skips creation when the app already has a production instance", async () => {
+      await runCreate("My SaaS App", { productionDomain: "example.com", json: true });
+
+      expect(mockCreateProductionInstance).not.toHaveBeenCalled();
+    });
+  });
+
+  describe("--write-env", () => {
+    const originalCwd = process.cwd();
+    let tempDir: string;
+
+    beforeEach(async () => {
+      tempDir = await mkdtemp(join(tmpdir(), "clerk-apps-create-"));
+      process.chdir(tempDir);
+    });
+
+    afterEach(async () => {
+      process.chdir(originalCwd);
+      await rm(tempDir, { recursive: true, force: true });
+    });
+
+    test("writes only the development keys to the env file", async () => {
+      await runCreate("My SaaS App", { writeEnv: ".env.local", json: true });
+
+      const content = await readFile(join(tempDir, ".env.local"), "utf8");
+      expect(content).toContain("CLERK_PUBLISHABLE_KEY=pk_test_xxx");
+      expect(content).toContain("CLERK_SECRET_KEY=sk_test_xxx");
+      expect(content).not.toContain("sk_live_xxx");
+      expect(JSON.parse(captured.out).env_file).toBe(".env.local");
+    });
+  });
+
   describe("error handling", () => {
     test("propagates createApplication failure without fetching", async () => {
       mockCreateApplication.mockRejectedValue(new Error("Unprocessable Entity"));
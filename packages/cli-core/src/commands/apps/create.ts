@@ -1,19 +1,66 @@
-import { createApplication, fetchApplication } from "../../lib/plapi.ts";
+import { basename } from "node:path";
+import {
+  createApplication,
+  createProductionInstance,
+  fetchApplication,
+  type Application,
+} from "../../lib/plapi.ts";
 import { UserAbortError, isPromptExitError, withApiContext } from "../../lib/errors.ts";
 import { dim, cyan } from "../../lib/color.ts";
 import { withSpinner, intro, outro, pausedOutro } from "../../lib/spinner.ts";
 import { stripSecrets, displayName, printJson, type AppsOptions } from "./shared.ts";
 import { isInsideGutter, log } from "../../lib/log.ts";
 import { isAgent } from "../../mode.ts";
+import { detectEnvFile } from "../../lib/framework.ts";
+import { isQuiet, printQuietIds } from "../../lib/quiet.ts";
+import { resolveTargetFile, writeInstanceKeys } from "../env/pull.ts";
 
-export async function create(name: string, options: AppsOptions = {}): Promise<void> {
-  const shouldWrap = !isInsideGutter() && !options.json && !isAgent();
+export type AppsCreateOptions = AppsOptions & {
+  productionDomain?: string;
+  writeEnv?: boolean | string;
+};
+
+async function addProductionInstance(app: Application, domain: string): Promise<Application> {
+  if (app.instances.some((instance) => instance.environment_type === "production")) {
+    log.debug(`apps: ${app.application_id} already has a production instance`);
+    return app;
+  }
+  const development = app.instances.find((instance) => instance.environment_type === "development");
+  await withApiContext(
+    createProductionInstance(app.application_id, {
+      domain,
+      environment_type: "production",
+      clone_instance_id: development?.instance_id,
+    }),
+    "Failed to create production instance",
+  );
+  return withApiContext(fetchApplication(app.application_id), "Failed to fetch application");
+}
+
+/**
+ * Write the development instance's keys to a local env file. Production keys
+ * never go into a local file. Returns the path written, relative to cwd.
+ */
+async function writeDevelopmentKeys(
+  app: Application,
+  file: string | undefined,
+): Promise<string | undefined> {
+  const development = app.instances.find((instance) => instance.environment_type === "development");
+  if (!development) return undefined;
+  const cwd = process.cwd();
+  const targetFile = await resolveTargetFile(cwd, file, await detectEnvFile(cwd));
+  await writeInstanceKeys(cwd, targetFile, development);
+  return file ?? basename(targetFile);
+}
+
+export async function create(name: string, options: AppsCreateOptions = {}): Promise<void> {
+  const shouldWrap = !isInsideGutter() && !options.json && !isAgent() && !isQuiet();
   if (shouldWrap) intro("Creating application");
 
   let nextSteps: string[] | undefined;
   let closeStatus: "success" | "failed" | "paused" | undefined;
   try {
-    const app = await withSpinner("Creating application...", async () => {
+    let app = await withSpinner("Creating application...", async () => {
       const created = await withApiContext(createApplication(name), "Failed to create application");
       return withApiContext(
         fetchApplication(created.application_id),
@@ -21,16 +68,36 @@ export async function create(name: string, options: AppsOptions = {}): Promise<v
       );
     });
 
-    if (printJson(stripSecrets(app), options)) {
+    const domain = options.productionDomain;
+    if (domain) {
+      app = await withSpinner(`Creating production instance for ${domain}...`, () =>
+        addProductionInstance(app, domain),
+      );
+    }
+
+    const envFile = options.writeEnv
+      ? await writeDevelopmentKeys(
+          app,
+          typeof options.writeEnv === "string" ? options.writeEnv : undefined,
+        )
+      : undefined;
+
+    if (printQuietIds([app.application_id])) return;
+    if (printJson({ ...stripSecrets(app), ...(envFile ? { env_file: envFile } : {}) }, options)) {
       return;
     }
 
     log.blank();
     log.info(`Created ${cyan(displayName(app))} ${dim(app.application_id)}`);
+    for (const instance of app.instances) {
+      log.info(`  ${instance.environment_type.padEnd(12)}${dim(instance.publishable_key)}`);
+    }
+    if (envFile) log.info(`Development keys written to ${envFile}`);
     nextSteps = [
       `Run \`clerk link --app ${app.application_id}\` to connect this directory`,
-      "Run `clerk env pull` to fetch your environment variables",
+      ...(envFile ? [] : ["Run `clerk env pull` to fetch your environment variables"]),
     ];
+    if (domain) nextSteps.push("Run `clerk deploy` to finish DNS and domain setup for production");
     closeStatus = "success";
   } catch (error) {
     closeStatus = error instanceof UserAbortError || isPromptExitError(error) ? "paused" : "failed";
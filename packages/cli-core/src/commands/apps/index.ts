@@ -1,6 +1,7 @@
 import type { Program } from "../../cli-program.ts";
 import { list } from "./list.ts";
 import { create } from "./create.ts";
+import { appsOpen } from "./open.ts";
 
 export function registerApps(program: Program): void {
   const apps = program.command("apps").description("Manage your Clerk applications");
@@ -9,9 +10,16 @@ export function registerApps(program: Program): void {
     .command("list")
     .description("List your Clerk applications")
     .option("--json", "Output as JSON")
+    .option("--columns <list>", "Table columns to show, comma-separated (e.g. name,id)")
+    .option("--sort <column[:dir]>", "Sort by a column, e.g. name:desc")
+    .option("--wide", "Show every table column")
     .setExamples([
       { command: "clerk apps list", description: "List all applications" },
       { command: "clerk apps list --json", description: "Output as JSON" },
+      {
+        command: "clerk apps list --wide --sort name",
+        description: "Show instance IDs, sorted by name",
+      },
     ])
     .action(list);
 
@@ -20,9 +28,37 @@ export function registerApps(program: Program): void {
     .description("Create a new Clerk application")
     .argument("<name>", "Application name")
     .option("--json", "Output as JSON")
+    .option(
+      "--production-domain <domain>",
+      "Also create a production instance for this domain, cloned from development",
+    )
+    .option(
+      "--write-env [file]",
+      "Write the development keys to a local env file (default: detected from the framework)",
+    )
     .setExamples([
       { command: 'clerk apps create "My App"', description: "Create a new application" },
       { command: 'clerk apps create "My App" --json', description: "Output as JSON" },
+      {
+        command: 'clerk apps create "My App" --production-domain example.com --write-env',
+        description: "Create dev and prod instances and write dev keys to .env.local",
+      },
     ])
     .action(create);
+
+  apps
+    .command("open")
+    .description("Open an application in the Clerk Dashboard")
+    .argument("[app-id]", "Application ID (defaults to the linked or `clerk use` app)")
+    .option("--instance <id>", "Instance to open (dev, prod, or a full instance ID)")
+    .option("--print", "Print the URL without opening the browser")
+    .setExamples([
+      { command: "clerk apps open", description: "Open the linked app's dashboard" },
+      {
+        command: "clerk apps open app_123 --instance prod",
+        description: "Open another app's production instance",
+      },
+      { command: "clerk apps open --print", description: "Print the dashboard URL" },
+    ])
+    .action(appsOpen);
 }
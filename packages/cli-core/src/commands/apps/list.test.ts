@@ -135,6 +135,31 @@ describe("apps list", () => {
     });
   });
 
+  describe("table options", () => {
+    test("--wide adds instance IDs", async () => {
+      mockListApplications.mockResolvedValue(mockApps);
+
+      await runList({ wide: true });
+
+      expect(stdoutOut()).toContain("ins_dev1, ins_prod1");
+    });
+
+    test("--columns and --sort shape the table", async () => {
+      mockListApplications.mockResolvedValue(mockApps);
+
+      await runList({ columns: "id", sort: "name:desc" });
+
+      const out = stdoutOut();
+      expect(out).not.toContain("My SaaS App");
+      expect(out.indexOf("app_xyz789")).toBeLessThan(out.indexOf("app_abc123"));
+    });
+
+    test("rejects an unknown --sort column before calling the API", async () => {
+      await expect(runList({ sort: "created" })).rejects.toThrow('Unknown column "created"');
+      expect(mockListApplications).not.toHaveBeenCalled();
+    });
+  });
+
   describe("JSON output", () => {
     test("outputs JSON when --json flag is set", async () => {
       mockListApplications.mockResolvedValue(mockApps);
@@ -2,31 +2,33 @@ import { listApplications, type Application } from "../../lib/plapi.ts";
 import { UserAbortError, isPromptExitError, withApiContext } from "../../lib/errors.ts";
 import { dim, cyan } from "../../lib/color.ts";
 import { withSpinner, intro, outro, pausedOutro } from "../../lib/spinner.ts";
+import { isDocumentFormat } from "../../lib/output-format.ts";
+import { pageOutput, printOutput } from "../../lib/pager.ts";
+import { renderTable, validateTableOptions, type TableColumn } from "../../lib/table.ts";
 import { ui } from "../../lib/ui.ts";
+import { isQuiet, printQuietIds } from "../../lib/quiet.ts";
 import { stripSecrets, displayName, printJson, type AppsOptions } from "./shared.ts";
 import { isAgent } from "../../mode.ts";
 
-const COLUMN_PADDING = 2;
-
-function formatAppsTable(apps: Application[]): void {
-  const nameWidth =
-    Math.max("NAME".length, ...apps.map((a) => displayName(a).length)) + COLUMN_PADDING;
-  const idWidth =
-    Math.max("APP ID".length, ...apps.map((a) => a.application_id.length)) + COLUMN_PADDING;
-
-  const header = `${"NAME".padEnd(nameWidth)}${"APP ID".padEnd(idWidth)}ENVIRONMENTS`;
-  const rows = apps.map((app) => {
-    const name = displayName(app).padEnd(nameWidth);
-    const id = dim(app.application_id.padEnd(idWidth));
-    const envs = app.instances.map((i) => i.environment_type).join(", ");
-    return `${cyan(name)}${id}${envs}`;
-  });
-
-  ui.message([dim(header), ...rows]);
-}
+const APP_COLUMNS: TableColumn<Application>[] = [
+  { key: "name", header: "NAME", value: displayName, style: cyan },
+  { key: "id", header: "APP ID", value: (app) => app.application_id, style: dim },
+  {
+    key: "environments",
+    header: "ENVIRONMENTS",
+    value: (app) => app.instances.map((i) => i.environment_type).join(", "),
+  },
+  {
+    key: "instances",
+    header: "INSTANCE IDS",
+    value: (app) => app.instances.map((i) => i.instance_id).join(", "),
+    wide: true,
+  },
+];
 
 export async function list(options: AppsOptions = {}): Promise<void> {
-  const shouldWrap = !options.json && !isAgent();
+  const shouldWrap = !options.json && !isAgent() && !isQuiet();
+  validateTableOptions(APP_COLUMNS, options);
   if (shouldWrap) intro("Listing applications");
   let closeStatus: "success" | "failed" | "paused" | undefined;
 
@@ -36,6 +38,7 @@ export async function list(options: AppsOptions = {}): Promise<void> {
       ? await withSpinner("Fetching applications...", fetchApps)
       : await fetchApps();
 
+    if (printQuietIds(result.map((app) => app.application_id))) return;
     if (printJson(result.map(stripSecrets), options)) {
       return;
     }
@@ -46,7 +49,12 @@ export async function list(options: AppsOptions = {}): Promise<void> {
       return;
     }
 
-    formatAppsTable(result);
+    const table = renderTable(result, APP_COLUMNS, options);
+    if (isDocumentFormat()) {
+      await printOutput(table);
+    } else if (!(await pageOutput(table))) {
+      ui.message(table);
+    }
 
     const count = result.length;
     ui.message(`${count} application${count === 1 ? "" : "s"}`);
@@ -0,0 +1,16 @@
+import { resolveAppContext } from "../../lib/config.ts";
+import { openDashboardTarget } from "../open/index.ts";
+
+export type AppsOpenOptions = {
+  instance?: string;
+  print?: boolean;
+};
+
+/** Open an application's dashboard: the given app, or the linked / `clerk use` default. */
+export async function appsOpen(
+  appId: string | undefined,
+  options: AppsOpenOptions = {},
+): Promise<void> {
+  const target = await resolveAppContext({ app: appId, instance: options.instance });
+  await openDashboardTarget(target, undefined, { print: options.print, title: "Opening app" });
+}
@@ -4,6 +4,9 @@ import { log } from "../../lib/log.ts";
 
 export type AppsOptions = {
   json?: boolean;
+  columns?: string;
+  sort?: string;
+  wide?: boolean;
 };
 
 export function stripSecrets(app: Application) {
@@ -0,0 +1,124 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { BapiError } from "../../lib/errors.ts";
+import { getRetryPolicy, resetHttpRetryState } from "../../lib/http-retry.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+const mockResolveBapiSecretKey = mock();
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: (...args: unknown[]) => mockResolveBapiSecretKey(...args),
+  describeBapiTarget: async () => "My App (development)",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { bench, percentile, BENCH_ENDPOINTS } = await import("./bench.ts");
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("percentile", () => {
+  test("uses the nearest rank", () => {
+    const values = [40, 10, 30, 20, 50, 60, 70, 80, 90, 100];
+    expect(percentile(values, 50)).toBe(50);
+    expect(percentile(values, 95)).toBe(100);
+    expect(percentile([7], 95)).toBe(7);
+  });
+
+  test("returns null without samples", () => {
+    expect(percentile([], 50)).toBeNull();
+  });
+});
+
+describe("bench", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockResolveBapiSecretKey.mockResolvedValue("sk_test_123");
+    mockBapiRequest.mockResolvedValue(respond({}));
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockResolveBapiSecretKey.mockReset();
+    mockIsAgent.mockReset();
+    resetHttpRetryState();
+  });
+
+  test("sends the requested number of GETs to each endpoint", async () => {
+    await bench({ requests: 3 });
+
+    expect(mockBapiRequest).toHaveBeenCalledTimes(3 * BENCH_ENDPOINTS.length);
+    for (const [args] of mockBapiRequest.mock.calls) {
+      expect(args).toMatchObject({ method: "GET", secretKey: "sk_test_123" });
+    }
+    expect(captured.err).toContain("GET /users/count");
+    expect(captured.err).toContain("0 (0%)");
+  });
+
+  test("reports error rates and status breakdowns as JSON", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path === "/jwks") throw new BapiError(429, "{}", new Headers());
+      if (path === "/users/count") throw new TypeError("fetch failed");
+      return respond({});
+    });
+
+    await bench({ requests: 2, json: true });
+
+    const output = JSON.parse(captured.out) as {
+      target: string;
+      endpoints: {
+        name: string;
+        errors: number;
+        error_rate: number;
+        p50_ms: number | null;
+        statuses: Record<string, number>;
+      }[];
+    };
+    expect(output.target).toBe("My App (development)");
+    const byName = Object.fromEntries(output.endpoints.map((e) => [e.name, e]));
+    expect(byName.jwks).toMatchObject({ errors: 2, error_rate: 1, statuses: { "429": 2 } });
+    expect(byName.jwks!.p50_ms).not.toBeNull();
+    expect(byName["users.count"]).toMatchObject({
+      errors: 2,
+      p50_ms: null,
+      statuses: { network_error: 2 },
+    });
+    expect(byName["organizations.list"]).toMatchObject({ errors: 0, error_rate: 0 });
+  });
+
+  test("turns retries and the circuit breaker off during the run, then restores them", async () => {
+    const before = getRetryPolicy();
+    let during: ReturnType<typeof getRetryPolicy> | undefined;
+    mockBapiRequest.mockImplementation(async () => {
+      during = getRetryPolicy();
+      return respond({});
+    });
+
+    await bench({ requests: 1 });
+
+    expect(during).toMatchObject({ retries: 0, circuitBreaker: false });
+    expect(getRetryPolicy()).toEqual(before);
+  });
+});
@@ -0,0 +1,170 @@
+import { describeBapiTarget, resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim, red } from "../../lib/color.ts";
+import { ApiError, errorMessage } from "../../lib/errors.ts";
+import { getRetryPolicy, setRetryPolicy } from "../../lib/http-retry.ts";
+import { log } from "../../lib/log.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+
+export const DEFAULT_BENCH_REQUESTS = 10;
+
+export type BenchOptions = {
+  requests?: number;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+/** Cheap, read-only Backend API calls that every instance can answer. */
+export const BENCH_ENDPOINTS = [
+  { name: "jwks", path: "/jwks" },
+  { name: "users.count", path: "/users/count" },
+  { name: "organizations.list", path: "/organizations?limit=1" },
+] as const;
+
+type Sample = { ms: number; status?: number; error?: string };
+
+export type EndpointStats = {
+  name: string;
+  method: "GET";
+  path: string;
+  requests: number;
+  errors: number;
+  error_rate: number;
+  p50_ms: number | null;
+  p95_ms: number | null;
+  max_ms: number | null;
+  statuses: Record<string, number>;
+};
+
+/** Nearest-rank percentile of `values` (0 < p <= 100), or `null` when empty. */
+export function percentile(values: number[], p: number): number | null {
+  if (values.length === 0) return null;
+  const sorted = [...values].sort((a, b) => a - b);
+  const rank = Math.ceil((p / 100) * sorted.length);
+  return sorted[Math.min(sorted.length, Math.max(1, rank)) - 1]!;
+}
+
+function round(ms: number | null): number | null {
+  return ms === null ? null : Math.round(ms * 10) / 10;
+}
+
+async function timeRequest(secretKey: string, path: string): Promise<Sample> {
+  const start = performance.now();
+  try {
+    const response = await bapiRequest({ method: "GET", path, secretKey });
+    return { ms: performance.now() - start, status: response.status };
+  } catch (error) {
+    const ms = performance.now() - start;
+    if (error instanceof ApiError) return { ms, status: error.status };
+    return { ms, error: errorMessage(error) };
+  }
+}
+
+export function summarize(
+  endpoint: (typeof BENCH_ENDPOINTS)[number],
+  samples: Sample[],
+): EndpointStats {
+  const statuses: Record<string, number> = {};
+  let errors = 0;
+  for (const sample of samples) {
+    const key = sample.status === undefined ? "network_error" : String(sample.status);
+    statuses[key] = (statuses[key] ?? 0) + 1;
+    if (sample.status === undefined || sample.status >= 400) errors++;
+  }
+  // Latency is only meaningful for requests the API actually answered.
+  const answered = samples.filter((sample) => sample.status !== undefined).map((s) => s.ms);
+  return {
+    name: endpoint.name,
+    method: "GET",
+    path: endpoint.path,
+    requests: samples.length,
+    errors,
+    error_rate: samples.length ? errors / samples.length : 0,
+    p50_ms: round(percentile(answered, 50)),
+    p95_ms: round(percentile(answered, 95)),
+    max_ms: round(answered.length ? Math.max(...answered) : null),
+    statuses,
+  };
+}
+
+const formatMs = (ms: number | null) => (ms === null ? undefined : `${Math.round(ms)}ms`);
+
+const BENCH_COLUMNS: TableColumn<EndpointStats>[] = [
+  { key: "endpoint", header: "ENDPOINT", value: (s) => `${s.method} ${s.path}`, style: cyan },
+  { key: "requests", header: "REQUESTS", value: (s) => String(s.requests) },
+  {
+    key: "errors",
+    header: "ERRORS",
+    value: (s) => `${s.errors} (${Math.round(s.error_rate * 100)}%)`,
+  },
+  { key: "p50", header: "P50", value: (s) => formatMs(s.p50_ms) },
+  { key: "p95", header: "P95", value: (s) => formatMs(s.p95_ms) },
+  { key: "max", header: "MAX", value: (s) => formatMs(s.max_ms) },
+];
+
+function formatStatuses(stats: EndpointStats): string | undefined {
+  const failures = Object.entries(stats.statuses).filter(
+    ([status]) => status === "network_error" || Number(status) >= 400,
+  );
+  if (failures.length === 0) return undefined;
+  const detail = failures.map(([status, count]) => `${status} x${count}`).join(", ");
+  return `${stats.name}: ${red(detail)}`;
+}
+
+export async function bench(options: BenchOptions = {}): Promise<void> {
+  const requests = options.requests ?? DEFAULT_BENCH_REQUESTS;
+  const json = Boolean(options.json || isAgent());
+  const targeting = {
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  };
+
+  const secretKey = await resolveBapiSecretKey(targeting);
+  const target = await describeBapiTarget(targeting);
+
+  if (!json) intro(target ? `Benchmarking ${target}` : "Benchmarking the Backend API");
+
+  // Measure the API as-is: retries would hide errors and stretch latencies,
+  // and an open circuit would record instant failures instead of requests.
+  const policy = getRetryPolicy();
+  setRetryPolicy({ ...policy, retries: 0, circuitBreaker: false });
+
+  const results: EndpointStats[] = [];
+  try {
+    await withSpinner("Sending requests...", async (spinner) => {
+      for (const endpoint of BENCH_ENDPOINTS) {
+        const samples: Sample[] = [];
+        // Sequential on purpose: concurrent requests measure the rate limiter,
+        // not round-trip latency.
+        for (let i = 0; i < requests; i++) {
+          spinner.update(`${endpoint.name} ${dim(`${i + 1}/${requests}`)}`);
+          samples.push(await timeRequest(secretKey, endpoint.path));
+        }
+        results.push(summarize(endpoint, samples));
+      }
+    });
+  } finally {
+    setRetryPolicy(policy);
+  }
+
+  if (json) {
+    log.data(JSON.stringify({ target: target ?? null, requests, endpoints: results }, null, 2));
+    return;
+  }
+
+  for (const line of renderTable(results, BENCH_COLUMNS)) log.info(line);
+  const failures = results.map(formatStatuses).filter((line) => line !== undefined);
+  if (failures.length > 0) {
+    log.blank();
+    for (const line of failures) log.info(line);
+  }
+
+  const total = results.reduce((sum, stats) => sum + stats.requests, 0);
+  const errors = results.reduce((sum, stats) => sum + stats.errors, 0);
+  await outro(errors ? `${errors} of ${total} requests failed` : `${total} requests succeeded`);
+}
@@ -0,0 +1,27 @@
+import type { Program } from "../../cli-program.ts";
+import { parseIntegerOption } from "../../lib/option-parsers.ts";
+import { bench, DEFAULT_BENCH_REQUESTS } from "./bench.ts";
+
+export function registerBench(program: Program): void {
+  program
+    .command("bench")
+    .description("Measure Backend API latency and error rates from this machine")
+    .option(
+      "-n, --requests <count>",
+      `Requests per endpoint (1-100, default ${DEFAULT_BENCH_REQUESTS})`,
+      (value) => parseIntegerOption(value, "--requests", { min: 1, max: 100 }),
+    )
+    .option("--json", "Output results as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      { command: "clerk bench", description: "Time 10 requests to each benchmark endpoint" },
+      { command: "clerk bench -n 50", description: "Take more samples for steadier percentiles" },
+      {
+        command: "clerk bench --instance prod --json",
+        description: "Benchmark the production instance and print JSON",
+      },
+    ])
+    .action((_opts, cmd) => bench(cmd.optsWithGlobals() as Parameters<typeof bench>[0]));
+}
@@ -0,0 +1,95 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { ApiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { billingCheck } = await import("./check.ts");
+
+function item(slug: string, status: string, features: string[]) {
+  return {
+    id: `csubi_${slug}`,
+    status,
+    plan: { id: `cplan_${slug}`, name: slug, slug, features: features.map((f) => ({ slug: f })) },
+  };
+}
+
+const SUBSCRIPTION = {
+  id: "csub_1",
+  status: "active",
+  subscription_items: [
+    item("free", "ended", ["basic"]),
+    item("pro", "active", ["basic", "ai_assistant"]),
+    item("team", "upcoming", ["basic", "ai_assistant", "sso"]),
+  ],
+};
+
+describe("billing check", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(true);
+    mockBapiRequest.mockResolvedValue({ status: 200, headers: new Headers(), body: SUBSCRIPTION });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = 0;
+  });
+
+  test("reports the active plan that grants the feature", async () => {
+    await billingCheck("org_123", { feature: "ai_assistant" });
+
+    expect(mockBapiRequest.mock.calls[0]![0].path).toBe(
+      "/organizations/org_123/billing/subscription",
+    );
+    const result = JSON.parse(captured.out);
+    expect(result.entitled).toBe(true);
+    expect(result.granted_by).toBe("pro");
+    expect(process.exitCode).toBe(0);
+  });
+
+  test("ignores features on items that are not active", async () => {
+    await billingCheck("user_123", { feature: "sso" });
+
+    expect(JSON.parse(captured.out).entitled).toBe(false);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("treats a payer without a subscription as not entitled", async () => {
+    mockBapiRequest.mockRejectedValue(new ApiError(404, JSON.stringify({ errors: [] })));
+
+    await billingCheck("user_123", { feature: "basic" });
+
+    expect(JSON.parse(captured.out)).toMatchObject({ entitled: false, items: [] });
+  });
+
+  test("rejects IDs that are not users or organizations", async () => {
+    await expect(billingCheck("sess_123", { feature: "basic" })).rejects.toThrow("user_...");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
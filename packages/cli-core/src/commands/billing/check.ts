@@ -0,0 +1,147 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import {
+  fetchBillingSubscription,
+  type BillingSubscription,
+  type BillingSubscriptionItem,
+} from "../../lib/billing.ts";
+import { bold, cyan, dim, green, red } from "../../lib/color.ts";
+import { EXIT_CODE, throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { formatAmount } from "../../lib/locale.ts";
+import { log } from "../../lib/log.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+
+export type BillingCheckOptions = {
+  feature?: string;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+/**
+ * Item statuses whose plan features end up in the session's `fea` claim.
+ * A canceled item stays `active` until its period ends, so it still counts.
+ */
+const ENTITLING_STATUSES = new Set(["active"]);
+
+export type EntitlementResult = {
+  payer_id: string;
+  feature: string;
+  entitled: boolean;
+  /** Plan slug that grants the feature, when entitled. */
+  granted_by: string | null;
+  subscription_status: string | null;
+  items: {
+    plan: string | null;
+    status: string;
+    features: string[];
+  }[];
+};
+
+function featureSlugs(item: BillingSubscriptionItem): string[] {
+  return (item.plan?.features ?? []).map((feature) => feature.slug);
+}
+
+/**
+ * Decide whether `subscription` entitles its payer to `feature`, the way
+ * `has({ feature })` does in application code: the feature must be on the
+ * plan of an active subscription item.
+ */
+export function checkEntitlement(
+  payerId: string,
+  feature: string,
+  subscription: BillingSubscription | undefined,
+): EntitlementResult {
+  const items = subscription?.subscription_items ?? [];
+  const granting = items.find(
+    (item) => ENTITLING_STATUSES.has(item.status) && featureSlugs(item).includes(feature),
+  );
+  return {
+    payer_id: payerId,
+    feature,
+    entitled: Boolean(granting),
+    granted_by: granting?.plan?.slug ?? null,
+    subscription_status: subscription?.status ?? null,
+    items: items.map((item) => ({
+      plan: item.plan?.slug ?? null,
+      status: item.status,
+      features: featureSlugs(item),
+    })),
+  };
+}
+
+const ITEM_COLUMNS: TableColumn<BillingSubscriptionItem>[] = [
+  { key: "plan", header: "PLAN", value: (item) => item.plan?.slug, style: cyan },
+  { key: "status", header: "STATUS", value: (item) => item.status },
+  { key: "period", header: "PERIOD", value: (item) => item.plan_period },
+  {
+    key: "price",
+    header: "PRICE",
+    value: (item) => (item.amount ? formatAmount(item.amount) : undefined),
+  },
+  {
+    key: "ends",
+    header: "PERIOD ENDS",
+    value: (item) => formatTimestamp(item.period_end),
+    sortValue: (item) => item.period_end ?? undefined,
+  },
+  {
+    key: "features",
+    header: "FEATURES",
+    value: (item) => featureSlugs(item).join(", "),
+    style: dim,
+  },
+];
+
+export async function billingCheck(
+  payerArg: string | undefined,
+  options: BillingCheckOptions = {},
+): Promise<void> {
+  const payerId = (await requireArg(payerArg, { label: "User or organization ID" })).trim();
+  if (!payerId.startsWith("user_") && !payerId.startsWith("org_")) {
+    throwUsageError(
+      `Expected a user ID (user_...) or organization ID (org_...), got "${payerId}".`,
+    );
+  }
+  const feature = options.feature?.trim();
+  if (!feature) throwUsageError("Pass the feature key to check with --feature.");
+
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const subscription = await withSpinner("Fetching subscription...", () =>
+    withApiContext(
+      fetchBillingSubscription(secretKey, payerId),
+      `Failed to fetch the subscription for ${payerId}`,
+    ),
+  );
+  const result = checkEntitlement(payerId, feature, subscription);
+
+  // Like `test`, a negative answer exits non-zero so scripts can branch on it.
+  if (!result.entitled) process.exitCode = EXIT_CODE.GENERAL;
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(result, null, 2));
+    return;
+  }
+
+  if (!subscription) {
+    log.info(`${payerId} has no billing subscription.`);
+  } else {
+    log.info(`${bold("Subscription")} ${subscription.id} ${dim(`(${subscription.status})`)}`);
+    for (const line of renderTable(subscription.subscription_items ?? [], ITEM_COLUMNS)) {
+      log.info(`  ${line}`);
+    }
+  }
+  log.blank();
+  if (result.entitled) {
+    log.info(green(`✓ ${payerId} is entitled to "${feature}" via the ${result.granted_by} plan`));
+  } else {
+    log.info(red(`✗ ${payerId} is not entitled to "${feature}"`));
+  }
+}
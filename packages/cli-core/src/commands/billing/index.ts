@@ -1,3 +1,4 @@
+import type { Program } from "../../cli-program.ts";
 import { resolveAppContext } from "../../lib/config.ts";
 import { throwUsageError } from "../../lib/errors.ts";
 import { isAgent, isHuman } from "../../mode.ts";
@@ -8,6 +9,7 @@ import { NEXT_STEPS } from "../../lib/next-steps.ts";
 import { withGutter } from "../../lib/spinner.ts";
 import { resolveSkillsRunner, runSkillsAdd } from "../../lib/skills.ts";
 import { applyConfigPatch } from "../config/apply-patch.ts";
+import { billingCheck } from "./check.ts";
 
 interface BillingOptions {
   app?: string;
@@ -141,3 +143,32 @@ export async function billingDisable(options: BillingOptions): Promise<void> {
     });
   });
 }
+
+export function registerBilling(program: Program): void {
+  const billing = program
+    .command("billing")
+    .description("Inspect Clerk Billing subscriptions")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  billing
+    .command("check")
+    .description("Check whether a user or organization is entitled to a plan feature")
+    .argument("[payer]", "User ID (user_...) or organization ID (org_...)")
+    .requiredOption("--feature <key>", "Feature key, as passed to has({ feature })")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk billing check user_123 --feature ai_assistant",
+        description: "Show the user's plans and whether they include the feature",
+      },
+      {
+        command: "clerk billing check org_123 --feature sso --json",
+        description: "Report the result as JSON (exits 1 when not entitled)",
+      },
+    ])
+    .action((payer, _opts, cmd) =>
+      billingCheck(payer, cmd.optsWithGlobals() as Parameters<typeof billingCheck>[1]),
+    );
+}
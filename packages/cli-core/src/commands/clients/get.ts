@@ -0,0 +1,82 @@
+import { bold, cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { fetchClient, type Session } from "../../lib/sessions.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatFields, formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import { sessionClient, sessionLocation } from "../sessions/list.ts";
+import {
+  resolveClientsSecretKey,
+  summarizeClient,
+  type ClientSummary,
+  type TargetingOptions,
+} from "./list.ts";
+
+export type ClientsGetOptions = TargetingOptions & {
+  json?: boolean;
+};
+
+const SESSION_COLUMNS: TableColumn<Session>[] = [
+  { key: "id", header: "SESSION ID", value: (session) => session.id, style: cyan },
+  { key: "user", header: "USER", value: (session) => session.user_id },
+  { key: "status", header: "STATUS", value: (session) => session.status },
+  {
+    key: "ip",
+    header: "IP",
+    value: (session) => session.latest_activity?.ip_address ?? undefined,
+  },
+  { key: "location", header: "LOCATION", value: sessionLocation },
+  { key: "client", header: "DEVICE", value: sessionClient, style: dim },
+  {
+    key: "last_active",
+    header: "LAST ACTIVE",
+    value: (session) => formatTimestamp(session.last_active_at),
+  },
+];
+
+export function formatClientDetail(client: ClientSummary): string[] {
+  const sessions = client.sessions ?? [];
+  return [
+    bold(client.id),
+    ...formatFields([
+      ["Users", client.user_ids.join(", ") || undefined],
+      ["Last active session", client.last_active_session_id ?? undefined],
+      ["Sign-in attempt", client.sign_in_id ?? undefined],
+      ["Sign-up attempt", client.sign_up_id ?? undefined],
+      ["Last active", formatTimestamp(client.last_active_at)],
+      ["Created", formatTimestamp(client.created_at)],
+      ["Updated", formatTimestamp(client.updated_at)],
+    ]),
+    "",
+    bold(`Sessions (${sessions.length})`),
+    ...(sessions.length
+      ? renderTable(sessions, SESSION_COLUMNS).map((line) => `  ${line}`)
+      : [dim("  (none)")]),
+  ];
+}
+
+export async function clientsGet(
+  clientArg: string | undefined,
+  options: ClientsGetOptions = {},
+): Promise<void> {
+  const clientId = (await requireArg(clientArg, { label: "Client ID" })).trim();
+  if (!clientId.startsWith("client_")) {
+    throwUsageError(`Expected a client ID (client_...), got \`${clientId}\`.`);
+  }
+  const secretKey = await resolveClientsSecretKey(options);
+
+  const client = await withSpinner("Fetching client...", () =>
+    withApiContext(fetchClient(secretKey, clientId), `Failed to fetch client ${clientId}`),
+  );
+  const summary = summarizeClient(client);
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(summary, null, 2));
+    return;
+  }
+
+  await printOutput(formatClientDetail(summary), { page: false });
+}
@@ -0,0 +1,60 @@
+import type { Program } from "../../cli-program.ts";
+import { parseIntegerOption, parseTimeOption } from "../../lib/option-parsers.ts";
+import { clientsGet } from "./get.ts";
+import { clientsList, DEFAULT_LIMIT } from "./list.ts";
+
+export function registerClients(program: Program): void {
+  const clients = program
+    .command("clients")
+    .description("Inspect clients (the browsers and devices users sign in from)")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  clients
+    .command("list")
+    .description("List clients, most recently active first")
+    .option("--user <user-id>", "Only clients this user has sessions on")
+    .option(
+      "--active-since <time>",
+      "Only clients active since a time (e.g. 24h, 2024-06-01)",
+      (value) => parseTimeOption(value, "--active-since"),
+    )
+    .option(
+      "--limit <number>",
+      `Clients to fetch (1-500, default ${DEFAULT_LIMIT}); ignored with --user`,
+      (value) => parseIntegerOption(value, "--limit", { min: 1, max: 500 }),
+    )
+    .option("--offset <number>", "Clients to skip before fetching (0+)", (value) =>
+      parseIntegerOption(value, "--offset", { min: 0 }),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk clients list --user user_123",
+        description: "Every browser or device a user has signed in from",
+      },
+      {
+        command: "clerk clients list --active-since 24h",
+        description: "Clients used in the last day",
+      },
+    ])
+    .action((_opts, cmd) =>
+      clientsList(cmd.optsWithGlobals() as Parameters<typeof clientsList>[0]),
+    );
+
+  clients
+    .command("get")
+    .description("Show a client and its sessions")
+    .argument("[client-id]", "Client ID (client_...)")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk clients get client_123",
+        description: "Show which users and sessions share a device",
+      },
+    ])
+    .action((clientId, _opts, cmd) =>
+      clientsGet(clientId, cmd.optsWithGlobals() as Parameters<typeof clientsGet>[1]),
+    );
+}
@@ -0,0 +1,160 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { clientsList, summarizeClient } = await import("./list.ts");
+const { clientsGet } = await import("./get.ts");
+
+const LAPTOP = {
+  id: "client_laptop",
+  updated_at: 1_000,
+  sessions: [
+    {
+      id: "sess_1",
+      user_id: "user_1",
+      status: "active",
+      last_active_at: 5_000,
+      latest_activity: { browser_name: "Firefox", city: "Berlin", country: "DE" },
+    },
+    { id: "sess_2", user_id: "user_2", status: "ended", last_active_at: 3_000 },
+  ],
+};
+const PHONE = { id: "client_phone", updated_at: 9_000, sessions: [] };
+
+type Call = { method: string; path: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+function paths(): string[] {
+  return mockBapiRequest.mock.calls.map(([args]) => (args as Call).path);
+}
+
+describe("summarizeClient", () => {
+  test("takes activity and users from the sessions", () => {
+    const summary = summarizeClient(LAPTOP);
+    expect(summary.last_active_at).toBe(5_000);
+    expect(summary.user_ids).toEqual(["user_1", "user_2"]);
+  });
+
+  test("falls back to updated_at without sessions", () => {
+    expect(summarizeClient(PHONE).last_active_at).toBe(9_000);
+  });
+});
+
+describe("clients list", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("lists a page of clients, most recently active first", async () => {
+    mockBapiRequest.mockResolvedValue(respond([LAPTOP, PHONE]));
+
+    await clientsList({ limit: 2, json: true });
+
+    expect(paths()).toEqual(["/clients?limit=2"]);
+    const clients = JSON.parse(captured.out) as { id: string }[];
+    expect(clients.map((client) => client.id)).toEqual(["client_phone", "client_laptop"]);
+  });
+
+  test("--user finds clients through the user's sessions", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: Call) => {
+      if (path.startsWith("/sessions?")) return respond([withClient(LAPTOP.sessions[0]!)]);
+      return respond(LAPTOP);
+    });
+
+    await clientsList({ user: "user_1", json: true });
+
+    expect(paths()).toEqual(["/sessions?user_id=user_1", "/clients/client_laptop"]);
+    const [client] = JSON.parse(captured.out) as { user_ids: string[]; sessions: unknown[] }[];
+    expect(client!.user_ids).toEqual(["user_1"]);
+    expect(client!.sessions).toHaveLength(1);
+  });
+
+  test("--active-since drops clients idle since then", async () => {
+    mockBapiRequest.mockResolvedValue(respond([LAPTOP, PHONE]));
+
+    await clientsList({ activeSince: 6_000, json: true });
+
+    expect((JSON.parse(captured.out) as { id: string }[]).map((client) => client.id)).toEqual([
+      "client_phone",
+    ]);
+  });
+
+  test("shows device, location, and session counts", async () => {
+    mockBapiRequest.mockResolvedValue(respond([LAPTOP]));
+
+    await clientsList();
+
+    expect(captured.err).toContain("Firefox");
+    expect(captured.err).toContain("Berlin, DE");
+    expect(captured.err).toContain("2 (1 active)");
+  });
+
+  test("rejects a --user that isn't a user ID", async () => {
+    await expect(clientsList({ user: "alice" })).rejects.toThrow(/user_/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
+
+describe("clients get", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockResolvedValue(respond(LAPTOP));
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("shows the client's sessions", async () => {
+    await clientsGet("client_laptop");
+
+    expect(paths()).toEqual(["/clients/client_laptop"]);
+    expect(captured.err).toContain("Sessions (2)");
+    expect(captured.err).toContain("sess_2");
+  });
+
+  test("rejects IDs that aren't client IDs", async () => {
+    await expect(clientsGet("sess_1")).rejects.toThrow(/client_/);
+  });
+});
+
+function withClient<T>(session: T): T & { client_id: string } {
+  return { ...session, client_id: "client_laptop" };
+}
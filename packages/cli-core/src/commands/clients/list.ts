@@ -0,0 +1,162 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { printQuietIds } from "../../lib/quiet.ts";
+import {
+  fetchClient,
+  listClients,
+  listUserSessions,
+  type Session,
+  type SessionClient,
+} from "../../lib/sessions.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import { sessionClient, sessionLocation } from "../sessions/list.ts";
+
+export type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type ClientsListOptions = TargetingOptions & {
+  user?: string;
+  activeSince?: number;
+  limit?: number;
+  offset?: number;
+  json?: boolean;
+};
+
+/** A client with the activity derived from its sessions. */
+export type ClientSummary = SessionClient & {
+  /** Latest `last_active_at` across the client's sessions, else `updated_at`. */
+  last_active_at: number | null;
+  user_ids: string[];
+};
+
+export const DEFAULT_LIMIT = 20;
+
+export function resolveClientsSecretKey(options: TargetingOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+function latestSession(sessions: Session[]): Session | undefined {
+  return sessions.reduce<Session | undefined>(
+    (latest, session) =>
+      (session.last_active_at ?? 0) > (latest?.last_active_at ?? 0) ? session : latest,
+    undefined,
+  );
+}
+
+export function summarizeClient(client: SessionClient, sessions = client.sessions ?? []) {
+  const latest = latestSession(sessions);
+  const summary: ClientSummary = {
+    ...client,
+    sessions,
+    last_active_at: latest?.last_active_at ?? client.updated_at ?? null,
+    user_ids: [...new Set(sessions.flatMap((session) => session.user_id ?? []))].sort(),
+  };
+  return summary;
+}
+
+function formatSessionCounts(client: ClientSummary): string {
+  const sessions = client.sessions ?? [];
+  const active = sessions.filter((session) => session.status === "active").length;
+  return active ? `${sessions.length} (${active} active)` : String(sessions.length);
+}
+
+const CLIENT_COLUMNS: TableColumn<ClientSummary>[] = [
+  { key: "id", header: "CLIENT ID", value: (client) => client.id, style: cyan },
+  {
+    key: "device",
+    header: "DEVICE",
+    value: (client) => {
+      const latest = latestSession(client.sessions ?? []);
+      return latest ? sessionClient(latest) : undefined;
+    },
+    style: dim,
+  },
+  {
+    key: "location",
+    header: "LOCATION",
+    value: (client) => {
+      const latest = latestSession(client.sessions ?? []);
+      return latest ? sessionLocation(latest) : undefined;
+    },
+  },
+  { key: "users", header: "USERS", value: (client) => client.user_ids.join(", ") },
+  { key: "sessions", header: "SESSIONS", value: formatSessionCounts },
+  {
+    key: "last_active",
+    header: "LAST ACTIVE",
+    value: (client) => formatTimestamp(client.last_active_at),
+  },
+];
+
+/**
+ * The clients a user has signed in from. BAPI can't filter clients by user,
+ * so they are found through the user's sessions, which also stand in for the
+ * client's embedded session list (limited to this user).
+ */
+async function userClients(secretKey: string, userId: string): Promise<ClientSummary[]> {
+  const sessions = await listUserSessions(secretKey, { userId });
+  const byClient = Map.groupBy(
+    sessions.filter((session) => session.client_id),
+    (session) => session.client_id!,
+  );
+  return Promise.all(
+    [...byClient].map(async ([clientId, clientSessions]) =>
+      summarizeClient(await fetchClient(secretKey, clientId), clientSessions),
+    ),
+  );
+}
+
+export async function clientsList(options: ClientsListOptions = {}): Promise<void> {
+  const user = options.user;
+  if (user !== undefined && !user.startsWith("user_")) {
+    throwUsageError(`--user expects a user ID (user_...), got \`${user}\`.`);
+  }
+  const secretKey = await resolveClientsSecretKey(options);
+  const limit = options.limit ?? DEFAULT_LIMIT;
+
+  const all = await withSpinner("Fetching clients...", () =>
+    withApiContext(
+      user
+        ? userClients(secretKey, user)
+        : listClients(secretKey, { limit, offset: options.offset }).then((clients) =>
+            clients.map((client) => summarizeClient(client)),
+          ),
+      user ? `Failed to list clients for ${user}` : "Failed to list clients",
+    ),
+  );
+  const since = options.activeSince;
+  const clients = all
+    .filter((client) => since === undefined || (client.last_active_at ?? 0) >= since)
+    .sort((a, b) => (b.last_active_at ?? 0) - (a.last_active_at ?? 0));
+
+  if (printQuietIds(clients.map((client) => client.id))) return;
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(clients, null, 2));
+    return;
+  }
+
+  if (clients.length === 0) {
+    log.warn(all.length ? `None of the ${all.length} clients match.` : "No clients found.");
+    return;
+  }
+
+  const table = renderTable(clients, CLIENT_COLUMNS);
+  await printOutput(table);
+  const filtered = clients.length < all.length ? ` (of ${all.length})` : "";
+  log.info(`\n${clients.length} client${clients.length === 1 ? "" : "s"}${filtered}`);
+  if (!user && all.length === limit) {
+    log.info(dim(`More may exist; re-run with \`--offset ${(options.offset ?? 0) + limit}\`.`));
+  }
+}
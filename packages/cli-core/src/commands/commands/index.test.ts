@@ -0,0 +1,121 @@
+import { test, expect, describe, beforeEach } from "bun:test";
+import { Command, Option } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { setMode } from "../../mode.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+import { commands, describeCommand } from "./index.ts";
+
+function buildProgram() {
+  const program = new Command().name("clerk").description("Clerk CLI");
+  program.option("--verbose", "Show debug output");
+  const users = program.command("users").description("Manage users").alias("user");
+  users
+    .command("list")
+    .description("List users")
+    .option("-l, --limit <n>", "Maximum results", "10")
+    .addOption(new Option("--order-by <field>", "Sort field").choices(["created_at", "email"]))
+    .option("--no-banned", "Exclude banned users")
+    .setExamples([{ command: "clerk users list", description: "List users" }]);
+  users.command("get").description("Get a user").argument("<userId>", "User ID");
+  program.command("secret", { hidden: true }).description("Not listed");
+  return program;
+}
+
+describe("describeCommand", () => {
+  test("describes the tree with arguments, options, and examples", () => {
+    const schema = describeCommand(buildProgram());
+
+    expect(schema.name).toBe("clerk");
+    expect(schema.options.map((option) => option.long)).toEqual(["--verbose"]);
+    expect(schema.commands.map((command) => command.name)).toEqual(["users"]);
+
+    const users = schema.commands[0]!;
+    expect(users.aliases).toEqual(["user"]);
+    expect(users.commands.map((command) => command.command)).toEqual([
+      "clerk users list",
+      "clerk users get",
+    ]);
+
+    const get = users.commands[1]!;
+    expect(get.arguments).toEqual([
+      { name: "userId", description: "User ID", required: true, variadic: false },
+    ]);
+
+    const list = users.commands[0]!;
+    expect(list.examples).toEqual([{ command: "clerk users list", description: "List users" }]);
+    expect(list.options).toEqual([
+      {
+        flags: "-l, --limit <n>",
+        name: "limit",
+        long: "--limit",
+        short: "-l",
+        description: "Maximum results",
+        value: { name: "n", required: true },
+        required: false,
+        variadic: false,
+        negatable: false,
+        default: "10",
+      },
+      {
+        flags: "--order-by <field>",
+        name: "orderBy",
+        long: "--order-by",
+        description: "Sort field",
+        value: { name: "field", required: true },
+        required: false,
+        variadic: false,
+        negatable: false,
+        choices: ["created_at", "email"],
+      },
+      {
+        flags: "--no-banned",
+        name: "banned",
+        long: "--no-banned",
+        description: "Exclude banned users",
+        value: null,
+        required: false,
+        variadic: false,
+        negatable: true,
+      },
+    ]);
+  });
+
+  test("leaves out hidden commands and the implicit help command", () => {
+    const names = describeCommand(buildProgram()).commands.map((command) => command.name);
+    expect(names).not.toContain("secret");
+    expect(names).not.toContain("help");
+  });
+});
+
+describe("commands", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    setMode("human");
+  });
+
+  test("prints the command tree", () => {
+    commands(buildProgram() as unknown as Program);
+
+    expect(captured.stdout).toHaveLength(3);
+    expect(captured.stdout[0]).toStartWith("users ");
+    expect(captured.stdout[1]).toStartWith("  list ");
+    expect(captured.stdout[2]).toStartWith("  get <userId>");
+    expect(captured.stdout[2]).toContain("Get a user");
+  });
+
+  test("--json prints the schema with the CLI version", () => {
+    commands(buildProgram() as unknown as Program, { json: true });
+
+    const payload = JSON.parse(captured.out);
+    expect(payload.version).toBeString();
+    expect(payload.commands[0].commands[0].command).toBe("clerk users list");
+  });
+
+  test("agent mode prints JSON", () => {
+    setMode("agent");
+    commands(buildProgram() as unknown as Program);
+
+    expect(JSON.parse(captured.out).name).toBe("clerk");
+  });
+});
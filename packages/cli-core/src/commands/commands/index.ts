@@ -0,0 +1,152 @@
+import type { Argument, CommandUnknownOpts, Option } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { dim } from "../../lib/color.ts";
+import { getExamples, type Example } from "../../lib/help.ts";
+import { log } from "../../lib/log.ts";
+import { getCurrentVersion } from "../../lib/update-check.ts";
+import { isAgent } from "../../mode.ts";
+
+type CommandsOptions = {
+  json?: boolean;
+};
+
+export type ArgumentSchema = {
+  name: string;
+  description: string;
+  required: boolean;
+  variadic: boolean;
+  choices?: string[];
+  default?: unknown;
+};
+
+export type OptionSchema = {
+  flags: string;
+  /** Key the parsed value is stored under, e.g. `secretKey` for `--secret-key`. */
+  name: string;
+  long?: string;
+  short?: string;
+  description: string;
+  /** Placeholder for the option's value, or `null` for boolean flags. */
+  value: { name: string; required: boolean } | null;
+  /** Whether the option itself must be passed. */
+  required: boolean;
+  variadic: boolean;
+  negatable: boolean;
+  choices?: string[];
+  default?: unknown;
+};
+
+export type CommandSchema = {
+  name: string;
+  /** Full invocation, e.g. `clerk users list`. */
+  command: string;
+  description: string;
+  aliases: string[];
+  arguments: ArgumentSchema[];
+  options: OptionSchema[];
+  examples: Example[];
+  commands: CommandSchema[];
+};
+
+function describeArgument(arg: Argument): ArgumentSchema {
+  return {
+    name: arg.name(),
+    description: arg.description,
+    required: arg.required,
+    variadic: arg.variadic,
+    ...(arg.argChoices && { choices: [...arg.argChoices] }),
+    ...(arg.defaultValue !== undefined && { default: arg.defaultValue }),
+  };
+}
+
+function describeOption(option: Option): OptionSchema {
+  const placeholder = /[<[]([^>\]]+)[>\]]/.exec(option.flags)?.[1];
+  return {
+    flags: option.flags,
+    name: option.attributeName(),
+    ...(option.long && { long: option.long }),
+    ...(option.short && { short: option.short }),
+    description: option.description,
+    value: placeholder
+      ? { name: placeholder.replace(/\.{3}$/, ""), required: option.required }
+      : null,
+    required: option.mandatory,
+    variadic: option.variadic,
+    negatable: option.negate,
+    ...(option.argChoices && { choices: [...option.argChoices] }),
+    ...(option.defaultValue !== undefined && { default: option.defaultValue }),
+  };
+}
+
+/**
+ * Describe `cmd` and its visible subcommands. Hidden commands and options are
+ * left out, as are Commander's implicit `help` command and `--help` option.
+ */
+export function describeCommand(cmd: CommandUnknownOpts, parentPath = ""): CommandSchema {
+  const helper = cmd.createHelp();
+  const path = parentPath ? `${parentPath} ${cmd.name()}` : cmd.name();
+  return {
+    name: cmd.name(),
+    command: path,
+    description: cmd.description(),
+    aliases: cmd.aliases(),
+    arguments: cmd.registeredArguments.map(describeArgument),
+    options: helper
+      .visibleOptions(cmd)
+      .filter((option) => cmd.options.includes(option))
+      .map(describeOption),
+    examples: getExamples(cmd),
+    commands: helper
+      .visibleCommands(cmd)
+      .filter((sub) => cmd.commands.includes(sub))
+      .map((sub) => describeCommand(sub, path)),
+  };
+}
+
+function formatTree(schema: CommandSchema): string[] {
+  const rows: { term: string; description: string }[] = [];
+  const walk = (node: CommandSchema, depth: number) => {
+    for (const sub of node.commands) {
+      const args = sub.arguments.map((arg) => {
+        const name = `${arg.name}${arg.variadic ? "..." : ""}`;
+        return arg.required ? `<${name}>` : `[${name}]`;
+      });
+      rows.push({
+        term: `${"  ".repeat(depth)}${[sub.name, ...args].join(" ")}`,
+        description: sub.description,
+      });
+      walk(sub, depth + 1);
+    }
+  };
+  walk(schema, 0);
+  const width = Math.max(...rows.map((row) => row.term.length));
+  return rows.map((row) => `${row.term.padEnd(width)}  ${dim(row.description)}`);
+}
+
+/**
+ * Print the full command tree. `--json` (and agent mode) emits every command's
+ * arguments, options, and examples for docs generators and other tools.
+ */
+export function commands(program: Program, options: CommandsOptions = {}): void {
+  const schema = describeCommand(program as unknown as CommandUnknownOpts);
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify({ version: getCurrentVersion(), ...schema }, null, 2));
+    return;
+  }
+  for (const line of formatTree(schema)) log.data(line);
+}
+
+export function registerCommands(program: Program): void {
+  program
+    .command("commands")
+    .description("List every command, or describe them all as JSON")
+    .option("--json", "Output a JSON schema of commands, arguments, and options")
+    .setExamples([
+      { command: "clerk commands", description: "Show the command tree" },
+      {
+        command: "clerk commands --json",
+        description: "Describe every command, argument, and option as JSON",
+      },
+    ])
+    .action((options) => commands(program, options));
+}
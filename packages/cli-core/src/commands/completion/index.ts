@@ -1,24 +1,16 @@
-import { createArgument } from "@commander-js/extra-typings";
+import { createArgument, createOption } from "@commander-js/extra-typings";
 import type { Program } from "../../cli-program.ts";
-import { generate as generateBash } from "./shells/bash.ts";
-import { generate as generateZsh } from "./shells/zsh.ts";
-import { generate as generateFish } from "./shells/fish.ts";
-import { generate as generatePowershell } from "./shells/powershell.ts";
 import { throwUsageError } from "../../lib/errors.ts";
 import { printNextSteps } from "../../lib/next-steps.ts";
+import { installCompletion } from "./install.ts";
+import {
+  generateCompletion,
+  isSupportedShell,
+  SUPPORTED_SHELLS,
+  type SupportedShell,
+} from "./shells/index.ts";
 
-type CompletionGenerator = (binaryName: string) => string;
-
-export const SUPPORTED_SHELLS = ["bash", "zsh", "fish", "powershell"] as const;
-
-export type SupportedShell = (typeof SUPPORTED_SHELLS)[number];
-
-const GENERATORS: Record<SupportedShell, CompletionGenerator> = {
-  bash: generateBash,
-  zsh: generateZsh,
-  fish: generateFish,
-  powershell: generatePowershell,
-};
+export { SUPPORTED_SHELLS, type SupportedShell };
 
 const INSTALL_HINTS: Record<SupportedShell, readonly string[]> = {
   bash: [
@@ -39,10 +31,6 @@ const INSTALL_HINTS: Record<SupportedShell, readonly string[]> = {
   ],
 };
 
-function isSupportedShell(shell: string): shell is SupportedShell {
-  return (SUPPORTED_SHELLS as readonly string[]).includes(shell);
-}
-
 export function completion(shell?: string): void {
   if (!shell) {
     throwUsageError(
@@ -55,6 +43,7 @@ Examples:
   $ clerk completion bash              Output bash completion script
   $ clerk completion zsh               Output zsh completion script
   $ eval "$(clerk completion bash)"    Enable completions in current session
+  $ clerk completion install           Detect your shell and install permanently
 
 Run 'clerk completion --help' for full setup instructions.`,
     );
@@ -62,12 +51,12 @@ Run 'clerk completion --help' for full setup instructions.`,
   if (!isSupportedShell(shell)) {
     throwUsageError(`Unsupported shell: ${shell}. Supported: ${SUPPORTED_SHELLS.join(", ")}`);
   }
-  process.stdout.write(GENERATORS[shell]("clerk"));
+  process.stdout.write(generateCompletion(shell));
   printNextSteps(INSTALL_HINTS[shell]);
 }
 
 export function registerCompletion(program: Program): void {
-  program
+  const completionCmd = program
     .command("completion")
     .description("Generate shell autocompletion script")
     .addArgument(
@@ -83,12 +72,17 @@ export function registerCompletion(program: Program): void {
         command: "clerk completion powershell",
         description: "Output PowerShell completion script",
       },
+      { command: "clerk completion install", description: "Detect your shell and install" },
     ])
     .addHelpText(
       "after",
       `
 Tutorial — enable completions for your shell:
 
+  Any shell (detects it, writes the script, and updates your rc file):
+    $ clerk completion install
+    $ clerk completion install --dry-run                      # Preview the changes
+
   Bash:
     $ eval "$(clerk completion bash)"                          # Current session only
     $ clerk completion bash > /etc/bash_completion.d/clerk     # Permanent (Linux)
@@ -108,4 +102,22 @@ Tutorial — enable completions for your shell:
     $ clerk completion powershell >> $PROFILE                       # Permanent`,
     )
     .action(completion);
+
+  completionCmd
+    .command("install")
+    .description("Install completions for your shell and load them from its startup file")
+    .addOption(
+      createOption("--shell <shell>", "Shell to install for (default: detected)").choices(
+        SUPPORTED_SHELLS,
+      ),
+    )
+    .option("--dry-run", "Show the files that would change without writing them")
+    .setExamples([
+      { command: "clerk completion install", description: "Install for the detected shell" },
+      { command: "clerk completion install --shell zsh", description: "Install for zsh" },
+      { command: "clerk completion install --dry-run", description: "Preview the changes" },
+    ])
+    .action(async (options) => {
+      await installCompletion(options);
+    });
 }
@@ -0,0 +1,94 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, readFile, rm, writeFile } from "node:fs/promises";
+import { existsSync } from "node:fs";
+import { tmpdir } from "node:os";
+import { detectShell, installCompletion, upsertRcBlock } from "./install.ts";
+import { CliError } from "../../lib/errors.ts";
+
+describe("upsertRcBlock", () => {
+  test("appends a marked block after existing content", () => {
+    expect(upsertRcBlock("export A=1\n", ["source x"])).toBe(
+      "export A=1\n\n# >>> clerk completion >>>\nsource x\n# <<< clerk completion <<<\n",
+    );
+  });
+
+  test("replaces an existing block in place", () => {
+    const first = upsertRcBlock("before\n", ["old"]);
+    const second = upsertRcBlock(`${first}after\n`, ["new"]);
+    expect(second).toBe(
+      "before\n\n# >>> clerk completion >>>\nnew\n# <<< clerk completion <<<\nafter\n",
+    );
+    expect(upsertRcBlock(second, ["new"])).toBe(second);
+  });
+});
+
+describe("detectShell", () => {
+  test("prefers fish when FISH_VERSION is set", () => {
+    expect(detectShell({ FISH_VERSION: "3.7.0", SHELL: "/bin/zsh" })).toBe("fish");
+  });
+
+  test("reads the login shell from $SHELL", () => {
+    expect(detectShell({ SHELL: "/usr/local/bin/bash" })).toBe("bash");
+    expect(detectShell({ SHELL: "/usr/bin/pwsh" })).toBe("powershell");
+  });
+});
+
+describe("installCompletion", () => {
+  const originalEnv = { ...process.env };
+  let home: string;
+
+  beforeEach(async () => {
+    home = await mkdtemp(join(tmpdir(), "clerk-completion-test-"));
+    process.env.HOME = home;
+    delete process.env.ZDOTDIR;
+    delete process.env.XDG_CONFIG_HOME;
+    delete process.env.XDG_DATA_HOME;
+  });
+
+  afterEach(async () => {
+    process.env = { ...originalEnv };
+    await rm(home, { recursive: true, force: true });
+  });
+
+  test("writes the zsh script and an fpath block, then is a no-op on re-run", async () => {
+    await writeFile(join(home, ".zshrc"), "alias ll='ls -l'\n");
+
+    const first = await installCompletion({ shell: "zsh" });
+    expect(first.script).toEqual({ path: join(home, ".zfunc", "_clerk"), status: "created" });
+    expect(first.rc?.status).toBe("updated");
+
+    const zshrc = await readFile(join(home, ".zshrc"), "utf-8");
+    expect(zshrc).toStartWith("alias ll='ls -l'\n");
+    expect(zshrc).toContain(`fpath=("${join(home, ".zfunc")}" $fpath)`);
+    expect(await readFile(join(home, ".zfunc", "_clerk"), "utf-8")).toStartWith("#compdef clerk");
+
+    const second = await installCompletion({ shell: "zsh" });
+    expect(second.script.status).toBe("unchanged");
+    expect(second.rc?.status).toBe("unchanged");
+    expect(await readFile(join(home, ".zshrc"), "utf-8")).toBe(zshrc);
+  });
+
+  test("fish needs no rc file", async () => {
+    const result = await installCompletion({ shell: "fish" });
+    expect(result.script.path).toBe(join(home, ".config", "fish", "completions", "clerk.fish"));
+    expect(result.rc).toBeUndefined();
+  });
+
+  test("--dry-run reports changes without writing", async () => {
+    const result = await installCompletion({ shell: "bash", dryRun: true });
+    expect(result.script.status).toBe("created");
+    expect(result.rc).toEqual({ path: join(home, ".bashrc"), status: "created" });
+    expect(existsSync(result.script.path)).toBe(false);
+    expect(existsSync(join(home, ".bashrc"))).toBe(false);
+  });
+
+  test("asks for --shell when detection fails", async () => {
+    process.env.SHELL = "";
+    delete process.env.FISH_VERSION;
+    delete process.env.PSModulePath;
+    if (process.platform === "win32") return;
+    await expect(installCompletion()).rejects.toThrow(CliError);
+    await expect(installCompletion()).rejects.toThrow(/Pass it with --shell/);
+  });
+});
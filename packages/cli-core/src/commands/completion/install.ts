@@ -0,0 +1,230 @@
+/**
+ * `clerk completion install` — write the completion script for the user's
+ * shell and wire it into their startup file, so completions survive new
+ * sessions without hand-editing dotfiles.
+ *
+ * The shell comes from `--shell`, else `$FISH_VERSION`/`$SHELL`, else
+ * PowerShell on Windows. Re-running is safe: the script file is rewritten
+ * and the rc file keeps a single marked block, replaced in place.
+ */
+
+import { mkdir, readFile, writeFile } from "node:fs/promises";
+import { homedir, platform } from "node:os";
+import { dirname, join } from "node:path";
+import { cyan, dim } from "../../lib/color.ts";
+import { throwUsageError } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printNextSteps } from "../../lib/next-steps.ts";
+import {
+  generateCompletion,
+  isSupportedShell,
+  SUPPORTED_SHELLS,
+  type SupportedShell,
+} from "./shells/index.ts";
+
+export type CompletionInstallOptions = {
+  shell?: string;
+  dryRun?: boolean;
+};
+
+const BLOCK_START = "# >>> clerk completion >>>";
+const BLOCK_END = "# <<< clerk completion <<<";
+
+type InstallPlan = {
+  shell: SupportedShell;
+  /** Where the generated completion script is written. */
+  scriptPath: string;
+  /** Startup file that loads the script, or `undefined` when the shell auto-discovers it. */
+  rcPath?: string;
+  /** Lines placed between the markers in `rcPath`. */
+  rcLines?: string[];
+  /** Next step for picking up the change in an already-open shell. */
+  activate: string;
+};
+
+export type FileChange = "created" | "updated" | "unchanged";
+
+export type CompletionInstallResult = {
+  shell: SupportedShell;
+  script: { path: string; status: FileChange };
+  rc?: { path: string; status: FileChange };
+};
+
+/**
+ * The shell to install for, or `undefined` when nothing identifies one.
+ * `FISH_VERSION` wins over `$SHELL` because fish is often started from a
+ * bash or zsh login shell.
+ */
+export function detectShell(env: NodeJS.ProcessEnv = process.env): SupportedShell | undefined {
+  if (env.FISH_VERSION) return "fish";
+  const name = env.SHELL?.split(/[\\/]/).pop()?.replace(/\.exe$/i, "");
+  if (name === "pwsh") return "powershell";
+  if (name && isSupportedShell(name)) return name;
+  if (platform() === "win32" || env.PSModulePath) return "powershell";
+  return undefined;
+}
+
+function resolveShell(requested: string | undefined): SupportedShell {
+  if (requested) {
+    if (!isSupportedShell(requested)) {
+      throwUsageError(`Unsupported shell: ${requested}. Supported: ${SUPPORTED_SHELLS.join(", ")}`);
+    }
+    return requested;
+  }
+  const detected = detectShell();
+  if (!detected) {
+    throwUsageError(
+      `Could not detect your shell. Pass it with --shell (${SUPPORTED_SHELLS.join(", ")}).`,
+    );
+  }
+  return detected;
+}
+
+function homeDir(): string {
+  return process.env.HOME || homedir();
+}
+
+function xdgDir(variable: "XDG_CONFIG_HOME" | "XDG_DATA_HOME", fallback: string): string {
+  return process.env[variable]?.trim() || join(homeDir(), fallback);
+}
+
+function quote(path: string): string {
+  return `"${path.replace(/(["\\$`])/g, "\\$1")}"`;
+}
+
+export function planInstall(shell: SupportedShell): InstallPlan {
+  const home = homeDir();
+  switch (shell) {
+    case "bash": {
+      // bash-completion's per-user directory, so it lazy-loads where that
+      // package is installed; the rc block covers shells without it.
+      const scriptPath = join(
+        xdgDir("XDG_DATA_HOME", ".local/share"),
+        "bash-completion",
+        "completions",
+        "clerk",
+      );
+      return {
+        shell,
+        scriptPath,
+        rcPath: join(home, ".bashrc"),
+        rcLines: [`[ -f ${quote(scriptPath)} ] && . ${quote(scriptPath)}`],
+        activate: "Run `source ~/.bashrc` to enable completions in this shell",
+      };
+    }
+    case "zsh": {
+      const zdotdir = process.env.ZDOTDIR?.trim() || home;
+      const scriptPath = join(home, ".zfunc", "_clerk");
+      return {
+        shell,
+        scriptPath,
+        rcPath: join(zdotdir, ".zshrc"),
+        rcLines: [
+          `fpath=(${quote(dirname(scriptPath))} $fpath)`,
+          "autoload -Uz compinit && compinit",
+        ],
+        activate: "Run `exec zsh` to enable completions in this shell",
+      };
+    }
+    case "fish":
+      // Fish auto-discovers files in its completions directory.
+      return {
+        shell,
+        scriptPath: join(
+          xdgDir("XDG_CONFIG_HOME", ".config"),
+          "fish",
+          "completions",
+          "clerk.fish",
+        ),
+        activate: "Open a new fish session to enable completions",
+      };
+    case "powershell": {
+      const profileDir =
+        platform() === "win32"
+          ? join(home, "Documents", "PowerShell")
+          : join(xdgDir("XDG_CONFIG_HOME", ".config"), "powershell");
+      const scriptPath = join(profileDir, "clerk-completion.ps1");
+      return {
+        shell,
+        scriptPath,
+        rcPath: join(profileDir, "Microsoft.PowerShell_profile.ps1"),
+        rcLines: [`. '${scriptPath.replace(/'/g, "''")}'`],
+        activate: "Run `. $PROFILE` to enable completions in this session",
+      };
+    }
+  }
+}
+
+async function readOptional(path: string): Promise<string | undefined> {
+  try {
+    return await readFile(path, "utf-8");
+  } catch (error) {
+    if ((error as NodeJS.ErrnoException).code === "ENOENT") return undefined;
+    throw error;
+  }
+}
+
+/**
+ * Put `lines` between the clerk markers in `content`: replace an existing
+ * block in place, or append one. Everything outside the markers is kept.
+ */
+export function upsertRcBlock(content: string, lines: string[]): string {
+  const block = [BLOCK_START, ...lines, BLOCK_END].join("\n");
+  const start = content.indexOf(BLOCK_START);
+  const end = start === -1 ? -1 : content.indexOf(BLOCK_END, start);
+  if (start !== -1 && end !== -1) {
+    return content.slice(0, start) + block + content.slice(end + BLOCK_END.length);
+  }
+  if (!content) return `${block}\n`;
+  const separator = content.endsWith("\n") ? "\n" : "\n\n";
+  return `${content}${separator}${block}\n`;
+}
+
+async function syncFile(path: string, next: string, dryRun: boolean): Promise<FileChange> {
+  const current = await readOptional(path);
+  if (current === next) return "unchanged";
+  if (!dryRun) {
+    await mkdir(dirname(path), { recursive: true });
+    await writeFile(path, next);
+  }
+  return current === undefined ? "created" : "updated";
+}
+
+function describeChange(path: string, status: FileChange, dryRun: boolean): string {
+  if (status === "unchanged") return `${dim(path)} is up to date`;
+  if (dryRun) return `Would ${status === "created" ? "create" : "update"} ${cyan(path)}`;
+  return `${status === "created" ? "Created" : "Updated"} ${cyan(path)}`;
+}
+
+export async function installCompletion(
+  options: CompletionInstallOptions = {},
+): Promise<CompletionInstallResult> {
+  const dryRun = Boolean(options.dryRun);
+  const plan = planInstall(resolveShell(options.shell));
+
+  const result: CompletionInstallResult = {
+    shell: plan.shell,
+    script: {
+      path: plan.scriptPath,
+      status: await syncFile(plan.scriptPath, generateCompletion(plan.shell), dryRun),
+    },
+  };
+  if (plan.rcPath && plan.rcLines) {
+    const current = (await readOptional(plan.rcPath)) ?? "";
+    result.rc = {
+      path: plan.rcPath,
+      status: await syncFile(plan.rcPath, upsertRcBlock(current, plan.rcLines), dryRun),
+    };
+  }
+
+  log.info(`Installing ${plan.shell} completions${dryRun ? dim(" (dry run)") : ""}`);
+  log.info(`  ${describeChange(result.script.path, result.script.status, dryRun)}`);
+  if (result.rc) {
+    log.info(`  ${describeChange(result.rc.path, result.rc.status, dryRun)}`);
+    if (dryRun && result.rc.status !== "unchanged") {
+      for (const line of [BLOCK_START, ...plan.rcLines!, BLOCK_END]) log.info(`    ${dim(line)}`);
+    }
+  }
+  if (!dryRun) printNextSteps([plan.activate]);
+  return result;
+}
@@ -0,0 +1,26 @@
+import { generate as generateBash } from "./bash.ts";
+import { generate as generateZsh } from "./zsh.ts";
+import { generate as generateFish } from "./fish.ts";
+import { generate as generatePowershell } from "./powershell.ts";
+
+type CompletionGenerator = (binaryName: string) => string;
+
+export const SUPPORTED_SHELLS = ["bash", "zsh", "fish", "powershell"] as const;
+
+export type SupportedShell = (typeof SUPPORTED_SHELLS)[number];
+
+const GENERATORS: Record<SupportedShell, CompletionGenerator> = {
+  bash: generateBash,
+  zsh: generateZsh,
+  fish: generateFish,
+  powershell: generatePowershell,
+};
+
+export function isSupportedShell(shell: string): shell is SupportedShell {
+  return (SUPPORTED_SHELLS as readonly string[]).includes(shell);
+}
+
+/** The completion script for `shell`, registered for the `clerk` binary. */
+export function generateCompletion(shell: SupportedShell): string {
+  return GENERATORS[shell]("clerk");
+}
@@ -1,5 +1,5 @@
 import { fetchInstanceConfig, patchInstanceConfig } from "../../lib/plapi.ts";
-import { throwUserAbort, withApiContext } from "../../lib/errors.ts";
+import { errorMessage, throwUserAbort, withApiContext } from "../../lib/errors.ts";
 import { withSpinner } from "../../lib/spinner.ts";
 import { confirm } from "../../lib/prompts.ts";
 import { isHuman } from "../../mode.ts";
@@ -17,6 +17,33 @@ export interface ApplyPatchOptions {
   warning?: string;
   /** Pre-fetched current config; skips the extra GET when caller already has it. */
   currentConfig?: Record<string, unknown>;
+  /**
+   * Describes what else the change affects (e.g. "12 organizations"). Printed
+   * after the diff and before the confirm prompt; skipped entirely with --yes.
+   * Failures are logged at debug level and never block the change.
+   */
+  impact?: () => Promise<string[]>;
+}
+
+/**
+ * Print the blast-radius summary for a destructive change. Best effort: the
+ * summary informs the confirm prompt but is never a reason to fail.
+ */
+export async function printImpact(impact: () => Promise<string[]>): Promise<void> {
+  let lines: string[];
+  try {
+    lines = await withSpinner("Checking what this affects...", impact);
+  } catch (error) {
+    log.debug(`config: impact summary unavailable: ${errorMessage(error)}`);
+    return;
+  }
+  if (lines.length === 0) return;
+
+  log.info("\nThis affects:");
+  for (const line of lines) {
+    log.info(`  • ${line}`);
+  }
+  log.blank();
 }
 
 /** Fetch + diff + confirm + PATCH, matching `clerk config patch` semantics. */
@@ -44,6 +71,10 @@ export async function applyConfigPatch(opts: ApplyPatchOptions): Promise<boolean
   // prompt — the warning is an audit signal, not a confirmation cue.
   if (warning) log.warn(warning);
 
+  if (!yes && opts.impact) {
+    await printImpact(opts.impact);
+  }
+
   if (!dryRun && isHuman() && !yes) {
     const ok = await confirm({ message: "Proceed?" });
     if (!ok) throwUserAbort();
@@ -2,6 +2,7 @@ import type { Program } from "../../cli-program.ts";
 import { configPull } from "./pull.ts";
 import { configSchema } from "./schema.ts";
 import { configPatch, configPut } from "./push.ts";
+import { configSources, SOURCE_KEYS } from "./sources.ts";
 
 export function registerConfig(program: Program): void {
   const config = program
@@ -36,6 +37,10 @@ export function registerConfig(program: Program): void {
         command: "clerk config put --instance prod --file config.json",
         description: "Replace production config",
       },
+      {
+        command: "clerk config sources secret-key",
+        description: "Show where the CLI gets its secret key",
+      },
     ]);
 
   config
@@ -140,4 +145,23 @@ export function registerConfig(program: Program): void {
       },
     ])
     .action(configPut);
+
+  config
+    .command("sources")
+    .description("Show every place a key can come from and which one the CLI uses")
+    .argument("<key>", `Key to inspect (${SOURCE_KEYS.join(", ")})`)
+    .option("--secret-key <key>", "Include a --secret-key value in the resolution")
+    .option("--app <id>", "Include an --app value in the resolution")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk config sources secret-key",
+        description: "Show where the CLI gets its secret key",
+      },
+      { command: "clerk config sources app", description: "Show which application is targeted" },
+      { command: "clerk config sources http.retries --json", description: "Output as JSON" },
+    ])
+    .action((key, _opts, cmd) =>
+      configSources(key, cmd.optsWithGlobals() as Parameters<typeof configSources>[1]),
+    );
 }
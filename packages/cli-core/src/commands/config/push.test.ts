@@ -10,7 +10,12 @@ import {
   libPromptsStubs,
   stubFetch,
 } from "../../test/lib/stubs.ts";
-import { printDiff, hasConfigChanges } from "./push.ts";
+import {
+  printDiff,
+  hasConfigChanges,
+  summarizeConfigChanges,
+  formatChangeSummary,
+} from "./push.ts";
 
 mock.module("../../lib/credential-store.ts", () => credentialStoreStubs);
 mock.module("../../lib/git.ts", () => gitStubs);
@@ -791,3 +796,32 @@ describe("hasConfigChanges", () => {
     expect(hasConfigChanges(current, payload, false)).toBe(false);
   });
 });
+
+describe("summarizeConfigChanges", () => {
+  test("put mode: counts changed, added, and removed leaves per section", () => {
+    const current = {
+      session: { lifetime: 604800, cookie: "__session" },
+      sign_up: { mode: "public" },
+    };
+    const payload = { session: { lifetime: 3600, cookie: "__session", claims: {} } };
+
+    const summary = summarizeConfigChanges(current, payload, false);
+
+    expect(summary).toEqual({ sections: 2, added: 1, changed: 1, removed: 1 });
+    expect(formatChangeSummary(summary)).toBe(
+      "Summary: 1 changed, 1 added, 1 removed across 2 sections",
+    );
+  });
+
+  test("patch mode: ignores keys absent from the payload", () => {
+    const current = { session: { lifetime: 604800 }, sign_up: { mode: "public" } };
+    const payload = { session: { lifetime: 3600 } };
+
+    expect(summarizeConfigChanges(current, payload, true)).toEqual({
+      sections: 1,
+      added: 0,
+      changed: 1,
+      removed: 0,
+    });
+  });
+});
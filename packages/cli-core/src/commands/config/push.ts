@@ -14,6 +14,8 @@ import { dim, bold, red, green } from "../../lib/color.ts";
 import { withSpinner, intro, outro, pausedOutro } from "../../lib/spinner.ts";
 import { isInsideGutter, log } from "../../lib/log.ts";
 import { NEXT_STEPS, printNextSteps } from "../../lib/next-steps.ts";
+import { parseJsonInput } from "../../lib/json-parse.ts";
+import { isRecord } from "../../lib/objects.ts";
 
 interface ConfigPushOptions {
   app?: string;
@@ -62,24 +64,16 @@ export async function configPatch(options: ConfigPushOptions): Promise<void> {
 }
 
 async function configPush(options: ConfigPushOptions, op: Operation): Promise<void> {
-  const ctx = await resolveAppContext(options);
+  // Validate input before any API call so a malformed file never reaches the backend.
   const rawInput = await readInput(options);
+  const configPayload = parseJsonInput(rawInput, inputSource(options));
 
-  let configPayload: Record<string, unknown>;
-  try {
-    configPayload = JSON.parse(rawInput);
-  } catch {
-    throwUsageError(
-      "Invalid JSON input. Please provide valid JSON.",
-      undefined,
-      ERROR_CODE.INVALID_JSON,
-    );
-  }
-
-  if (typeof configPayload !== "object" || configPayload === null || Array.isArray(configPayload)) {
+  if (!isRecord(configPayload)) {
     throwUsageError("Config must be a JSON object.", undefined, ERROR_CODE.INVALID_JSON);
   }
 
+  const ctx = await resolveAppContext(options);
+
   // Strip config_version — it's returned by pull but not accepted by the backend
   delete configPayload.config_version;
 
@@ -108,6 +102,11 @@ async function configPush(options: ConfigPushOptions, op: Operation): Promise<vo
     log.info(`\n${prefix} config on ${ctx.appLabel} (${ctx.instanceLabel}):\n`);
     printDiff(currentConfig, configPayload, isPatch);
 
+    if (!options.yes) {
+      const summary = summarizeConfigChanges(currentConfig, configPayload, isPatch);
+      log.info(`\n${formatChangeSummary(summary)}`);
+    }
+
     if (!options.dryRun && isHuman() && !options.yes) {
       if (op.warning) {
         log.warn(`${op.warning}`);
@@ -160,6 +159,11 @@ async function configPush(options: ConfigPushOptions, op: Operation): Promise<vo
   }
 }
 
+function inputSource(options: { file?: string; json?: string }): string {
+  if (options.json) return "--json";
+  return options.file ?? "stdin";
+}
+
 export async function readInput(options: { file?: string; json?: string }): Promise<string> {
   if (options.json) {
     return options.json;
@@ -271,6 +275,47 @@ export function hasConfigChanges(
   return false;
 }
 
+export interface ConfigChangeSummary {
+  sections: number;
+  added: number;
+  changed: number;
+  removed: number;
+}
+
+/** Count leaf-level changes, so the confirm prompt can state the blast radius. */
+export function summarizeConfigChanges(
+  current: Record<string, unknown>,
+  payload: Record<string, unknown>,
+  patchMode: boolean,
+): ConfigChangeSummary {
+  const summary: ConfigChangeSummary = { sections: 0, added: 0, changed: 0, removed: 0 };
+  for (const key of topLevelKeys(current, payload, patchMode)) {
+    const changes: Change[] = [];
+    collectChanges(current[key], payload[key], "", changes, patchMode);
+    if (changes.length === 0) continue;
+    summary.sections++;
+    for (const { oldVal, newVal } of changes) {
+      if (oldVal === undefined) summary.added++;
+      else if (newVal === undefined) summary.removed++;
+      else summary.changed++;
+    }
+  }
+  return summary;
+}
+
+function plural(count: number, noun: string): string {
+  return `${count} ${noun}${count === 1 ? "" : "s"}`;
+}
+
+export function formatChangeSummary(summary: ConfigChangeSummary): string {
+  const parts = [
+    summary.changed > 0 && `${summary.changed} changed`,
+    summary.added > 0 && `${summary.added} added`,
+    summary.removed > 0 && `${summary.removed} removed`,
+  ].filter(Boolean);
+  return `Summary: ${parts.join(", ")} across ${plural(summary.sections, "section")}`;
+}
+
 /**
  * Prints a diff showing only leaf values that actually changed,
  * grouped by top-level config key.
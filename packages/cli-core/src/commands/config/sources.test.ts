@@ -0,0 +1,117 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockResolveProfile = mock();
+const mockGetDefaultContext = mock();
+mock.module("../../lib/config.ts", () => ({
+  resolveProfile: (...args: unknown[]) => mockResolveProfile(...args),
+  getDefaultContext: (...args: unknown[]) => mockGetDefaultContext(...args),
+}));
+
+const settings: Record<string, string> = {};
+mock.module("../../lib/settings.ts", () => ({
+  getSetting: async (key: string) => settings[key],
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { configSources, rankSources } = await import("./sources.ts");
+
+type Output = {
+  key: string;
+  value: string | null;
+  source: string | null;
+  sources: { source: string; value: string | null; status: string }[];
+};
+
+describe("rankSources", () => {
+  test("picks the first set source and marks later ones as overridden", () => {
+    const ranked = rankSources([
+      { source: "a", value: undefined },
+      { source: "b", value: ".env", ignored: true },
+      { source: "c", value: "1" },
+      { source: "d", value: "2" },
+    ]);
+    expect(ranked.map((entry) => entry.status)).toEqual(["unset", "ignored", "active", "shadowed"]);
+  });
+});
+
+describe("config sources", () => {
+  const captured = useCaptureLog();
+  const originalCwd = process.cwd();
+  const originalSecretKey = process.env.CLERK_SECRET_KEY;
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-sources-"));
+    process.chdir(tempDir);
+    delete process.env.CLERK_SECRET_KEY;
+    mockIsAgent.mockReturnValue(false);
+    mockResolveProfile.mockResolvedValue({
+      path: "github.com/acme/web",
+      profile: { appId: "app_linked" },
+      resolvedVia: "remote",
+    });
+    mockGetDefaultContext.mockResolvedValue(undefined);
+  });
+
+  afterEach(async () => {
+    process.chdir(originalCwd);
+    await rm(tempDir, { recursive: true, force: true });
+    if (originalSecretKey === undefined) delete process.env.CLERK_SECRET_KEY;
+    else process.env.CLERK_SECRET_KEY = originalSecretKey;
+    for (const key of Object.keys(settings)) delete settings[key];
+    mockResolveProfile.mockReset();
+    mockGetDefaultContext.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("CLERK_SECRET_KEY beats the linked project, and .env files are never used", async () => {
+    process.env.CLERK_SECRET_KEY = "sk_test_fromenvironment";
+    await writeFile(join(tempDir, ".env.local"), "CLERK_SECRET_KEY=sk_test_fromdotenvfile\n");
+
+    await configSources("secret-key", { json: true });
+
+    const output = JSON.parse(captured.out) as Output;
+    expect(output.source).toBe("CLERK_SECRET_KEY");
+    const byName = Object.fromEntries(output.sources.map((entry) => [entry.source, entry.status]));
+    expect(byName).toMatchObject({
+      "--secret-key": "unset",
+      CLERK_SECRET_KEY: "active",
+      "linked project": "shadowed",
+      ".env.local": "ignored",
+    });
+  });
+
+  test("an --app flag wins over the linked project", async () => {
+    await configSources("app", { app: "app_flag" });
+
+    expect(captured.out).toContain("app_flag");
+    expect(captured.out).toContain("(overridden)");
+    expect(captured.err).toContain("Using app from --app.");
+  });
+
+  test("falls back to defaults for settings", async () => {
+    settings["http.max-delay"] = "30";
+
+    await configSources("http.retries", { json: true });
+    expect(JSON.parse(captured.out)).toMatchObject({ value: "2", source: "default" });
+
+    captured.clear();
+    await configSources("http.max-delay", { json: true });
+    expect(JSON.parse(captured.out)).toMatchObject({ value: "30", source: "clerk settings" });
+  });
+
+  test("rejects unknown keys", async () => {
+    await expect(configSources("nope")).rejects.toThrow('Unknown key "nope"');
+  });
+});
@@ -0,0 +1,169 @@
+import { join } from "node:path";
+import { bold, dim, green } from "../../lib/color.ts";
+import { getDefaultContext, resolveProfile } from "../../lib/config.ts";
+import { ENV_FILE_CANDIDATES, parseEnvFile } from "../../lib/dotenv.ts";
+import { getCurrentEnv, getCurrentEnvName } from "../../lib/environment.ts";
+import { throwUsageError } from "../../lib/errors.ts";
+import { DEFAULT_MAX_DELAY_MS, DEFAULT_RETRIES } from "../../lib/http-retry.ts";
+import { log } from "../../lib/log.ts";
+import { DEFAULT_PAGER } from "../../lib/pager.ts";
+import { getSetting } from "../../lib/settings.ts";
+import { isAgent } from "../../mode.ts";
+
+export type SourcesOptions = {
+  secretKey?: string;
+  app?: string;
+  readOnly?: boolean;
+  json?: boolean;
+};
+
+/**
+ * - `active`: the value the CLI uses.
+ * - `shadowed`: set, but a higher-priority source wins.
+ * - `unset`: nothing there.
+ * - `ignored`: set somewhere the CLI never reads (e.g. a `.env` file).
+ */
+export type SourceStatus = "active" | "shadowed" | "unset" | "ignored";
+
+export type KeySource = {
+  source: string;
+  value: string | null;
+  status: SourceStatus;
+  note?: string;
+};
+
+/** A candidate before statuses are assigned. `ignored` candidates never win. */
+type Candidate = { source: string; value: string | undefined; note?: string; ignored?: boolean };
+
+type Resolver = (options: SourcesOptions, cwd: string) => Promise<Candidate[]>;
+
+async function envFileCandidates(cwd: string, variable: string): Promise<Candidate[]> {
+  const candidates: Candidate[] = [];
+  for (const name of ENV_FILE_CANDIDATES) {
+    const file = Bun.file(join(cwd, name));
+    if (!(await file.exists())) continue;
+    const entry = parseEnvFile(await file.text()).find(
+      (line) => line.type === "entry" && line.key === variable,
+    );
+    candidates.push({
+      source: name,
+      value: entry?.type === "entry" ? entry.value : undefined,
+      note: "read by your app, not by the CLI",
+      ignored: true,
+    });
+  }
+  return candidates;
+}
+
+async function linkedAppCandidates(cwd: string): Promise<Candidate[]> {
+  const [resolved, context] = await Promise.all([resolveProfile(cwd), getDefaultContext()]);
+  return [
+    {
+      source: "linked project",
+      value: resolved?.profile.appId,
+      note: resolved ? `profile ${resolved.path}` : "run `clerk link`",
+    },
+    { source: "`clerk use` default", value: context?.app },
+  ];
+}
+
+const RESOLVERS: Record<string, Resolver> = {
+  "secret-key": async (options, cwd) => [
+    { source: "--secret-key", value: options.secretKey },
+    { source: "--app", value: options.app, note: "key fetched from the Platform API" },
+    { source: "CLERK_SECRET_KEY", value: process.env.CLERK_SECRET_KEY },
+    ...(await linkedAppCandidates(cwd)).map((candidate) => ({
+      ...candidate,
+      note: "key fetched from the Platform API",
+    })),
+    ...(await envFileCandidates(cwd, "CLERK_SECRET_KEY")),
+  ],
+  app: async (options, cwd) => [
+    { source: "--app", value: options.app },
+    ...(await linkedAppCandidates(cwd)),
+  ],
+  "platform-api-url": async () => [
+    { source: "CLERK_PLATFORM_API_URL", value: process.env.CLERK_PLATFORM_API_URL },
+    { source: `${getCurrentEnvName()} environment`, value: getCurrentEnv().platformApiUrl },
+  ],
+  "backend-api-url": async () => [
+    { source: "CLERK_BACKEND_API_URL", value: process.env.CLERK_BACKEND_API_URL },
+    { source: `${getCurrentEnvName()} environment`, value: getCurrentEnv().backendApiUrl },
+  ],
+  "core.read-only": async (options) => [
+    { source: "--read-only", value: options.readOnly ? "true" : undefined },
+    { source: "CLERK_READ_ONLY", value: process.env.CLERK_READ_ONLY || undefined },
+    { source: "clerk settings", value: await getSetting("core.read-only") },
+    { source: "default", value: "false" },
+  ],
+  "core.pager": async () => [
+    { source: "CLERK_PAGER", value: process.env.CLERK_PAGER },
+    { source: "clerk settings", value: await getSetting("core.pager") },
+    { source: "PAGER", value: process.env.PAGER },
+    { source: "default", value: DEFAULT_PAGER },
+  ],
+  "http.retries": async () => [
+    { source: "clerk settings", value: await getSetting("http.retries") },
+    { source: "default", value: String(DEFAULT_RETRIES) },
+  ],
+  "http.max-delay": async () => [
+    { source: "clerk settings", value: await getSetting("http.max-delay") },
+    { source: "default", value: String(DEFAULT_MAX_DELAY_MS / 1000) },
+  ],
+};
+
+export const SOURCE_KEYS = Object.keys(RESOLVERS);
+
+/** Mark the first set, non-ignored candidate as the winner. */
+export function rankSources(candidates: Candidate[]): KeySource[] {
+  let found = false;
+  return candidates.map(({ source, value, note, ignored }) => {
+    let status: SourceStatus;
+    if (value === undefined) status = "unset";
+    else if (ignored) status = "ignored";
+    else if (found) status = "shadowed";
+    else {
+      status = "active";
+      found = true;
+    }
+    return { source, value: value ?? null, status, ...(note ? { note } : {}) };
+  });
+}
+
+function formatSource(entry: KeySource, width: number): string {
+  const marker = entry.status === "active" ? green("→") : " ";
+  const source = entry.source.padEnd(width);
+  const value = entry.value ?? dim("(not set)");
+  const note = entry.note ? `  ${dim(entry.note)}` : "";
+  switch (entry.status) {
+    case "active":
+      return `${marker} ${bold(source)}  ${value}${note}`;
+    case "shadowed":
+      return `${marker} ${source}  ${value}  ${dim("(overridden)")}${note}`;
+    default:
+      return `${marker} ${dim(source)}  ${value}${note}`;
+  }
+}
+
+export async function configSources(key: string, options: SourcesOptions = {}): Promise<void> {
+  const resolver = RESOLVERS[key];
+  if (!resolver) {
+    throwUsageError(`Unknown key "${key}". Known keys: ${SOURCE_KEYS.join(", ")}`);
+  }
+
+  const sources = rankSources(await resolver(options, process.cwd()));
+  const winner = sources.find((entry) => entry.status === "active");
+
+  if (options.json || isAgent()) {
+    const result = { key, value: winner?.value ?? null, source: winner?.source ?? null, sources };
+    log.data(JSON.stringify(result, null, 2));
+    return;
+  }
+
+  const width = Math.max(...sources.map((entry) => entry.source.length));
+  log.info(dim(`Sources for ${key}, highest priority first:`));
+  for (const entry of sources) log.data(formatSource(entry, width));
+  log.blank();
+  if (winner) log.info(`Using ${key} from ${winner.source}.`);
+  else log.info(`${key} is not set anywhere the CLI looks.`);
+}
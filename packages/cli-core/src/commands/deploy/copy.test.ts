@@ -108,6 +108,13 @@ describe("deployStatusRetryMessage", () => {
       "Verifying DNS records for example.com... 2/5 attempts, retrying in 30s",
     );
   });
+
+  test("omits the total when polling until a timeout", () => {
+    const message = "Verifying DNS records for example.com...";
+    expect(deployStatusRetryMessage(message, 7, undefined, 60)).toBe(
+      `${message} 7 attempts, retrying in 60s`,
+    );
+  });
 });
 
 describe("nextStepsBlock", () => {
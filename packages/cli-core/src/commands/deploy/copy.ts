@@ -102,7 +102,7 @@ export function cnameTargetPending(target: CnameTarget, status: DeployComponentS
   return !status.dns;
 }
 
-function isMailCnameTarget(target: CnameTarget): boolean {
+export function isMailCnameTarget(target: CnameTarget): boolean {
   const prefix = target.host.split(".", 1)[0];
   return prefix === "clkmail" || prefix === "clk" || prefix === "clk2";
 }
@@ -177,13 +177,15 @@ export function deployComponentStatus(status: DeployComponentStatus): string {
   return `DNS: ${mark(status.dns)}  SSL: ${mark(status.ssl)}  Email DNS: ${mark(status.mail)}`;
 }
 
+/** Spinner text between checks. Without `totalRetries`, polling runs until a deadline. */
 export function deployStatusRetryMessage(
   message: string,
   currentRetry: number,
-  totalRetries: number,
+  totalRetries: number | undefined,
   seconds: number,
 ): string {
-  return `${message} ${currentRetry}/${totalRetries} attempts, retrying in ${seconds}s`;
+  const attempts = totalRetries === undefined ? currentRetry : `${currentRetry}/${totalRetries}`;
+  return `${message} ${attempts} attempts, retrying in ${seconds}s`;
 }
 
 /**
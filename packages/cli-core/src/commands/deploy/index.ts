@@ -1,5 +1,5 @@
 import type { Program } from "../../cli-program.ts";
-import { deployStatus } from "./status-command.ts";
+import { DEFAULT_WAIT_TIMEOUT_SECONDS, deployStatus } from "./status-command.ts";
 import { isAgent } from "../../mode.ts";
 import { applyPrefix, isInsideGutter, log } from "../../lib/log.ts";
 import { bold, dim } from "../../lib/color.ts";
@@ -13,6 +13,7 @@ import {
   throwUsageError,
 } from "../../lib/errors.ts";
 import { setProfile } from "../../lib/config.ts";
+import { parseIntegerOption } from "../../lib/option-parsers.ts";
 import {
   createProductionInstance as apiCreateProductionInstance,
   patchInstanceConfig,
@@ -646,6 +647,23 @@ export function registerDeploy(program: Program): void {
   deployCmd
     .command("status")
     .description("Show production deploy status (read-only)")
-    .option("--wait", "Wait for DNS, SSL, and email DNS verification with retries")
-    .action(deployStatus);
+    .option("--wait", "Poll until DNS, SSL, and email DNS are verified, or until --timeout")
+    .option(
+      "--timeout <seconds>",
+      `With --wait, seconds to keep polling (default ${DEFAULT_WAIT_TIMEOUT_SECONDS})`,
+      (value) => parseIntegerOption(value, "--timeout", { min: 1 }),
+    )
+    .option("--app <id>", "Application ID to check instead of the linked project")
+    .option("--json", "Output the status report as JSON")
+    .setExamples([
+      {
+        command: "clerk deploy status --app app_123 --wait --json",
+        description: "Gate a pipeline on DNS, SSL, and email DNS verification",
+      },
+      {
+        command: "clerk deploy status --wait --timeout 1800",
+        description: "Wait up to 30 minutes for the certificate to be issued",
+      },
+    ])
+    .action((options) => deployStatus(options));
 }
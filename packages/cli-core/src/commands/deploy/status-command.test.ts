@@ -182,6 +182,17 @@ describe("deploy status", () => {
     expect(payload.domainStatus).toEqual({ dns: "complete", ssl: "complete", mail: "complete" });
   });
 
+  test("--app skips the linked profile and --json emits JSON in human mode", async () => {
+    setMode("human");
+    mockFetchApplication.mockResolvedValue(appWith(false));
+
+    await deployStatus({ app: "app_1", json: true });
+
+    expect(mockFetchApplication).toHaveBeenCalledTimes(1);
+    expect(JSON.parse(captured.out)).toMatchObject({ state: "not_started", complete: false });
+    expect(process.exitCode).toBe(EXIT_CODE.GENERAL);
+  });
+
   test("human mode not_started prints a readable status block and no JSON stdout", async () => {
     setMode("human");
     mockFetchApplication.mockResolvedValue(appWith(false));
@@ -218,6 +229,50 @@ describe("deploy status", () => {
     });
   });
 
+  test("--timeout without --wait is a usage error", async () => {
+    await expect(deployStatus({ timeout: 60 })).rejects.toThrow("--timeout applies to --wait");
+    expect(mockFetchApplication).not.toHaveBeenCalled();
+  });
+
+  test("--wait polls past the default retries until the domain is ready", async () => {
+    mockFetchApplication.mockResolvedValue(appWith(true));
+    mockDomain();
+    mockOAuthComplete();
+    mockTriggerApplicationDomainDNSCheck.mockResolvedValue(pendingSslDomainStatus());
+    let polls = 0;
+    mockGetApplicationDomainStatus.mockImplementation(() =>
+      ++polls > 8 ? completeDomainStatus() : pendingSslDomainStatus(),
+    );
+
+    await deployStatus({ wait: true });
+
+    expect(process.exitCode).toBe(EXIT_CODE.SUCCESS);
+    const payload = JSON.parse(captured.out);
+    expect(payload.state).toBe("complete");
+    expect(payload.domainChecks.ssl).toEqual({ status: "complete", required: true });
+    // One status snapshot, then the initial check and seven retries.
+    expect(mockGetApplicationDomainStatus).toHaveBeenCalledTimes(9);
+  });
+
+  test("--wait --timeout gives up with the last check state once the time is spent", async () => {
+    mockFetchApplication.mockResolvedValue(appWith(true));
+    mockDomain();
+    mockOAuthComplete();
+    mockTriggerApplicationDomainDNSCheck.mockResolvedValue(pendingSslDomainStatus());
+    mockGetApplicationDomainStatus.mockResolvedValue(pendingSslDomainStatus());
+
+    await deployStatus({ wait: true, timeout: 10 });
+
+    expect(process.exitCode).toBe(EXIT_CODE.GENERAL);
+    // The 2s preflight delay, then 3s + 6s of backoff and the 1s left of the timeout.
+    const sleptMs = mockSleep.mock.calls.reduce((total, [ms]) => total + ms, 0);
+    expect(sleptMs).toBe(12_000);
+    expect(mockGetApplicationDomainStatus).toHaveBeenCalledTimes(5);
+    const payload = JSON.parse(captured.out);
+    expect(payload.state).toBe("domain_pending");
+    expect(payload.domainChecks.ssl).toEqual({ status: "pending", required: true });
+  });
+
   test("agent mode status snapshot failures surface as errors", async () => {
     mockFetchApplication.mockResolvedValue(appWith(true));
     mockDomain();
@@ -260,6 +315,7 @@ describe("deploy status", () => {
     expect(output).toContain(
       "SSL still provisioning for example.com. Re-run `clerk deploy status` in a few minutes, DNS propagation can take time. Visit the Clerk Dashboard domains page to monitor its status there: https://dashboard.clerk.com/apps/app_1/instances/ins_prod/domains",
     );
+    expect(output).toContain("Domain   DNS: complete  SSL: pending  Email DNS: complete");
     expect(output).not.toContain("Ask the user to visit");
     expect(output).not.toContain("offer to open it");
   });
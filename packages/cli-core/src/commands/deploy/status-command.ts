@@ -1,5 +1,5 @@
 import { isAgent } from "../../mode.ts";
-import { CliError, ERROR_CODE, EXIT_CODE } from "../../lib/errors.ts";
+import { CliError, ERROR_CODE, EXIT_CODE, throwUsageError } from "../../lib/errors.ts";
 import { log } from "../../lib/log.ts";
 import { sleep } from "../../lib/sleep.ts";
 import { withSpinner } from "../../lib/spinner.ts";
@@ -14,17 +14,26 @@ import {
   type DeployState,
   type DeployStatusOutcome,
   type DeployStatusReport,
+  type DomainCheckState,
 } from "./status.ts";
 import type { DeployContext } from "./state.ts";
 
 type DeployStatusOptions = {
   wait?: boolean;
+  /** Seconds `--wait` keeps polling before reporting the deploy as incomplete. */
+  timeout?: number;
+  app?: string;
+  json?: boolean;
 };
 
 const DEPLOY_STATUS_PREFLIGHT_DELAY_MS = 2000;
+export const DEFAULT_WAIT_TIMEOUT_SECONDS = 600;
 
 export async function deployStatus(options: DeployStatusOptions = {}): Promise<void> {
-  const ctx = await resolveDeployContext();
+  if (options.timeout !== undefined && !options.wait) {
+    throwUsageError("--timeout applies to --wait. Pass both to poll until the deploy is ready.");
+  }
+  const ctx = await resolveDeployContext(options.app);
   if (!ctx.appId || !ctx.developmentInstanceId) {
     throw new CliError(
       "No Clerk project linked to this directory. Run `clerk link`, then rerun `clerk deploy status`.",
@@ -35,13 +44,18 @@ export async function deployStatus(options: DeployStatusOptions = {}): Promise<v
   const preflightTriggered = await runPreflightDeployStatusCheck(ctx);
   const state = await resolveDeployState(ctx);
   const shouldWait = options.wait === true || !isAgent();
+  // Without --wait, human mode still retries a few times; --wait polls until
+  // the deploy is ready or the timeout runs out.
+  const timeoutMs = options.wait
+    ? (options.timeout ?? DEFAULT_WAIT_TIMEOUT_SECONDS) * 1000
+    : undefined;
   const outcome =
     state.kind === "active" && shouldWait
-      ? await runWait(state, { triggerCheck: !preflightTriggered })
+      ? await runWait(state, { triggerCheck: !preflightTriggered, timeoutMs })
       : null;
   const report = buildDeployStatusReport(state, outcome);
 
-  emitReport(report);
+  emitReport(report, options.json === true);
   process.exitCode = report.complete ? EXIT_CODE.SUCCESS : EXIT_CODE.GENERAL;
 }
 
@@ -61,7 +75,7 @@ async function runPreflightDeployStatusCheck(ctx: DeployContext): Promise<boolea
 
 function runWait(
   state: Extract<DeployState, { kind: "active" }>,
-  options: { triggerCheck?: boolean } = {},
+  options: { triggerCheck?: boolean; timeoutMs?: number } = {},
 ): Promise<DeployStatusOutcome> {
   const { snapshot } = state;
   const domainIdOrName = snapshot.productionDomainId ?? snapshot.domain;
@@ -79,8 +93,8 @@ function runWait(
   );
 }
 
-function emitReport(report: DeployStatusReport): void {
-  if (isAgent()) {
+function emitReport(report: DeployStatusReport, json: boolean): void {
+  if (json || isAgent()) {
     log.data(JSON.stringify(report, null, 2));
     return;
   }
@@ -95,11 +109,18 @@ function renderHuman(report: DeployStatusReport): void {
     log.info("Deploy status");
   }
 
-  if (report.domainStatus) {
+  if (report.domainChecks) {
+    const { dns, ssl, mail } = report.domainChecks;
     log.info(
-      `  Domain   DNS: ${report.domainStatus.dns}  SSL: ${report.domainStatus.ssl}  Email DNS: ${report.domainStatus.mail}`,
+      `  Domain   DNS: ${formatCheck(dns)}  SSL: ${formatCheck(ssl)}  Email DNS: ${formatCheck(mail)}`,
     );
   }
+  if (report.pendingDnsRecords.length > 0) {
+    log.info("  Waiting on DNS records:");
+    for (const record of report.pendingDnsRecords) {
+      log.info(`    ${record.type} ${record.host} -> ${record.value}`);
+    }
+  }
 
   const oauthStatus = report.oauth.complete
     ? "complete"
@@ -117,6 +138,10 @@ function renderHuman(report: DeployStatusReport): void {
   log.blank();
 }
 
+function formatCheck(check: DomainCheckState): string {
+  return check.required ? check.status.replace(/_/g, " ") : "not required";
+}
+
 function formatHumanNextAction(nextAction: string): string {
   return nextAction.replace(
     /Ask the user to visit the Clerk Dashboard domains page, or offer to open it: (https:\/\/\S+)/,
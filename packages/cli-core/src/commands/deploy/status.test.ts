@@ -41,6 +41,12 @@ const completeStatus = {
   mail: { status: "complete", required: true },
 };
 
+const completeChecks = {
+  dns: { status: "complete", required: true },
+  ssl: { status: "complete", required: true },
+  mail: { status: "complete", required: true },
+};
+
 const passthroughHandlers = {
   runVerification: <T>(_label: string, work: (controls: { update: () => void }) => Promise<T>) =>
     work({ update: () => {} }),
@@ -169,6 +175,7 @@ describe("waitForDeployStatus", () => {
     expect(outcome).toEqual({
       verified: true,
       status: { dns: true, ssl: true, mail: true },
+      checks: completeChecks,
     });
   });
 
@@ -183,6 +190,7 @@ describe("waitForDeployStatus", () => {
     expect(outcome).toEqual({
       verified: true,
       status: { dns: true, ssl: true, mail: true },
+      checks: completeChecks,
     });
   });
 });
@@ -203,6 +211,11 @@ describe("buildDeployStatusReport", () => {
     ],
     domainComplete: false,
     componentStatus: { dns: false, ssl: false, mail: false },
+    domainChecks: {
+      dns: { status: "not_started", required: true },
+      ssl: { status: "not_started", required: true },
+      mail: { status: "not_started", required: true },
+    },
     unsupportedOAuthProviderCount: 0,
     unsupportedOAuthProviders: [],
     pending: { type: "oauth" as const, provider: "github" },
@@ -216,6 +229,7 @@ describe("buildDeployStatusReport", () => {
     expect(report.domain).toBeNull();
     expect(report.productionInstanceId).toBeNull();
     expect(report.domainStatus).toBeNull();
+    expect(report.domainChecks).toBeNull();
     expect(report.nextAction).toContain("clerk deploy");
   });
 
@@ -236,7 +250,11 @@ describe("buildDeployStatusReport", () => {
   test("active with pending domain gives domain precedence over OAuth", () => {
     const report = buildDeployStatusReport(
       { kind: "active", snapshot: activeSnapshot },
-      { verified: false, status: { dns: false, ssl: true, mail: true } },
+      {
+        verified: false,
+        status: { dns: false, ssl: true, mail: true },
+        checks: { ...completeChecks, dns: { status: "in_progress", required: true } },
+      },
     );
 
     expect(report.state).toBe("domain_pending");
@@ -247,6 +265,7 @@ describe("buildDeployStatusReport", () => {
     expect(report.nextAction).toContain("Ask the user to visit");
     expect(report.nextAction).toContain("offer to open it");
     expect(report.domainStatus).toEqual({ dns: "pending", ssl: "complete", mail: "complete" });
+    expect(report.domainChecks?.dns).toEqual({ status: "in_progress", required: true });
     expect(report.pendingDnsRecords).toContainEqual({
       type: "CNAME",
       host: "clerk.example.com",
@@ -258,7 +277,11 @@ describe("buildDeployStatusReport", () => {
   test("active with pending email DNS reports only email CNAME records", () => {
     const report = buildDeployStatusReport(
       { kind: "active", snapshot: activeSnapshot },
-      { verified: false, status: { dns: true, ssl: true, mail: false } },
+      {
+        verified: false,
+        status: { dns: true, ssl: true, mail: false },
+        checks: { ...completeChecks, mail: { status: "not_started", required: true } },
+      },
     );
 
     expect(report.pendingDnsRecords).toEqual([
@@ -273,7 +296,7 @@ describe("buildDeployStatusReport", () => {
   test("active with complete domain but pending OAuth reports oauth_pending", () => {
     const report = buildDeployStatusReport(
       { kind: "active", snapshot: activeSnapshot },
-      { verified: true, status: { dns: true, ssl: true, mail: true } },
+      { verified: true, status: { dns: true, ssl: true, mail: true }, checks: completeChecks },
     );
 
     expect(report.state).toBe("oauth_pending");
@@ -295,7 +318,7 @@ describe("buildDeployStatusReport", () => {
     } satisfies LiveDeploySnapshot;
     const report = buildDeployStatusReport(
       { kind: "active", snapshot: allDone },
-      { verified: true, status: { dns: true, ssl: true, mail: true } },
+      { verified: true, status: { dns: true, ssl: true, mail: true }, checks: completeChecks },
     );
 
     expect(report.state).toBe("complete");
@@ -316,7 +339,7 @@ describe("buildDeployStatusReport", () => {
     } satisfies LiveDeploySnapshot;
     const report = buildDeployStatusReport(
       { kind: "active", snapshot: withUnsupported },
-      { verified: true, status: { dns: true, ssl: true, mail: true } },
+      { verified: true, status: { dns: true, ssl: true, mail: true }, checks: completeChecks },
     );
 
     expect(report.complete).toBe(true);
@@ -18,6 +18,7 @@ import {
   deployComponentLabels,
   deployStatusRetryMessage,
   domainsDashboardUrl,
+  type DeployComponent,
   type DeployComponentStatus,
 } from "./copy.ts";
 import { mapDeployError } from "./errors.ts";
@@ -33,6 +34,8 @@ import type { DeployContext, DeployOperationState } from "./state.ts";
 const DEPLOY_STATUS_INITIAL_RETRY_DELAY_MS = 3000;
 const DEPLOY_STATUS_MAX_RETRIES = 5;
 const DEPLOY_STATUS_BACKOFF_FACTOR = 2;
+/** Longest pause between checks when polling until a deadline. */
+const DEPLOY_STATUS_MAX_RETRY_DELAY_MS = 60_000;
 
 export interface DeployProgressHandlers {
   runVerification<T>(
@@ -42,7 +45,17 @@ export interface DeployProgressHandlers {
   onVerified?(): void;
 }
 
-export type DeployStatusOutcome = { verified: boolean; status: DeployComponentStatus };
+/** A domain check as the Platform API reports it (`not_started`, `complete`, ...). */
+export type DomainCheckState = { status: string; required: boolean };
+
+/** The DNS, SSL certificate, and email DNS checks of a production domain. */
+export type DomainChecks = Record<DeployComponent, DomainCheckState>;
+
+export type DeployStatusOutcome = {
+  verified: boolean;
+  status: DeployComponentStatus;
+  checks: DomainChecks;
+};
 
 export type DeployStatusState =
   | "complete"
@@ -57,6 +70,7 @@ export interface DeployStatusReport {
   domain: string | null;
   productionInstanceId: string | null;
   domainStatus: { dns: string; ssl: string; mail: string } | null;
+  domainChecks: DomainChecks | null;
   pendingDnsRecords: { type: "CNAME"; host: string; value: string }[];
   oauth: { complete: boolean; configured: string[]; pending: string[]; unsupported: string[] };
   nextAction: string;
@@ -72,6 +86,7 @@ export type LiveDeploySnapshot = Omit<
   completedOAuthProviders: OAuthProvider[];
   domainComplete: boolean;
   componentStatus: DeployComponentStatus;
+  domainChecks: DomainChecks;
   unsupportedOAuthProviderCount: number;
   unsupportedOAuthProviders: string[];
 };
@@ -96,10 +111,15 @@ export type DiscoveredOAuthProviders = {
   unsupported: string[];
 };
 
-export async function resolveDeployContext(): Promise<DeployContext> {
-  const resolved = await withSpinner("Resolving linked Clerk application...", () =>
-    resolveProfile(process.cwd()),
-  );
+export async function resolveDeployContext(appId?: string): Promise<DeployContext> {
+  const resolved = appId
+    ? {
+        path: process.cwd(),
+        profile: { workspaceId: "", appId, instances: { development: "" } },
+      }
+    : await withSpinner("Resolving linked Clerk application...", () =>
+        resolveProfile(process.cwd()),
+      );
   if (!resolved) {
     return {
       profileKey: process.cwd(),
@@ -223,6 +243,7 @@ export async function resolveLiveDeploySnapshot(
     completedOAuthProviders,
     cnameTargets: domain.cname_targets ?? [],
     componentStatus: deployComponentStatusFromDomainStatus(deployStatus),
+    domainChecks: domainChecksFromDomainStatus(deployStatus),
     unsupportedOAuthProviderCount: unsupported.length,
     unsupportedOAuthProviders: unsupported,
   };
@@ -308,6 +329,7 @@ export function buildDeployStatusReport(
       domain: null,
       productionInstanceId: null,
       domainStatus: null,
+      domainChecks: null,
       pendingDnsRecords: [],
       oauth: { complete: false, configured: [], pending: [], unsupported: [] },
       nextAction:
@@ -326,6 +348,7 @@ export function buildDeployStatusReport(
       domain: null,
       productionInstanceId: state.productionInstanceId,
       domainStatus: null,
+      domainChecks: null,
       pendingDnsRecords: [],
       oauth: { complete: false, configured: [], pending: [], unsupported: [] },
       nextAction:
@@ -361,6 +384,7 @@ export function buildDeployStatusReport(
       ssl: domainComponentState(componentStatus.ssl),
       mail: domainComponentState(componentStatus.mail),
     },
+    domainChecks: outcome?.checks ?? snapshot.domainChecks,
     pendingDnsRecords,
     oauth: {
       complete: oauthComplete,
@@ -448,53 +472,69 @@ export function discoverEnabledOAuthProviderSlugs(config: Record<string, unknown
   return providers;
 }
 
+/**
+ * Poll the domain status until every check is complete. By default this gives
+ * up after {@link DEPLOY_STATUS_MAX_RETRIES} backed-off retries; with
+ * `timeoutMs` it keeps polling until that much time has been spent waiting,
+ * backing off to at most one check a minute.
+ */
 export async function waitForDeployStatus(
   appId: string,
   domainIdOrName: string,
   domain: string,
   handlers: DeployProgressHandlers,
-  options: { triggerCheck?: boolean } = {},
+  options: { triggerCheck?: boolean; timeoutMs?: number } = {},
 ): Promise<DeployStatusOutcome> {
   if (options.triggerCheck !== false) {
     await triggerDeployStatusCheck(appId, domainIdOrName);
   }
   let response = await mapDeployError(getApplicationDomainStatus(appId, domainIdOrName));
-  let status = deployComponentStatusFromDomainStatus(response);
 
   const labels = deployComponentLabels("dns", domain);
+  const { timeoutMs } = options;
   const verified = await handlers.runVerification(labels.progress, async (spinner) => {
     if (response.status === "complete") return true;
 
-    let retriesRemaining = DEPLOY_STATUS_MAX_RETRIES;
+    let attempt = 0;
+    let waitedMs = 0;
     let nextRetryDelay = DEPLOY_STATUS_INITIAL_RETRY_DELAY_MS;
-    while (retriesRemaining > 0) {
+    const keepPolling = () =>
+      timeoutMs === undefined ? attempt < DEPLOY_STATUS_MAX_RETRIES : waitedMs < timeoutMs;
+    while (keepPolling()) {
+      attempt++;
+      const delay =
+        timeoutMs === undefined ? nextRetryDelay : Math.min(nextRetryDelay, timeoutMs - waitedMs);
       await sleepWithRetryCountdown(
         labels.progress,
-        DEPLOY_STATUS_MAX_RETRIES - retriesRemaining + 1,
-        DEPLOY_STATUS_MAX_RETRIES,
-        nextRetryDelay,
+        attempt,
+        timeoutMs === undefined ? DEPLOY_STATUS_MAX_RETRIES : undefined,
+        delay,
         spinner,
       );
-      retriesRemaining--;
-      nextRetryDelay *= DEPLOY_STATUS_BACKOFF_FACTOR;
+      waitedMs += delay;
+      nextRetryDelay = Math.min(
+        nextRetryDelay * DEPLOY_STATUS_BACKOFF_FACTOR,
+        DEPLOY_STATUS_MAX_RETRY_DELAY_MS,
+      );
       response = await mapDeployError(getApplicationDomainStatus(appId, domainIdOrName));
-      status = deployComponentStatusFromDomainStatus(response);
       if (response.status === "complete") return true;
     }
     return false;
   });
 
-  if (!verified) {
-    return { verified: false, status };
-  }
-  handlers.onVerified?.();
-  return { verified: true, status };
+  const outcome = {
+    verified,
+    status: deployComponentStatusFromDomainStatus(response),
+    checks: domainChecksFromDomainStatus(response),
+  };
+  if (verified) handlers.onVerified?.();
+  return outcome;
 }
 
 async function sleepWithRetryCountdown(
   message: string,
   currentRetry: number,
-  totalRetries: number,
+  totalRetries: number | undefined,
   delayMs: number,
   spinner: SpinnerControls,
 ): Promise<void> {
@@ -534,6 +574,14 @@ export function deployComponentStatusFromDomainStatus(
   };
 }
 
+export function domainChecksFromDomainStatus(response: DomainStatusResponse): DomainChecks {
+  const check = (value: { status: string; required?: boolean } | undefined) => ({
+    status: value?.status ?? "not_started",
+    required: value?.required !== false,
+  });
+  return { dns: check(response.dns), ssl: check(response.ssl), mail: check(response.mail) };
+}
+
 function checkStatusComplete(check: { status: string; required?: boolean } | undefined): boolean {
   if (!check) return false;
   if (check.required === false) return true;
@@ -1,6 +1,9 @@
 import { join } from "node:path";
 import { homedir } from "node:os";
-import { getConfigFile } from "../../lib/config.ts";
+import { chmod, stat } from "node:fs/promises";
+import { getConfigFile, updateConfig } from "../../lib/config.ts";
+import { credentialsFile } from "../../lib/credential-store.ts";
+import { isSettingKey } from "../../lib/settings.ts";
 import { fetchUserInfo } from "../../lib/token-exchange.ts";
 import { errorMessage, isAuthError, PlapiError } from "../../lib/errors.ts";
 import { detectPublishableKeyName, detectSecretKeyName } from "../../lib/framework.ts";
@@ -23,6 +26,8 @@ interface CheckOptions {
   remedy?: string;
   detail?: string;
   fixable?: boolean;
+  /** Fix for this result in place of the check's default one. */
+  fix?: FixAction;
 }
 
 interface CheckBuilder {
@@ -40,13 +45,14 @@ function defineCheck(name: string, fixFactory?: () => FixAction): CheckBuilder {
     fixableByDefault: boolean,
   ): CheckResult {
     const { remedy, detail, fixable = fixableByDefault } = opts ?? {};
+    const fix = opts?.fix ?? (fixable && fixFactory ? fixFactory() : undefined);
     return {
       name,
       status,
       message,
       ...(detail && { detail }),
       ...(remedy && { remedy }),
-      ...(fixable && fixFactory && { fix: fixFactory() }),
+      ...(fix && { fix }),
     };
   }
 
@@ -302,7 +308,12 @@ export async function checkConfigFile(ctx: DoctorContext): Promise<CheckResult>
     return check.warn(`${configFile} does not exist`, {
       detail: "The config file is created when you first run `clerk auth login` or `clerk link`.",
       remedy: "Run `clerk auth login` to initialize the CLI.",
-      fixable: true,
+      fix: {
+        label: `Create ${configFile}`,
+        safe: true,
+        // Writing through updateConfig creates the directory and a default file.
+        run: () => updateConfig(() => {}),
+      },
     });
   }
 
@@ -324,6 +335,92 @@ export async function checkConfigFile(ctx: DoctorContext): Promise<CheckResult>
   }
 }
 
+/**
+ * Leftovers that the CLI tolerates on read but never cleans up on its own: the
+ * pre-environment `auth: { userId }` shape (migrated in memory on every read)
+ * and settings keys that are no longer declared (ignored by `clerk settings`).
+ */
+export async function checkConfigFormat(): Promise<CheckResult> {
+  const check = defineCheck("Config format");
+  const configFile = getConfigFile();
+  const file = Bun.file(configFile);
+  if (!(await file.exists())) return check.pass("Config format (no config file, skipped)");
+
+  let raw: { auth?: { userId?: unknown }; settings?: Record<string, unknown> };
+  try {
+    raw = (await file.json()) as typeof raw;
+  } catch {
+    // `checkConfigFile` already reports the parse failure.
+    return check.pass("Config format (config file unreadable, skipped)");
+  }
+
+  const problems: string[] = [];
+  if (typeof raw.auth?.userId === "string") {
+    problems.push("auth uses the legacy single-account format");
+  }
+  const unknownSettings = Object.keys(raw.settings ?? {}).filter((key) => !isSettingKey(key));
+  if (unknownSettings.length > 0) {
+    problems.push(`unknown settings: ${unknownSettings.join(", ")}`);
+  }
+  if (problems.length === 0) return check.pass("Config format is current");
+
+  return check.warn(`Config file needs migration (${problems.join("; ")})`, {
+    remedy: "Run `clerk doctor --fix` to rewrite it in the current format.",
+    fix: {
+      label: "Migrate config file and drop unknown settings",
+      safe: true,
+      run: () =>
+        updateConfig((config) => {
+          if (!config.settings) return;
+          for (const key of Object.keys(config.settings)) {
+            if (!isSettingKey(key)) delete config.settings[key];
+          }
+          if (Object.keys(config.settings).length === 0) delete config.settings;
+        }),
+    },
+  });
+}
+
+async function looseMode(path: string): Promise<number | null> {
+  try {
+    const mode = (await stat(path)).mode & 0o777;
+    return mode & 0o077 ? mode : null;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * The config file holds webhook relay tokens and the credentials file holds
+ * the OAuth session when no keyring is available, so neither should be
+ * readable by other users. POSIX only: Windows has no mode bits to check.
+ */
+export async function checkFilePermissions(): Promise<CheckResult> {
+  const check = defineCheck("File permissions");
+  if (process.platform === "win32") return check.pass("File permissions (Windows, skipped)");
+
+  const loose: { path: string; mode: number }[] = [];
+  for (const path of [getConfigFile(), credentialsFile()]) {
+    const mode = await looseMode(path);
+    if (mode !== null) loose.push({ path, mode });
+  }
+  if (loose.length === 0) return check.pass("CLI files are private to your user");
+
+  return check.warn(`${loose.map((entry) => entry.path).join(", ")} accessible by other users`, {
+    detail: loose
+      .map((entry) => `${entry.path}: ${entry.mode.toString(8).padStart(4, "0")}`)
+      .join("\n"),
+    remedy: `Run \`chmod 600 ${loose.map((entry) => entry.path).join(" ")}\``,
+    fix: {
+      label: "Restrict CLI files to mode 0600",
+      safe: true,
+      run: async () => {
+        for (const { path } of loose) await chmod(path, 0o600);
+      },
+    },
+  });
+}
+
 // ── CLI version check ─────────────────────────────────────────────────────────
 
 export async function checkCliVersion(): Promise<CheckResult> {
@@ -414,5 +511,14 @@ export async function checkShellCompletion(): Promise<CheckResult> {
   const { isInstalled, remedy } = SHELL_COMPLETION[shell];
 
   if (await isInstalled(home)) return check.pass(`Shell completion installed for ${shell}`);
-  return check.warn(`Shell completion not installed for ${shell}`, { remedy });
+  return check.warn(`Shell completion not installed for ${shell}`, {
+    remedy,
+    fix: {
+      label: `Install ${shell} completions with clerk completion install`,
+      run: async () => {
+        const { installCompletion } = await import("../completion/install.ts");
+        await installCompletion({ shell });
+      },
+    },
+  });
 }
@@ -1,5 +1,5 @@
 import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
-import { mkdtemp, rm } from "node:fs/promises";
+import { chmod, mkdtemp, rm, stat } from "node:fs/promises";
 import { join } from "node:path";
 import { tmpdir } from "node:os";
 import { ApiError, AuthError } from "../../lib/errors.ts";
@@ -39,6 +39,8 @@ const {
   checkInstances,
   checkEnvVars,
   checkConfigFile,
+  checkConfigFormat,
+  checkFilePermissions,
   checkShellCompletion,
 } = await import("./checks.ts");
 
@@ -630,6 +632,17 @@ describe("checkConfigFile", () => {
     });
   });
 
+  test("safe fix creates the missing config directory and file", async () => {
+    _setConfigDir(join(tempDir, "nonexistent"));
+    const result = await checkConfigFile(createMockContext());
+    expect(result.fix?.safe).toBe(true);
+
+    await result.fix!.run();
+    expect(await Bun.file(join(tempDir, "nonexistent", "config.json")).json()).toEqual({
+      profiles: {},
+    });
+  });
+
   test("fail when config has invalid JSON", async () => {
     _setConfigDir(tempDir);
     await Bun.write(join(tempDir, "config.json"), "{ invalid json }");
@@ -644,6 +657,75 @@ describe("checkConfigFile", () => {
   });
 });
 
+describe("checkConfigFormat", () => {
+  test("pass when config is current", async () => {
+    _setConfigDir(tempDir);
+    await Bun.write(
+      join(tempDir, "config.json"),
+      JSON.stringify({ profiles: {}, auth: { production: { userId: "u_1" } } }),
+    );
+    const result = await checkConfigFormat();
+    expectCheck(result, { name: "Config format", status: "pass", message: "current" });
+  });
+
+  test("safe fix migrates legacy auth and drops unknown settings", async () => {
+    _setConfigDir(tempDir);
+    const path = join(tempDir, "config.json");
+    await Bun.write(
+      path,
+      JSON.stringify({
+        profiles: {},
+        auth: { userId: "u_1" },
+        settings: { "core.pager": "cat", "core.removed": "x" },
+      }),
+    );
+    const result = await checkConfigFormat();
+    expectCheck(result, {
+      name: "Config format",
+      status: "warn",
+      message: ["legacy", "core.removed"],
+      fix: true,
+    });
+    expect(result.fix?.safe).toBe(true);
+
+    await result.fix!.run();
+    const migrated = await Bun.file(path).json();
+    expect(migrated.auth).toEqual({ production: { userId: "u_1" } });
+    expect(migrated.settings).toEqual({ "core.pager": "cat" });
+    expectCheck(await checkConfigFormat(), { name: "Config format", status: "pass", message: "current" });
+  });
+});
+
+describe("checkFilePermissions", () => {
+  test.skipIf(process.platform === "win32")(
+    "safe fix restricts a group-readable config file to 0600",
+    async () => {
+      _setConfigDir(tempDir);
+      process.env.CLERK_CONFIG_DIR = tempDir;
+      const path = join(tempDir, "config.json");
+      await Bun.write(path, JSON.stringify({ profiles: {} }));
+      await chmod(path, 0o644);
+
+      const result = await checkFilePermissions();
+      expectCheck(result, {
+        name: "File permissions",
+        status: "warn",
+        message: "config.json",
+        detail: "0644",
+        fix: true,
+      });
+
+      await result.fix!.run();
+      expect((await stat(path)).mode & 0o777).toBe(0o600);
+      expectCheck(await checkFilePermissions(), {
+        name: "File permissions",
+        status: "pass",
+        message: "private",
+      });
+    },
+  );
+});
+
 describe("checkShellCompletion", () => {
   test("pass when shell cannot be detected", async () => {
     process.env.SHELL = "";
@@ -14,6 +14,8 @@ import {
   checkInstances,
   checkEnvVars,
   checkConfigFile,
+  checkConfigFormat,
+  checkFilePermissions,
   checkShellCompletion,
   checkCliVersion,
 } from "./checks.ts";
@@ -30,6 +32,8 @@ const BASE_CHECKS: CheckFn[] = [
   checkInstances,
   checkEnvVars,
   checkConfigFile,
+  checkConfigFormat,
+  checkFilePermissions,
   checkShellCompletion,
   checkMcp,
 ];
@@ -63,6 +67,19 @@ function printResults(results: CheckResult[], options: DoctorOptions): void {
   log.blank();
 }
 
+async function applyFix(result: CheckResult): Promise<void> {
+  const fix = result.fix;
+  if (!fix) return;
+  try {
+    await fix.run();
+    // Safe fixes ran without a prompt, so say what they did.
+    const what = fix.safe ? ` (${fix.label})` : "";
+    log.info(`  ${green("✓")} ${result.name} fixed${what}`);
+  } catch (error) {
+    log.info(`  ${red("✗")} Fix failed: ${errorMessage(error)}`);
+  }
+}
+
 export async function doctor(options: DoctorOptions = {}): Promise<void> {
   if (!options.json) {
     intro("Running diagnostics");
@@ -80,7 +97,7 @@ export async function doctor(options: DoctorOptions = {}): Promise<void> {
     log.data(formatJson(output));
   }
 
-  if (options.fix && !options.json && isHuman()) {
+  if (options.fix && !options.json) {
     const fixable = allResults.filter((r) => r.status !== "pass" && r.fix);
 
     const seen = new Set<string>();
@@ -90,29 +107,32 @@ export async function doctor(options: DoctorOptions = {}): Promise<void> {
       seen.add(label);
       return true;
     });
+    // Safe fixes run unattended; the rest open browsers or pickers, so they
+    // are offered one by one and only to a human.
+    const safeFixes = uniqueFixable.filter((r) => r.fix?.safe);
+    const promptedFixes = isHuman() ? uniqueFixable.filter((r) => !r.fix?.safe) : [];
 
-    if (uniqueFixable.length > 0) {
+    if (safeFixes.length > 0 || promptedFixes.length > 0) {
       log.blank();
       log.info(bold("Auto-fix"));
       log.blank();
 
-      const { confirm } = await import("../../lib/prompts.ts");
+      for (const result of safeFixes) {
+        await applyFix(result);
+      }
 
-      for (const result of uniqueFixable) {
-        const fix = result.fix;
-        if (!fix) continue;
-        const proceed = await confirm({
-          message: `Fix "${result.name}"? (${fix.label})`,
-          default: true,
-        });
+      if (promptedFixes.length > 0) {
+        const { confirm } = await import("../../lib/prompts.ts");
+
+        for (const result of promptedFixes) {
+          const fix = result.fix;
+          if (!fix) continue;
+          const proceed = await confirm({
+            message: `Fix "${result.name}"? (${fix.label})`,
+            default: true,
+          });
 
-        if (proceed) {
-          try {
-            await fix.run();
-            log.info(`  ${green("✓")} ${result.name} fixed`);
-          } catch (error) {
-            log.info(`  ${red("✗")} Fix failed: ${errorMessage(error)}`);
-          }
+          if (proceed) await applyFix(result);
         }
       }
 
@@ -149,7 +169,7 @@ export function registerDoctor(program: Program): void {
     .option("--verbose", "Show detailed output for each check")
     .option("--json", "Output results as JSON")
     .option("--spotlight", "Only show warnings and failures")
-    .option("--fix", "Attempt to auto-fix issues")
+    .option("--fix", "Apply safe fixes and offer the rest interactively")
     .setExamples([
       { command: "clerk doctor", description: "Run all health checks" },
       { command: "clerk doctor --verbose", description: "Show detailed output for each check" },
@@ -7,6 +7,11 @@ export type ResolvedProfile = NonNullable<Awaited<ReturnType<typeof resolveProfi
 
 export interface FixAction {
   label: string;
+  /**
+   * Safe fixes only touch the CLI's own local files and need no input, so
+   * `--fix` applies them without asking, in any mode.
+   */
+  safe?: boolean;
   run: () => Promise<void>;
 }
 
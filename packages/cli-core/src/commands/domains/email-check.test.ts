@@ -0,0 +1,153 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockResolveCname = mock();
+mock.module("node:dns/promises", () => ({
+  resolveCname: (...args: unknown[]) => mockResolveCname(...args),
+}));
+
+mock.module("../../lib/config.ts", () => ({
+  resolveAppContext: async () => ({
+    appId: "app_123",
+    appLabel: "My App",
+    instanceId: "ins_dev",
+    instanceLabel: "development",
+  }),
+}));
+
+const mockListApplicationDomains = mock();
+mock.module("../../lib/plapi.ts", () => ({
+  listApplicationDomains: (...args: unknown[]) => mockListApplicationDomains(...args),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { emailCheck, zoneFileRecord } = await import("./email-check.ts");
+
+const CNAME_TARGETS = [
+  { host: "clerk.example.com", value: "frontend-api.clerk.services", required: true },
+  { host: "clkmail.example.com", value: "mail.abc123.clerk.services", required: true },
+  { host: "clk._domainkey.example.com", value: "dkim1.abc123.clerk.services", required: true },
+  { host: "clk2._domainkey.example.com", value: "dkim2.abc123.clerk.services", required: true },
+];
+
+function dnsError(code: string): Error {
+  return Object.assign(new Error(`queryCname ${code}`), { code });
+}
+
+function stubDns(records: Record<string, string[] | Error>) {
+  mockResolveCname.mockImplementation(async (host: string) => {
+    const answer = records[host] ?? dnsError("ENOTFOUND");
+    if (answer instanceof Error) throw answer;
+    return answer;
+  });
+}
+
+describe("zoneFileRecord", () => {
+  test("writes fully qualified names", () => {
+    expect(zoneFileRecord({ host: "clkmail.example.com", expected: "mail.x.clerk.services" })).toBe(
+      "clkmail.example.com. 300 IN CNAME mail.x.clerk.services.",
+    );
+  });
+});
+
+describe("domains email-check", () => {
+  const captured = useCaptureLog();
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(() => {
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockListApplicationDomains.mockResolvedValue({
+      data: [{ id: "dmn_1", name: "example.com", cname_targets: CNAME_TARGETS }],
+      total_count: 1,
+    });
+  });
+
+  afterEach(() => {
+    process.exitCode = originalExitCode;
+    mockResolveCname.mockReset();
+    mockListApplicationDomains.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("passes when every email record points at Clerk", async () => {
+    stubDns({
+      "clkmail.example.com": ["mail.abc123.clerk.services"],
+      "clk._domainkey.example.com": ["DKIM1.abc123.clerk.services."],
+      "clk2._domainkey.example.com": ["dkim2.abc123.clerk.services"],
+    });
+
+    await emailCheck("Example.com");
+
+    expect(mockResolveCname).toHaveBeenCalledTimes(3);
+    expect(mockResolveCname).not.toHaveBeenCalledWith("clerk.example.com");
+    expect(captured.err).toContain("Return-path (SPF)");
+    expect(captured.err).not.toContain("Add these records");
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("prints copy-pasteable records for missing and mismatched hosts", async () => {
+    stubDns({
+      "clkmail.example.com": ["mail.abc123.clerk.services"],
+      "clk._domainkey.example.com": ["dkim.other-provider.test"],
+    });
+
+    await emailCheck("example.com");
+
+    expect(captured.err).toContain("(no CNAME record)");
+    expect(captured.err).toContain("→ dkim.other-provider.test");
+    expect(captured.err).toContain(
+      "clk._domainkey.example.com. 300 IN CNAME dkim1.abc123.clerk.services.",
+    );
+    expect(captured.err).toContain(
+      "clk2._domainkey.example.com. 300 IN CNAME dkim2.abc123.clerk.services.",
+    );
+    expect(captured.err).not.toContain("clkmail.example.com. 300");
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("reports each record as JSON and keeps lookup failures distinct", async () => {
+    stubDns({
+      "clkmail.example.com": dnsError("ETIMEOUT"),
+      "clk._domainkey.example.com": ["dkim1.abc123.clerk.services"],
+      "clk2._domainkey.example.com": dnsError("ENODATA"),
+    });
+
+    await emailCheck("example.com", { json: true });
+
+    const output = JSON.parse(captured.out) as {
+      ok: boolean;
+      records: { host: string; purpose: string; status: string; error?: string }[];
+    };
+    expect(output.ok).toBe(false);
+    expect(output.records.map(({ host, purpose, status }) => [host, purpose, status])).toEqual([
+      ["clkmail.example.com", "return-path", "error"],
+      ["clk._domainkey.example.com", "dkim", "ok"],
+      ["clk2._domainkey.example.com", "dkim", "missing"],
+    ]);
+    expect(output.records[0]!.error).toBe("ETIMEOUT");
+  });
+
+  test("fails with the known domains when the domain is not attached", async () => {
+    await expect(emailCheck("other.test")).rejects.toMatchObject({
+      code: "domain_not_found",
+      message: expect.stringContaining("Known domains: example.com."),
+    });
+    expect(mockResolveCname).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,155 @@
+import { resolveCname } from "node:dns/promises";
+import { resolveAppContext } from "../../lib/config.ts";
+import { cyan, dim, green, red, yellow } from "../../lib/color.ts";
+import { CliError, ERROR_CODE, EXIT_CODE, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { listApplicationDomains, type CnameTarget } from "../../lib/plapi.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { isAgent } from "../../mode.ts";
+import { isMailCnameTarget } from "../deploy/copy.ts";
+
+export type EmailCheckOptions = {
+  app?: string;
+  json?: boolean;
+};
+
+export type EmailRecordPurpose = "return-path" | "dkim";
+
+/**
+ * - `ok`: the CNAME points where Clerk expects.
+ * - `missing`: no CNAME exists at the host.
+ * - `mismatch`: a CNAME exists but points somewhere else.
+ * - `error`: the lookup itself failed (timeout, SERVFAIL); try again.
+ */
+export type EmailRecordStatus = "ok" | "missing" | "mismatch" | "error";
+
+export type EmailRecordCheck = {
+  type: "CNAME";
+  purpose: EmailRecordPurpose;
+  host: string;
+  expected: string;
+  actual: string[];
+  required: boolean;
+  status: EmailRecordStatus;
+  error?: string;
+};
+
+const PURPOSE_LABELS: Record<EmailRecordPurpose, string> = {
+  "return-path": "Return-path (SPF)",
+  dkim: "DKIM",
+};
+
+/** DNS answers for a name that exists but has no such record, or doesn't exist at all. */
+const NOT_FOUND_CODES = new Set(["ENOTFOUND", "ENODATA"]);
+
+function normalizeHost(host: string): string {
+  return host.trim().toLowerCase().replace(/\.$/, "");
+}
+
+function purposeOf(target: CnameTarget): EmailRecordPurpose {
+  return target.host.startsWith("clkmail.") ? "return-path" : "dkim";
+}
+
+export async function checkEmailRecord(target: CnameTarget): Promise<EmailRecordCheck> {
+  const base = {
+    type: "CNAME" as const,
+    purpose: purposeOf(target),
+    host: target.host,
+    expected: target.value,
+    required: target.required,
+  };
+  try {
+    const actual = await resolveCname(target.host);
+    const matches = actual.some((value) => normalizeHost(value) === normalizeHost(target.value));
+    return { ...base, actual, status: matches ? "ok" : "mismatch" };
+  } catch (error) {
+    const code = (error as NodeJS.ErrnoException).code;
+    if (code && NOT_FOUND_CODES.has(code)) return { ...base, actual: [], status: "missing" };
+    return { ...base, actual: [], status: "error", error: code ?? String(error) };
+  }
+}
+
+/** A record in zone-file syntax, ready to paste into most DNS providers' import boxes. */
+export function zoneFileRecord(check: Pick<EmailRecordCheck, "host" | "expected">): string {
+  return `${normalizeHost(check.host)}. 300 IN CNAME ${normalizeHost(check.expected)}.`;
+}
+
+function formatCheck(check: EmailRecordCheck): string {
+  const label = PURPOSE_LABELS[check.purpose].padEnd(18);
+  switch (check.status) {
+    case "ok":
+      return `${green("✓")} ${label}${check.host} ${dim("→")} ${check.expected}`;
+    case "missing":
+      return `${red("✗")} ${label}${check.host} ${dim("(no CNAME record)")}`;
+    case "mismatch":
+      return `${red("✗")} ${label}${check.host} ${dim(`→ ${check.actual.join(", ")}`)}`;
+    case "error":
+      return `${yellow("!")} ${label}${check.host} ${dim(`(lookup failed: ${check.error})`)}`;
+  }
+}
+
+export async function emailCheck(domain: string, options: EmailCheckOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const ctx = await resolveAppContext({ app: options.app });
+
+  const domains = await withSpinner("Fetching domains...", () =>
+    withApiContext(listApplicationDomains(ctx.appId), "Failed to list domains"),
+  );
+  const wanted = normalizeHost(domain);
+  const match = domains.data.find((entry) => normalizeHost(entry.name) === wanted);
+  if (!match) {
+    const known = domains.data.map((entry) => entry.name).join(", ");
+    throw new CliError(
+      `Domain ${domain} is not attached to ${ctx.appLabel}.` +
+        (known ? ` Known domains: ${known}.` : ""),
+      { code: ERROR_CODE.DOMAIN_NOT_FOUND },
+    );
+  }
+
+  const targets = (match.cname_targets ?? []).filter(isMailCnameTarget);
+  const checks = await withSpinner("Looking up DNS records...", () =>
+    Promise.all(targets.map(checkEmailRecord)),
+  );
+  const failing = checks.filter((check) => check.status !== "ok");
+  const ok = targets.length > 0 && failing.every((check) => !check.required);
+
+  if (json) {
+    log.data(JSON.stringify({ domain: match.name, ok, records: checks }, null, 2));
+    if (!ok) process.exitCode = EXIT_CODE.GENERAL;
+    return;
+  }
+
+  intro(`Email DNS for ${cyan(match.name)}`);
+  if (targets.length === 0) {
+    log.warn(
+      `Clerk does not send email from ${match.name}, so there are no records to check. ` +
+        "Development instances and provider domains send from Clerk's own domain.",
+    );
+    await outro("Nothing to check");
+    process.exitCode = EXIT_CODE.GENERAL;
+    return;
+  }
+
+  for (const check of checks) log.info(formatCheck(check));
+
+  const fixable = failing.filter((check) => check.status !== "error");
+  if (fixable.length > 0) {
+    log.blank();
+    log.info("Add these records at your DNS provider (zone-file format):");
+    log.blank();
+    for (const check of fixable) log.info(`  ${zoneFileRecord(check)}`);
+    log.blank();
+    log.info(
+      dim('Set them to "DNS only" if your provider proxies records, or verification will fail.'),
+    );
+    if (fixable.some((check) => check.status === "mismatch")) {
+      log.info(dim("Replace the existing CNAMEs at those hosts; a host can only have one."));
+    }
+  }
+  if (failing.some((check) => check.status === "error")) {
+    log.info(dim("Some lookups failed. DNS changes can take up to 48 hours to propagate."));
+  }
+
+  await outro(ok ? "Email DNS records are in place" : `${failing.length} record(s) need attention`);
+  if (!ok) process.exitCode = EXIT_CODE.GENERAL;
+}
@@ -0,0 +1,28 @@
+import type { Program } from "../../cli-program.ts";
+import { emailCheck } from "./email-check.ts";
+
+export function registerDomains(program: Program): void {
+  const domains = program
+    .command("domains")
+    .description("Inspect your application's domains")
+    .option("--app <id>", "Application ID to target (works from any directory)");
+
+  domains
+    .command("email-check")
+    .description("Verify the DNS records Clerk needs to send email from a domain")
+    .argument("<domain>", "Domain attached to the application, e.g. example.com")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk domains email-check example.com",
+        description: "Check SPF and DKIM records and print any that are missing",
+      },
+      {
+        command: "clerk domains email-check example.com --json",
+        description: "Report each record's status as JSON",
+      },
+    ])
+    .action((domain, _opts, cmd) =>
+      emailCheck(domain, cmd.optsWithGlobals() as Parameters<typeof emailCheck>[1]),
+    );
+}
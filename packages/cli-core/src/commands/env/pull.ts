@@ -1,6 +1,6 @@
 import { resolve, join, basename } from "node:path";
 import { resolveAppContext, type AppContextOptions } from "../../lib/config.ts";
-import { fetchApplication } from "../../lib/plapi.ts";
+import { fetchApplication, type ApplicationInstance } from "../../lib/plapi.ts";
 import { parseEnvFile, mergeEnvVars, serializeEnvFile } from "../../lib/dotenv.ts";
 import {
   detectPublishableKeyName,
@@ -25,7 +25,7 @@ async function hasClerkKeys(path: string): Promise<boolean> {
   return /(?:CLERK_SECRET_KEY|(?:\w+_)?CLERK_PUBLISHABLE_KEY)=/.test(content);
 }
 
-async function resolveTargetFile(
+export async function resolveTargetFile(
   cwd: string,
   flag?: string,
   fallbackFile: string = ".env.local",
@@ -47,6 +47,34 @@ async function resolveTargetFile(
   return fallback;
 }
 
+/**
+ * Merge an instance's publishable and secret keys into `targetFile`, using the
+ * variable names the framework in `cwd` expects. Other lines are kept as-is.
+ */
+export async function writeInstanceKeys(
+  cwd: string,
+  targetFile: string,
+  instance: ApplicationInstance,
+): Promise<void> {
+  const publishableKeyName = await detectPublishableKeyName(cwd);
+  const secretKeyName = await detectSecretKeyName(cwd);
+
+  const file = Bun.file(targetFile);
+  const existingContent = (await file.exists()) ? await file.text() : "";
+
+  const lines = parseEnvFile(existingContent);
+  const vars: Record<string, string> = {
+    [publishableKeyName]: instance.publishable_key,
+  };
+  if (instance.secret_key) {
+    vars[secretKeyName] = instance.secret_key;
+  }
+  const merged = mergeEnvVars(lines, vars);
+  const output = serializeEnvFile(merged);
+
+  await Bun.write(targetFile, output);
+}
+
 export async function pull(options: EnvPullOptions): Promise<void> {
   await withGutter("Pulling environment variables", async () => {
     const cwd = options.cwd ?? process.cwd();
@@ -68,23 +96,7 @@ export async function pull(options: EnvPullOptions): Promise<void> {
         });
       }
 
-      const publishableKeyName = await detectPublishableKeyName(cwd);
-      const secretKeyName = await detectSecretKeyName(cwd);
-
-      const file = Bun.file(targetFile);
-      const existingContent = (await file.exists()) ? await file.text() : "";
-
-      const lines = parseEnvFile(existingContent);
-      const vars: Record<string, string> = {
-        [publishableKeyName]: matched.publishable_key,
-      };
-      if (matched.secret_key) {
-        vars[secretKeyName] = matched.secret_key;
-      }
-      const merged = mergeEnvVars(lines, vars);
-      const output = serializeEnvFile(merged);
-
-      await Bun.write(targetFile, output);
+      await writeInstanceKeys(cwd, targetFile, matched);
     });
 
     log.info(`Environment variables written to ${displayPath}`);
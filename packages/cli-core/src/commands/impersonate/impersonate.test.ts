@@ -39,6 +39,11 @@ mock.module("../../lib/prompts.ts", () => ({
   editor: async () => "{}",
 }));
 
+const mockAppendAuditEntry = mock();
+mock.module("../../lib/audit-log.ts", () => ({
+  appendAuditEntry: (...args: unknown[]) => mockAppendAuditEntry(...args),
+}));
+
 mock.module("../../lib/spinner.ts", () => ({
   intro: () => {},
   outro: () => {},
@@ -89,6 +94,7 @@ describe("impersonate", () => {
     mockResolveImpersonationTarget.mockResolvedValue("user_2x9k");
     mockConfirm.mockResolvedValue(true);
     mockOpenBrowser.mockResolvedValue({ ok: true, launcher: "open" });
+    mockAppendAuditEntry.mockResolvedValue("/tmp/clerk/audit.jsonl");
     mockBapiRequest.mockResolvedValue({
       status: 200,
       headers: new Headers(),
@@ -106,6 +112,7 @@ describe("impersonate", () => {
     mockBapiRequest.mockReset();
     mockOpenBrowser.mockReset();
     mockConfirm.mockReset();
+    mockAppendAuditEntry.mockReset();
   });
 
   test("hard-fails before any BAPI call when requireLoginEmail rejects", async () => {
@@ -205,6 +212,35 @@ describe("impersonate", () => {
     expect(mockConfirm).not.toHaveBeenCalled();
   });
 
+  test("records an audit entry for the token before opening the browser", async () => {
+    mockOpenBrowser.mockImplementation(async () => {
+      expect(mockAppendAuditEntry).toHaveBeenCalledTimes(1);
+      return { ok: true, launcher: "open" };
+    });
+
+    await impersonate({ user: "user_2x9k", yes: true, open: true });
+
+    expect(mockAppendAuditEntry).toHaveBeenCalledWith({
+      event: "impersonation.created",
+      actor_token_id: "act_1",
+      user_id: "user_2x9k",
+      actor: "cli:admin@example.com",
+      app_id: CTX.appId,
+      instance_id: CTX.instanceId,
+      instance_label: CTX.instanceLabel,
+      expires_in_seconds: 3600,
+      open: true,
+    });
+    expect(captured.err).toContain("Recorded in /tmp/clerk/audit.jsonl");
+  });
+
+  test("no audit entry when the token can't be created", async () => {
+    reject422();
+
+    await expect(impersonate({ user: "user_2x9k", yes: true, print: true })).rejects.toThrow();
+    expect(mockAppendAuditEntry).not.toHaveBeenCalled();
+  });
+
   test("non-TTY stdin in human mode behaves like --print and never prompts", async () => {
     setStdinTTY(false);
 
@@ -1,5 +1,6 @@
 import { bold, cyan, dim } from "../../lib/color.ts";
 import { createActorToken } from "../../lib/actor-tokens.ts";
+import { appendAuditEntry } from "../../lib/audit-log.ts";
 import {
   BapiError,
   BILLING_ERROR_REASON,
@@ -152,6 +153,20 @@ export async function impersonate(options: ImpersonateOptions = {}): Promise<voi
     throw error;
   }
 
+  // Recorded before the URL is shown or opened, so every session a ticket can
+  // start has an entry, whatever happens to the rest of the command.
+  const auditLog = await appendAuditEntry({
+    event: "impersonation.created",
+    actor_token_id: token.id,
+    user_id: userId,
+    actor: actor.sub,
+    app_id: ctx.appId ?? null,
+    instance_id: ctx.instanceId ?? null,
+    instance_label: ctx.instanceLabel ?? null,
+    expires_in_seconds: expiresIn,
+    open: Boolean(options.open && !options.print),
+  });
+
   if (isAgent()) {
     log.data(
       JSON.stringify({
@@ -183,6 +198,7 @@ export async function impersonate(options: ImpersonateOptions = {}): Promise<voi
     ctx.instanceId ? ` --instance ${ctx.instanceId}` : "",
   ].join("");
   log.info(dim(`Revoke with: clerk imp revoke ${token.id}${revokeTarget}`));
+  if (auditLog) log.info(dim(`Recorded in ${auditLog}`));
 
   if (options.print) {
     return;
@@ -1,30 +1,41 @@
-import { createArgument } from "@commander-js/extra-typings";
+import { createArgument, type CommandUnknownOpts } from "@commander-js/extra-typings";
 import type { Program } from "../../cli-program.ts";
 import { parseIntegerOption } from "../../lib/option-parsers.ts";
 import { impersonate } from "./impersonate.ts";
 import { revoke } from "./revoke.ts";
 
-export function registerImpersonate(program: Program): void {
-  const impersonateCommand = program
-    .command("impersonate")
-    .alias("imp")
-    .description("Impersonate a Clerk user")
-    .addArgument(
-      createArgument(
-        "[user]",
-        "User ID (user_...), exact email, or search term to impersonate. Omit to pick interactively.",
-      ),
-    )
-    .option("--secret-key <key>", "Backend API secret key to use")
-    .option("--app <id>", "Application ID to target (works from any directory)")
-    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+/**
+ * Add the options `impersonate()` reads. `clerk users impersonate` registers
+ * the same command under `users`, so both get them from here.
+ */
+export function addImpersonateOptions<T extends CommandUnknownOpts>(command: T): T {
+  command
     .option("--actor <context>", "Extra context appended to the actor stamp: cli:<email>+<context>")
     .option("--expires-in <seconds>", "Actor token lifetime in seconds (default 3600)", (value) =>
       parseIntegerOption(value, "--expires-in", { min: 1 }),
     )
     .option("--open", "Open the sign-in URL in your browser immediately, skipping the prompt")
     .option("--print", "Print the sign-in URL only — no prompt, no browser")
-    .option("--yes", "Skip the impersonation confirmation prompt")
+    .option("--yes", "Skip the impersonation confirmation prompt");
+  return command;
+}
+
+export function registerImpersonate(program: Program): void {
+  const impersonateCommand = addImpersonateOptions(
+    program
+      .command("impersonate")
+      .alias("imp")
+      .description("Impersonate a Clerk user")
+      .addArgument(
+        createArgument(
+          "[user]",
+          "User ID (user_...), exact email, or search term to impersonate. Omit to pick interactively.",
+        ),
+      )
+      .option("--secret-key <key>", "Backend API secret key to use")
+      .option("--app <id>", "Application ID to target (works from any directory)")
+      .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)"),
+  )
     .setExamples([
       { command: "clerk imp", description: "Pick a user interactively and impersonate" },
       { command: "clerk imp user_2x9k", description: "Impersonate a specific user" },
@@ -1,4 +1,5 @@
 import { throwUsageError } from "../../lib/errors.ts";
+import { requireArg } from "../../lib/require-arg.ts";
 import { searchUsers } from "../../lib/users.ts";
 import { isAgent } from "../../mode.ts";
 import { pickUser } from "../users/interactive/pick-user.ts";
@@ -38,10 +39,10 @@ export async function resolveImpersonationTarget(
 ): Promise<string> {
   const secretKey = ctx.secretKey;
   if (user === undefined) {
-    if (isAgent()) {
-      throwUsageError("A user is required in agent mode. Pass it as a positional argument.");
-    }
-    return pickUser({ secretKey, message: "Pick a user to impersonate:" });
+    return requireArg(user, {
+      label: "A user",
+      prompt: () => pickUser({ secretKey, message: "Pick a user to impersonate:" }),
+    });
   }
 
   if (USER_ID_PATTERN.test(user)) {
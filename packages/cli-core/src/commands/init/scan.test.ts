@@ -23,63 +23,63 @@ describe("detectAuthLibraries", () => {
 
   test("detects NextAuth", () => {
     runDetectAuthLibraries({ "next-auth": "5.0.0", next: "15.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("NextAuth");
     expect(output).toContain("clerk.com/docs/migrations/nextauth");
   });
 
   test("detects Auth0 via @auth0/nextjs-auth0", () => {
     runDetectAuthLibraries({ "@auth0/nextjs-auth0": "3.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Auth0");
   });
 
   test("detects Auth0 via auth0 package", () => {
     runDetectAuthLibraries({ auth0: "4.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Auth0");
   });
 
   test("detects Supabase Auth via @supabase/ssr", () => {
     runDetectAuthLibraries({ "@supabase/ssr": "0.5.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Supabase Auth");
   });
 
   test("detects Firebase", () => {
     runDetectAuthLibraries({ firebase: "11.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Firebase");
   });
 
   test("detects Passport.js", () => {
     runDetectAuthLibraries({ passport: "0.7.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Passport.js");
   });
 
   test("detects Better Auth", () => {
     runDetectAuthLibraries({ "better-auth": "1.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Better Auth");
   });
 
   test("detects Kinde", () => {
     runDetectAuthLibraries({ "@kinde-oss/kinde-auth-nextjs": "2.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("Kinde");
   });
 
   test("detects multiple auth libraries", () => {
     runDetectAuthLibraries({ "next-auth": "5.0.0", firebase: "11.0.0" });
-    const output = captured.out;
+    const output = captured.err;
     expect(output).toContain("NextAuth");
     expect(output).toContain("Firebase");
   });
 
   test("does not warn when no auth library found", () => {
     runDetectAuthLibraries({ react: "19.0.0", next: "15.0.0" });
-    expect(captured.out).toBe("");
+    expect(captured.err).toBe("");
   });
 });
 
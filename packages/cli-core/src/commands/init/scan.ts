@@ -66,8 +66,8 @@ export function detectAuthLibraries(deps: Record<string, string>): void {
     const found = scan.packages.some((pkg) => pkg in deps);
     if (!found) continue;
 
-    log.data(yellow(`\n⚠ Detected ${scan.name} in your project.`));
-    log.data(dim(`  Migration guide: ${scan.docsUrl}`));
+    log.warn(`\n⚠ Detected ${scan.name} in your project.`);
+    log.info(dim(`  Migration guide: ${scan.docsUrl}`));
   }
 }
 
@@ -171,10 +171,10 @@ export async function scanForIssues(cwd: string, frameworkDep: string): Promise<
 export function printFindings(findings: ScanFinding[]): void {
   if (findings.length === 0) return;
 
-  log.data(dim("\n  Recommendations:"));
+  log.info(dim("\n  Recommendations:"));
   for (const f of findings) {
     const location = `${cyan(f.file)}:${f.line}`;
-    log.data(`  ${yellow("⚠")} ${location} ${dim("—")} ${f.message}`);
-    if (f.docsUrl) log.data(`    ${dim(f.docsUrl)}`);
+    log.info(`  ${yellow("⚠")} ${location} ${dim("—")} ${f.message}`);
+    if (f.docsUrl) log.info(`    ${dim(f.docsUrl)}`);
   }
 }
@@ -0,0 +1,141 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, readFile, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { invitationsExport, summarizeInvitations } = await import("./export.ts");
+
+const HOUR = 3_600_000;
+const T0 = Date.UTC(2026, 0, 1);
+
+function invitation(id: string, email: string, status: string, acceptedAfter?: number) {
+  return {
+    id,
+    email_address: email,
+    status,
+    created_at: T0,
+    updated_at: T0 + (acceptedAfter ?? 0),
+  };
+}
+
+const INVITATIONS = [
+  invitation("inv_1", "a@acme.test", "accepted", 2 * HOUR),
+  invitation("inv_2", "b@acme.test", "accepted", 4 * HOUR),
+  invitation("inv_3", "c@acme.test", "expired"),
+  invitation("inv_4", "d@Example.com", "accepted", 30 * HOUR),
+  invitation("inv_5", "e@example.com", "pending"),
+];
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body };
+}
+
+describe("summarizeInvitations", () => {
+  test("computes the funnel over resolved invitations", () => {
+    expect(summarizeInvitations(INVITATIONS)).toEqual({
+      total: 5,
+      by_status: { accepted: 3, expired: 1, pending: 1 },
+      acceptance_rate: 0.75,
+      median_time_to_accept_ms: 4 * HOUR,
+      top_domains: [
+        { domain: "acme.test", invited: 3, accepted: 2 },
+        { domain: "example.com", invited: 2, accepted: 1 },
+      ],
+    });
+  });
+
+  test("has no rate or median while everything is pending", () => {
+    const summary = summarizeInvitations([invitation("inv_1", "a@acme.test", "pending")]);
+    expect(summary.acceptance_rate).toBeNull();
+    expect(summary.median_time_to_accept_ms).toBeNull();
+  });
+});
+
+describe("invitations export", () => {
+  const captured = useCaptureLog();
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-invitations-export-"));
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockResolvedValue(respond(INVITATIONS));
+  });
+
+  afterEach(async () => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("prints CSV to stdout, filtered by --status", async () => {
+    await invitationsExport({ status: "accepted" });
+
+    const [call] = mockBapiRequest.mock.calls[0] as [{ path: string }];
+    expect(call.path).toBe("/invitations?limit=100&offset=0&status=accepted");
+    const lines = captured.out.split("\n");
+    expect(lines[0]).toBe("id,email,status,created_at,updated_at,expires_at");
+    expect(lines[1]).toBe(
+      "inv_1,a@acme.test,accepted,2026-01-01T00:00:00.000Z,2026-01-01T02:00:00.000Z,",
+    );
+  });
+
+  test("follows pagination until a short page", async () => {
+    const fullPage = Array.from({ length: 100 }, (_, i) =>
+      invitation(`inv_${i}`, `u${i}@acme.test`, "pending"),
+    );
+    mockBapiRequest
+      .mockResolvedValueOnce(respond({ data: fullPage, total_count: 101 }))
+      .mockResolvedValueOnce(respond({ data: [INVITATIONS[0]], total_count: 101 }));
+
+    await invitationsExport({ json: true });
+
+    expect(JSON.parse(captured.out)).toHaveLength(101);
+    expect(mockBapiRequest).toHaveBeenCalledTimes(2);
+  });
+
+  test("--analytics prints the summary instead of rows", async () => {
+    await invitationsExport({ analytics: true });
+
+    expect(captured.out).toContain("Acceptance rate: 75.0%");
+    expect(captured.out).toContain("Median time to accept: 4h 0m");
+    expect(captured.out).toContain("acme.test    3 invited, 2 accepted");
+    expect(captured.out).not.toContain("inv_1");
+  });
+
+  test("--file writes the rows and reports the summary as JSON", async () => {
+    const file = join(tempDir, "invitations.json");
+    await invitationsExport({ file, analytics: true, json: true });
+
+    expect(JSON.parse(await readFile(file, "utf-8"))).toHaveLength(5);
+    const output = JSON.parse(captured.out) as { invitations: number; analytics: unknown };
+    expect(output.invitations).toBe(5);
+    expect(output.analytics).toMatchObject({ total: 5, acceptance_rate: 0.75 });
+  });
+});
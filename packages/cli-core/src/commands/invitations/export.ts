@@ -0,0 +1,192 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { formatCsv } from "../../lib/csv.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { listInvitations, type BapiInvitation } from "../../lib/invitations.ts";
+import { log } from "../../lib/log.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { isAgent } from "../../mode.ts";
+
+export type InvitationsExportOptions = {
+  status?: string;
+  file?: string;
+  analytics?: boolean;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type InvitationRow = {
+  id: string;
+  email: string;
+  status: string;
+  created_at: string;
+  updated_at: string;
+  expires_at: string;
+};
+
+export type InvitationAnalytics = {
+  total: number;
+  by_status: Record<string, number>;
+  /** Accepted share of resolved (non-pending) invitations, or `null` when none resolved. */
+  acceptance_rate: number | null;
+  median_time_to_accept_ms: number | null;
+  top_domains: { domain: string; invited: number; accepted: number }[];
+};
+
+const CSV_HEADER: (keyof InvitationRow)[] = [
+  "id",
+  "email",
+  "status",
+  "created_at",
+  "updated_at",
+  "expires_at",
+];
+
+const TOP_DOMAINS = 5;
+
+function isoOrEmpty(ms: number | null | undefined): string {
+  return typeof ms === "number" ? new Date(ms).toISOString() : "";
+}
+
+function toRow(invitation: BapiInvitation): InvitationRow {
+  return {
+    id: invitation.id,
+    email: invitation.email_address,
+    status: invitation.status,
+    created_at: isoOrEmpty(invitation.created_at),
+    updated_at: isoOrEmpty(invitation.updated_at),
+    expires_at: isoOrEmpty(invitation.expires_at),
+  };
+}
+
+function median(values: number[]): number | null {
+  if (values.length === 0) return null;
+  const sorted = [...values].sort((a, b) => a - b);
+  const middle = Math.floor(sorted.length / 2);
+  return sorted.length % 2 ? sorted[middle]! : (sorted[middle - 1]! + sorted[middle]!) / 2;
+}
+
+/**
+ * Funnel numbers for a set of invitations. Invitations carry no acceptance
+ * timestamp, so time-to-accept is measured to `updated_at`, which the API sets
+ * when an invitation is accepted. Domains are the invitees' email domains.
+ */
+export function summarizeInvitations(invitations: BapiInvitation[]): InvitationAnalytics {
+  const byStatus: Record<string, number> = {};
+  const domains = new Map<string, { invited: number; accepted: number }>();
+  const acceptDelays: number[] = [];
+
+  for (const invitation of invitations) {
+    byStatus[invitation.status] = (byStatus[invitation.status] ?? 0) + 1;
+    const accepted = invitation.status === "accepted";
+    const domain = invitation.email_address.split("@").pop()!.toLowerCase();
+    const entry = domains.get(domain) ?? { invited: 0, accepted: 0 };
+    entry.invited += 1;
+    if (accepted) entry.accepted += 1;
+    domains.set(domain, entry);
+    if (accepted && invitation.created_at && invitation.updated_at) {
+      acceptDelays.push(invitation.updated_at - invitation.created_at);
+    }
+  }
+
+  const resolved = invitations.length - (byStatus.pending ?? 0);
+  return {
+    total: invitations.length,
+    by_status: byStatus,
+    acceptance_rate: resolved > 0 ? (byStatus.accepted ?? 0) / resolved : null,
+    median_time_to_accept_ms: median(acceptDelays),
+    top_domains: [...domains]
+      .map(([domain, counts]) => ({ domain, ...counts }))
+      .sort((a, b) => b.invited - a.invited || a.domain.localeCompare(b.domain))
+      .slice(0, TOP_DOMAINS),
+  };
+}
+
+function formatElapsed(ms: number): string {
+  const minutes = Math.round(ms / 60_000);
+  if (minutes < 60) return `${minutes}m`;
+  const hours = Math.floor(minutes / 60);
+  if (hours < 24) return `${hours}h ${minutes % 60}m`;
+  return `${Math.floor(hours / 24)}d ${hours % 24}h`;
+}
+
+export function formatAnalytics(summary: InvitationAnalytics): string[] {
+  const lines = [`Invitations: ${cyan(String(summary.total))}`];
+  for (const [status, count] of Object.entries(summary.by_status)) {
+    lines.push(`  ${status.padEnd(10)} ${count}`);
+  }
+  const resolved = summary.total - (summary.by_status.pending ?? 0);
+  const rate = summary.acceptance_rate;
+  lines.push(
+    rate === null
+      ? `Acceptance rate: ${dim("n/a (nothing resolved yet)")}`
+      : `Acceptance rate: ${(rate * 100).toFixed(1)}% ${dim(`of ${resolved} resolved`)}`,
+  );
+  lines.push(
+    `Median time to accept: ${
+      summary.median_time_to_accept_ms === null
+        ? dim("n/a")
+        : formatElapsed(summary.median_time_to_accept_ms)
+    }`,
+  );
+  if (summary.top_domains.length > 0) {
+    lines.push("Top domains:");
+    const width = Math.max(...summary.top_domains.map((entry) => entry.domain.length));
+    for (const entry of summary.top_domains) {
+      lines.push(
+        `  ${entry.domain.padEnd(width)}  ${entry.invited} invited, ${entry.accepted} accepted`,
+      );
+    }
+  }
+  return lines;
+}
+
+/**
+ * Export the instance's invitations with their status and timestamps, as CSV
+ * or JSON, optionally with a funnel summary (`--analytics`).
+ */
+export async function invitationsExport(options: InvitationsExportOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  if (!json && options.file) intro("Exporting invitations");
+  const invitations = await withSpinner("Fetching invitations...", () =>
+    withApiContext(listInvitations(secretKey, options.status), "Failed to list invitations"),
+  );
+  const rows = invitations.map(toRow);
+  const analytics = options.analytics ? summarizeInvitations(invitations) : undefined;
+
+  if (!options.file) {
+    if (analytics) {
+      log.data(json ? JSON.stringify(analytics, null, 2) : formatAnalytics(analytics).join("\n"));
+    } else {
+      log.data(json ? JSON.stringify(rows, null, 2) : formatCsv(CSV_HEADER, rows).trimEnd());
+    }
+    return;
+  }
+
+  // The file's format follows its extension; --json only changes what stdout reports.
+  const asJson = options.file.toLowerCase().endsWith(".json");
+  await Bun.write(
+    options.file,
+    asJson ? `${JSON.stringify(rows, null, 2)}\n` : formatCsv(CSV_HEADER, rows),
+  );
+  if (json) {
+    log.data(
+      JSON.stringify(
+        { file: options.file, invitations: rows.length, ...(analytics && { analytics }) },
+        null,
+        2,
+      ),
+    );
+    return;
+  }
+  if (analytics) for (const line of formatAnalytics(analytics)) log.info(line);
+  await outro(`Wrote ${rows.length} invitation(s) to ${options.file}`);
+}
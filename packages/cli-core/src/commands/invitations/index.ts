@@ -0,0 +1,42 @@
+import { createOption } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { INVITATION_STATUSES } from "../../lib/invitations.ts";
+import { invitationsExport } from "./export.ts";
+
+export function registerInvitations(program: Program): void {
+  const invitations = program
+    .command("invitations")
+    .description("Work with application invitations")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  invitations
+    .command("export")
+    .description("Export invitations with their status and timestamps")
+    .addOption(
+      createOption("--status <status>", "Only invitations in this status").choices(
+        INVITATION_STATUSES,
+      ),
+    )
+    .option("--file <path>", "Write to a file (.json for JSON, otherwise CSV) instead of stdout")
+    .option("--analytics", "Summarize acceptance rate, time to accept, and top email domains")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk invitations export --file invitations.csv",
+        description: "Export every invitation with status and timestamps",
+      },
+      {
+        command: "clerk invitations export --analytics",
+        description: "Print the invitation funnel summary",
+      },
+      {
+        command: "clerk invitations export --status accepted --json",
+        description: "Print accepted invitations as a JSON array",
+      },
+    ])
+    .action((_opts, cmd) =>
+      invitationsExport(cmd.optsWithGlobals() as Parameters<typeof invitationsExport>[0]),
+    );
+}
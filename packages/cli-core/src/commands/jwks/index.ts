@@ -0,0 +1,40 @@
+import type { Program } from "../../cli-program.ts";
+import { jwksList } from "./keys.ts";
+import { jwksVerify } from "./verify.ts";
+
+export function registerJwks(program: Program): void {
+  const jwks = program
+    .command("jwks")
+    .description("Inspect the instance's token signing keys")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  jwks
+    .command("list")
+    .description("List the instance's public signing keys with their sizes and thumbprints")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk jwks list", description: "Show the keys session tokens are signed with" },
+      {
+        command: "clerk jwks list --instance prod --json",
+        description: "Production keys as JSON, e.g. to pin thumbprints",
+      },
+    ])
+    .action((_opts, cmd) => jwksList(cmd.optsWithGlobals() as Parameters<typeof jwksList>[0]));
+
+  jwks
+    .command("verify")
+    .description("Check that a JWKS endpoint you serve matches the instance's keys")
+    .argument("[url]", "URL of the JWKS your backend serves or caches")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk jwks verify https://api.example.com/.well-known/jwks.json --instance prod",
+        description: "Catch a stale cached or pinned JWKS before tokens start failing",
+      },
+    ])
+    .action((url, _opts, cmd) =>
+      jwksVerify(url, cmd.optsWithGlobals() as Parameters<typeof jwksVerify>[1]),
+    );
+}
@@ -0,0 +1,130 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan } from "../../lib/color.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+
+export type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type JwksListOptions = TargetingOptions & {
+  json?: boolean;
+};
+
+export type Jwk = {
+  kid?: string;
+  kty?: string;
+  alg?: string;
+  use?: string;
+  n?: string;
+  e?: string;
+  crv?: string;
+  x?: string;
+  y?: string;
+};
+
+/** A JWK with the facts worth comparing and showing. */
+export type KeySummary = {
+  kid: string | null;
+  kty: string | null;
+  alg: string | null;
+  use: string | null;
+  /** Key size in bits: the modulus for RSA, the curve for EC and OKP. */
+  bits: number | null;
+  /** RFC 7638 SHA-256 thumbprint, the key's identity regardless of `kid`. */
+  thumbprint: string | null;
+};
+
+/** The members RFC 7638 hashes for each key type, in lexicographic order. */
+const THUMBPRINT_MEMBERS: Record<string, (keyof Jwk)[]> = {
+  RSA: ["e", "kty", "n"],
+  EC: ["crv", "kty", "x", "y"],
+  OKP: ["crv", "kty", "x"],
+};
+
+const CURVE_BITS: Record<string, number> = {
+  "P-256": 256,
+  "P-384": 384,
+  "P-521": 521,
+  Ed25519: 256,
+  Ed448: 448,
+};
+
+export function jwkThumbprint(jwk: Jwk): string | null {
+  const members = jwk.kty ? THUMBPRINT_MEMBERS[jwk.kty] : undefined;
+  if (!members || members.some((member) => typeof jwk[member] !== "string")) return null;
+  // Members are written in order with no whitespace, as the RFC requires.
+  const canonical = JSON.stringify(Object.fromEntries(members.map((m) => [m, jwk[m]])));
+  return new Bun.CryptoHasher("sha256").update(canonical).digest("base64url");
+}
+
+function keyBits(jwk: Jwk): number | null {
+  if (jwk.kty === "RSA" && jwk.n) return Buffer.from(jwk.n, "base64url").length * 8;
+  return (jwk.crv && CURVE_BITS[jwk.crv]) || null;
+}
+
+export function summarizeKey(jwk: Jwk): KeySummary {
+  return {
+    kid: jwk.kid ?? null,
+    kty: jwk.kty ?? null,
+    alg: jwk.alg ?? null,
+    use: jwk.use ?? null,
+    bits: keyBits(jwk),
+    thumbprint: jwkThumbprint(jwk),
+  };
+}
+
+/** The `keys` of a JWKS document, or `undefined` when it isn't one. */
+export function parseJwks(body: unknown): Jwk[] | undefined {
+  if (!isRecord(body) || !Array.isArray(body.keys)) return undefined;
+  return body.keys.filter(isRecord) as Jwk[];
+}
+
+export function resolveJwksSecretKey(options: TargetingOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+/** The instance's public signing keys, as its Frontend API also serves them. */
+export async function fetchInstanceJwks(secretKey: string): Promise<Jwk[]> {
+  const response = await bapiRequest({ method: "GET", path: "/jwks", secretKey });
+  return parseJwks(response.body) ?? [];
+}
+
+export const KEY_COLUMNS: TableColumn<KeySummary>[] = [
+  { key: "kid", header: "KEY ID", value: (key) => key.kid ?? undefined, style: cyan },
+  { key: "alg", header: "ALG", value: (key) => key.alg ?? key.kty ?? undefined },
+  { key: "bits", header: "BITS", value: (key) => (key.bits ? String(key.bits) : undefined) },
+  { key: "use", header: "USE", value: (key) => key.use ?? undefined },
+  { key: "thumbprint", header: "THUMBPRINT", value: (key) => key.thumbprint ?? undefined },
+];
+
+export async function jwksList(options: JwksListOptions = {}): Promise<void> {
+  const secretKey = await resolveJwksSecretKey(options);
+  const jwks = await withSpinner("Fetching signing keys...", () =>
+    withApiContext(fetchInstanceJwks(secretKey), "Failed to fetch the instance's JWKS"),
+  );
+  const keys = jwks.map(summarizeKey);
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(keys, null, 2));
+    return;
+  }
+
+  if (keys.length === 0) {
+    log.warn("The instance has no signing keys.");
+    return;
+  }
+  await printOutput(renderTable(keys, KEY_COLUMNS), { page: false });
+}
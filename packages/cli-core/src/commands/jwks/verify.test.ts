@@ -0,0 +1,134 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+const mockLoggedFetch = mock();
+mock.module("../../lib/fetch.ts", () => ({
+  loggedFetch: (...args: unknown[]) => mockLoggedFetch(...args),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { jwkThumbprint, summarizeKey } = await import("./keys.ts");
+const { compareKeys, jwksVerify } = await import("./verify.ts");
+
+/** The example key from RFC 7638, section 3.1. */
+const RFC_KEY = {
+  kty: "RSA",
+  kid: "ins_current",
+  alg: "RS256",
+  use: "sig",
+  e: "AQAB",
+  n:
+    "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc" +
+    "_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQ" +
+    "R0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bF" +
+    "TWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+};
+const OTHER_KEY = { kty: "RSA", kid: "ins_old", e: "AQAB", n: "sXchDaQebHnPiGvyDOAT4saGEUetSyo9" };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("jwkThumbprint", () => {
+  test("matches the RFC 7638 example", () => {
+    expect(jwkThumbprint(RFC_KEY)).toBe("NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs");
+    expect(summarizeKey(RFC_KEY).bits).toBe(2048);
+  });
+
+  test("is null for keys it can't canonicalize", () => {
+    expect(jwkThumbprint({ kty: "oct" })).toBeNull();
+    expect(jwkThumbprint({ kty: "RSA", e: "AQAB" })).toBeNull();
+  });
+});
+
+describe("compareKeys", () => {
+  test("classifies every instance and served key", () => {
+    const instance = [RFC_KEY, { ...OTHER_KEY, kid: "ins_next" }, OTHER_KEY].map(summarizeKey);
+    const served = [RFC_KEY, { ...RFC_KEY, kid: "ins_old" }, { ...OTHER_KEY, kid: "stale" }].map(
+      summarizeKey,
+    );
+
+    expect(compareKeys(instance, served).map((check) => [check.kid, check.status])).toEqual([
+      ["ins_current", "ok"],
+      ["ins_next", "missing"],
+      ["ins_old", "mismatch"],
+      ["stale", "extra"],
+    ]);
+  });
+});
+
+describe("jwks verify", () => {
+  const captured = useCaptureLog();
+  const originalExitCode = process.exitCode;
+  const url = "https://api.example.com/.well-known/jwks.json";
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockLoggedFetch.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = originalExitCode;
+  });
+
+  test("passes when the endpoint serves every instance key", async () => {
+    mockBapiRequest.mockResolvedValue(respond({ keys: [RFC_KEY] }));
+    mockLoggedFetch.mockResolvedValue(Response.json({ keys: [RFC_KEY, OTHER_KEY] }));
+
+    await jwksVerify(url, { json: true });
+
+    expect(mockLoggedFetch.mock.calls[0]![0]).toBe(url);
+    const result = JSON.parse(captured.out) as { ok: boolean; keys: { status: string }[] };
+    expect(result.ok).toBe(true);
+    expect(result.keys.map((key) => key.status)).toEqual(["ok", "extra"]);
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("exits non-zero when an instance key is missing", async () => {
+    mockBapiRequest.mockResolvedValue(respond({ keys: [RFC_KEY, OTHER_KEY] }));
+    mockLoggedFetch.mockResolvedValue(Response.json({ keys: [RFC_KEY] }));
+
+    await jwksVerify(url);
+
+    expect(captured.err).toContain("instance key not served");
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("rejects a response that isn't a JWKS", async () => {
+    mockBapiRequest.mockResolvedValue(respond({ keys: [RFC_KEY] }));
+    mockLoggedFetch.mockResolvedValue(Response.json({ status: "ok" }));
+
+    await expect(jwksVerify(url)).rejects.toThrow(/"keys" array/);
+  });
+
+  test("rejects URLs that aren't http(s)", async () => {
+    await expect(jwksVerify("file:///etc/jwks.json")).rejects.toThrow(/http\(s\) URL/);
+    expect(mockLoggedFetch).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,150 @@
+import { cyan, dim, green, red, yellow } from "../../lib/color.ts";
+import {
+  CliError,
+  ERROR_CODE,
+  EXIT_CODE,
+  errorMessage,
+  throwUsageError,
+  withApiContext,
+} from "../../lib/errors.ts";
+import { loggedFetch } from "../../lib/fetch.ts";
+import { log } from "../../lib/log.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { isAgent } from "../../mode.ts";
+import {
+  fetchInstanceJwks,
+  parseJwks,
+  resolveJwksSecretKey,
+  summarizeKey,
+  type KeySummary,
+  type TargetingOptions,
+} from "./keys.ts";
+
+export type JwksVerifyOptions = TargetingOptions & {
+  json?: boolean;
+};
+
+/**
+ * - `ok`: served with the instance's key material.
+ * - `missing`: the instance key isn't served; tokens it signs will fail.
+ * - `mismatch`: the `kid` is served with different key material.
+ * - `extra`: served, but not an instance key (a stale pinned key, or another issuer's).
+ */
+export type KeyCheckStatus = "ok" | "missing" | "mismatch" | "extra";
+
+export type KeyCheck = {
+  kid: string | null;
+  thumbprint: string | null;
+  status: KeyCheckStatus;
+};
+
+/**
+ * Compare the keys a customer endpoint serves against the instance's. Keys
+ * are matched by `kid` and compared by thumbprint, so a re-encoded copy of the
+ * same key still matches.
+ */
+export function compareKeys(instance: KeySummary[], served: KeySummary[]): KeyCheck[] {
+  const servedByKid = new Map(served.map((key) => [key.kid, key]));
+  const checks: KeyCheck[] = instance.map((key) => {
+    const match = servedByKid.get(key.kid);
+    const status = !match ? "missing" : match.thumbprint === key.thumbprint ? "ok" : "mismatch";
+    return { kid: key.kid, thumbprint: key.thumbprint, status };
+  });
+  const instanceKids = new Set(instance.map((key) => key.kid));
+  for (const key of served) {
+    if (!instanceKids.has(key.kid)) {
+      checks.push({ kid: key.kid, thumbprint: key.thumbprint, status: "extra" });
+    }
+  }
+  return checks;
+}
+
+function formatCheck(check: KeyCheck): string {
+  const kid = check.kid ?? "(no kid)";
+  switch (check.status) {
+    case "ok":
+      return `${green("✓")} ${kid} ${dim(check.thumbprint ?? "")}`;
+    case "missing":
+      return `${red("✗")} ${kid} ${dim("(instance key not served)")}`;
+    case "mismatch":
+      return `${red("✗")} ${kid} ${dim("(served with different key material)")}`;
+    case "extra":
+      return `${yellow("!")} ${kid} ${dim("(served, but not an instance key)")}`;
+  }
+}
+
+async function fetchServedJwks(url: string): Promise<KeySummary[]> {
+  const response = await loggedFetch(url, {
+    tag: "jwks",
+    method: "GET",
+    headers: { Accept: "application/json" },
+  });
+  if (!response.ok) {
+    throw new CliError(`${url} answered ${response.status} ${response.statusText}.`);
+  }
+  let body: unknown;
+  try {
+    body = await response.json();
+  } catch (error) {
+    throw new CliError(`${url} did not return JSON: ${errorMessage(error)}`, {
+      code: ERROR_CODE.INVALID_JSON,
+    });
+  }
+  const keys = parseJwks(body);
+  if (!keys) throw new CliError(`${url} did not return a JWKS (an object with a "keys" array).`);
+  return keys.map(summarizeKey);
+}
+
+/**
+ * Check that a JWKS endpoint served by the customer's backend (a proxy, a
+ * cache, or a pinned copy) carries every current instance key. Exits non-zero
+ * when a key is missing or differs, so it can gate a deploy.
+ */
+export async function jwksVerify(
+  urlArg: string | undefined,
+  options: JwksVerifyOptions = {},
+): Promise<void> {
+  const url = await requireArg(urlArg, { label: "JWKS URL" });
+  if (!URL.canParse(url) || !/^https?:$/.test(new URL(url).protocol)) {
+    throwUsageError(`Expected an http(s) URL, got \`${url}\`.`);
+  }
+  const secretKey = await resolveJwksSecretKey(options);
+
+  const [instance, served] = await withSpinner("Fetching both key sets...", () =>
+    Promise.all([
+      withApiContext(fetchInstanceJwks(secretKey), "Failed to fetch the instance's JWKS"),
+      fetchServedJwks(url),
+    ]),
+  );
+  const checks = compareKeys(instance.map(summarizeKey), served);
+  const failing = checks.filter(
+    (check) => check.status === "missing" || check.status === "mismatch",
+  );
+  const ok = instance.length > 0 && failing.length === 0;
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify({ url, ok, keys: checks }, null, 2));
+    if (!ok) process.exitCode = EXIT_CODE.GENERAL;
+    return;
+  }
+
+  intro(`Signing keys served by ${cyan(url)}`);
+  for (const check of checks) log.info(formatCheck(check));
+  if (checks.some((check) => check.status === "extra")) {
+    log.info(dim("Extra keys are harmless unless they are stale pins of rotated instance keys."));
+  }
+  if (failing.length > 0) {
+    log.info(
+      dim("Refresh the cached or pinned JWKS from the instance; `clerk jwks list` shows it."),
+    );
+  }
+  await outro(
+    ok
+      ? "The endpoint serves every instance key"
+      : failing.length
+        ? `${failing.length} instance key(s) missing or different`
+        : "The instance has no signing keys to compare",
+  );
+  if (!ok) process.exitCode = EXIT_CODE.GENERAL;
+}
@@ -0,0 +1,52 @@
+import type { Program } from "../../cli-program.ts";
+import { parseIntegerOption } from "../../lib/option-parsers.ts";
+import {
+  DEFAULT_HOST,
+  DEFAULT_INTERVAL,
+  DEFAULT_PORT,
+  METRICS_INCLUDES,
+  metricsServe,
+} from "./serve.ts";
+
+export function registerMetrics(program: Program): void {
+  const metrics = program
+    .command("metrics")
+    .description("Expose instance metrics for monitoring")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  metrics
+    .command("serve")
+    .description("Serve instance gauges as Prometheus metrics on /metrics")
+    .option("--port <number>", `Port to listen on (default ${DEFAULT_PORT})`, (value) =>
+      parseIntegerOption(value, "--port", { min: 1, max: 65535 }),
+    )
+    .option("--host <host>", `Address to bind (default ${DEFAULT_HOST}; 0.0.0.0 for all)`)
+    .option(
+      "--interval <seconds>",
+      `Seconds between refreshes (10+, default ${DEFAULT_INTERVAL})`,
+      (value) => parseIntegerOption(value, "--interval", { min: 10 }),
+    )
+    .option(
+      "--include <list>",
+      `Gauges that page through records, comma-separated (${METRICS_INCLUDES.join(", ")}, or all)`,
+    )
+    .setExamples([
+      {
+        command: "clerk metrics serve --port 9090",
+        description: "Serve metrics for a local Prometheus to scrape",
+      },
+      {
+        command: "clerk metrics serve --host 0.0.0.0 --interval 300 --instance prod",
+        description: "Serve production metrics to other hosts, refreshed every 5 minutes",
+      },
+      {
+        command: "clerk metrics serve --include invitations --interval 600",
+        description: "Add the pending invitations gauge",
+      },
+    ])
+    .action((_opts, cmd) =>
+      metricsServe(cmd.optsWithGlobals() as Parameters<typeof metricsServe>[0]),
+    );
+}
@@ -0,0 +1,120 @@
+import { test, expect, describe, afterEach, mock } from "bun:test";
+import { DryRunRequest } from "../../lib/dry-run.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+const { GAUGES, formatMetrics, parseMetricsIncludes, refreshGauges, selectGauges } = await import(
+  "./serve.ts"
+);
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+function respondByPath(path: string) {
+  if (path === "/users/count") return respond({ object: "total_count", total_count: 42 });
+  if (path.startsWith("/organizations?")) return respond({ data: [], total_count: 3 });
+  if (path.startsWith("/clients?")) {
+    return respond([
+      { id: "client_1", sessions: [{ status: "active" }, { status: "ended" }] },
+      { id: "client_2", sessions: [{ status: "active" }] },
+    ]);
+  }
+  if (path.startsWith("/invitations?")) return respond({ data: [{ id: "inv_1" }], total_count: 5 });
+  throw new Error(`unexpected path ${path}`);
+}
+
+describe("refreshGauges", () => {
+  useCaptureLog();
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+  });
+
+  test("reads every gauge", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => respondByPath(path));
+
+    const snapshot = await refreshGauges("sk_test_123");
+
+    expect(Object.fromEntries(snapshot.values)).toEqual({
+      clerk_users: 42,
+      clerk_organizations: 3,
+      clerk_sessions_active: 2,
+      clerk_invitations_pending: 5,
+    });
+    expect(snapshot.failed.size).toBe(0);
+  });
+
+  test("counts pending invitations by listing them when the API returns no total", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) =>
+      path.startsWith("/invitations?") ? respond([{ id: "inv_1" }]) : respondByPath(path),
+    );
+
+    const snapshot = await refreshGauges("sk_test_123");
+
+    expect(snapshot.values.get("clerk_invitations_pending")).toBe(1);
+  });
+
+  test("keeps the previous value of a gauge that fails", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path === "/users/count") throw new Error("boom");
+      return respondByPath(path);
+    });
+
+    const snapshot = await refreshGauges("sk_test_123", new Map([["clerk_users", 40]]));
+
+    expect(snapshot.values.get("clerk_users")).toBe(40);
+    expect([...snapshot.failed]).toEqual(["clerk_users"]);
+  });
+
+  test("rethrows a --dry-run stop instead of counting it as a failure", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path === "/users/count") throw new DryRunRequest("GET", "https://api.clerk.com/v1");
+      return respondByPath(path);
+    });
+
+    await expect(refreshGauges("sk_test_123")).rejects.toBeInstanceOf(DryRunRequest);
+  });
+});
+
+describe("selectGauges", () => {
+  test("leaves out the scanning gauges unless --include names them", () => {
+    const names = (include?: string) =>
+      selectGauges(parseMetricsIncludes(include)).map(({ name }) => name);
+
+    expect(names()).toEqual(["clerk_users", "clerk_organizations"]);
+    expect(names("invitations")).toEqual([
+      "clerk_users",
+      "clerk_organizations",
+      "clerk_invitations_pending",
+    ]);
+    expect(names("all")).toHaveLength(GAUGES.length);
+  });
+
+  test("rejects unknown --include values", () => {
+    expect(() => parseMetricsIncludes("sessions,payments")).toThrow(/Unknown --include value/);
+  });
+});
+
+describe("formatMetrics", () => {
+  test("renders gauges in the Prometheus text format", () => {
+    const text = formatMetrics({
+      values: new Map(GAUGES.map(({ name }) => [name, name === "clerk_users" ? 42 : undefined])),
+      failed: new Set(["clerk_organizations"]),
+      refreshedAt: 1_700_000_000_000,
+      durationMs: 250,
+    });
+
+    expect(text).toContain("# TYPE clerk_users gauge\nclerk_users 42\n");
+    expect(text).not.toContain("clerk_organizations ");
+    expect(text).toContain('clerk_exporter_refresh_success{gauge="clerk_users"} 1');
+    expect(text).toContain('clerk_exporter_refresh_success{gauge="clerk_organizations"} 0');
+    expect(text).toContain("clerk_exporter_last_refresh_timestamp_seconds 1700000000");
+    expect(text).toContain("clerk_exporter_refresh_duration_seconds 0.25");
+    expect(text.endsWith("\n")).toBe(true);
+  });
+});
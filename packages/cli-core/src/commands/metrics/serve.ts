@@ -0,0 +1,219 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { dim } from "../../lib/color.ts";
+import { bestEffort } from "../../lib/dry-run.ts";
+import { errorMessage, throwUsageError } from "../../lib/errors.ts";
+import { countInvitations } from "../../lib/invitations.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { countOrganizations } from "../../lib/organizations.ts";
+import { listClients } from "../../lib/sessions.ts";
+
+export type MetricsServeOptions = {
+  port?: number;
+  host?: string;
+  interval?: number;
+  include?: string;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export const DEFAULT_PORT = 9090;
+export const DEFAULT_HOST = "127.0.0.1";
+/** Seconds between refreshes. Each one costs a few BAPI requests, plus any `--include` scans. */
+export const DEFAULT_INTERVAL = 60;
+
+/**
+ * Gauges left out unless `--include` names them, because a refresh may have
+ * to page through every record behind them.
+ */
+export const METRICS_INCLUDES = ["sessions", "invitations"] as const;
+
+export type MetricsInclude = (typeof METRICS_INCLUDES)[number];
+
+/** BAPI's MaxLimit for `GET /clients`. */
+const CLIENTS_PAGE_SIZE = 500;
+
+type Gauge = {
+  name: string;
+  help: string;
+  read: (secretKey: string) => Promise<number>;
+  /** The `--include` value that adds this gauge. Unset gauges are always served. */
+  include?: MetricsInclude;
+};
+
+async function countUsers(secretKey: string): Promise<number> {
+  const response = await bapiRequest({ method: "GET", path: "/users/count", secretKey });
+  const body = response.body;
+  return isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+}
+
+/**
+ * BAPI can only list sessions per user or client, so active sessions are
+ * counted from the sessions embedded in every client.
+ */
+async function countActiveSessions(secretKey: string): Promise<number> {
+  let active = 0;
+  for (let offset = 0; ; offset += CLIENTS_PAGE_SIZE) {
+    const clients = await listClients(secretKey, { limit: CLIENTS_PAGE_SIZE, offset });
+    for (const client of clients) {
+      active += (client.sessions ?? []).filter((session) => session.status === "active").length;
+    }
+    if (clients.length < CLIENTS_PAGE_SIZE) return active;
+  }
+}
+
+export const GAUGES: Gauge[] = [
+  { name: "clerk_users", help: "Users on the instance.", read: countUsers },
+  {
+    name: "clerk_organizations",
+    help: "Organizations on the instance.",
+    read: (secretKey) => countOrganizations(secretKey),
+  },
+  {
+    name: "clerk_sessions_active",
+    help: "Sessions with status active.",
+    read: countActiveSessions,
+    include: "sessions",
+  },
+  {
+    name: "clerk_invitations_pending",
+    help: "Application invitations with status pending.",
+    read: (secretKey) => countInvitations(secretKey, "pending"),
+    include: "invitations",
+  },
+];
+
+/** Parse `--include`, accepting a comma-separated list or `all`. */
+export function parseMetricsIncludes(value: string | undefined): Set<MetricsInclude> {
+  if (!value) return new Set();
+  const includes = new Set<MetricsInclude>();
+  for (const part of value.split(",")) {
+    const item = part.trim();
+    if (!item) continue;
+    if (item === "all") return new Set(METRICS_INCLUDES);
+    if (!(METRICS_INCLUDES as readonly string[]).includes(item)) {
+      throwUsageError(
+        `Unknown --include value "${item}". Expected one of: ${METRICS_INCLUDES.join(", ")}, all.`,
+      );
+    }
+    includes.add(item as MetricsInclude);
+  }
+  return includes;
+}
+
+/** The gauges to serve: the cheap ones, plus those `includes` names. */
+export function selectGauges(includes: Set<MetricsInclude>): Gauge[] {
+  return GAUGES.filter((gauge) => !gauge.include || includes.has(gauge.include));
+}
+
+/** A gauge's last successful value, or `undefined` when it has never been read. */
+export type GaugeValues = Map<string, number | undefined>;
+
+export type Snapshot = {
+  values: GaugeValues;
+  /** Gauges whose latest read failed. Their previous value is kept. */
+  failed: Set<string>;
+  refreshedAt: number;
+  durationMs: number;
+};
+
+/** Read every gauge, keeping the previous value of any that fail. */
+export async function refreshGauges(
+  secretKey: string,
+  previous: GaugeValues = new Map(),
+  gauges = GAUGES,
+): Promise<Snapshot> {
+  const started = Date.now();
+  const values: GaugeValues = new Map();
+  const failed = new Set<string>();
+  await Promise.all(
+    gauges.map(async ({ name, read }) => {
+      const value = await bestEffort(
+        () => read(secretKey),
+        (error) => {
+          failed.add(name);
+          log.warn(`Failed to refresh ${name}: ${errorMessage(error)}`);
+        },
+      );
+      values.set(name, value ?? previous.get(name));
+    }),
+  );
+  return { values, failed, refreshedAt: started, durationMs: Date.now() - started };
+}
+
+/** The Prometheus text exposition format (version 0.0.4). */
+export function formatMetrics(snapshot: Snapshot, gauges = GAUGES): string {
+  const lines: string[] = [];
+  const gauge = (name: string, help: string, samples: [labels: string, value: number][]) => {
+    lines.push(`# HELP ${name} ${help}`, `# TYPE ${name} gauge`);
+    for (const [labels, value] of samples) lines.push(`${name}${labels} ${value}`);
+  };
+
+  for (const { name, help } of gauges) {
+    const value = snapshot.values.get(name);
+    gauge(name, help, value === undefined ? [] : [["", value]]);
+  }
+  gauge(
+    "clerk_exporter_refresh_success",
+    "Whether the latest refresh of a gauge succeeded.",
+    gauges.map(({ name }) => [`{gauge="${name}"}`, snapshot.failed.has(name) ? 0 : 1]),
+  );
+  gauge("clerk_exporter_last_refresh_timestamp_seconds", "When the latest refresh started.", [
+    ["", snapshot.refreshedAt / 1000],
+  ]);
+  gauge("clerk_exporter_refresh_duration_seconds", "How long the latest refresh took.", [
+    ["", snapshot.durationMs / 1000],
+  ]);
+  return `${lines.join("\n")}\n`;
+}
+
+/**
+ * Serve instance gauges on `/metrics` for Prometheus (or the Datadog agent's
+ * OpenMetrics check) to scrape. Values are refreshed on an interval rather
+ * than per scrape, so scrape frequency doesn't drive BAPI traffic.
+ */
+export async function metricsServe(options: MetricsServeOptions = {}): Promise<void> {
+  const gauges = selectGauges(parseMetricsIncludes(options.include));
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const intervalMs = (options.interval ?? DEFAULT_INTERVAL) * 1000;
+
+  let snapshot = await refreshGauges(secretKey, new Map(), gauges);
+  let refreshing = false;
+  setInterval(async () => {
+    // A slow scan of clients must not overlap the next refresh.
+    if (refreshing) return;
+    refreshing = true;
+    try {
+      snapshot = await refreshGauges(secretKey, snapshot.values, gauges);
+      log.debug(`metrics: refreshed in ${snapshot.durationMs}ms`);
+    } finally {
+      refreshing = false;
+    }
+  }, intervalMs);
+
+  const server = Bun.serve({
+    hostname: options.host ?? DEFAULT_HOST,
+    port: options.port ?? DEFAULT_PORT,
+    routes: {
+      "/metrics": {
+        GET: () =>
+          new Response(formatMetrics(snapshot, gauges), {
+            headers: { "Content-Type": "text/plain; version=0.0.4; charset=utf-8" },
+          }),
+      },
+    },
+    fetch: () => new Response("Not found\n", { status: 404 }),
+  });
+
+  log.info(`Serving metrics on http://${server.hostname}:${server.port}/metrics`);
+  log.info(dim(`Refreshing every ${intervalMs / 1000}s. Press Ctrl+C to stop.`));
+
+  // serve never exits 0: it ends via SIGINT (130) or an unrecoverable error (1).
+  await new Promise<never>(() => {});
+}
@@ -50,3 +50,42 @@ export function isKnownDashboardPath(path: string): path is KnownDashboardPath {
   //   "platform/api-keys"    → matches "platform/api-keys"
   return paths.some((known) => path === known || path.startsWith(`${known}/`));
 }
+
+/** Short names accepted in place of a dashboard section (`clerk open orgs`). */
+export const DASHBOARD_PATH_ALIASES: Record<string, string> = {
+  orgs: "organizations",
+};
+
+type ResourcePage = { prefix: string; label: string; hint: string };
+
+/** ID prefixes expected after sections that have per-resource pages. */
+const RESOURCE_ID_PREFIXES: Record<string, ResourcePage> = {
+  users: { prefix: "user_", label: "user", hint: "Use `clerk users open` to search for a user." },
+  organizations: {
+    prefix: "org_",
+    label: "organization",
+    hint: "Use `clerk orgs open <slug>` to open an organization by slug.",
+  },
+};
+
+/**
+ * Turn `clerk open <section> [id]` into a dashboard subpath, expanding aliases
+ * and checking that an ID looks like the section's resource. Returns an error
+ * message instead of a subpath when the ID doesn't match.
+ */
+export function resolveDashboardSubpath(
+  section: string | undefined,
+  id?: string,
+): { subpath: string | undefined } | { error: string } {
+  if (!section) return { subpath: undefined };
+  const [head = "", ...rest] = section.replace(/^\//, "").split("/");
+  const path = [DASHBOARD_PATH_ALIASES[head] ?? head, ...rest].join("/");
+  if (!id) return { subpath: path };
+
+  const resource = RESOURCE_ID_PREFIXES[path];
+  if (resource && !id.startsWith(resource.prefix)) {
+    const expected = `Expected a ${resource.label} ID (${resource.prefix}...), got '${id}'.`;
+    return { error: `${expected} ${resource.hint}` };
+  }
+  return { subpath: `${path}/${id}` };
+}
@@ -2,7 +2,7 @@ import { test, expect, describe, afterEach, beforeEach, mock } from "bun:test";
 import { setMode } from "../../mode.ts";
 import { setCurrentEnv } from "../../lib/environment.ts";
 import { useCaptureLog } from "../../test/lib/stubs.ts";
-import { isKnownDashboardPath } from "./dashboard-paths.ts";
+import { isKnownDashboardPath, resolveDashboardSubpath } from "./dashboard-paths.ts";
 
 const mockResolveProfile = mock();
 const mockOpenBrowser = mock();
@@ -21,7 +21,7 @@ mock.module("../../lib/spinner.ts", () => ({
   pausedOutro: () => {},
 }));
 
-const { openDashboard, buildDashboardUrl } = await import("./index.ts");
+const { openDashboard, openDashboardTarget, buildDashboardUrl } = await import("./index.ts");
 
 const PROFILE = {
   path: "/test/project",
@@ -52,6 +52,30 @@ describe("isKnownDashboardPath", () => {
   });
 });
 
+describe("resolveDashboardSubpath", () => {
+  test("expands the orgs alias", () => {
+    expect(resolveDashboardSubpath("orgs")).toEqual({ subpath: "organizations" });
+    expect(resolveDashboardSubpath("orgs/org_1")).toEqual({ subpath: "organizations/org_1" });
+  });
+
+  test("appends a resource ID to the section", () => {
+    expect(resolveDashboardSubpath("users", "user_1")).toEqual({ subpath: "users/user_1" });
+    expect(resolveDashboardSubpath("orgs", "org_1")).toEqual({ subpath: "organizations/org_1" });
+  });
+
+  test("rejects an ID that doesn't match the section", () => {
+    const result = resolveDashboardSubpath("orgs", "acme");
+    expect(result).toHaveProperty("error");
+    expect((result as { error: string }).error).toContain("clerk orgs open <slug>");
+    expect(resolveDashboardSubpath("users", "org_1")).toHaveProperty("error");
+  });
+
+  test("leaves other sections and missing subpaths alone", () => {
+    expect(resolveDashboardSubpath("api-keys")).toEqual({ subpath: "api-keys" });
+    expect(resolveDashboardSubpath(undefined)).toEqual({ subpath: undefined });
+  });
+});
+
 describe("buildDashboardUrl", () => {
   beforeEach(() => {
     setCurrentEnv("production");
@@ -175,6 +199,22 @@ describe("openDashboard", () => {
     expect(captured.err).not.toContain("not a known dashboard path");
   });
 
+  test("--print with a section and ID emits the resource URL", async () => {
+    mockResolveProfile.mockResolvedValue(PROFILE);
+
+    await openDashboard("orgs", { print: true, id: "org_42" });
+
+    expect(captured.err).not.toContain("not a known dashboard path");
+    expect(captured.out).toBe(
+      "https://dashboard.clerk.com/apps/app_abc123/instances/ins_dev789/organizations/org_42",
+    );
+  });
+
+  test("rejects a mismatched ID before resolving the profile", async () => {
+    await expect(openDashboard("users", { id: "org_42" })).rejects.toThrow(/user ID/);
+    expect(mockResolveProfile).not.toHaveBeenCalled();
+  });
+
   test("unknown subpath warns to stderr but still emits URL", async () => {
     mockResolveProfile.mockResolvedValue(PROFILE);
 
@@ -206,3 +246,44 @@ describe("openDashboard", () => {
     expect(mockOpenBrowser).not.toHaveBeenCalled();
   });
 });
+
+describe("openDashboardTarget", () => {
+  const captured = useCaptureLog();
+  const TARGET = {
+    appId: "app_abc123",
+    appLabel: "Test App",
+    instanceId: "ins_prod456",
+    instanceLabel: "production",
+  };
+
+  beforeEach(() => {
+    setMode("human");
+    setCurrentEnv("production");
+    mockOpenBrowser.mockResolvedValue({ ok: true, launcher: "open" });
+  });
+
+  afterEach(() => {
+    mockOpenBrowser.mockReset();
+  });
+
+  test("opens the deep link for the given instance", async () => {
+    await openDashboardTarget(TARGET, "organizations/org_1", { title: "Opening organization" });
+
+    expect(mockOpenBrowser).toHaveBeenCalledWith(
+      "https://dashboard.clerk.com/apps/app_abc123/instances/ins_prod456/organizations/org_1",
+    );
+    expect(captured.err).toContain("production");
+  });
+
+  test("agent mode: merges details into the JSON", async () => {
+    setMode("agent");
+
+    await openDashboardTarget(TARGET, "organizations/org_1", {
+      title: "Opening organization",
+      details: { orgId: "org_1" },
+    });
+
+    expect(JSON.parse(captured.out)).toMatchObject({ instanceId: "ins_prod456", orgId: "org_1" });
+    expect(mockOpenBrowser).not.toHaveBeenCalled();
+  });
+});
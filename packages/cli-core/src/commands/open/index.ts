@@ -1,17 +1,19 @@
 import { createArgument } from "@commander-js/extra-typings";
 import type { Program } from "../../cli-program.ts";
 import { resolveProfile } from "../../lib/config.ts";
-import { CliError, ERROR_CODE } from "../../lib/errors.ts";
+import { CliError, ERROR_CODE, throwUsageError } from "../../lib/errors.ts";
 import { getDashboardUrl } from "../../lib/environment.ts";
 import { openBrowser } from "../../lib/open.ts";
 import { log } from "../../lib/log.ts";
 import { bold, cyan, dim } from "../../lib/color.ts";
 import { intro, outro } from "../../lib/spinner.ts";
 import { isAgent } from "../../mode.ts";
-import { isKnownDashboardPath } from "./dashboard-paths.ts";
+import { isKnownDashboardPath, resolveDashboardSubpath } from "./dashboard-paths.ts";
 
 interface OpenOptions {
   print?: boolean;
+  /** Resource ID appended to the section, e.g. a `user_` ID after `users`. */
+  id?: string;
 }
 
 /**
@@ -26,10 +28,66 @@ export function buildDashboardUrl(appId: string, instanceId: string, subpath?: s
   return cleaned ? `${base}/${cleaned}` : base;
 }
 
-export async function openDashboard(
+export type DashboardTarget = {
+  appId: string;
+  appLabel: string;
+  instanceId: string;
+  instanceLabel: string;
+};
+
+/**
+ * Print or open a dashboard deep link for a resolved app instance, with the
+ * same output rules as `clerk open`. `details` is merged into agent-mode JSON.
+ */
+export async function openDashboardTarget(
+  target: DashboardTarget,
   subpath: string | undefined,
+  options: { print?: boolean; title: string; details?: Record<string, unknown> },
+): Promise<void> {
+  const url = buildDashboardUrl(target.appId, target.instanceId, subpath);
+
+  if (options.print) {
+    log.data(url);
+    return;
+  }
+
+  if (isAgent()) {
+    log.data(
+      JSON.stringify({
+        url,
+        appId: target.appId,
+        appName: target.appLabel,
+        instanceId: target.instanceId,
+        instanceLabel: target.instanceLabel,
+        ...options.details,
+      }),
+    );
+    return;
+  }
+
+  intro(options.title);
+  const arrow = subpath ? ` → ${cyan(subpath)}` : "";
+  log.info(`↗ Opening ${bold(target.appLabel)} (${target.instanceLabel})${arrow}`);
+  log.info(`  ${dim(url)}`);
+
+  const result = await openBrowser(url);
+  if (!result.ok) {
+    log.warn(
+      `Could not open your browser automatically. Open this URL to continue:\n  ${cyan(url)}\n${dim(`(Reason: ${result.reason})`)}`,
+    );
+  }
+
+  outro();
+}
+
+export async function openDashboard(
+  section: string | undefined,
   options: OpenOptions = {},
 ): Promise<void> {
+  const resolvedPath = resolveDashboardSubpath(section, options.id);
+  if ("error" in resolvedPath) throwUsageError(resolvedPath.error);
+  const { subpath } = resolvedPath;
+
   const cwd = process.cwd();
   const resolved = await resolveProfile(cwd);
 
@@ -112,14 +170,19 @@ export function registerOpen(program: Program): void {
     .command("dashboard", { isDefault: true })
     .description("Open the linked app's dashboard in your browser")
     .addArgument(
-      createArgument("[subpath]", "Optional dashboard subpath (e.g. users, api-keys, settings)"),
+      createArgument("[subpath]", "Optional dashboard subpath (e.g. users, orgs, api-keys)"),
+    )
+    .addArgument(
+      createArgument("[id]", "Resource ID to open within the subpath (e.g. user_..., org_...)"),
     )
     .option("--print", "Print the URL without opening the browser")
     .setExamples([
       { command: "clerk open", description: "Open the linked app's dashboard" },
       { command: "clerk open users", description: "Open the users page" },
+      { command: "clerk open users user_123", description: "Open a user's page" },
+      { command: "clerk open orgs org_123 --print", description: "Print an organization's URL" },
       { command: "clerk open api-keys", description: "Open the API keys page" },
       { command: "clerk open --print", description: "Print the dashboard URL" },
     ])
-    .action((subpath, options) => openDashboard(subpath, options));
+    .action((subpath, id, options) => openDashboard(subpath, { ...options, id }));
 }
@@ -0,0 +1,79 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { orgsCount, invitationsCount } = await import("./count.ts");
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("orgs count", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.includes("/invitations")) return respond({ data: [], total_count: 4 });
+      if (path.startsWith("/organizations/")) return respond({ id: "org_123", name: "Acme" });
+      return respond({ data: [], total_count: 17 });
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  function requestedPaths(): string[] {
+    return mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path);
+  }
+
+  test("reads total_count from a one-row page", async () => {
+    await orgsCount({ query: "acme", where: ["order_by=-created_at"] });
+
+    expect(requestedPaths()).toEqual(["/organizations?limit=1&query=acme&order_by=-created_at"]);
+    expect(captured.out).toBe("17");
+  });
+
+  test("counts an organization's invitations by status", async () => {
+    await invitationsCount("acme", { status: "pending", json: true });
+
+    expect(requestedPaths()).toEqual([
+      "/organizations/acme?include_members_count=true",
+      "/organizations/org_123/invitations?status=pending&limit=1",
+    ]);
+    expect(JSON.parse(captured.out)).toEqual({ total_count: 4 });
+  });
+
+  test("counts invitations of every status by default", async () => {
+    await invitationsCount("org_123");
+
+    expect(requestedPaths()[1]).toBe("/organizations/org_123/invitations?limit=1");
+    expect(captured.out).toBe("4");
+  });
+});
@@ -0,0 +1,77 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { parseWhereOption } from "../../lib/option-parsers.ts";
+import {
+  countOrganizationInvitations,
+  countOrganizations,
+  fetchOrganization,
+} from "../../lib/organizations.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { isAgent } from "../../mode.ts";
+import { orgArgOrDefault, pickOrganization } from "./pick-organization.ts";
+
+type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type OrgsCountOptions = TargetingOptions & {
+  query?: string;
+  where?: string[];
+  json?: boolean;
+};
+
+export type InvitationsCountOptions = TargetingOptions & {
+  status?: string;
+  json?: boolean;
+};
+
+/** A bare number on stdout for scripts, or `{ "total_count": n }` as JSON. */
+function printCount(total: number, options: { json?: boolean }): void {
+  log.data(options.json || isAgent() ? JSON.stringify({ total_count: total }) : String(total));
+}
+
+function resolveSecretKey(options: TargetingOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+export async function orgsCount(options: OrgsCountOptions = {}): Promise<void> {
+  const filters = parseWhereOption(options.where);
+  if (options.query?.trim()) filters.unshift(["query", options.query.trim()]);
+  const secretKey = await resolveSecretKey(options);
+
+  const total = await withSpinner("Counting organizations...", () =>
+    withApiContext(countOrganizations(secretKey, filters), "Failed to count organizations"),
+  );
+  printCount(total, options);
+}
+
+export async function invitationsCount(
+  orgArg: string | undefined,
+  options: InvitationsCountOptions = {},
+): Promise<void> {
+  const secretKey = await resolveSecretKey(options);
+  const org = await requireArg(await orgArgOrDefault(orgArg), {
+    label: "Organization",
+    prompt: () => pickOrganization({ secretKey }),
+  });
+
+  const total = await withSpinner("Counting invitations...", async () => {
+    const organization = await withApiContext(
+      fetchOrganization(secretKey, org),
+      `Failed to fetch organization ${org}`,
+    );
+    return withApiContext(
+      countOrganizationInvitations(secretKey, organization.id, options.status),
+      "Failed to count invitations",
+    );
+  });
+  printCount(total, options);
+}
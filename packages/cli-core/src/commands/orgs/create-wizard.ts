@@ -0,0 +1,88 @@
+import { confirm, text } from "../../lib/prompts.ts";
+import { isOrganizationSlugAvailable } from "../../lib/organizations.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { validateSlugFormat } from "../../lib/validate.ts";
+import { pickUser } from "../users/interactive/pick-user.ts";
+
+export type CreateOrgWizardFields = {
+  name: string;
+  slug?: string;
+  createdBy?: string;
+  publicMetadata?: Record<string, unknown>;
+  privateMetadata?: Record<string, unknown>;
+};
+
+/** Lowercase, hyphen-separated slug suggestion for an organization name. */
+export function suggestSlug(name: string): string {
+  return name
+    .normalize("NFKD")
+    .replace(/[\u0300-\u036f]/g, "")
+    .toLowerCase()
+    .replace(/[^a-z0-9]+/g, "-")
+    .replace(/^-+|-+$/g, "");
+}
+
+function parseMetadata(value: string | undefined): Record<string, unknown> | undefined {
+  try {
+    const parsed: unknown = JSON.parse(value ?? "");
+    return isRecord(parsed) ? parsed : undefined;
+  } catch {
+    return undefined;
+  }
+}
+
+async function promptMetadata(kind: "public" | "private") {
+  if (!(await confirm({ message: `Add ${kind} metadata?`, default: false }))) return undefined;
+  const value = await text({
+    message: `${kind === "public" ? "Public" : "Private"} metadata (JSON object)`,
+    placeholder: '{"plan": "pro"}',
+    validate: (input) => (parseMetadata(input) ? undefined : "Enter a JSON object"),
+  });
+  return parseMetadata(value);
+}
+
+/**
+ * Prompt for an organization's name, slug, creator, and metadata. The slug
+ * defaults to one derived from the name and is checked for availability
+ * before the wizard moves on.
+ */
+export async function runCreateOrgWizard(secretKey: string): Promise<CreateOrgWizardFields> {
+  const name = (
+    await text({
+      message: "Organization name *",
+      validate: (value) => (value?.trim() ? undefined : "Organization name is required"),
+    })
+  ).trim();
+
+  const slug = (
+    await text({
+      message: "Slug (optional)",
+      default: suggestSlug(name),
+      validate: async (value) => {
+        const candidate = value?.trim() ?? "";
+        if (!candidate) return undefined;
+        const formatError = validateSlugFormat(candidate);
+        if (formatError) return formatError;
+        return (await isOrganizationSlugAvailable(secretKey, candidate))
+          ? undefined
+          : `The slug "${candidate}" is already taken`;
+      },
+    })
+  ).trim();
+
+  const createdBy = (await confirm({
+    message: "Set a creator? They become the organization's first admin.",
+    default: true,
+  }))
+    ? await pickUser({ secretKey, message: "Search for the creator:" })
+    : undefined;
+
+  const fields: CreateOrgWizardFields = { name };
+  if (slug) fields.slug = slug;
+  if (createdBy) fields.createdBy = createdBy;
+  const publicMetadata = await promptMetadata("public");
+  if (publicMetadata) fields.publicMetadata = publicMetadata;
+  const privateMetadata = await promptMetadata("private");
+  if (privateMetadata) fields.privateMetadata = privateMetadata;
+  return fields;
+}
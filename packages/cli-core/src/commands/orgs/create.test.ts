@@ -0,0 +1,149 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { BapiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockText = mock();
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  text: (...args: unknown[]) => mockText(...args),
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+}));
+
+const mockPickUser = mock();
+mock.module("../users/interactive/pick-user.ts", () => ({
+  pickUser: (...args: unknown[]) => mockPickUser(...args),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { orgsCreate } = await import("./create.ts");
+const { suggestSlug } = await import("./create-wizard.ts");
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body };
+}
+
+type Call = { method: string; path: string; body?: string };
+
+function calls(): Call[] {
+  return mockBapiRequest.mock.calls.map(([args]) => args as Call);
+}
+
+describe("suggestSlug", () => {
+  test("lowercases, strips accents, and hyphenates", () => {
+    expect(suggestSlug("  Café Società & Co. ")).toBe("cafe-societa-co");
+  });
+});
+
+describe("orgs create", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async ({ method }: Call) =>
+      method === "POST" ? respond({ id: "org_new", name: "Acme Inc", slug: "acme-inc" }) : {},
+    );
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockText.mockReset();
+    mockConfirm.mockReset();
+    mockPickUser.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("creates from flags", async () => {
+    await orgsCreate({
+      name: "Acme Inc",
+      createdBy: "user_1",
+      publicMetadata: '{"plan":"pro"}',
+      json: true,
+    });
+
+    expect(calls()[0]!.path).toBe("/organizations");
+    expect(JSON.parse(calls()[0]!.body!)).toEqual({
+      name: "Acme Inc",
+      created_by: "user_1",
+      public_metadata: { plan: "pro" },
+    });
+    expect(JSON.parse(captured.out).id).toBe("org_new");
+  });
+
+  test("--dry-run --json prints the planned request on stdout without sending it", async () => {
+    await orgsCreate({ name: "Acme Inc", dryRun: true, json: true });
+
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+    expect(JSON.parse(captured.out)).toEqual({
+      dry_run: true,
+      method: "POST",
+      path: "/v1/organizations",
+      body: { name: "Acme Inc" },
+    });
+  });
+
+  test("rejects a missing name and a malformed slug before any request", async () => {
+    await expect(orgsCreate({})).rejects.toThrow("--name");
+    await expect(orgsCreate({ name: "Acme", slug: "Acme Inc" })).rejects.toThrow("--slug");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("--interactive suggests a slug, checks it, and searches for the creator", async () => {
+    mockBapiRequest.mockImplementation(async ({ method }: Call) => {
+      if (method === "POST") return respond({ id: "org_new", name: "Acme Inc" });
+      throw new BapiError(404, JSON.stringify({ errors: [{ code: "not_found" }] }), new Headers());
+    });
+    mockText.mockImplementation(
+      async (config: { message: string; default?: string; validate: (v: string) => unknown }) => {
+        if (config.message.startsWith("Organization name")) return "Acme Inc";
+        expect(config.default).toBe("acme-inc");
+        expect(await config.validate("acme-inc")).toBeUndefined();
+        return config.default;
+      },
+    );
+    mockConfirm.mockImplementation(async ({ message }: { message: string }) =>
+      message.startsWith("Set a creator") || message.startsWith("Create"),
+    );
+    mockPickUser.mockResolvedValue("user_9");
+
+    await orgsCreate({ interactive: true });
+
+    expect(calls().map((call) => `${call.method} ${call.path}`)).toEqual([
+      "GET /organizations/acme-inc",
+      "POST /organizations",
+    ]);
+    expect(JSON.parse(calls()[1]!.body!)).toEqual({
+      name: "Acme Inc",
+      slug: "acme-inc",
+      created_by: "user_9",
+    });
+  });
+
+  test("--interactive is refused in agent mode", async () => {
+    mockIsAgent.mockReturnValue(true);
+    await expect(orgsCreate({ interactive: true })).rejects.toThrow("needs a terminal");
+  });
+});
@@ -0,0 +1,116 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, throwUserAbort, withApiContext } from "../../lib/errors.ts";
+import { parseJsonInput } from "../../lib/json-parse.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { createOrganization } from "../../lib/organizations.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { isQuiet, printQuietIds } from "../../lib/quiet.ts";
+import { validateSlugFormat } from "../../lib/validate.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+import { runCreateOrgWizard, type CreateOrgWizardFields } from "./create-wizard.ts";
+import type { TargetingOptions } from "./invitations.ts";
+
+export type OrgsCreateOptions = TargetingOptions & {
+  name?: string;
+  slug?: string;
+  createdBy?: string;
+  publicMetadata?: string;
+  privateMetadata?: string;
+  interactive?: boolean;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+};
+
+function parseMetadataFlag(value: string | undefined, flag: string) {
+  if (value === undefined) return undefined;
+  const parsed = parseJsonInput(value, flag);
+  if (!isRecord(parsed)) throwUsageError(`${flag} must be a JSON object.`);
+  return parsed;
+}
+
+function fieldsFromFlags(options: OrgsCreateOptions): CreateOrgWizardFields {
+  const name = options.name?.trim();
+  if (!name) {
+    throwUsageError("Pass the organization name with --name, or use --interactive.");
+  }
+  if (options.slug) {
+    const formatError = validateSlugFormat(options.slug);
+    if (formatError) throwUsageError(`--slug: ${formatError}.`);
+  }
+  if (options.createdBy && !options.createdBy.startsWith("user_")) {
+    throwUsageError(`--created-by expects a user ID (user_...), got "${options.createdBy}".`);
+  }
+  return {
+    name,
+    slug: options.slug,
+    createdBy: options.createdBy,
+    publicMetadata: parseMetadataFlag(options.publicMetadata, "--public-metadata"),
+    privateMetadata: parseMetadataFlag(options.privateMetadata, "--private-metadata"),
+  };
+}
+
+export function buildCreateOrganizationBody(
+  fields: CreateOrgWizardFields,
+): Record<string, unknown> {
+  const body: Record<string, unknown> = { name: fields.name };
+  if (fields.slug) body.slug = fields.slug;
+  if (fields.createdBy) body.created_by = fields.createdBy;
+  if (fields.publicMetadata) body.public_metadata = fields.publicMetadata;
+  if (fields.privateMetadata) body.private_metadata = fields.privateMetadata;
+  return body;
+}
+
+export async function orgsCreate(options: OrgsCreateOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  if (options.interactive && !isHuman()) {
+    throwUsageError("--interactive needs a terminal. Pass --name and the other flags instead.");
+  }
+
+  // Validate flags before resolving a key, so typos fail without a network call.
+  const flagFields = options.interactive ? undefined : fieldsFromFlags(options);
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  if (!json && !isQuiet()) intro("Creating organization");
+  const body = buildCreateOrganizationBody(flagFields ?? (await runCreateOrgWizard(secretKey)));
+
+  if (options.dryRun) {
+    if (json) {
+      const plan = { dry_run: true, method: "POST", path: "/v1/organizations", body };
+      log.data(JSON.stringify(plan, null, 2));
+      return;
+    }
+    log.info("[dry-run] POST /v1/organizations");
+    log.blank();
+    log.info(JSON.stringify(body, null, 2));
+    if (!isQuiet()) outro();
+    return;
+  }
+
+  if (options.interactive && !options.yes) {
+    log.blank();
+    log.info(JSON.stringify(body, null, 2));
+    if (!(await confirm({ message: `Create "${body.name}"?` }))) throwUserAbort();
+  }
+
+  const organization = await withSpinner("Creating organization...", () =>
+    withApiContext(createOrganization(secretKey, body), "Failed to create organization"),
+  );
+
+  if (printQuietIds([organization.id])) return;
+  if (json) {
+    log.data(JSON.stringify(organization, null, 2));
+    return;
+  }
+
+  const slug = organization.slug ? ` ${dim(`(${organization.slug})`)}` : "";
+  log.success(`Created ${organization.name}${slug} ${cyan(organization.id)}`);
+  await outro([`Run \`clerk orgs open ${organization.id}\` to view it in the dashboard`]);
+}
@@ -0,0 +1,132 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { BapiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+const mockResolveBapiSecretKey = mock();
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: (...args: unknown[]) => mockResolveBapiSecretKey(...args),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { orgsGet } = await import("./get.ts");
+
+const ORG = {
+  object: "organization",
+  id: "org_123",
+  name: "Acme Inc",
+  slug: "acme",
+  members_count: 12,
+  max_allowed_memberships: 50,
+  admin_delete_enabled: true,
+  public_metadata: { plan: "pro" },
+  private_metadata: {},
+  created_at: 1_700_000_000_000,
+  updated_at: 1_700_000_000_000,
+};
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("orgs get", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockResolveBapiSecretKey.mockResolvedValue("sk_test_123");
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.includes("/invitations")) return respond({ data: [], total_count: 3 });
+      if (path.includes("/domains")) return respond({ data: [], total_count: 1 });
+      return respond(ORG);
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockResolveBapiSecretKey.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("fetches the organization with its member count, then related counts", async () => {
+    await orgsGet("acme", { app: "app_1", instance: "prod" });
+
+    expect(mockResolveBapiSecretKey).toHaveBeenCalledWith({
+      secretKey: undefined,
+      app: "app_1",
+      instance: "prod",
+    });
+    const paths = mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path);
+    expect(paths).toEqual([
+      "/organizations/acme?include_members_count=true",
+      "/organizations/org_123/invitations?status=pending&limit=1",
+      "/organizations/org_123/domains?limit=1",
+    ]);
+  });
+
+  test("renders a grouped detail view in human mode", async () => {
+    await orgsGet("org_123");
+
+    const err = captured.err;
+    expect(err).toContain("Acme Inc");
+    expect(err).toContain("12 / 50");
+    expect(err).toMatch(/Pending invitations\s+\S*3/);
+    expect(err).toContain("enabled");
+    expect(err).toContain("2023-11-14T22:13:20Z");
+    expect(err).toContain('"plan": "pro"');
+    expect(err).toContain("(empty)");
+    expect(captured.out).toBe("");
+  });
+
+  test("prints the organization with counts as JSON", async () => {
+    await orgsGet("org_123", { json: true });
+
+    expect(JSON.parse(captured.out)).toEqual({
+      ...ORG,
+      pending_invitations_count: 3,
+      domains_count: 1,
+    });
+  });
+
+  test("leaves out counts that could not be fetched", async () => {
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.includes("/domains")) throw new BapiError(403, "{}", new Headers());
+      if (path.includes("/invitations")) return respond({ data: [], total_count: 0 });
+      return respond(ORG);
+    });
+
+    await orgsGet("org_123", { json: true });
+
+    const parsed = JSON.parse(captured.out) as Record<string, unknown>;
+    expect(parsed.pending_invitations_count).toBe(0);
+    expect(parsed).not.toHaveProperty("domains_count");
+  });
+
+  test("surfaces a missing organization with context", async () => {
+    mockBapiRequest.mockRejectedValue(new BapiError(404, "{}", new Headers()));
+
+    const error = await orgsGet("nope").catch((e: unknown) => e);
+
+    expect(error).toBeInstanceOf(BapiError);
+    expect((error as BapiError).context).toBe("Failed to fetch organization nope");
+  });
+});
@@ -0,0 +1,120 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bold, dim } from "../../lib/color.ts";
+import { bestEffort } from "../../lib/dry-run.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import {
+  countOrganizationDomains,
+  countPendingInvitations,
+  fetchOrganization,
+  type BapiOrganization,
+} from "../../lib/organizations.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatFields, formatTimestamp } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import { orgArgOrDefault, pickOrganization } from "./pick-organization.ts";
+
+export interface OrgsGetOptions {
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+}
+
+export interface OrganizationDetail extends BapiOrganization {
+  pending_invitations_count?: number;
+  domains_count?: number;
+}
+
+/**
+ * Related counts are best-effort: a failure (e.g. verified domains not enabled
+ * on the instance) leaves the field out instead of failing the whole view.
+ */
+function bestEffortCount(label: string, fetch: () => Promise<number>) {
+  return bestEffort(fetch, (error) =>
+    log.debug(`orgs: could not count ${label}: ${String(error)}`),
+  );
+}
+
+function formatMembers(org: OrganizationDetail): string | undefined {
+  if (org.members_count === undefined) return undefined;
+  // The API reports 0 for "no limit".
+  const max = org.max_allowed_memberships;
+  return max ? `${org.members_count} / ${max}` : `${org.members_count} (no limit)`;
+}
+
+function formatToggle(value: boolean | undefined): string | undefined {
+  if (value === undefined) return undefined;
+  return value ? "enabled" : "disabled";
+}
+
+function formatMetadata(metadata: Record<string, unknown> | undefined): string[] {
+  if (!metadata || Object.keys(metadata).length === 0) return [dim("  (empty)")];
+  return JSON.stringify(metadata, null, 2)
+    .split("\n")
+    .map((line) => `  ${line}`);
+}
+
+export function formatOrganizationDetail(org: OrganizationDetail): string[] {
+  const fields: [string, string | undefined][] = [
+    ["ID", org.id],
+    ["Slug", org.slug ?? undefined],
+    ["Members", formatMembers(org)],
+    ["Pending invitations", org.pending_invitations_count?.toString()],
+    ["Domains", org.domains_count?.toString()],
+    ["Admin delete", formatToggle(org.admin_delete_enabled)],
+    ["Created by", org.created_by ?? undefined],
+    ["Created", formatTimestamp(org.created_at)],
+    ["Updated", formatTimestamp(org.updated_at)],
+  ];
+
+  return [
+    bold(org.name),
+    ...formatFields(fields),
+    "",
+    bold("Public metadata"),
+    ...formatMetadata(org.public_metadata),
+    "",
+    bold("Private metadata"),
+    ...formatMetadata(org.private_metadata),
+  ];
+}
+
+export async function orgsGet(
+  orgArg: string | undefined,
+  options: OrgsGetOptions = {},
+): Promise<void> {
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const idOrSlug = await requireArg(await orgArgOrDefault(orgArg), {
+    label: "Organization",
+    prompt: () => pickOrganization({ secretKey }),
+  });
+
+  const detail = await withSpinner("Fetching organization...", async () => {
+    const org = await withApiContext(
+      fetchOrganization(secretKey, idOrSlug),
+      `Failed to fetch organization ${idOrSlug}`,
+    );
+    const [pendingInvitations, domains] = await Promise.all([
+      bestEffortCount("pending invitations", () => countPendingInvitations(secretKey, org.id)),
+      bestEffortCount("domains", () => countOrganizationDomains(secretKey, org.id)),
+    ]);
+    const result: OrganizationDetail = { ...org };
+    if (pendingInvitations !== undefined) result.pending_invitations_count = pendingInvitations;
+    if (domains !== undefined) result.domains_count = domains;
+    return result;
+  });
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(detail, null, 2));
+    return;
+  }
+
+  await printOutput(formatOrganizationDetail(detail), { page: false });
+}
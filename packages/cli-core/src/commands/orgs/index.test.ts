@@ -286,6 +286,60 @@ describe("clerk enable/disable orgs", () => {
     expect(parsed.organization_settings.enabled).toBe(false);
   });
 
+  test("disable summarizes how many organizations are affected before confirming", async () => {
+    let patchCalls = 0;
+    stubFetch(async (input, init) => {
+      const url = String(input);
+      if (init?.method === "PATCH") patchCalls++;
+      if (url.includes("/v1/organizations")) {
+        return new Response(JSON.stringify({ data: [], total_count: 12 }), { status: 200 });
+      }
+      if (url.includes("/v1/platform/applications/app_1?")) {
+        return new Response(
+          JSON.stringify({
+            application_id: "app_1",
+            instances: [
+              {
+                instance_id: "ins_dev",
+                environment_type: "development",
+                publishable_key: "pk_test_1",
+                secret_key: "sk_test_1",
+              },
+            ],
+          }),
+          { status: 200 },
+        );
+      }
+      return new Response(JSON.stringify({ billing: { organization_enabled: false } }), {
+        status: 200,
+      });
+    });
+
+    await setupProfile();
+    const { orgsDisable } = await import("./index.ts");
+    await orgsDisable({});
+
+    expect(captured.err).toContain("12 existing organizations will become inaccessible");
+    expect(patchCalls).toBe(1);
+  });
+
+  test("disable with --yes skips the impact summary", async () => {
+    const urls: string[] = [];
+    stubFetch(async (input) => {
+      urls.push(String(input));
+      return new Response(JSON.stringify({ billing: { organization_enabled: false } }), {
+        status: 200,
+      });
+    });
+
+    await setupProfile();
+    const { orgsDisable } = await import("./index.ts");
+    await orgsDisable({ yes: true });
+
+    expect(urls.some((url) => url.includes("/v1/organizations"))).toBe(false);
+    expect(captured.err).not.toContain("This affects");
+  });
+
   test("disable shows success message when billing is off", async () => {
     stubFetch(async () => {
       return new Response(JSON.stringify({ billing: { organization_enabled: false } }), {
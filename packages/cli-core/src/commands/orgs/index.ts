@@ -1,3 +1,5 @@
+import { createOption } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
 import { resolveAppContext } from "../../lib/config.ts";
 import { fetchInstanceConfig } from "../../lib/plapi.ts";
 import { throwUsageError, withApiContext } from "../../lib/errors.ts";
@@ -5,6 +7,27 @@ import { withGutter, withSpinner } from "../../lib/spinner.ts";
 import { isHuman } from "../../mode.ts";
 import { NEXT_STEPS } from "../../lib/next-steps.ts";
 import { applyConfigPatch } from "../config/apply-patch.ts";
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { countOrganizations } from "../../lib/organizations.ts";
+import { collectOptionValues, parseIntegerOption } from "../../lib/option-parsers.ts";
+import { invitationsCount, orgsCount } from "./count.ts";
+import { orgsCreate } from "./create.ts";
+import { orgsGet } from "./get.ts";
+import { membersExport } from "./members.ts";
+import { orgsOpen } from "./open.ts";
+import { orgsRename } from "./rename.ts";
+import { DEFAULT_THRESHOLD, seatsGet, seatsReport, seatsSet } from "./seats.ts";
+import { slugCheck } from "./slug.ts";
+import {
+  DEFAULT_INVITATION_ROLE,
+  invitationsBulkCreate,
+  invitationsCreate,
+  invitationsRevokeAll,
+} from "./invitations.ts";
+import { presetsList, presetsRemove, presetsSet } from "./invitation-presets.ts";
+
+const ORG_ARGUMENT_DESCRIPTION =
+  "Organization ID or slug. Omit to use the `clerk use --org` default or pick interactively.";
 
 interface OrgsOptions {
   app?: string;
@@ -103,6 +126,351 @@ export async function orgsDisable(options: OrgsOptions): Promise<void> {
         ? "Organization billing is currently enabled. Disabling organizations will leave `billing.organization_enabled` stranded — consider running `clerk disable billing --for orgs` separately."
         : undefined,
       currentConfig: current,
+      impact: async () => {
+        const secretKey = await resolveBapiSecretKey({ app: ctx.appId, instance: ctx.instanceId });
+        const count = await countOrganizations(secretKey);
+        if (count === 0) return [];
+        const noun = count === 1 ? "organization" : "organizations";
+        return [`${count} existing ${noun} will become inaccessible to members`];
+      },
     });
   });
 }
+
+export function registerOrgs(program: Program): void {
+  const orgs = program
+    .command("orgs")
+    .description("Manage Clerk organizations")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  orgs
+    .command("get")
+    .description("Show an organization's details, counts, and metadata")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk orgs get org_123", description: "Show an organization by ID" },
+      { command: "clerk orgs get acme --json", description: "Look up by slug and print JSON" },
+    ])
+    .action((org, _opts, cmd) =>
+      orgsGet(org, cmd.optsWithGlobals() as Parameters<typeof orgsGet>[1]),
+    );
+
+  orgs
+    .command("create")
+    .description("Create an organization")
+    .option("--name <name>", "Organization name")
+    .option("--slug <slug>", "URL-friendly identifier (defaults to one derived from the name)")
+    .option("--created-by <user-id>", "User who becomes the organization's first admin")
+    .option("--public-metadata <json>", "Public metadata as a JSON object")
+    .option("--private-metadata <json>", "Private metadata as a JSON object")
+    .option("-i, --interactive", "Prompt for each field, with slug suggestions and user search")
+    .option("--dry-run", "Show the request without sending it")
+    .option("--yes", "Skip the confirmation prompt in --interactive mode")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs create --name 'Acme Inc' --created-by user_123",
+        description: "Create an organization with an admin",
+      },
+      {
+        command: "clerk orgs create --interactive",
+        description: "Walk through name, slug, creator, and metadata prompts",
+      },
+    ])
+    .action((_opts, cmd) => orgsCreate(cmd.optsWithGlobals() as Parameters<typeof orgsCreate>[0]));
+
+  orgs
+    .command("open")
+    .description("Open an organization in the Clerk Dashboard")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .option("--print", "Print the URL without opening the browser")
+    .setExamples([
+      { command: "clerk orgs open org_123", description: "Open an organization's dashboard page" },
+      { command: "clerk orgs open acme --print", description: "Look up by slug and print the URL" },
+    ])
+    .action((org, _opts, cmd) =>
+      orgsOpen(org, cmd.optsWithGlobals() as Parameters<typeof orgsOpen>[1]),
+    );
+
+  orgs
+    .command("count")
+    .description("Count organizations, optionally filtered")
+    .option("--query <query>", "Match organization name, slug, or ID")
+    .option(
+      "--where <key=value>",
+      "Any other Backend API list filter, as key=value (repeatable)",
+      collectOptionValues,
+      [],
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk orgs count", description: "Total organizations on the instance" },
+      { command: "clerk orgs count --query acme", description: "Organizations matching a search" },
+    ])
+    .action((_opts, cmd) => orgsCount(cmd.optsWithGlobals() as Parameters<typeof orgsCount>[0]));
+
+  const slug = orgs.command("slug").description("Work with organization slugs");
+
+  slug
+    .command("check")
+    .description("Check whether an organization slug is valid and free")
+    .argument("[slug]", "Slug to check")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk orgs slug check acme-inc", description: "Exits 1 when the slug is taken" },
+    ])
+    .action((value, _opts, cmd) =>
+      slugCheck(value, cmd.optsWithGlobals() as Parameters<typeof slugCheck>[1]),
+    );
+
+  orgs
+    .command("rename")
+    .description("Apply many organization name and slug changes from a CSV")
+    .requiredOption(
+      "--map-file <path>",
+      "CSV with an organization column (ID or slug) and name and/or slug columns",
+    )
+    .option("--dry-run", "Show the plan and any conflicts without renaming")
+    .option("--yes", "Skip confirmation prompt")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs rename --map-file renames.csv --dry-run",
+        description: "Check a batch of renames for conflicts",
+      },
+      {
+        command: "clerk orgs rename --map-file renames.csv --yes",
+        description: "Apply the renames, then verify each one",
+      },
+    ])
+    .action((_opts, cmd) =>
+      orgsRename(cmd.optsWithGlobals() as Parameters<typeof orgsRename>[0]),
+    );
+
+  const members = orgs.command("members").description("Work with organization members");
+
+  members
+    .command("export")
+    .description("Export an organization's members with their email and name")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .option("--file <path>", "Write to a file (.json for JSON, otherwise CSV) instead of stdout")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs members export acme --file members.csv",
+        description: "Export members with email, name, role, and join date",
+      },
+      {
+        command: "clerk orgs members export org_123 --json",
+        description: "Print members as a JSON array",
+      },
+    ])
+    .action((org, _opts, cmd) =>
+      membersExport(org, cmd.optsWithGlobals() as Parameters<typeof membersExport>[1]),
+    );
+
+  const seats = orgs.command("seats").description("Check and set organization membership limits");
+
+  seats
+    .command("get")
+    .description("Show an organization's member count against its seat limit")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk orgs seats get acme", description: "Show seats used and available" },
+    ])
+    .action((org, _opts, cmd) =>
+      seatsGet(org, cmd.optsWithGlobals() as Parameters<typeof seatsGet>[1]),
+    );
+
+  seats
+    .command("set")
+    .description("Set an organization's maximum number of members")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .requiredOption("--max <n>", "Maximum members (0 removes the limit)", (value) =>
+      parseIntegerOption(value, "--max", { min: 0 }),
+    )
+    .option("--yes", "Skip the confirmation when the limit is below the member count")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk orgs seats set acme --max 25", description: "Allow up to 25 members" },
+      { command: "clerk orgs seats set org_123 --max 0", description: "Remove the limit" },
+    ])
+    .action((org, _opts, cmd) =>
+      seatsSet(org, cmd.optsWithGlobals() as Parameters<typeof seatsSet>[1]),
+    );
+
+  seats
+    .command("report")
+    .description("List organizations nearing their seat limit")
+    .option(
+      "--threshold <percent>",
+      `Seats in use, as a percentage of the limit (default ${DEFAULT_THRESHOLD})`,
+      (value) => parseIntegerOption(value, "--threshold", { min: 1, max: 100 }),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs seats report",
+        description: `Organizations using ${DEFAULT_THRESHOLD}% or more of their seats`,
+      },
+      {
+        command: "clerk orgs seats report --threshold 100 --json",
+        description: "Full organizations, for a customer-success workflow",
+      },
+    ])
+    .action((_opts, cmd) =>
+      seatsReport(cmd.optsWithGlobals() as Parameters<typeof seatsReport>[0]),
+    );
+
+  const invitations = orgs.command("invitations").description("Manage organization invitations");
+
+  invitations
+    .command("count")
+    .description("Count an organization's invitations")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .addOption(
+      createOption("--status <status>", "Only count invitations with this status").choices([
+        "pending",
+        "accepted",
+        "revoked",
+        "expired",
+      ]),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs invitations count acme --status pending",
+        description: "Invitations still waiting for a response",
+      },
+    ])
+    .action((org, _opts, cmd) =>
+      invitationsCount(org, cmd.optsWithGlobals() as Parameters<typeof invitationsCount>[1]),
+    );
+
+  invitations
+    .command("create")
+    .description("Invite email addresses to an organization")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .option("--email <address>", "Address to invite (repeatable)", collectOptionValues)
+    .option("--preset <name>", "Use a saved role, redirect URL, and metadata preset")
+    .option("--role <role>", `Role to assign (default ${DEFAULT_INVITATION_ROLE})`)
+    .option("--redirect-url <url>", "Where the invitation link sends users")
+    .option("--public-metadata <json>", "Public metadata for the invitation, as a JSON object")
+    .option("--dry-run", "Show the request without sending it")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs invitations create acme --email ada@example.com --role org:admin",
+        description: "Invite one address as an admin",
+      },
+      {
+        command: "clerk orgs invitations create acme --email ada@x.test --preset contractor",
+        description: "Invite with a saved preset's role and metadata",
+      },
+    ])
+    .action((org, _opts, cmd) =>
+      invitationsCreate(org, cmd.optsWithGlobals() as Parameters<typeof invitationsCreate>[1]),
+    );
+
+  invitations
+    .command("bulk-create")
+    .description("Invite everyone listed in a file to an organization")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .requiredOption("--file <path>", "JSON array, CSV with an email column, or one email per line")
+    .option("--preset <name>", "Use a saved role, redirect URL, and metadata preset")
+    .option("--role <role>", `Role for entries without one (default ${DEFAULT_INVITATION_ROLE})`)
+    .option("--redirect-url <url>", "Where the invitation link sends users")
+    .option("--public-metadata <json>", "Public metadata for entries without their own")
+    .option("--resume <file>", "Checkpoint sent batches to a file, and resume from it")
+    .option("--dry-run", "Show the requests without sending them")
+    .option("--yes", "Skip confirmation prompt")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs invitations bulk-create acme --file invites.csv",
+        description: "Invite everyone in a CSV with email and role columns",
+      },
+      {
+        command: "clerk orgs invitations bulk-create org_123 --file emails.txt --role org:admin",
+        description: "Invite a list of emails as admins",
+      },
+      {
+        command: "clerk orgs invitations bulk-create acme --file invites.csv --resume invites.state",
+        description: "Rerun the same command to pick up after an interruption",
+      },
+    ])
+    .action((org, _opts, cmd) =>
+      invitationsBulkCreate(
+        org,
+        cmd.optsWithGlobals() as Parameters<typeof invitationsBulkCreate>[1],
+      ),
+    );
+
+  invitations
+    .command("revoke-all")
+    .description("Revoke an organization's pending invitations, optionally filtered")
+    .argument("[org]", ORG_ARGUMENT_DESCRIPTION)
+    .addOption(
+      createOption("--status <status>", "Invitation status to revoke")
+        .choices(["pending"])
+        .default("pending"),
+    )
+    .option("--role <role>", "Only revoke invitations for this role")
+    .option("--email-domain <domain>", "Only revoke invitations to addresses at this domain")
+    .option("--dry-run", "List matching invitations without revoking them")
+    .option("--yes", "Skip confirmation prompt")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk orgs invitations revoke-all acme --status pending",
+        description: "Revoke every pending invitation",
+      },
+      {
+        command: "clerk orgs invitations revoke-all acme --email-domain contractor.test --dry-run",
+        description: "Preview which invitations to one domain would be revoked",
+      },
+    ])
+    .action((org, _opts, cmd) =>
+      invitationsRevokeAll(
+        org,
+        cmd.optsWithGlobals() as Parameters<typeof invitationsRevokeAll>[1],
+      ),
+    );
+
+  const presets = invitations
+    .command("presets")
+    .description("Manage named invitation presets for create and bulk-create");
+
+  presets
+    .command("list", { isDefault: true })
+    .description("List saved invitation presets")
+    .option("--json", "Output as JSON")
+    .action((options) => presetsList(options));
+
+  presets
+    .command("set")
+    .description("Create or replace an invitation preset")
+    .argument("<name>", "Preset name")
+    .option("--role <role>", "Role to assign")
+    .option("--redirect-url <url>", "Where the invitation link sends users")
+    .option("--public-metadata <json>", "Public metadata, as a JSON object")
+    .setExamples([
+      {
+        command:
+          "clerk orgs invitations presets set contractor --role org:contractor " +
+          `--public-metadata '{"type":"contractor"}'`,
+        description: "Save a preset for contractor invitations",
+      },
+    ])
+    .action((name, options) => presetsSet(name, options));
+
+  presets
+    .command("remove")
+    .description("Delete an invitation preset")
+    .argument("<name>", "Preset name")
+    .action((name) => presetsRemove(name));
+}
@@ -0,0 +1,74 @@
+import { dim } from "../../lib/color.ts";
+import {
+  listInvitationPresets,
+  removeInvitationPreset,
+  setInvitationPreset,
+  type InvitationPreset,
+} from "../../lib/config.ts";
+import { throwUsageError } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { isAgent } from "../../mode.ts";
+import { parsePublicMetadata } from "./invitations.ts";
+
+const PRESET_NAME_PATTERN = /^[A-Za-z0-9_-]+$/;
+
+export type PresetSetOptions = {
+  role?: string;
+  redirectUrl?: string;
+  publicMetadata?: string;
+};
+
+function formatPreset(preset: InvitationPreset): string {
+  const parts = [
+    preset.role && `role=${preset.role}`,
+    preset.redirectUrl && `redirect=${preset.redirectUrl}`,
+    preset.publicMetadata && `metadata=${JSON.stringify(preset.publicMetadata)}`,
+  ].filter(Boolean);
+  return parts.length ? parts.join("  ") : dim("(empty)");
+}
+
+export async function presetsList(options: { json?: boolean } = {}): Promise<void> {
+  const presets = await listInvitationPresets();
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(presets, null, 2));
+    return;
+  }
+
+  const names = Object.keys(presets).sort();
+  if (names.length === 0) {
+    log.info("No invitation presets. Create one with `clerk orgs invitations presets set`.");
+    return;
+  }
+  const width = Math.max(...names.map((name) => name.length));
+  for (const name of names) log.data(`${name.padEnd(width)}  ${formatPreset(presets[name]!)}`);
+}
+
+/** Create or replace a preset with the given values. */
+export async function presetsSet(name: string, options: PresetSetOptions = {}): Promise<void> {
+  if (!PRESET_NAME_PATTERN.test(name)) {
+    throwUsageError(
+      `Invalid preset name "${name}". Use letters, numbers, hyphens, and underscores.`,
+    );
+  }
+  const preset: InvitationPreset = {
+    ...(options.role && { role: options.role }),
+    ...(options.redirectUrl && { redirectUrl: options.redirectUrl }),
+    ...(options.publicMetadata !== undefined && {
+      publicMetadata: parsePublicMetadata(options.publicMetadata),
+    }),
+  };
+  if (Object.keys(preset).length === 0) {
+    throwUsageError("Pass at least one of --role, --redirect-url, or --public-metadata.");
+  }
+
+  await setInvitationPreset(name, preset);
+  log.success(`Saved preset ${name}: ${formatPreset(preset)}`);
+}
+
+export async function presetsRemove(name: string): Promise<void> {
+  if (!(await removeInvitationPreset(name))) {
+    throwUsageError(`No invitation preset named "${name}".`);
+  }
+  log.success(`Removed preset ${name}`);
+}
@@ -0,0 +1,440 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { BapiError, CliError, ERROR_CODE } from "../../lib/errors.ts";
+import { configStubs, useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockGetInvitationPreset = mock();
+mock.module("../../lib/config.ts", () => ({
+  ...configStubs,
+  getInvitationPreset: (...args: unknown[]) => mockGetInvitationPreset(...args),
+  listInvitationPresets: async () => ({ contractor: {} }),
+}));
+
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+  text: async () => "",
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { invitationsBulkCreate, invitationsCreate, invitationsRevokeAll, parseInvitationsFile } =
+  await import("./invitations.ts");
+
+const ORG = { id: "org_123", name: "Acme Inc", slug: "acme" };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+type Call = { method: string; path: string; body?: string };
+
+function calls(): Call[] {
+  return mockBapiRequest.mock.calls.map(([args]) => args as Call);
+}
+
+describe("parseInvitationsFile", () => {
+  test("reads a CSV with email and role columns", () => {
+    const text = "email,role\nalice@example.com,org:admin\nbob@example.com,\n";
+    expect(parseInvitationsFile(text, "invites.csv")).toEqual([
+      { email_address: "alice@example.com", role: "org:admin" },
+      { email_address: "bob@example.com", role: "org:member" },
+    ]);
+  });
+
+  test("keeps quoted cells that contain commas together", () => {
+    const text = 'name,email,role\n"Doe, Jane",jane@example.com,org:admin\n';
+    expect(parseInvitationsFile(text, "invites.csv")).toEqual([
+      { email_address: "jane@example.com", role: "org:admin" },
+    ]);
+  });
+
+  test("reads one email per line, skipping comments and duplicates", () => {
+    const text = "# team\nalice@example.com\n\nALICE@example.com\nbob@example.com\n";
+    expect(parseInvitationsFile(text, "emails.txt", "org:viewer")).toEqual([
+      { email_address: "alice@example.com", role: "org:viewer" },
+      { email_address: "bob@example.com", role: "org:viewer" },
+    ]);
+  });
+
+  test("reads a JSON array of strings and invitation objects", () => {
+    const text = JSON.stringify([
+      "alice@example.com",
+      { email_address: "bob@example.com", role: "org:admin", public_metadata: { team: "ops" } },
+    ]);
+    expect(parseInvitationsFile(text, "invites.json")).toEqual([
+      { email_address: "alice@example.com", role: "org:member" },
+      { email_address: "bob@example.com", role: "org:admin", public_metadata: { team: "ops" } },
+    ]);
+  });
+
+  test("checks JSON entries against the invitation schema", () => {
+    const text = [
+      "[",
+      '  "alice@example.com",',
+      '  { "email_address": "bob@example.com", "rol": "org:admin" }',
+      "]",
+    ].join("\n");
+    expect(() => parseInvitationsFile(text, "invites.json")).toThrow(
+      "invites.json line 3, column 48 ([1].rol): unknown property (expected one of email_address",
+    );
+  });
+
+  test("rejects a CSV header without an email column", () => {
+    expect(() => parseInvitationsFile("name,role\nJane,org:admin\n", "invites.csv")).toThrow(
+      'the header row needs an "email" or "email_address" column (found name, role)',
+    );
+  });
+
+  test("rejects an invalid address before anything is sent", () => {
+    expect(() => parseInvitationsFile("alice@example.com\nnot-an-email\n", "emails.txt")).toThrow(
+      '"not-an-email" is not an email address',
+    );
+  });
+});
+
+describe("orgs invitations create", () => {
+  const captured = useCaptureLog();
+  const CONTRACTOR = {
+    role: "org:contractor",
+    redirectUrl: "https://app.test/welcome",
+    publicMetadata: { type: "contractor" },
+  };
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async ({ method, body }: Call) => {
+      if (method === "GET") return respond(ORG);
+      const batch = JSON.parse(body!) as Record<string, unknown>[];
+      return respond(batch.map((entry, i) => ({ id: `orginv_${i}`, ...entry })));
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    mockGetInvitationPreset.mockReset();
+  });
+
+  function postedInvitations(): Record<string, unknown>[] {
+    const post = calls().find((call) => call.method === "POST");
+    return JSON.parse(post!.body!) as Record<string, unknown>[];
+  }
+
+  test("invites each --email with the given role", async () => {
+    await invitationsCreate("acme", {
+      email: ["ada@example.com", "bob@example.com"],
+      role: "org:admin",
+      publicMetadata: '{"team":"ops"}',
+    });
+
+    expect(postedInvitations()).toEqual([
+      { email_address: "ada@example.com", role: "org:admin", public_metadata: { team: "ops" } },
+      { email_address: "bob@example.com", role: "org:admin", public_metadata: { team: "ops" } },
+    ]);
+    expect(captured.err).toContain("Invited ada@example.com to Acme Inc as org:admin");
+  });
+
+  test("--dry-run --json prints the planned request on stdout without sending it", async () => {
+    await invitationsCreate("acme", { email: ["ada@example.com"], dryRun: true, json: true });
+
+    expect(calls().every((call) => call.method === "GET")).toBe(true);
+    expect(JSON.parse(captured.out)).toEqual({
+      dry_run: true,
+      method: "POST",
+      path: "/v1/organizations/org_123/invitations/bulk",
+      body: [{ email_address: "ada@example.com", role: "org:member" }],
+    });
+  });
+
+  test("--preset fills in the role, redirect URL, and metadata", async () => {
+    mockGetInvitationPreset.mockResolvedValue(CONTRACTOR);
+
+    await invitationsCreate("acme", { email: ["ada@example.com"], preset: "contractor" });
+
+    expect(mockGetInvitationPreset).toHaveBeenCalledWith("contractor");
+    expect(postedInvitations()).toEqual([
+      {
+        email_address: "ada@example.com",
+        role: "org:contractor",
+        redirect_url: "https://app.test/welcome",
+        public_metadata: { type: "contractor" },
+      },
+    ]);
+  });
+
+  test("flags win over the preset", async () => {
+    mockGetInvitationPreset.mockResolvedValue(CONTRACTOR);
+
+    await invitationsCreate("acme", {
+      email: ["ada@example.com"],
+      preset: "contractor",
+      role: "org:member",
+    });
+
+    expect(postedInvitations()[0]).toMatchObject({
+      role: "org:member",
+      redirect_url: "https://app.test/welcome",
+    });
+  });
+
+  test("names the preset when its role doesn't exist", async () => {
+    mockGetInvitationPreset.mockResolvedValue(CONTRACTOR);
+    mockBapiRequest.mockImplementation(async ({ path }: Call) =>
+      path.startsWith("/organization_roles")
+        ? respond({ data: [{ key: "org:member" }], total_count: 1 })
+        : respond(ORG),
+    );
+
+    const error = await invitationsCreate("acme", {
+      email: ["ada@example.com"],
+      preset: "contractor",
+    }).catch((e) => e);
+    expect(error.message).toStartWith(
+      'Preset "contractor": "org:contractor" is not a role on this instance.',
+    );
+    expect(calls().filter((call) => call.method === "POST")).toHaveLength(0);
+  });
+
+  test("rejects unknown presets, bad addresses, and non-object metadata", async () => {
+    mockGetInvitationPreset.mockResolvedValue(undefined);
+
+    await expect(
+      invitationsCreate("acme", { email: ["ada@example.com"], preset: "vendor" }),
+    ).rejects.toThrow('No invitation preset named "vendor". Available presets: contractor.');
+    await expect(invitationsCreate("acme", { email: ["not-an-email"] })).rejects.toThrow(
+      /^--email: "not-an-email" is not an email address/,
+    );
+    await expect(
+      invitationsCreate("acme", { email: ["ada@example.com"], publicMetadata: "[1]" }),
+    ).rejects.toThrow("--public-metadata must be a JSON object.");
+    await expect(invitationsCreate("acme", {})).rejects.toThrow("--email");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
+
+describe("orgs invitations bulk-create", () => {
+  const captured = useCaptureLog();
+  let tempDir: string;
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-invitations-"));
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockConfirm.mockResolvedValue(true);
+  });
+
+  afterEach(async () => {
+    process.exitCode = originalExitCode;
+    await rm(tempDir, { recursive: true, force: true });
+    mockBapiRequest.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  async function writeEmails(count: number): Promise<string> {
+    const path = join(tempDir, "emails.txt");
+    const lines = Array.from({ length: count }, (_, i) => `user${i}@example.com`);
+    await writeFile(path, lines.join("\n"));
+    return path;
+  }
+
+  test("sends invitations in batches after confirming", async () => {
+    const file = await writeEmails(12);
+    mockBapiRequest.mockImplementation(async ({ method, body }: Call) => {
+      if (method === "GET") return respond(ORG);
+      const batch = JSON.parse(body!) as { email_address: string }[];
+      return respond(batch.map((entry, i) => ({ id: `orginv_${i}`, ...entry })));
+    });
+
+    await invitationsBulkCreate("acme", { file, redirectUrl: "https://app.test/join" });
+
+    expect(mockConfirm).toHaveBeenCalledTimes(1);
+    const posts = calls().filter((call) => call.method === "POST");
+    expect(posts.map((call) => call.path)).toEqual([
+      "/organizations/org_123/invitations/bulk",
+      "/organizations/org_123/invitations/bulk",
+    ]);
+    const first = JSON.parse(posts[0]!.body!) as Record<string, unknown>[];
+    expect(first).toHaveLength(10);
+    expect(first[0]).toEqual({
+      email_address: "user0@example.com",
+      role: "org:member",
+      redirect_url: "https://app.test/join",
+    });
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("reports failed batches without stopping the run", async () => {
+    const file = await writeEmails(12);
+    let posts = 0;
+    mockBapiRequest.mockImplementation(async ({ method, body }: Call) => {
+      if (method === "GET") return respond(ORG);
+      if (posts++ === 0) throw new BapiError(422, '{"errors":[]}', new Headers());
+      return respond((JSON.parse(body!) as unknown[]).map((_, i) => ({ id: `orginv_${i}` })));
+    });
+
+    await invitationsBulkCreate("acme", { file, json: true });
+
+    const output = JSON.parse(captured.out) as {
+      created: unknown[];
+      failed: { email_addresses: string[] }[];
+    };
+    expect(output.created).toHaveLength(2);
+    expect(output.failed).toHaveLength(1);
+    expect(output.failed[0]!.email_addresses).toHaveLength(10);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("stops on errors that would hit every batch", async () => {
+    const file = await writeEmails(12);
+    mockBapiRequest.mockImplementation(async ({ method }: Call) => {
+      if (method === "GET") return respond(ORG);
+      throw new CliError("read-only", { code: ERROR_CODE.READ_ONLY });
+    });
+
+    await expect(invitationsBulkCreate("acme", { file, yes: true })).rejects.toMatchObject({
+      code: "read_only",
+    });
+    expect(calls().filter((call) => call.method === "POST")).toHaveLength(1);
+  });
+
+  test("--dry-run prints the payload without sending it", async () => {
+    const file = await writeEmails(2);
+    mockBapiRequest.mockResolvedValue(respond(ORG));
+
+    await invitationsBulkCreate("acme", { file, dryRun: true });
+
+    expect(calls().every((call) => call.method === "GET")).toBe(true);
+    expect(captured.err).toContain("[dry-run] POST /v1/organizations/org_123/invitations/bulk");
+    expect(mockConfirm).not.toHaveBeenCalled();
+  });
+
+  test("rejects roles the instance doesn't define before sending anything", async () => {
+    const file = join(tempDir, "invites.csv");
+    await writeFile(file, "email,role\nalice@example.com,org:admin\nbob@example.com,org:owner\n");
+    mockBapiRequest.mockImplementation(async ({ path }: Call) =>
+      path.startsWith("/organization_roles")
+        ? respond({ data: [{ key: "org:admin" }, { key: "org:member" }], total_count: 2 })
+        : respond(ORG),
+    );
+
+    const error = await invitationsBulkCreate("acme", { file, yes: true }).catch((e) => e);
+    expect(error).toBeInstanceOf(CliError);
+    expect(error.message).toBe(
+      `${file}: "org:owner" (for bob@example.com) is not a role on this instance. ` +
+        "Available roles: org:admin, org:member.",
+    );
+
+    const emails = await writeEmails(1);
+    const flagError = await invitationsBulkCreate("acme", {
+      file: emails,
+      role: "org:viewer",
+    }).catch((e) => e);
+    expect(flagError.message).toStartWith('--role: "org:viewer" is not a role on this instance.');
+    expect(calls().filter((call) => call.method === "POST")).toHaveLength(0);
+  });
+});
+
+describe("orgs invitations revoke-all", () => {
+  const captured = useCaptureLog();
+  let originalExitCode: typeof process.exitCode;
+
+  const PENDING = [
+    { id: "orginv_1", email_address: "a@contractor.test", role: "org:member" },
+    { id: "orginv_2", email_address: "b@example.com", role: "org:member" },
+    { id: "orginv_3", email_address: "c@contractor.test", role: "org:admin" },
+  ];
+
+  beforeEach(() => {
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockConfirm.mockResolvedValue(true);
+    mockBapiRequest.mockImplementation(async ({ method, path }: Call) => {
+      if (path.includes("/invitations?")) return respond({ data: PENDING, total_count: 3 });
+      if (method === "GET") return respond(ORG);
+      return respond({});
+    });
+  });
+
+  afterEach(() => {
+    process.exitCode = originalExitCode;
+    mockBapiRequest.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  function revokedPaths(): string[] {
+    return calls()
+      .filter((call) => call.method === "POST")
+      .map((call) => call.path);
+  }
+
+  test("revokes every pending invitation matching the filters", async () => {
+    await invitationsRevokeAll("acme", { emailDomain: "@Contractor.test", role: "org:member" });
+
+    expect(calls()[1]!.path).toBe(
+      "/organizations/org_123/invitations?status=pending&limit=100&offset=0",
+    );
+    expect(revokedPaths()).toEqual(["/organizations/org_123/invitations/orginv_1/revoke"]);
+    expect(mockConfirm).toHaveBeenCalledTimes(1);
+  });
+
+  test("--dry-run lists matches without revoking", async () => {
+    await invitationsRevokeAll("acme", { emailDomain: "contractor.test", dryRun: true });
+
+    expect(revokedPaths()).toEqual([]);
+    expect(captured.err).toContain("Would revoke 2 of 3 pending invitation(s)");
+    expect(captured.err).toContain("orginv_3");
+  });
+
+  test("does not revoke anything when the prompt is declined", async () => {
+    mockConfirm.mockResolvedValue(false);
+
+    await expect(invitationsRevokeAll("acme")).rejects.toThrow();
+    expect(revokedPaths()).toEqual([]);
+  });
+
+  test("reports per-invitation failures as JSON", async () => {
+    mockBapiRequest.mockImplementation(async ({ method, path }: Call) => {
+      if (path.includes("/invitations?")) return respond({ data: PENDING, total_count: 3 });
+      if (method === "GET") return respond(ORG);
+      if (path.includes("orginv_2")) throw new BapiError(404, "{}", new Headers());
+      return respond({});
+    });
+
+    await invitationsRevokeAll("acme", { json: true });
+
+    const output = JSON.parse(captured.out) as { revoked: string[]; failed: { id: string }[] };
+    expect(output.revoked).toEqual(["orginv_1", "orginv_3"]);
+    expect(output.failed.map((failure) => failure.id)).toEqual(["orginv_2"]);
+    expect(process.exitCode).toBe(1);
+  });
+});
@@ -0,0 +1,559 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { getInvitationPreset, listInvitationPresets } from "../../lib/config.ts";
+import { parseCsv } from "../../lib/csv.ts";
+import {
+  ApiError,
+  ERROR_CODE,
+  errorMessage,
+  throwUsageError,
+  throwUserAbort,
+  withApiContext,
+} from "../../lib/errors.ts";
+import { openJob } from "../../lib/job-state.ts";
+import { parseJsonInput } from "../../lib/json-parse.ts";
+import { assertMatchesSchema, type JsonSchema } from "../../lib/json-schema.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import {
+  createOrganizationInvitations,
+  fetchOrganization,
+  listOrganizationInvitations,
+  listOrganizationRoles,
+  revokeOrganizationInvitation,
+  type BapiOrganization,
+  type BapiOrganizationInvitation,
+} from "../../lib/organizations.ts";
+import { attemptBulk, withProgress } from "../../lib/progress.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { printQuietIds } from "../../lib/quiet.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { validateEmail } from "../../lib/validate.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+import { orgArgOrDefault, pickOrganization } from "./pick-organization.ts";
+
+export const DEFAULT_INVITATION_ROLE = "org:member";
+
+/** Invitations per bulk request. A batch fails as a whole, so keep them small. */
+const BULK_BATCH_SIZE = 10;
+
+export type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+/** Invitation settings that can come from flags or a named preset. */
+export type InvitationDefaultsOptions = {
+  preset?: string;
+  role?: string;
+  redirectUrl?: string;
+  publicMetadata?: string;
+};
+
+export type CreateOptions = TargetingOptions &
+  InvitationDefaultsOptions & {
+    email?: string[];
+    dryRun?: boolean;
+    json?: boolean;
+  };
+
+export type BulkCreateOptions = TargetingOptions &
+  InvitationDefaultsOptions & {
+    file?: string;
+    resume?: string;
+    dryRun?: boolean;
+    yes?: boolean;
+    json?: boolean;
+  };
+
+export type RevokeAllOptions = TargetingOptions & {
+  status?: string;
+  role?: string;
+  emailDomain?: string;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+};
+
+type InvitationInput = Record<string, unknown> & { email_address: string; role: string };
+
+type BatchFailure = { email_addresses: string[]; error: string };
+
+type InvitationDefaults = {
+  role?: string;
+  /** Where `role` came from, for error messages: `--role` or the preset. */
+  roleSource: string;
+  redirectUrl?: string;
+  publicMetadata?: Record<string, unknown>;
+};
+
+/**
+ * A JSON invitations file: emails, or objects with the fields
+ * `POST /organizations/{id}/invitations/bulk` accepts per invitation.
+ */
+const INVITATIONS_FILE_SCHEMA: JsonSchema = {
+  type: "array",
+  minItems: 1,
+  items: {
+    anyOf: [
+      { type: "string", minLength: 1 },
+      {
+        type: "object",
+        required: ["email_address"],
+        additionalProperties: false,
+        properties: {
+          email_address: { type: "string", minLength: 1 },
+          role: { type: "string", minLength: 1 },
+          inviter_user_id: { type: "string" },
+          redirect_url: { type: "string" },
+          public_metadata: { type: "object" },
+          private_metadata: { type: "object" },
+          expires_in_days: { type: "integer", minimum: 1 },
+        },
+      },
+    ],
+  },
+};
+
+/** CSV columns read from an invitations file; any others are ignored. */
+const EMAIL_COLUMNS = ["email", "email_address"];
+
+function fromJson(text: string, source: string, role: string): InvitationInput[] {
+  const parsed = parseJsonInput(text, source);
+  assertMatchesSchema(text, parsed, INVITATIONS_FILE_SCHEMA, source);
+  return (parsed as (string | Record<string, unknown>)[]).map((entry) =>
+    typeof entry === "string"
+      ? { email_address: entry, role }
+      : { ...entry, email_address: String(entry.email_address), role: String(entry.role ?? role) },
+  );
+}
+
+function fromLines(text: string, source: string, role: string): InvitationInput[] {
+  const lines = text
+    .split(/\r?\n/)
+    .map((line) => line.trim())
+    .filter((line) => line && !line.startsWith("#"));
+  // Quoted cells may hold commas (e.g. a display name), so parse as real CSV.
+  const [header = [], ...rows] = parseCsv(lines.join("\n")).map((row) =>
+    row.map((cell) => cell.trim()),
+  );
+  const columns = header.map((cell) => cell.toLowerCase());
+  const emailColumn = columns.findIndex((cell) => EMAIL_COLUMNS.includes(cell));
+
+  if (emailColumn === -1) {
+    // A header row names its columns; a bare list has one address per line.
+    if (header.length > 1) {
+      throwUsageError(
+        `${source}: the header row needs an "email" or "email_address" column ` +
+          `(found ${header.join(", ")}).`,
+      );
+    }
+    return lines.map((line) => ({ email_address: line, role }));
+  }
+
+  const roleColumn = columns.indexOf("role");
+  return rows.map((cells, index) => {
+    const email = cells[emailColumn];
+    if (!email) throwUsageError(`${source}: row ${index + 2} has no email.`);
+    return { email_address: email, role: (roleColumn >= 0 && cells[roleColumn]) || role };
+  });
+}
+
+/**
+ * Parse an invitations file: a JSON array (of emails or BAPI invitation
+ * objects), a CSV with an `email` column and optional `role` column, or one
+ * email per line. Duplicate emails are dropped.
+ */
+export function parseInvitationsFile(
+  text: string,
+  source: string,
+  role = DEFAULT_INVITATION_ROLE,
+): InvitationInput[] {
+  const entries = text.trimStart().startsWith("[")
+    ? fromJson(text, source, role)
+    : fromLines(text, source, role);
+
+  const seen = new Set<string>();
+  const unique: InvitationInput[] = [];
+  for (const entry of entries) {
+    const emailError = validateEmail(entry.email_address);
+    if (emailError) throwUsageError(`${source}: ${emailError}.`);
+    const key = entry.email_address.toLowerCase();
+    if (seen.has(key)) continue;
+    seen.add(key);
+    unique.push(entry);
+  }
+  if (unique.length === 0) throwUsageError(`${source} contains no invitations.`);
+  return unique;
+}
+
+async function readInvitationsFile(path: string | undefined): Promise<string> {
+  if (!path) throwUsageError("Pass the invitations file with --file <path>.");
+  const file = Bun.file(path);
+  if (!(await file.exists())) {
+    throwUsageError(`File not found: ${path}`, undefined, ERROR_CODE.FILE_NOT_FOUND);
+  }
+  return file.text();
+}
+
+/**
+ * Resolve the secret key and the target organization. Without `orgArg`, a
+ * terminal user picks the organization from a search.
+ */
+export async function resolveOrganization(
+  orgArg: string | undefined,
+  options: TargetingOptions,
+): Promise<{ secretKey: string; organization: BapiOrganization }> {
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const org = await requireArg(await orgArgOrDefault(orgArg), {
+    label: "Organization",
+    prompt: () => pickOrganization({ secretKey }),
+  });
+  const organization = await withSpinner("Fetching organization...", () =>
+    withApiContext(fetchOrganization(secretKey, org), `Failed to fetch organization ${org}`),
+  );
+  return { secretKey, organization };
+}
+
+export function parsePublicMetadata(value: string): Record<string, unknown> {
+  const parsed = parseJsonInput(value, "--public-metadata");
+  if (!isRecord(parsed)) throwUsageError("--public-metadata must be a JSON object.");
+  return parsed;
+}
+
+/**
+ * Merge `--preset` with the role, redirect URL, and public metadata flags.
+ * Flags win over the preset's values.
+ */
+export async function resolveInvitationDefaults(
+  options: InvitationDefaultsOptions,
+): Promise<InvitationDefaults> {
+  const flagMetadata =
+    options.publicMetadata === undefined ? undefined : parsePublicMetadata(options.publicMetadata);
+  if (!options.preset) {
+    return {
+      role: options.role,
+      roleSource: "--role",
+      redirectUrl: options.redirectUrl,
+      publicMetadata: flagMetadata,
+    };
+  }
+
+  const preset = await getInvitationPreset(options.preset);
+  if (!preset) {
+    const names = Object.keys(await listInvitationPresets());
+    throwUsageError(
+      `No invitation preset named "${options.preset}". ` +
+        (names.length
+          ? `Available presets: ${names.join(", ")}.`
+          : "Create one with `clerk orgs invitations presets set`."),
+    );
+  }
+  return {
+    role: options.role ?? preset.role,
+    roleSource: options.role ? "--role" : `Preset "${options.preset}"`,
+    redirectUrl: options.redirectUrl ?? preset.redirectUrl,
+    publicMetadata: flagMetadata ?? preset.publicMetadata,
+  };
+}
+
+/** Fill in the redirect URL and public metadata on entries that don't set their own. */
+function applyDefaults(entry: InvitationInput, defaults: InvitationDefaults): InvitationInput {
+  return {
+    ...entry,
+    ...(defaults.redirectUrl &&
+      entry.redirect_url === undefined && { redirect_url: defaults.redirectUrl }),
+    ...(defaults.publicMetadata &&
+      entry.public_metadata === undefined && { public_metadata: defaults.publicMetadata }),
+  };
+}
+
+/**
+ * Reject roles the instance doesn't define before anything is sent, naming
+ * the flag, preset, or file entry they came from. Best effort: when the roles
+ * can't be listed, the API stays the judge.
+ */
+async function assertKnownRoles(
+  secretKey: string,
+  invitations: InvitationInput[],
+  defaults: InvitationDefaults,
+  file?: string,
+): Promise<void> {
+  let known: string[];
+  try {
+    known = (await listOrganizationRoles(secretKey)).map((role) => role.key);
+  } catch (error) {
+    if (!(error instanceof ApiError)) throw error;
+    log.debug(`invitations: skipping role check, could not list roles: ${errorMessage(error)}`);
+    return;
+  }
+  if (known.length === 0) return;
+
+  const available = `Available roles: ${known.join(", ")}.`;
+  const fallback = defaults.role ?? DEFAULT_INVITATION_ROLE;
+  for (const entry of invitations) {
+    if (known.includes(entry.role)) continue;
+    if (file && entry.role !== fallback) {
+      throwUsageError(
+        `${file}: "${entry.role}" (for ${entry.email_address}) is not a role on this ` +
+          `instance. ${available}`,
+      );
+    }
+    throwUsageError(
+      defaults.role
+        ? `${defaults.roleSource}: "${entry.role}" is not a role on this instance. ${available}`
+        : `The default role "${entry.role}" is not a role on this instance. Pass --role. ` +
+            available,
+    );
+  }
+}
+
+export async function confirmOrAbort(message: string, yes: boolean | undefined): Promise<void> {
+  if (!isHuman() || yes) return;
+  if (!(await confirm({ message }))) throwUserAbort();
+}
+
+function chunk<T>(items: T[], size: number): T[][] {
+  const chunks: T[][] = [];
+  for (let i = 0; i < items.length; i += size) chunks.push(items.slice(i, i + size));
+  return chunks;
+}
+
+/**
+ * Invite one or more addresses given with `--email`, using the role, redirect
+ * URL, and public metadata from the flags or `--preset`.
+ */
+export async function invitationsCreate(
+  org: string | undefined,
+  options: CreateOptions = {},
+): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const emails = options.email ?? [];
+  if (emails.length === 0) throwUsageError("Pass at least one --email <address>.");
+  for (const email of emails) {
+    const emailError = validateEmail(email);
+    if (emailError) throwUsageError(`--email: ${emailError}.`);
+  }
+
+  const defaults = await resolveInvitationDefaults(options);
+  const role = defaults.role ?? DEFAULT_INVITATION_ROLE;
+  const invitations = emails.map((email) =>
+    applyDefaults({ email_address: email, role }, defaults),
+  );
+
+  const { secretKey, organization } = await resolveOrganization(org, options);
+  await assertKnownRoles(secretKey, invitations, defaults);
+
+  if (options.dryRun) {
+    const path = `/v1/organizations/${organization.id}/invitations/bulk`;
+    if (json) {
+      const plan = { dry_run: true, method: "POST", path, body: invitations };
+      log.data(JSON.stringify(plan, null, 2));
+      return;
+    }
+    log.info(`[dry-run] POST ${path}`);
+    log.blank();
+    log.info(JSON.stringify(invitations, null, 2));
+    return;
+  }
+
+  const created = await withSpinner("Creating invitations...", () =>
+    withApiContext(
+      createOrganizationInvitations(secretKey, organization.id, invitations),
+      `Failed to invite to ${organization.name}`,
+    ),
+  );
+
+  if (printQuietIds(created.map((invitation) => invitation.id))) return;
+  if (json) {
+    log.data(JSON.stringify({ organization_id: organization.id, created }, null, 2));
+    return;
+  }
+  for (const invitation of created) {
+    log.success(
+      `Invited ${invitation.email_address} to ${organization.name} as ${invitation.role} ` +
+        dim(`(${invitation.id})`),
+    );
+  }
+}
+
+export async function invitationsBulkCreate(
+  org: string | undefined,
+  options: BulkCreateOptions = {},
+): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const defaults = await resolveInvitationDefaults(options);
+  const invitations = parseInvitationsFile(
+    await readInvitationsFile(options.file),
+    options.file!,
+    defaults.role,
+  ).map((entry) => applyDefaults(entry, defaults));
+
+  const { secretKey, organization } = await resolveOrganization(org, options);
+  const path = `/v1/organizations/${organization.id}/invitations/bulk`;
+  await assertKnownRoles(secretKey, invitations, defaults, options.file);
+
+  if (options.dryRun) {
+    if (json) {
+      const plan = { dry_run: true, method: "POST", path, body: invitations };
+      log.data(JSON.stringify(plan, null, 2));
+      return;
+    }
+    log.info(`[dry-run] POST ${path} (${invitations.length} invitations)`);
+    log.blank();
+    log.info(JSON.stringify(invitations, null, 2));
+    return;
+  }
+
+  // A checkpoint is only valid for the same file and organization; the
+  // cursor is the index of the next batch to send.
+  const job = await openJob(
+    options.resume,
+    "orgs invitations bulk-create",
+    { organization_id: organization.id, file: options.file! },
+    { batch: 0 },
+  );
+  const batches = chunk(invitations, BULK_BATCH_SIZE);
+  const pending = batches.slice(job.cursor.batch).flat();
+
+  if (!json) {
+    intro(`Inviting to ${organization.name}`);
+    for (const entry of pending) log.info(`  ${entry.email_address}  ${dim(entry.role)}`);
+  }
+  await confirmOrAbort(
+    `Send ${pending.length} invitation(s) to join ${organization.name}?`,
+    options.yes,
+  );
+
+  const created: BapiOrganizationInvitation[] = [];
+  const failed: BatchFailure[] = [];
+  await withProgress("Creating invitations...", pending.length, async (progress) => {
+    for (let index = job.cursor.batch; index < batches.length; index++) {
+      const batch = batches[index]!;
+      const attempt = await attemptBulk(
+        progress,
+        () => createOrganizationInvitations(secretKey, organization.id, batch),
+        batch.length,
+      );
+      if (attempt.ok) {
+        created.push(...attempt.value);
+      } else {
+        failed.push({
+          email_addresses: batch.map((entry) => entry.email_address),
+          error: attempt.error,
+        });
+      }
+      await job.save(
+        { batch: index + 1 },
+        { done: job.done + created.length, failed: job.failed + failed.length },
+      );
+    }
+  });
+  await job.finish();
+
+  if (failed.length > 0) process.exitCode = 1;
+
+  if (json) {
+    log.data(JSON.stringify({ organization_id: organization.id, created, failed }, null, 2));
+    return;
+  }
+
+  for (const failure of failed) {
+    log.error(`Failed to invite ${failure.email_addresses.join(", ")}: ${failure.error}`);
+  }
+  await outro(
+    failed.length
+      ? `Created ${created.length} invitation(s); ${failed.length} batch(es) failed`
+      : `Created ${created.length} invitation(s)`,
+  );
+}
+
+function matchesFilters(invitation: BapiOrganizationInvitation, options: RevokeAllOptions) {
+  if (options.role && invitation.role !== options.role) return false;
+  if (options.emailDomain) {
+    const domain = options.emailDomain.replace(/^@/, "").toLowerCase();
+    if (!invitation.email_address.toLowerCase().endsWith(`@${domain}`)) return false;
+  }
+  return true;
+}
+
+export async function invitationsRevokeAll(
+  org: string | undefined,
+  options: RevokeAllOptions = {},
+): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const status = options.status ?? "pending";
+  const { secretKey, organization } = await resolveOrganization(org, options);
+
+  const all = await withSpinner("Fetching invitations...", () =>
+    withApiContext(
+      listOrganizationInvitations(secretKey, organization.id, status),
+      "Failed to list invitations",
+    ),
+  );
+  const targets = all.filter((invitation) => matchesFilters(invitation, options));
+
+  if (targets.length === 0) {
+    if (json) log.data(JSON.stringify({ revoked: [], failed: [] }, null, 2));
+    else log.info(`No ${status} invitations match.`);
+    return;
+  }
+
+  if (options.dryRun) {
+    log.info(
+      `[dry-run] Would revoke ${targets.length} of ${all.length} ${status} invitation(s) ` +
+        `to ${organization.name}:`,
+    );
+    for (const invitation of targets) {
+      log.info(`  ${cyan(invitation.id)}  ${invitation.email_address}  ${dim(invitation.role)}`);
+    }
+    return;
+  }
+
+  if (!json) intro(`Revoking invitations to ${organization.name}`);
+  await confirmOrAbort(
+    `Revoke ${targets.length} ${status} invitation(s) to ${organization.name}?`,
+    options.yes,
+  );
+
+  const revoked: string[] = [];
+  const failed: { id: string; email_address: string; error: string }[] = [];
+  await withProgress("Revoking invitations...", targets.length, async (progress) => {
+    for (const invitation of targets) {
+      const attempt = await attemptBulk(progress, () =>
+        revokeOrganizationInvitation(secretKey, organization.id, invitation.id),
+      );
+      if (attempt.ok) {
+        revoked.push(invitation.id);
+      } else {
+        failed.push({
+          id: invitation.id,
+          email_address: invitation.email_address,
+          error: attempt.error,
+        });
+      }
+    }
+  });
+
+  if (failed.length > 0) process.exitCode = 1;
+
+  if (json) {
+    log.data(JSON.stringify({ revoked, failed }, null, 2));
+    return;
+  }
+
+  for (const failure of failed) {
+    log.error(`Failed to revoke ${failure.id} (${failure.email_address}): ${failure.error}`);
+  }
+  await outro(
+    failed.length
+      ? `Revoked ${revoked.length} invitation(s); ${failed.length} failed`
+      : `Revoked ${revoked.length} invitation(s)`,
+  );
+}
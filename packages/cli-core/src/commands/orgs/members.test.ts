@@ -0,0 +1,105 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, readFile, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { membersExport } = await import("./members.ts");
+
+const ORG = { id: "org_123", name: "Acme Inc", slug: "acme" };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body };
+}
+
+function membership(userId: string, role: string) {
+  return {
+    id: `orgmem_${userId}`,
+    role,
+    public_user_data: { user_id: userId, identifier: `${userId}@example.com` },
+    created_at: 1_700_000_000_000,
+  };
+}
+
+describe("orgs members export", () => {
+  const captured = useCaptureLog();
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-members-"));
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.startsWith("/organizations/acme")) return respond(ORG);
+      if (path.startsWith("/organizations/org_123/memberships")) {
+        return respond({
+          data: [membership("user_a", "org:admin"), membership("user_b", "org:member")],
+          total_count: 2,
+        });
+      }
+      return respond([
+        {
+          id: "user_a",
+          first_name: "Ada",
+          last_name: "Lovelace, Countess",
+          primary_email_address_id: "idn_2",
+          email_addresses: [
+            { id: "idn_1", email_address: "old@example.com" },
+            { id: "idn_2", email_address: "ada@example.com" },
+          ],
+        },
+      ]);
+    });
+  });
+
+  afterEach(async () => {
+    await rm(tempDir, { recursive: true, force: true });
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("looks up members' users in one batch and writes a CSV", async () => {
+    const file = join(tempDir, "members.csv");
+    await membersExport("acme", { file });
+
+    const paths = mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path);
+    expect(paths[2]).toBe("/users?limit=2&user_id=user_a&user_id=user_b");
+    expect(await readFile(file, "utf8")).toBe(
+      "user_id,email,first_name,last_name,role,joined_at\n" +
+        'user_a,ada@example.com,Ada,"Lovelace, Countess",org:admin,2023-11-14T22:13:20.000Z\n' +
+        "user_b,user_b@example.com,,,org:member,2023-11-14T22:13:20.000Z\n",
+    );
+  });
+
+  test("prints JSON rows to stdout without --file", async () => {
+    await membersExport("acme", { json: true });
+
+    const rows = JSON.parse(captured.out) as { user_id: string; email: string }[];
+    expect(rows.map((row) => row.email)).toEqual(["ada@example.com", "user_b@example.com"]);
+  });
+});
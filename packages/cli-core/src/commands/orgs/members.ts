@@ -0,0 +1,122 @@
+import { bapiRequest } from "../../lib/bapi.ts";
+import { formatCsv } from "../../lib/csv.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { listOrganizationMemberships } from "../../lib/organizations.ts";
+import { withProgress } from "../../lib/progress.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { primaryEmail, type BapiUserSummary } from "../../lib/users.ts";
+import { isAgent } from "../../mode.ts";
+import { resolveOrganization, type TargetingOptions } from "./invitations.ts";
+
+export type MembersExportOptions = TargetingOptions & {
+  file?: string;
+  json?: boolean;
+};
+
+export type MemberRow = {
+  user_id: string;
+  email: string;
+  first_name: string;
+  last_name: string;
+  role: string;
+  joined_at: string;
+};
+
+/** Users per lookup. The users list endpoint accepts up to 100 `user_id` filters. */
+const USER_BATCH_SIZE = 100;
+
+const CSV_HEADER: (keyof MemberRow)[] = [
+  "user_id",
+  "email",
+  "first_name",
+  "last_name",
+  "role",
+  "joined_at",
+];
+
+async function fetchUsersById(
+  secretKey: string,
+  userIds: string[],
+  onBatch: (size: number) => void,
+): Promise<Map<string, BapiUserSummary>> {
+  const users = new Map<string, BapiUserSummary>();
+  for (let i = 0; i < userIds.length; i += USER_BATCH_SIZE) {
+    const batch = userIds.slice(i, i + USER_BATCH_SIZE);
+    const params = new URLSearchParams({ limit: String(batch.length) });
+    for (const id of batch) params.append("user_id", id);
+    const response = await bapiRequest({ method: "GET", path: `/users?${params}`, secretKey });
+    for (const user of Array.isArray(response.body) ? (response.body as BapiUserSummary[]) : []) {
+      users.set(user.id, user);
+    }
+    onBatch(batch.length);
+  }
+  return users;
+}
+
+export function toCsv(rows: MemberRow[]): string {
+  return formatCsv(CSV_HEADER, rows);
+}
+
+/**
+ * Export an organization's members with each user's email and name. The
+ * memberships endpoint only carries user IDs and roles, so users are looked up
+ * in batches and joined in.
+ */
+export async function membersExport(
+  org: string | undefined,
+  options: MembersExportOptions = {},
+): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const { secretKey, organization } = await resolveOrganization(org, options);
+
+  if (!json && options.file) intro(`Exporting members of ${organization.name}`);
+  const memberships = await withSpinner("Fetching memberships...", () =>
+    withApiContext(
+      listOrganizationMemberships(secretKey, organization.id),
+      "Failed to list memberships",
+    ),
+  );
+  const userIds = [
+    ...new Set(memberships.flatMap((membership) => membership.public_user_data?.user_id ?? [])),
+  ];
+  const users = await withProgress("Looking up users...", userIds.length, (progress) =>
+    withApiContext(
+      fetchUsersById(secretKey, userIds, (size) => progress.advance(size)),
+      "Failed to look up users",
+    ),
+  );
+
+  const rows: MemberRow[] = memberships.map((membership) => {
+    const userId = membership.public_user_data?.user_id ?? "";
+    const user = users.get(userId);
+    return {
+      user_id: userId,
+      email: user ? (primaryEmail(user) ?? "") : (membership.public_user_data?.identifier ?? ""),
+      first_name: user?.first_name ?? "",
+      last_name: user?.last_name ?? "",
+      role: membership.role,
+      joined_at: membership.created_at ? new Date(membership.created_at).toISOString() : "",
+    };
+  });
+
+  if (!options.file) {
+    log.data(json ? JSON.stringify(rows, null, 2) : toCsv(rows).trimEnd());
+    return;
+  }
+
+  // The file's format follows its extension; --json only changes what stdout reports.
+  const asJson = options.file.toLowerCase().endsWith(".json");
+  await Bun.write(options.file, asJson ? `${JSON.stringify(rows, null, 2)}\n` : toCsv(rows));
+  if (json) {
+    log.data(
+      JSON.stringify(
+        { organization_id: organization.id, file: options.file, members: rows.length },
+        null,
+        2,
+      ),
+    );
+    return;
+  }
+  await outro(`Wrote ${rows.length} member(s) to ${options.file}`);
+}
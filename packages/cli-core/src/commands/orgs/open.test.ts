@@ -0,0 +1,63 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/config.ts", () => ({
+  resolveAppContext: async () => ({
+    appId: "app_123",
+    appLabel: "My App",
+    instanceId: "ins_dev",
+    instanceLabel: "development",
+  }),
+  getDefaultOrg: async () => undefined,
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const { orgsOpen } = await import("./open.ts");
+
+describe("orgs open", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockBapiRequest.mockResolvedValue({
+      status: 200,
+      headers: new Headers(),
+      body: { id: "org_456", name: "Acme", slug: "acme" },
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+  });
+
+  test("uses an org ID without calling the Backend API", async () => {
+    await orgsOpen("org_123", { print: true });
+
+    expect(captured.out).toEndWith("/apps/app_123/instances/ins_dev/organizations/org_123");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("resolves a slug to its org ID", async () => {
+    await orgsOpen("acme", { print: true });
+
+    expect(mockBapiRequest.mock.calls[0]![0]).toMatchObject({
+      path: "/organizations/acme?include_members_count=true",
+    });
+    expect(captured.out).toEndWith("/organizations/org_456");
+  });
+});
@@ -0,0 +1,52 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { resolveAppContext } from "../../lib/config.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { fetchOrganization } from "../../lib/organizations.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { openDashboardTarget } from "../open/index.ts";
+import { orgArgOrDefault, pickOrganization } from "./pick-organization.ts";
+
+export type OrgsOpenOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+  print?: boolean;
+};
+
+/**
+ * Open an organization's dashboard page. An `org_` ID is used as-is; a slug
+ * is looked up first, and the interactive picker searches organizations.
+ * Both need a Backend API secret key.
+ */
+export async function orgsOpen(
+  orgArg: string | undefined,
+  options: OrgsOpenOptions = {},
+): Promise<void> {
+  const target = await resolveAppContext({ app: options.app, instance: options.instance });
+  const resolveSecretKey = () =>
+    resolveBapiSecretKey({
+      secretKey: options.secretKey,
+      app: options.app,
+      instance: options.instance,
+    });
+
+  const org = await requireArg(await orgArgOrDefault(orgArg), {
+    label: "Organization",
+    prompt: async () => pickOrganization({ secretKey: await resolveSecretKey() }),
+  });
+  let orgId = org;
+  if (!org.startsWith("org_")) {
+    const secretKey = await resolveSecretKey();
+    const organization = await withSpinner("Fetching organization...", () =>
+      withApiContext(fetchOrganization(secretKey, org), `Failed to fetch organization ${org}`),
+    );
+    orgId = organization.id;
+  }
+
+  await openDashboardTarget(target, `organizations/${orgId}`, {
+    print: options.print,
+    title: "Opening organization",
+    details: { orgId },
+  });
+}
@@ -0,0 +1,43 @@
+import { getDefaultOrg } from "../../lib/config.ts";
+import { search, Separator } from "../../lib/listage.ts";
+import { log } from "../../lib/log.ts";
+import { type BapiOrganization, searchOrganizations } from "../../lib/organizations.ts";
+
+export type PickOrganizationOptions = {
+  secretKey: string;
+  message?: string;
+};
+
+const PICKER_LIMIT = 20;
+
+export function formatOrganizationChoice(org: BapiOrganization): string {
+  return org.slug ? `${org.name} (${org.slug}) — ${org.id}` : `${org.name} — ${org.id}`;
+}
+
+export async function pickOrganization(options: PickOrganizationOptions): Promise<string> {
+  return search<string>({
+    message: options.message ?? "Pick an organization:",
+    source: async (term) => {
+      // Request one extra so we can flag overflow with a refine-search hint.
+      const all = await searchOrganizations(options.secretKey, term ?? "", PICKER_LIMIT + 1);
+      const hasMore = all.length > PICKER_LIMIT;
+      const organizations = hasMore ? all.slice(0, PICKER_LIMIT) : all;
+
+      const choices: Array<{ value: string; name: string } | Separator> = organizations.map(
+        (org) => ({ value: org.id, name: formatOrganizationChoice(org) }),
+      );
+      if (hasMore) {
+        choices.push(new Separator("More results available, type to refine your search"));
+      }
+      return choices;
+    },
+  });
+}
+
+/** The `[org]` argument, else the default organization stored by `clerk use --org`. */
+export async function orgArgOrDefault(orgArg: string | undefined): Promise<string | undefined> {
+  if (orgArg) return orgArg;
+  const org = await getDefaultOrg();
+  if (org) log.debug(`orgs: using default organization ${org.id} from \`clerk use --org\``);
+  return org?.id;
+}
@@ -0,0 +1,258 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { BapiError } from "../../lib/errors.ts";
+import { configStubs, useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+mock.module("../../lib/config.ts", () => configStubs);
+
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: async () => true,
+  text: async () => "",
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { orgsRename, parseRenameMap, planRenames } = await import("./rename.ts");
+const { slugCheck } = await import("./slug.ts");
+
+type Org = { id: string; name: string; slug: string };
+type Call = { method: string; path: string; body?: string };
+
+let orgs: Org[] = [];
+
+/** A tiny in-memory BAPI: look up by ID or slug, and PATCH names and slugs. */
+function fakeBapi({ method, path, body }: Call) {
+  const ref = decodeURIComponent(path.split("/")[2]!.split("?")[0]!);
+  const org = orgs.find((candidate) => candidate.id === ref || candidate.slug === ref);
+  if (!org) throw new BapiError(404, JSON.stringify({ errors: [] }), new Headers());
+  if (method === "PATCH") Object.assign(org, JSON.parse(body!) as Partial<Org>);
+  return { status: 200, headers: new Headers(), body: { ...org }, rawBody: "" };
+}
+
+function patches(): Call[] {
+  return mockBapiRequest.mock.calls
+    .map(([args]) => args as Call)
+    .filter((call) => call.method === "PATCH");
+}
+
+const byRef = (...list: Org[]) => new Map(list.flatMap((org) => [[org.id, org], [org.slug, org]]));
+
+describe("parseRenameMap", () => {
+  test("reads quoted names and leaves empty cells alone", () => {
+    const rows = parseRenameMap(
+      'organization,name,slug\norg_1,"Acme, Inc.",acme-inc\nbeta,,beta-labs\n',
+      "renames.csv",
+    );
+
+    expect(rows).toEqual([
+      { row: 2, organization: "org_1", name: "Acme, Inc.", slug: "acme-inc" },
+      { row: 3, organization: "beta", name: undefined, slug: "beta-labs" },
+    ]);
+  });
+
+  test("rejects invalid slugs and files without the needed columns", () => {
+    expect(() => parseRenameMap("organization,slug\norg_1,Not A Slug\n", "r.csv")).toThrow(
+      /row 2 slug/,
+    );
+    expect(() => parseRenameMap("id,owner\norg_1,alice\n", "r.csv")).toThrow(/header row/);
+  });
+});
+
+describe("planRenames", () => {
+  const alpha = { id: "org_a", name: "Alpha", slug: "alpha" };
+  const beta = { id: "org_b", name: "Beta", slug: "beta" };
+  const gamma = { id: "org_c", name: "Gamma", slug: "gamma" };
+
+  test("orders a rename after the one that frees its slug", () => {
+    const plan = planRenames(
+      [
+        { row: 2, organization: "org_a", slug: "beta" },
+        { row: 3, organization: "org_b", slug: "beta-old" },
+      ],
+      byRef(alpha, beta),
+      byRef(beta),
+    );
+
+    expect(plan.renames.map((rename) => rename.row)).toEqual([3, 2]);
+    expect(plan.conflicts).toEqual([]);
+  });
+
+  test("reports taken slugs, duplicates, swaps, and missing organizations", () => {
+    const plan = planRenames(
+      [
+        { row: 2, organization: "org_a", slug: "gamma" },
+        { row: 3, organization: "org_b", slug: "shared" },
+        { row: 4, organization: "org_c", slug: "shared" },
+        { row: 5, organization: "missing", name: "Nobody" },
+        { row: 6, organization: "org_b", name: "Beta again" },
+      ],
+      byRef(alpha, beta, gamma),
+      byRef(gamma),
+    );
+
+    expect(plan.conflicts.map((item) => [item.row, item.reason])).toEqual([
+      [2, 'slug "gamma" is taken by Gamma (org_c)'],
+      [4, 'slug "shared" is also assigned in row 3'],
+      [5, "organization not found"],
+      [6, "org_b is already renamed in row 3"],
+    ]);
+    expect(plan.renames.map((rename) => rename.row)).toEqual([3]);
+
+    const swap = planRenames(
+      [
+        { row: 2, organization: "org_a", slug: "beta" },
+        { row: 3, organization: "org_b", slug: "alpha" },
+      ],
+      byRef(alpha, beta),
+      byRef(alpha, beta),
+    );
+    expect(swap.renames).toEqual([]);
+    expect(swap.conflicts[0]!.reason).toContain("temporary slug");
+  });
+
+  test("skips rows that already match", () => {
+    const plan = planRenames(
+      [{ row: 2, organization: "alpha", name: "Alpha", slug: "alpha" }],
+      byRef(alpha),
+      byRef(alpha),
+    );
+
+    expect(plan.unchanged).toHaveLength(1);
+    expect(plan.renames).toEqual([]);
+  });
+});
+
+describe("orgs rename", () => {
+  const captured = useCaptureLog();
+  let tempDir: string;
+  let mapFile: string;
+  const originalExitCode = process.exitCode;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-orgs-rename-"));
+    mapFile = join(tempDir, "renames.csv");
+    orgs = [
+      { id: "org_a", name: "Alpha", slug: "alpha" },
+      { id: "org_b", name: "Beta", slug: "beta" },
+    ];
+    mockBapiRequest.mockImplementation(async (call: Call) => fakeBapi(call));
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  afterEach(async () => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = originalExitCode;
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("applies renames in dependency order and verifies them", async () => {
+    await writeFile(mapFile, "organization,name,slug\nalpha,Alpha Co,beta\nbeta,,beta-legacy\n");
+
+    await orgsRename({ mapFile, yes: true, json: true });
+
+    expect(patches().map((call) => [call.path, JSON.parse(call.body!)])).toEqual([
+      ["/organizations/org_b", { slug: "beta-legacy" }],
+      ["/organizations/org_a", { name: "Alpha Co", slug: "beta" }],
+    ]);
+    const result = JSON.parse(captured.out) as { renamed: unknown[]; mismatches: unknown[] };
+    expect(result.renamed).toHaveLength(2);
+    expect(result.mismatches).toEqual([]);
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("flags a rename that doesn't read back as written", async () => {
+    await writeFile(mapFile, "organization,name\nalpha,Alpha Co\n");
+    mockBapiRequest.mockImplementation(async (call: Call) =>
+      // The PATCH succeeds but the change doesn't stick.
+      call.method === "PATCH" ? { status: 200, headers: new Headers(), body: {} } : fakeBapi(call),
+    );
+
+    await orgsRename({ mapFile, yes: true, json: true });
+
+    const result = JSON.parse(captured.out) as { mismatches: { actual: Org }[] };
+    expect(result.mismatches[0]!.actual.name).toBe("Alpha");
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("--dry-run changes nothing and still fails on conflicts", async () => {
+    await writeFile(mapFile, "organization,slug\nalpha,beta\n");
+
+    await orgsRename({ mapFile, dryRun: true, json: true });
+
+    expect(patches()).toEqual([]);
+    const plan = JSON.parse(captured.out) as { conflicts: { reason: string }[] };
+    expect(plan.conflicts[0]!.reason).toContain("taken by Beta");
+    expect(process.exitCode).toBe(1);
+  });
+});
+
+describe("orgs slug check", () => {
+  const captured = useCaptureLog();
+  const originalExitCode = process.exitCode;
+
+  beforeEach(() => {
+    orgs = [{ id: "org_a", name: "Alpha", slug: "alpha" }];
+    mockBapiRequest.mockImplementation(async (call: Call) => fakeBapi(call));
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = originalExitCode;
+  });
+
+  test("reports a free slug", async () => {
+    await slugCheck("acme");
+
+    expect(captured.err).toContain("acme is available");
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("names the organization holding a taken slug and exits 1", async () => {
+    await slugCheck("alpha", { json: true });
+
+    expect(JSON.parse(captured.out)).toEqual({
+      slug: "alpha",
+      available: false,
+      invalid_reason: null,
+      taken_by: { id: "org_a", name: "Alpha" },
+    });
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("rejects a malformed slug without calling the API", async () => {
+    await slugCheck("Not A Slug");
+
+    expect(captured.err).toContain("not a valid slug");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+    expect(process.exitCode).toBe(1);
+  });
+});
@@ -0,0 +1,326 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim, red } from "../../lib/color.ts";
+import { parseCsv } from "../../lib/csv.ts";
+import { ERROR_CODE, throwUsageError } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import {
+  findOrganization,
+  updateOrganization,
+  type BapiOrganization,
+} from "../../lib/organizations.ts";
+import { attemptBulk, withProgress } from "../../lib/progress.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { validateSlugFormat } from "../../lib/validate.ts";
+import { isAgent } from "../../mode.ts";
+import { confirmOrAbort, type TargetingOptions } from "./invitations.ts";
+
+export type OrgsRenameOptions = TargetingOptions & {
+  mapFile?: string;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+};
+
+/** One line of the map file. Empty `name` or `slug` cells leave that field alone. */
+export type RenameRow = {
+  row: number;
+  organization: string;
+  name?: string;
+  slug?: string;
+};
+
+export type Rename = {
+  row: number;
+  organization_id: string;
+  from: { name: string; slug: string | null };
+  to: { name?: string; slug?: string };
+};
+
+export type RenameConflict = {
+  row: number;
+  organization: string;
+  reason: string;
+};
+
+export type RenamePlan = {
+  /** In the order they must be applied: a slug is freed before it is reused. */
+  renames: Rename[];
+  unchanged: Rename[];
+  conflicts: RenameConflict[];
+};
+
+const ORGANIZATION_COLUMNS = ["organization", "organization_id", "org", "id"];
+
+/**
+ * Parse the map file: a CSV with an organization column (ID or slug) and
+ * `name` and/or `slug` columns holding the new values.
+ */
+export function parseRenameMap(text: string, source: string): RenameRow[] {
+  const [header = [], ...lines] = parseCsv(text);
+  const columns = header.map((cell) => cell.trim().toLowerCase());
+  const orgColumn = columns.findIndex((cell) => ORGANIZATION_COLUMNS.includes(cell));
+  const nameColumn = columns.indexOf("name");
+  const slugColumn = columns.indexOf("slug");
+  if (orgColumn === -1 || (nameColumn === -1 && slugColumn === -1)) {
+    throwUsageError(
+      `${source} needs a header row with an "organization" column (ID or slug) ` +
+        `and a "name" and/or "slug" column.`,
+    );
+  }
+
+  const rows = lines.map((cells, index) => {
+    const line = index + 2;
+    const cell = (column: number) => (column >= 0 ? cells[column]?.trim() || undefined : undefined);
+    const organization = cell(orgColumn);
+    if (!organization) throwUsageError(`${source}: row ${line} has no organization.`);
+    const slug = cell(slugColumn);
+    const formatError = slug ? validateSlugFormat(slug) : undefined;
+    if (formatError) throwUsageError(`${source}: row ${line} slug "${slug}": ${formatError}.`);
+    return { row: line, organization, name: cell(nameColumn), slug };
+  });
+  if (rows.length === 0) throwUsageError(`${source} contains no renames.`);
+  return rows;
+}
+
+/**
+ * Decide what to apply. `organizations` maps each row's organization
+ * reference, and `slugOwners` each new slug, to the organization that has it
+ * now (or `undefined`). A new slug held by another organization is a conflict,
+ * unless that organization is renamed away from it first; swap cycles need a
+ * temporary slug, so they are conflicts too.
+ */
+export function planRenames(
+  rows: RenameRow[],
+  organizations: Map<string, BapiOrganization | undefined>,
+  slugOwners: Map<string, BapiOrganization | undefined>,
+): RenamePlan {
+  const conflicts: RenameConflict[] = [];
+  const unchanged: Rename[] = [];
+  const candidates: Rename[] = [];
+  const rowByOrg = new Map<string, number>();
+  const rowBySlug = new Map<string, number>();
+  const conflict = (row: RenameRow, reason: string) =>
+    conflicts.push({ row: row.row, organization: row.organization, reason });
+
+  for (const row of rows) {
+    const org = organizations.get(row.organization);
+    if (!org) {
+      conflict(row, "organization not found");
+      continue;
+    }
+    const earlier = rowByOrg.get(org.id);
+    if (earlier !== undefined) {
+      conflict(row, `${org.id} is already renamed in row ${earlier}`);
+      continue;
+    }
+    rowByOrg.set(org.id, row.row);
+
+    const rename: Rename = {
+      row: row.row,
+      organization_id: org.id,
+      from: { name: org.name, slug: org.slug ?? null },
+      to: {},
+    };
+    if (row.name && row.name !== org.name) rename.to.name = row.name;
+    if (row.slug && row.slug !== org.slug) rename.to.slug = row.slug;
+    if (!rename.to.name && !rename.to.slug) {
+      unchanged.push(rename);
+      continue;
+    }
+    if (rename.to.slug) {
+      const slugRow = rowBySlug.get(rename.to.slug);
+      if (slugRow !== undefined) {
+        conflict(row, `slug "${rename.to.slug}" is also assigned in row ${slugRow}`);
+        continue;
+      }
+      rowBySlug.set(rename.to.slug, row.row);
+    }
+    candidates.push(rename);
+  }
+
+  // Each rename waits for the rename that frees its slug; anything left
+  // waiting when no more progress is possible is blocked or in a cycle.
+  const byOrg = new Map(candidates.map((rename) => [rename.organization_id, rename]));
+  const blocker = new Map<Rename, Rename>();
+  const pending = new Set<Rename>();
+  for (const rename of candidates) {
+    const owner = rename.to.slug ? slugOwners.get(rename.to.slug) : undefined;
+    if (!owner || owner.id === rename.organization_id) {
+      pending.add(rename);
+      continue;
+    }
+    const freeing = byOrg.get(owner.id);
+    if (!freeing?.to.slug) {
+      conflicts.push({
+        row: rename.row,
+        organization: rename.organization_id,
+        reason: `slug "${rename.to.slug}" is taken by ${owner.name} (${owner.id})`,
+      });
+      continue;
+    }
+    blocker.set(rename, freeing);
+    pending.add(rename);
+  }
+
+  const renames: Rename[] = [];
+  const applied = new Set<Rename>();
+  for (let progressed = true; progressed; ) {
+    progressed = false;
+    for (const rename of pending) {
+      const waitingOn = blocker.get(rename);
+      if (waitingOn && !applied.has(waitingOn)) continue;
+      renames.push(rename);
+      applied.add(rename);
+      pending.delete(rename);
+      progressed = true;
+    }
+  }
+  for (const rename of pending) {
+    const waitingOn = blocker.get(rename)!;
+    conflicts.push({
+      row: rename.row,
+      organization: rename.organization_id,
+      reason: pending.has(waitingOn)
+        ? `slug "${rename.to.slug}" is part of a swap with row ${waitingOn.row}; ` +
+          "rename through a temporary slug first"
+        : `slug "${rename.to.slug}" is only freed by row ${waitingOn.row}, which has a conflict`,
+    });
+  }
+
+  conflicts.sort((a, b) => a.row - b.row);
+  return { renames, unchanged, conflicts };
+}
+
+function formatChange(from: string | null, to: string | undefined): string {
+  return to === undefined ? dim(from ?? "-") : `${from ?? "-"} ${dim("→")} ${to}`;
+}
+
+async function readMapFile(path: string | undefined): Promise<string> {
+  if (!path) throwUsageError("Pass the renames file with --map-file <path>.");
+  const file = Bun.file(path);
+  if (!(await file.exists())) {
+    throwUsageError(`File not found: ${path}`, undefined, ERROR_CODE.FILE_NOT_FOUND);
+  }
+  return file.text();
+}
+
+/** Look up every organization and new slug the plan depends on, once each. */
+async function lookUp(secretKey: string, refs: string[]) {
+  const found = new Map<string, BapiOrganization | undefined>();
+  for (const ref of new Set(refs)) found.set(ref, await findOrganization(secretKey, ref));
+  return found;
+}
+
+type Failure = { row: number; organization_id: string; error: string };
+type Mismatch = Rename & { actual: { name: string; slug: string | null } | null };
+
+/**
+ * Apply many organization name and slug changes from a CSV. Conflicts are
+ * found before anything changes and those rows are left out; after applying,
+ * every renamed organization is fetched again to verify the result.
+ */
+export async function orgsRename(options: OrgsRenameOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const rows = parseRenameMap(await readMapFile(options.mapFile), options.mapFile!);
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  const plan = await withSpinner("Checking organizations and slugs...", async () => {
+    const organizations = await lookUp(secretKey, rows.map((row) => row.organization));
+    const slugOwners = await lookUp(secretKey, rows.flatMap((row) => row.slug ?? []));
+    return planRenames(rows, organizations, slugOwners);
+  });
+  const { renames, unchanged, conflicts } = plan;
+  if (conflicts.length > 0) process.exitCode = 1;
+
+  if (!json) {
+    intro(`Renaming organizations from ${options.mapFile}`);
+    for (const rename of renames) {
+      log.info(
+        `  ${cyan(rename.organization_id)}  ${formatChange(rename.from.name, rename.to.name)}  ` +
+          formatChange(rename.from.slug, rename.to.slug),
+      );
+    }
+    for (const item of conflicts) {
+      log.info(`  ${red("✗")} row ${item.row} ${item.organization}: ${item.reason}`);
+    }
+    if (unchanged.length) log.info(dim(`  ${unchanged.length} row(s) already match`));
+  }
+
+  if (options.dryRun || renames.length === 0) {
+    if (json) log.data(JSON.stringify({ dry_run: Boolean(options.dryRun), ...plan }, null, 2));
+    else await outro(renames.length ? "[dry-run] Nothing changed" : "Nothing to rename");
+    return;
+  }
+
+  await confirmOrAbort(
+    `Rename ${renames.length} organization(s)` +
+      (conflicts.length ? `, skipping ${conflicts.length} conflicting row(s)?` : "?"),
+    options.yes,
+  );
+
+  const renamed: Rename[] = [];
+  const failed: Failure[] = [];
+  await withProgress("Renaming organizations...", renames.length, async (progress) => {
+    for (const rename of renames) {
+      const attempt = await attemptBulk(progress, () =>
+        updateOrganization(secretKey, rename.organization_id, rename.to),
+      );
+      if (attempt.ok) {
+        renamed.push(rename);
+      } else {
+        failed.push({
+          row: rename.row,
+          organization_id: rename.organization_id,
+          error: attempt.error,
+        });
+      }
+    }
+  });
+
+  // Verification pass: read every renamed organization back.
+  const mismatches: Mismatch[] = [];
+  await withSpinner("Verifying...", async () => {
+    for (const rename of renamed) {
+      const actual = await findOrganization(secretKey, rename.organization_id);
+      const matches =
+        actual &&
+        (rename.to.name === undefined || actual.name === rename.to.name) &&
+        (rename.to.slug === undefined || actual.slug === rename.to.slug);
+      if (!matches) {
+        mismatches.push({
+          ...rename,
+          actual: actual ? { name: actual.name, slug: actual.slug ?? null } : null,
+        });
+      }
+    }
+  });
+  if (failed.length > 0 || mismatches.length > 0) process.exitCode = 1;
+
+  if (json) {
+    log.data(JSON.stringify({ renamed, unchanged, conflicts, failed, mismatches }, null, 2));
+    return;
+  }
+
+  for (const failure of failed) {
+    log.error(`Failed to rename ${failure.organization_id} (row ${failure.row}): ${failure.error}`);
+  }
+  for (const mismatch of mismatches) {
+    const actual = mismatch.actual
+      ? `${mismatch.actual.name} (${mismatch.actual.slug ?? "no slug"})`
+      : "not found";
+    log.error(`${mismatch.organization_id} (row ${mismatch.row}) reads back as ${actual}`);
+  }
+  const problems = [
+    conflicts.length && `${conflicts.length} conflicting`,
+    failed.length && `${failed.length} failed`,
+    mismatches.length && `${mismatches.length} unverified`,
+  ].filter(Boolean);
+  await outro(
+    `Renamed ${renamed.length - mismatches.length} organization(s)` +
+      (problems.length ? `; ${problems.join(", ")}` : ", all verified"),
+  );
+}
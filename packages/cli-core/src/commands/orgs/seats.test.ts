@@ -0,0 +1,143 @@
+import { test, expect, describe, beforeEach, mock } from "bun:test";
+import { configStubs, useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+mock.module("../../lib/config.ts", () => configStubs);
+
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+  text: async () => "",
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { nearingSeatLimit, seatUsage, seatsGet, seatsReport, seatsSet } = await import(
+  "./seats.ts"
+);
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body };
+}
+
+function org(id: string, members: number, max: number) {
+  return { id, name: id.replace("org_", ""), members_count: members, max_allowed_memberships: max };
+}
+
+describe("seatUsage", () => {
+  test("reports available seats and percentage in use", () => {
+    expect(seatUsage(org("org_a", 9, 12))).toMatchObject({
+      max_allowed_memberships: 12,
+      available: 3,
+      used_percent: 75,
+    });
+  });
+
+  test("treats 0 as no limit", () => {
+    expect(seatUsage(org("org_a", 40, 0))).toMatchObject({
+      max_allowed_memberships: null,
+      available: null,
+      used_percent: null,
+    });
+  });
+
+  test("never reports negative availability", () => {
+    expect(seatUsage(org("org_a", 12, 10)).available).toBe(0);
+  });
+});
+
+describe("nearingSeatLimit", () => {
+  test("keeps limited organizations at or above the threshold, fullest first", () => {
+    const orgs = [
+      org("org_a", 8, 10),
+      org("org_b", 5, 10),
+      org("org_c", 10, 10),
+      org("org_d", 99, 0),
+    ];
+    expect(nearingSeatLimit(orgs, 80).map((usage) => usage.id)).toEqual(["org_c", "org_a"]);
+  });
+});
+
+describe("orgs seats", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockBapiRequest.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  test("get prints JSON usage", async () => {
+    mockBapiRequest.mockResolvedValue(respond(org("org_123", 4, 5)));
+    await seatsGet("org_123", { json: true });
+    expect(JSON.parse(captured.out)).toMatchObject({
+      id: "org_123",
+      available: 1,
+      used_percent: 80,
+    });
+  });
+
+  test("set patches max_allowed_memberships", async () => {
+    mockBapiRequest.mockImplementation(async ({ method }: { method: string }) =>
+      respond(method === "PATCH" ? org("org_123", 4, 10) : org("org_123", 4, 5)),
+    );
+    await seatsSet("org_123", { max: 10 });
+
+    const patch = mockBapiRequest.mock.calls.find(([req]) => req.method === "PATCH")![0];
+    expect(patch.path).toBe("/organizations/org_123");
+    expect(JSON.parse(patch.body)).toEqual({ max_allowed_memberships: 10 });
+    expect(mockConfirm).not.toHaveBeenCalled();
+  });
+
+  test("set below the member count asks first", async () => {
+    mockBapiRequest.mockResolvedValue(respond(org("org_123", 8, 10)));
+    mockConfirm.mockResolvedValue(false);
+    await expect(seatsSet("org_123", { max: 5 })).rejects.toThrow();
+    expect(mockBapiRequest.mock.calls.some(([req]) => req.method === "PATCH")).toBe(false);
+  });
+
+  test("set below the member count needs --yes in agent mode", async () => {
+    mockIsAgent.mockReturnValue(true);
+    mockBapiRequest.mockResolvedValue(respond(org("org_123", 8, 10)));
+    await expect(seatsSet("org_123", { max: 5 })).rejects.toThrow("Pass --yes");
+  });
+
+  test("report pages through organizations", async () => {
+    const first = Array.from({ length: 100 }, (_, i) => org(`org_${i}`, 1, 10));
+    first[42] = org("org_full", 10, 10);
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) =>
+      path.includes("offset=0")
+        ? respond({ data: first, total_count: 101 })
+        : respond({ data: [org("org_near", 9, 10)], total_count: 101 }),
+    );
+    await seatsReport({ threshold: 90, json: true });
+
+    expect(mockBapiRequest).toHaveBeenCalledTimes(2);
+    const report = JSON.parse(captured.out);
+    expect(report.threshold).toBe(90);
+    expect(report.organizations.map((o: { id: string }) => o.id)).toEqual(["org_full", "org_near"]);
+  });
+});
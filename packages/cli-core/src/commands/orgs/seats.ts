@@ -0,0 +1,200 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bold } from "../../lib/color.ts";
+import { throwUsageError, throwUserAbort, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import {
+  fetchOrganization,
+  listOrganizations,
+  updateOrganization,
+  type BapiOrganization,
+} from "../../lib/organizations.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatFields, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+import { orgArgOrDefault, pickOrganization } from "./pick-organization.ts";
+
+type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type SeatsGetOptions = TargetingOptions & { json?: boolean };
+
+export type SeatsSetOptions = TargetingOptions & {
+  max?: number;
+  yes?: boolean;
+  json?: boolean;
+};
+
+export type SeatsReportOptions = TargetingOptions & {
+  threshold?: number;
+  json?: boolean;
+};
+
+/** Percentage of seats in use at which `seats report` lists an organization. */
+export const DEFAULT_THRESHOLD = 80;
+
+export type SeatUsage = {
+  id: string;
+  name: string;
+  slug: string | null;
+  members_count: number;
+  /** `null` when the organization has no limit (the API reports 0). */
+  max_allowed_memberships: number | null;
+  available: number | null;
+  /** Whole percent of seats in use, or `null` without a limit. */
+  used_percent: number | null;
+};
+
+export function seatUsage(org: BapiOrganization): SeatUsage {
+  const members = org.members_count ?? 0;
+  const max = org.max_allowed_memberships || null;
+  return {
+    id: org.id,
+    name: org.name,
+    slug: org.slug ?? null,
+    members_count: members,
+    max_allowed_memberships: max,
+    available: max === null ? null : Math.max(max - members, 0),
+    used_percent: max === null ? null : Math.round((members / max) * 100),
+  };
+}
+
+/**
+ * Limited organizations using at least `threshold` percent of their seats,
+ * fullest first. Organizations without a limit never fill up, so they're left
+ * out.
+ */
+export function nearingSeatLimit(orgs: BapiOrganization[], threshold: number): SeatUsage[] {
+  return orgs
+    .map(seatUsage)
+    .filter((usage) => usage.used_percent !== null && usage.used_percent >= threshold)
+    .sort((a, b) => b.used_percent! - a.used_percent! || b.members_count - a.members_count);
+}
+
+function resolveSecretKey(options: TargetingOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+async function resolveOrganization(
+  orgArg: string | undefined,
+  secretKey: string,
+): Promise<BapiOrganization> {
+  const org = await requireArg(await orgArgOrDefault(orgArg), {
+    label: "Organization",
+    prompt: () => pickOrganization({ secretKey }),
+  });
+  return withSpinner("Fetching organization...", () =>
+    withApiContext(fetchOrganization(secretKey, org), `Failed to fetch organization ${org}`),
+  );
+}
+
+function formatSeatUsage(usage: SeatUsage): string[] {
+  const limit =
+    usage.max_allowed_memberships === null
+      ? "no limit"
+      : `${usage.max_allowed_memberships} (${usage.available} available)`;
+  return [
+    bold(usage.name),
+    ...formatFields([
+      ["ID", usage.id],
+      ["Members", String(usage.members_count)],
+      ["Seat limit", limit],
+      ["In use", usage.used_percent === null ? undefined : `${usage.used_percent}%`],
+    ]),
+  ];
+}
+
+export async function seatsGet(
+  orgArg: string | undefined,
+  options: SeatsGetOptions = {},
+): Promise<void> {
+  const secretKey = await resolveSecretKey(options);
+  const usage = seatUsage(await resolveOrganization(orgArg, secretKey));
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(usage, null, 2));
+    return;
+  }
+  await printOutput(formatSeatUsage(usage), { page: false });
+}
+
+/**
+ * Set an organization's membership limit; 0 removes it. Lowering the limit
+ * below the current member count doesn't remove anyone but blocks new members
+ * and invitations, so it needs confirmation (or `--yes`).
+ */
+export async function seatsSet(
+  orgArg: string | undefined,
+  options: SeatsSetOptions = {},
+): Promise<void> {
+  if (options.max === undefined) throwUsageError("Pass the new limit with --max <n>.");
+  const max = options.max;
+  const secretKey = await resolveSecretKey(options);
+  const org = await resolveOrganization(orgArg, secretKey);
+  const members = org.members_count ?? 0;
+
+  if (max > 0 && max < members && !options.yes) {
+    const message =
+      `${org.name} has ${members} members, more than the new limit of ${max}. ` +
+      "Existing members stay, but no one can join until some leave.";
+    if (!isHuman()) throwUsageError(`${message} Pass --yes to set it anyway.`);
+    log.warn(message);
+    if (!(await confirm({ message: `Set the limit to ${max} anyway?` }))) throwUserAbort();
+  }
+
+  const updated = await withSpinner("Updating seat limit...", () =>
+    withApiContext(
+      updateOrganization(secretKey, org.id, { max_allowed_memberships: max }),
+      `Failed to update organization ${org.id}`,
+    ),
+  );
+  const usage = seatUsage({ ...updated, members_count: updated.members_count ?? members });
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(usage, null, 2));
+    return;
+  }
+  log.success(
+    max === 0
+      ? `Removed the seat limit for ${org.name}`
+      : `Set the seat limit for ${org.name} to ${max} (${members} in use)`,
+  );
+}
+
+const REPORT_COLUMNS: TableColumn<SeatUsage>[] = [
+  { key: "id", header: "ID", value: (row) => row.id },
+  { key: "name", header: "NAME", value: (row) => row.name },
+  { key: "members", header: "MEMBERS", value: (row) => String(row.members_count) },
+  { key: "limit", header: "LIMIT", value: (row) => String(row.max_allowed_memberships) },
+  { key: "used", header: "USED", value: (row) => `${row.used_percent}%` },
+];
+
+/** List organizations at or above `--threshold` percent of their seat limit. */
+export async function seatsReport(options: SeatsReportOptions = {}): Promise<void> {
+  const threshold = options.threshold ?? DEFAULT_THRESHOLD;
+  const secretKey = await resolveSecretKey(options);
+  const orgs = await withSpinner("Fetching organizations...", () =>
+    withApiContext(listOrganizations(secretKey), "Failed to list organizations"),
+  );
+  const nearing = nearingSeatLimit(orgs, threshold);
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify({ threshold, organizations: nearing }, null, 2));
+    return;
+  }
+  if (nearing.length === 0) {
+    log.info(`No organizations are at or above ${threshold}% of their seat limit.`);
+    return;
+  }
+  log.info(`${nearing.length} organization(s) at or above ${threshold}% of their seat limit:`);
+  await printOutput(renderTable(nearing, REPORT_COLUMNS));
+}
@@ -0,0 +1,68 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, green, red } from "../../lib/color.ts";
+import { EXIT_CODE, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { findOrganization } from "../../lib/organizations.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { validateSlugFormat } from "../../lib/validate.ts";
+import { isAgent } from "../../mode.ts";
+import type { TargetingOptions } from "./invitations.ts";
+
+export type SlugCheckOptions = TargetingOptions & {
+  json?: boolean;
+};
+
+export type SlugCheckResult = {
+  slug: string;
+  available: boolean;
+  /** Why the slug can't be used as written, when its format is wrong. */
+  invalid_reason: string | null;
+  /** The organization that already has the slug. */
+  taken_by: { id: string; name: string } | null;
+};
+
+/**
+ * Check whether an organization slug is free. Like `billing check`, a
+ * negative answer exits non-zero so scripts can branch on it.
+ */
+export async function slugCheck(
+  slugArg: string | undefined,
+  options: SlugCheckOptions = {},
+): Promise<void> {
+  const slug = (await requireArg(slugArg, { label: "Slug" })).trim();
+  const invalidReason = validateSlugFormat(slug) ?? null;
+
+  let takenBy: SlugCheckResult["taken_by"] = null;
+  if (!invalidReason) {
+    const secretKey = await resolveBapiSecretKey({
+      secretKey: options.secretKey,
+      app: options.app,
+      instance: options.instance,
+    });
+    const existing = await withSpinner("Checking slug...", () =>
+      withApiContext(findOrganization(secretKey, slug), `Failed to check slug ${slug}`),
+    );
+    if (existing) takenBy = { id: existing.id, name: existing.name };
+  }
+
+  const result: SlugCheckResult = {
+    slug,
+    available: !invalidReason && !takenBy,
+    invalid_reason: invalidReason,
+    taken_by: takenBy,
+  };
+  if (!result.available) process.exitCode = EXIT_CODE.GENERAL;
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(result, null, 2));
+    return;
+  }
+  if (invalidReason) {
+    log.info(`${red("✗")} ${slug} is not a valid slug. ${invalidReason}.`);
+  } else if (takenBy) {
+    log.info(`${red("✗")} ${slug} is taken by ${takenBy.name} ${cyan(takenBy.id)}`);
+  } else {
+    log.info(`${green("✓")} ${slug} is available`);
+  }
+}
@@ -0,0 +1,63 @@
+import { createOption } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { parseIntegerOption } from "../../lib/option-parsers.ts";
+import { RESTRICTION_LISTS } from "../../lib/restrictions.ts";
+import { MATCH_KINDS, MIN_INTERVAL, parseMaxPrune, restrictionsSync } from "./sync.ts";
+
+export function registerRestrictions(program: Program): void {
+  const restrictions = program
+    .command("restrictions")
+    .description("Manage the instance's allowlist and blocklist")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  restrictions
+    .command("sync")
+    .description("Reconcile the blocklist or allowlist with an external feed")
+    .requiredOption("--source <url|file>", "Feed to sync from: an http(s) URL or a local file")
+    .addOption(
+      createOption("--list <list>", "List to update")
+        .choices(RESTRICTION_LISTS)
+        .default("blocklist"),
+    )
+    .addOption(
+      createOption("--match <kind>", "What the feed's entries are")
+        .choices(MATCH_KINDS)
+        .default("email-domain"),
+    )
+    .option("--no-prune", "Only add entries; keep ones the feed no longer lists")
+    .option(
+      "--max-prune <n|percent>",
+      "Refuse to remove more entries than this count or share of the list (default 10%)",
+      parseMaxPrune,
+    )
+    .option("--force", "Prune even past --max-prune")
+    .option("--dry-run", "Print the diff without changing the list")
+    .option("-y, --yes", "Skip the confirmation prompt before removing entries")
+    .option(
+      "--interval <seconds>",
+      `Keep running and sync again every interval (${MIN_INTERVAL}+; requires --yes)`,
+      (value) => parseIntegerOption(value, "--interval", { min: MIN_INTERVAL }),
+    )
+    .option("--json", "Output the diff report as JSON")
+    .setExamples([
+      {
+        command: "clerk restrictions sync --source disposable-domains.txt --dry-run",
+        description: "Preview which email domains would be blocked or unblocked",
+      },
+      {
+        command:
+          "clerk restrictions sync --source https://feeds.example.com/domains.txt --yes " +
+          "--interval 3600",
+        description: "Keep the blocklist in step with a hosted feed, syncing hourly",
+      },
+      {
+        command: "clerk restrictions sync --source partners.csv --list allowlist --match email",
+        description: "Allow the addresses in a CSV's first column",
+      },
+    ])
+    .action((_opts, cmd) =>
+      restrictionsSync(cmd.optsWithGlobals() as Parameters<typeof restrictionsSync>[0]),
+    );
+}
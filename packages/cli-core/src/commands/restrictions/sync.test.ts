@@ -0,0 +1,242 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { BapiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockLoggedFetch = mock();
+mock.module("../../lib/fetch.ts", () => ({
+  loggedFetch: (...args: unknown[]) => mockLoggedFetch(...args),
+}));
+
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+  text: async () => "",
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { parseFeed, parseMaxPrune, planSync, pruneLimit, restrictionsSync } = await import(
+  "./sync.ts"
+);
+
+type Call = { method: string; path: string; body?: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+/** A fetch response whose headers are taken as given, Content-Length included. */
+function feedResponse(text: string, status: number, headers: Record<string, string> = {}) {
+  return {
+    ok: status >= 200 && status < 300,
+    status,
+    statusText: "",
+    headers: new Headers(headers),
+    arrayBuffer: async () => new TextEncoder().encode(text).buffer,
+  };
+}
+
+function calls(method: string): Call[] {
+  return mockBapiRequest.mock.calls
+    .map(([args]) => args as Call)
+    .filter((call) => call.method === method);
+}
+
+const CURRENT = [
+  { id: "blid_1", identifier: "*@kept.example" },
+  { id: "blid_2", identifier: "*@stale.example" },
+  { id: "blid_3", identifier: "someone@handpicked.example" },
+  { id: "blid_4", identifier: "+15555550100" },
+];
+
+describe("parseFeed", () => {
+  test("normalizes domains and skips comments, blanks, and junk", () => {
+    const feed = parseFeed(
+      "# disposable domains\nMailinator.example\n@temp.example, added 2026-01-02\n\n" +
+        "*@temp.example\nlocalhost\n",
+      "email-domain",
+    );
+
+    expect(feed.identifiers).toEqual(["*@mailinator.example", "*@temp.example"]);
+    expect(feed.skipped).toEqual([{ line: 6, value: "localhost" }]);
+  });
+
+  test("reads full addresses with --match email", () => {
+    const feed = parseFeed("a@example.com\nexample.com\n", "email");
+
+    expect(feed.identifiers).toEqual(["a@example.com"]);
+    expect(feed.skipped).toEqual([{ line: 2, value: "example.com" }]);
+  });
+});
+
+describe("planSync", () => {
+  test("adds missing entries and prunes only entries of the same kind", () => {
+    const plan = planSync(["*@kept.example", "*@new.example"], CURRENT, "email-domain");
+
+    expect(plan.add).toEqual(["*@new.example"]);
+    expect(plan.remove.map((entry) => entry.identifier)).toEqual(["*@stale.example"]);
+    expect(plan.unchanged).toBe(1);
+  });
+
+  test("never removes anything without pruning", () => {
+    const plan = planSync(["*@new.example"], CURRENT, "email-domain", false);
+
+    expect(plan.remove).toEqual([]);
+  });
+});
+
+describe("--max-prune", () => {
+  test("takes a count or a percentage of the managed entries, rounded up", () => {
+    expect(pruneLimit(parseMaxPrune("50"), 1000)).toBe(50);
+    expect(pruneLimit(parseMaxPrune("10%"), 1000)).toBe(100);
+    expect(pruneLimit(parseMaxPrune("10%"), 3)).toBe(1);
+  });
+
+  test("rejects anything else", () => {
+    expect(() => parseMaxPrune("ten")).toThrow(/--max-prune/);
+    expect(() => parseMaxPrune("150%")).toThrow(/--max-prune/);
+  });
+});
+
+describe("restrictions sync", () => {
+  const captured = useCaptureLog();
+  let tempDir: string;
+  let source: string;
+  const originalExitCode = process.exitCode;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-restrictions-sync-"));
+    source = join(tempDir, "domains.txt");
+    await writeFile(source, "kept.example\nnew.example\n");
+    mockBapiRequest.mockImplementation(async (call: Call) =>
+      call.method === "GET" ? respond({ data: CURRENT, total_count: CURRENT.length }) : respond({}),
+    );
+    mockIsAgent.mockReturnValue(false);
+  });
+
+  afterEach(async () => {
+    mockBapiRequest.mockReset();
+    mockLoggedFetch.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = originalExitCode;
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("adds and prunes after confirming, and reports the diff", async () => {
+    mockConfirm.mockResolvedValue(true);
+
+    await restrictionsSync({ source, json: true });
+
+    expect(mockConfirm).toHaveBeenCalledTimes(1);
+    expect(calls("POST").map((call) => [call.path, JSON.parse(call.body!)])).toEqual([
+      ["/blocklist_identifiers", { identifier: "*@new.example" }],
+    ]);
+    expect(calls("DELETE").map((call) => call.path)).toEqual(["/blocklist_identifiers/blid_2"]);
+    const report = JSON.parse(captured.out) as Record<string, unknown>;
+    expect(report).toMatchObject({
+      added: ["*@new.example"],
+      removed: ["*@stale.example"],
+      unchanged: 1,
+      failed: [],
+    });
+  });
+
+  test("--dry-run reports the diff without changing the list", async () => {
+    await restrictionsSync({ source, dryRun: true });
+
+    expect(calls("POST")).toEqual([]);
+    expect(calls("DELETE")).toEqual([]);
+    expect(captured.err).toContain("*@new.example");
+    expect(captured.err).toContain("*@stale.example");
+  });
+
+  test("targets the allowlist and records failed changes", async () => {
+    mockBapiRequest.mockImplementation(async (call: Call) => {
+      if (call.method === "GET") return respond([]);
+      const body = JSON.stringify({ errors: [{ message: "duplicate" }] });
+      throw new BapiError(422, body, new Headers());
+    });
+
+    await restrictionsSync({ source, list: "allowlist", yes: true, json: true });
+
+    expect(calls("POST")[0]!.path).toBe("/allowlist_identifiers");
+    const report = JSON.parse(captured.out) as { failed: { identifier: string }[] };
+    expect(report.failed.map((failure) => failure.identifier)).toEqual([
+      "*@kept.example",
+      "*@new.example",
+    ]);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("refuses to sync from a feed with no valid entries", async () => {
+    await writeFile(source, "# nothing yet\n");
+
+    await expect(restrictionsSync({ source, yes: true })).rejects.toThrow(/no valid/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("refuses to prune past --max-prune unless forced", async () => {
+    await writeFile(source, "new.example\n");
+
+    await expect(
+      restrictionsSync({ source, yes: true, maxPrune: { count: 1 } }),
+    ).rejects.toThrow(/remove 2 of 2 blocklist entries/);
+    expect(calls("POST")).toEqual([]);
+    expect(calls("DELETE")).toEqual([]);
+
+    await restrictionsSync({ source, yes: true, maxPrune: { count: 1 }, force: true, json: true });
+    expect(calls("DELETE")).toHaveLength(2);
+  });
+
+  test("only adds when a URL feed's body is shorter than its Content-Length", async () => {
+    mockLoggedFetch.mockResolvedValue(
+      feedResponse("kept.example\nnew.example\n", 200, { "Content-Length": "1000" }),
+    );
+
+    await restrictionsSync({ source: "https://feeds.example.com/domains.txt", yes: true });
+
+    expect(calls("POST")).toHaveLength(1);
+    expect(calls("DELETE")).toEqual([]);
+    expect(captured.err).toContain("only adding entries");
+  });
+
+  test("only adds when a URL feed answers a 2xx other than 200", async () => {
+    mockLoggedFetch.mockResolvedValue(feedResponse("kept.example\n", 203));
+
+    await restrictionsSync({ source: "https://feeds.example.com/domains.txt", yes: true });
+
+    expect(calls("DELETE")).toEqual([]);
+  });
+
+  test("requires --yes to run on an interval", async () => {
+    await expect(restrictionsSync({ source, interval: 3600 })).rejects.toThrow(/--yes/);
+  });
+});
@@ -0,0 +1,347 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim, green, red } from "../../lib/color.ts";
+import {
+  CliError,
+  ERROR_CODE,
+  EXIT_CODE,
+  errorMessage,
+  throwUsageError,
+  throwUserAbort,
+  withApiContext,
+} from "../../lib/errors.ts";
+import { loggedFetch } from "../../lib/fetch.ts";
+import { log } from "../../lib/log.ts";
+import { attemptBulk, withProgress } from "../../lib/progress.ts";
+import { confirm } from "../../lib/prompts.ts";
+import {
+  createIdentifier,
+  deleteIdentifier,
+  listIdentifiers,
+  type BapiListIdentifier,
+  type RestrictionList,
+} from "../../lib/restrictions.ts";
+import { sleep } from "../../lib/sleep.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { validateEmail } from "../../lib/validate.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+
+export const MATCH_KINDS = ["email-domain", "email"] as const;
+export type MatchKind = (typeof MATCH_KINDS)[number];
+
+export type RestrictionsSyncOptions = {
+  source?: string;
+  list?: RestrictionList;
+  match?: MatchKind;
+  /** `false` with `--no-prune`: only add entries. */
+  prune?: boolean;
+  /** Most entries one run may remove; see `parseMaxPrune`. */
+  maxPrune?: MaxPrune;
+  /** Prune past `maxPrune`. */
+  force?: boolean;
+  dryRun?: boolean;
+  yes?: boolean;
+  /** Seconds between runs; without it the sync runs once. */
+  interval?: number;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+/** Feeds are refetched at most this often with `--interval`. */
+export const MIN_INTERVAL = 60;
+
+export type MaxPrune = { count: number } | { percent: number };
+
+export const DEFAULT_MAX_PRUNE: MaxPrune = { percent: 10 };
+
+/** Parse `--max-prune`: a count of entries, or a percentage of the managed entries. */
+export function parseMaxPrune(value: string): MaxPrune {
+  const match = /^(\d+(?:\.\d+)?)(%?)$/.exec(value.trim());
+  if (match?.[2] === "%" && Number(match[1]) <= 100) return { percent: Number(match[1]) };
+  if (match && !match[2] && Number.isInteger(Number(match[1]))) return { count: Number(match[1]) };
+  throwUsageError(
+    `Invalid --max-prune value "${value}". Use a count like 50 or a percentage like 10%.`,
+  );
+}
+
+/**
+ * How many of `managed` entries one run may remove. A percentage rounds up,
+ * so a short list can still lose a single entry.
+ */
+export function pruneLimit(maxPrune: MaxPrune, managed: number): number {
+  return "count" in maxPrune ? maxPrune.count : Math.ceil((managed * maxPrune.percent) / 100);
+}
+
+const DOMAIN_PATTERN = /^(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$/;
+
+export type ParsedFeed = {
+  /** Identifiers as Clerk stores them, de-duplicated and sorted. */
+  identifiers: string[];
+  skipped: { line: number; value: string }[];
+};
+
+/**
+ * Parse a feed: one entry per line, `#` comments and blank lines ignored.
+ * Only the first field of a line is read, so CSV exports and annotated lists
+ * work too. Email domains may be written bare, as `@example.com`, or as
+ * `*@example.com`.
+ */
+export function parseFeed(text: string, match: MatchKind): ParsedFeed {
+  const identifiers = new Set<string>();
+  const skipped: ParsedFeed["skipped"] = [];
+  text.split(/\r?\n/).forEach((raw, index) => {
+    const value = raw.replace(/#.*/, "").trim().split(/[\s,;]+/)[0]!.toLowerCase();
+    if (!value) return;
+    const identifier = toIdentifier(value, match);
+    if (identifier) identifiers.add(identifier);
+    else skipped.push({ line: index + 1, value });
+  });
+  return { identifiers: [...identifiers].sort(), skipped };
+}
+
+function toIdentifier(value: string, match: MatchKind): string | undefined {
+  if (match === "email") return validateEmail(value) ? undefined : value;
+  const domain = value.replace(/^\*?@/, "");
+  return DOMAIN_PATTERN.test(domain) ? `*@${domain}` : undefined;
+}
+
+/**
+ * Whether a list entry is the kind the feed manages. Pruning never touches
+ * other kinds, so a domain feed leaves hand-added addresses and phone numbers
+ * alone.
+ */
+export function isManaged(identifier: string, match: MatchKind): boolean {
+  const isDomain = identifier.startsWith("*@");
+  return match === "email-domain" ? isDomain : !isDomain && identifier.includes("@");
+}
+
+export type SyncPlan = {
+  add: string[];
+  remove: BapiListIdentifier[];
+  unchanged: number;
+};
+
+export function planSync(
+  feed: string[],
+  current: BapiListIdentifier[],
+  match: MatchKind,
+  prune = true,
+): SyncPlan {
+  const wanted = new Set(feed);
+  const managed = current.filter((entry) => isManaged(entry.identifier.toLowerCase(), match));
+  const present = new Set(managed.map((entry) => entry.identifier.toLowerCase()));
+  const stale = managed.filter((entry) => !wanted.has(entry.identifier.toLowerCase()));
+  return {
+    add: feed.filter((identifier) => !present.has(identifier)),
+    remove: prune ? stale : [],
+    unchanged: managed.length - stale.length,
+  };
+}
+
+export type Feed = {
+  text: string;
+  /**
+   * False when a URL answered anything but a plain 200, or with fewer bytes
+   * than its Content-Length. Such a body may be missing entries, so it never
+   * prunes.
+   */
+  complete: boolean;
+};
+
+/** Read a feed from an http(s) URL or a local file. */
+export async function readFeed(source: string): Promise<Feed> {
+  if (/^https?:\/\//i.test(source)) {
+    const response = await loggedFetch(source, { tag: "restrictions", method: "GET" });
+    if (!response.ok) {
+      throw new CliError(`${source} answered ${response.status} ${response.statusText}.`);
+    }
+    const body = new Uint8Array(await response.arrayBuffer());
+    // A compressed response's Content-Length counts the encoded bytes.
+    const length = response.headers.has("content-encoding")
+      ? null
+      : response.headers.get("content-length");
+    return {
+      text: new TextDecoder().decode(body),
+      complete: response.status === 200 && (length === null || Number(length) === body.length),
+    };
+  }
+  const file = Bun.file(source);
+  if (!(await file.exists())) {
+    throwUsageError(`File not found: ${source}`, undefined, ERROR_CODE.FILE_NOT_FOUND);
+  }
+  return { text: await file.text(), complete: true };
+}
+
+export async function readFeedSource(source: string): Promise<string> {
+  return (await readFeed(source)).text;
+}
+
+type Failure = { identifier: string; error: string };
+
+export type SyncReport = {
+  list: RestrictionList;
+  match: MatchKind;
+  source: string;
+  dry_run: boolean;
+  added: string[];
+  removed: string[];
+  unchanged: number;
+  skipped: ParsedFeed["skipped"];
+  failed: Failure[];
+};
+
+type ResolvedOptions = RestrictionsSyncOptions & {
+  source: string;
+  list: RestrictionList;
+  match: MatchKind;
+  json: boolean;
+};
+
+async function syncOnce(secretKey: string, options: ResolvedOptions): Promise<SyncReport> {
+  const { source, list, match, json } = options;
+  const { text, complete } = await readFeed(source);
+  const feed = parseFeed(text, match);
+  // An empty feed is far more often a broken download than a real request to
+  // clear the list, so it never prunes.
+  if (feed.identifiers.length === 0) {
+    throw new CliError(`${source} has no valid ${match} entries; nothing was changed.`);
+  }
+
+  const current = await withSpinner(`Fetching the ${list}...`, () =>
+    withApiContext(listIdentifiers(secretKey, list), `Failed to fetch the ${list}`),
+  );
+  if (!complete && options.prune !== false) {
+    log.warn(`${source} didn't answer with a complete 200 response; only adding entries this run.`);
+  }
+  const plan = planSync(feed.identifiers, current, match, complete && options.prune !== false);
+  const report: SyncReport = {
+    list,
+    match,
+    source,
+    dry_run: Boolean(options.dryRun),
+    added: [],
+    removed: [],
+    unchanged: plan.unchanged,
+    skipped: feed.skipped,
+    failed: [],
+  };
+
+  if (!json) {
+    for (const identifier of plan.add) log.info(`  ${green("+")} ${identifier}`);
+    for (const entry of plan.remove) log.info(`  ${red("-")} ${entry.identifier}`);
+    if (feed.skipped.length) {
+      log.warn(`Skipped ${feed.skipped.length} line(s) that aren't valid ${match} entries.`);
+    }
+  }
+  const managed = plan.unchanged + plan.remove.length;
+  const limit = pruneLimit(options.maxPrune ?? DEFAULT_MAX_PRUNE, managed);
+  if (plan.remove.length > limit && !options.force) {
+    // A feed that suddenly drops most of its entries is more likely broken
+    // than reformed; refuse before changing anything.
+    throw new CliError(
+      `${source} would remove ${plan.remove.length} of ${managed} ${list} entries, more than ` +
+        `--max-prune allows (${limit}). Check the feed, then pass --force to prune anyway.`,
+    );
+  }
+  if (options.dryRun) {
+    report.added = plan.add;
+    report.removed = plan.remove.map((entry) => entry.identifier);
+    return report;
+  }
+  if (plan.add.length === 0 && plan.remove.length === 0) return report;
+
+  if (plan.remove.length > 0 && isHuman() && !options.yes && !options.interval) {
+    const message = `Add ${plan.add.length} and remove ${plan.remove.length} ${list} entries?`;
+    if (!(await confirm({ message }))) throwUserAbort();
+  }
+
+  const total = plan.add.length + plan.remove.length;
+  await withProgress(`Updating the ${list}...`, total, async (progress) => {
+    const attempt = async (identifier: string, change: () => Promise<unknown>) => {
+      const result = await attemptBulk(progress, change);
+      if (!result.ok) report.failed.push({ identifier, error: result.error });
+      return result.ok;
+    };
+    for (const identifier of plan.add) {
+      if (await attempt(identifier, () => createIdentifier(secretKey, list, identifier))) {
+        report.added.push(identifier);
+      }
+    }
+    for (const entry of plan.remove) {
+      if (await attempt(entry.identifier, () => deleteIdentifier(secretKey, list, entry.id))) {
+        report.removed.push(entry.identifier);
+      }
+    }
+  });
+  return report;
+}
+
+async function printReport(report: SyncReport, json: boolean, watching: boolean): Promise<void> {
+  if (json) {
+    // Watching prints one report per line so the stream can be tailed.
+    log.data(watching ? JSON.stringify(report) : JSON.stringify(report, null, 2));
+    return;
+  }
+  for (const failure of report.failed) {
+    log.error(`Failed to update ${failure.identifier}: ${failure.error}`);
+  }
+  const counts = report.dry_run
+    ? `[dry-run] Would add ${report.added.length} and remove ${report.removed.length}`
+    : `Added ${report.added.length}, removed ${report.removed.length}`;
+  const message =
+    `${counts}; ${report.unchanged} unchanged` +
+    (report.failed.length ? `; ${report.failed.length} failed` : "");
+  if (watching) log.info(`${dim(new Date().toISOString())} ${message}`);
+  else await outro(message);
+}
+
+/**
+ * Reconcile the instance's blocklist or allowlist with an external feed: add
+ * the feed's entries that are missing and, unless `--no-prune`, remove entries
+ * of the same kind that the feed no longer lists. With `--interval` it keeps
+ * running and syncs again every interval.
+ */
+export async function restrictionsSync(options: RestrictionsSyncOptions = {}): Promise<void> {
+  if (!options.source) throwUsageError("Pass the feed with --source <url|file>.");
+  if (options.interval && !options.yes && !options.dryRun) {
+    throwUsageError("--interval runs unattended; pass --yes to apply changes without prompting.");
+  }
+  const resolved: ResolvedOptions = {
+    ...options,
+    source: options.source,
+    list: options.list ?? "blocklist",
+    match: options.match ?? "email-domain",
+    json: Boolean(options.json || isAgent()),
+  };
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  if (!options.interval) {
+    if (!resolved.json) intro(`Syncing the ${resolved.list} with ${cyan(resolved.source)}`);
+    const report = await syncOnce(secretKey, resolved);
+    if (report.failed.length > 0) process.exitCode = EXIT_CODE.GENERAL;
+    await printReport(report, resolved.json, false);
+    return;
+  }
+
+  if (!resolved.json) {
+    log.info(
+      `Syncing the ${resolved.list} with ${cyan(resolved.source)} every ${options.interval}s. ` +
+        "Press Ctrl+C to stop.",
+    );
+  }
+  // Like `metrics serve`, this ends via SIGINT (130). A failed run (an
+  // unreachable feed, an API error) is logged and retried at the next interval.
+  for (;;) {
+    try {
+      await printReport(await syncOnce(secretKey, resolved), resolved.json, true);
+    } catch (error) {
+      log.error(`Sync failed: ${errorMessage(error)}`);
+    }
+    await sleep(options.interval * 1000);
+  }
+}
@@ -0,0 +1,161 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm, mkdir } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import {
+  crossCheckInstance,
+  readEnvEntries,
+  scanDeprecatedApis,
+  scanEnvNames,
+  scanSdks,
+  type EnvEntry,
+} from "./checks.ts";
+
+describe("scan checks", () => {
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-scan-"));
+  });
+
+  afterEach(async () => {
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("lists Clerk SDKs from package.json and go.mod and flags deprecated ones", async () => {
+    await Bun.write(
+      join(tempDir, "package.json"),
+      JSON.stringify({ dependencies: { "@clerk/clerk-react": "^4.0.0", react: "^18.0.0" } }),
+    );
+    await Bun.write(
+      join(tempDir, "go.mod"),
+      "module example.com/app\n\nrequire (\n\tgithub.com/clerk/clerk-sdk-go/v2 v2.1.0\n)\n",
+    );
+
+    const { sdks, findings } = await scanSdks(tempDir);
+
+    expect(sdks).toEqual([
+      { name: "@clerk/clerk-react", version: "^4.0.0", source: "package.json" },
+      { name: "github.com/clerk/clerk-sdk-go/v2", version: "v2.1.0", source: "go.mod" },
+    ]);
+    expect(findings).toHaveLength(1);
+    expect(findings[0]!.remedy).toBe("Migrate to @clerk/react.");
+  });
+
+  test("flags deprecated env var names and keeps the framework prefix", async () => {
+    await Bun.write(
+      join(tempDir, ".env.local"),
+      "# keys\nNEXT_PUBLIC_CLERK_AFTER_SIGN_IN_URL=/dashboard\nCLERK_SECRET_KEY=sk_test_1\n",
+    );
+
+    const findings = scanEnvNames(await readEnvEntries(tempDir));
+
+    expect(findings).toEqual([
+      {
+        category: "env",
+        level: "warn",
+        message: "NEXT_PUBLIC_CLERK_AFTER_SIGN_IN_URL is deprecated",
+        file: ".env.local",
+        line: 2,
+        remedy: "Rename it to NEXT_PUBLIC_CLERK_SIGN_IN_FALLBACK_REDIRECT_URL.",
+      },
+    ]);
+  });
+
+  test("finds deprecated APIs in source files and skips node_modules", async () => {
+    await mkdir(join(tempDir, "src"), { recursive: true });
+    await mkdir(join(tempDir, "node_modules/pkg"), { recursive: true });
+    await Bun.write(
+      join(tempDir, "src/middleware.ts"),
+      'import { authMiddleware } from "@clerk/nextjs";\n\nexport default authMiddleware({});\n',
+    );
+    await Bun.write(join(tempDir, "node_modules/pkg/index.js"), "authMiddleware();");
+
+    const findings = await scanDeprecatedApis(tempDir);
+
+    expect(findings).toHaveLength(1);
+    expect(findings[0]).toMatchObject({ file: "src/middleware.ts", line: 3 });
+  });
+});
+
+describe("crossCheckInstance", () => {
+  const app = {
+    application_id: "app_1",
+    name: "Acme",
+    instances: [
+      {
+        instance_id: "ins_dev",
+        environment_type: "development",
+        publishable_key: "pk_test_dev",
+        secret_key: "sk_test_dev",
+      },
+      {
+        instance_id: "ins_prod",
+        environment_type: "production",
+        publishable_key: "pk_live_prod",
+        secret_key: "sk_live_prod",
+      },
+    ],
+  };
+  const keyNames = { publishable: "CLERK_PUBLISHABLE_KEY", secret: "CLERK_SECRET_KEY" };
+
+  function env(values: Record<string, string>): EnvEntry[] {
+    return Object.entries(values).map(([key, value], index) => ({
+      key,
+      value,
+      file: ".env",
+      line: index + 1,
+    }));
+  }
+
+  test("reports which instance the keys belong to", () => {
+    const findings = crossCheckInstance(
+      env({ CLERK_PUBLISHABLE_KEY: "pk_test_dev", CLERK_SECRET_KEY: "sk_test_dev" }),
+      keyNames,
+      app,
+      ["example.com"],
+    );
+
+    expect(findings).toEqual([
+      expect.objectContaining({
+        level: "info",
+        message: "CLERK_PUBLISHABLE_KEY matches the development instance of Acme",
+      }),
+    ]);
+  });
+
+  test("flags keys from a different app and mixed environments", () => {
+    const findings = crossCheckInstance(
+      env({ CLERK_PUBLISHABLE_KEY: "pk_test_other", CLERK_SECRET_KEY: "sk_live_prod" }),
+      keyNames,
+      app,
+      [],
+    );
+
+    expect(findings.map((finding) => finding.message)).toEqual([
+      "CLERK_PUBLISHABLE_KEY and CLERK_SECRET_KEY are from different environments",
+      "CLERK_PUBLISHABLE_KEY does not belong to Acme",
+    ]);
+  });
+
+  test("flags production redirect URLs off the application's domains", () => {
+    const findings = crossCheckInstance(
+      env({
+        CLERK_PUBLISHABLE_KEY: "pk_live_prod",
+        CLERK_SIGN_IN_URL: "https://accounts.example.com/sign-in",
+        CLERK_SIGN_IN_FALLBACK_REDIRECT_URL: "https://staging.example.org/home",
+      }),
+      keyNames,
+      app,
+      ["example.com"],
+    );
+
+    expect(findings.filter((finding) => finding.level === "warn")).toEqual([
+      expect.objectContaining({
+        message:
+          "CLERK_SIGN_IN_FALLBACK_REDIRECT_URL points at staging.example.org, " +
+          "which is not a domain of Acme",
+      }),
+    ]);
+  });
+});
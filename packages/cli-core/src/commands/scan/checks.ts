@@ -0,0 +1,298 @@
+import { join } from "node:path";
+import { ENV_FILE_CANDIDATES, parseEnvFile } from "../../lib/dotenv.ts";
+import { readDeps } from "../../lib/framework.ts";
+import type { Application } from "../../lib/plapi.ts";
+
+export type SdkVersion = {
+  name: string;
+  version: string;
+  source: "package.json" | "go.mod";
+};
+
+export type ScanLevel = "info" | "warn";
+
+export type ScanFinding = {
+  category: "sdk" | "env" | "code" | "instance";
+  level: ScanLevel;
+  message: string;
+  file?: string;
+  line?: number;
+  remedy?: string;
+};
+
+export type EnvEntry = {
+  key: string;
+  value: string;
+  file: string;
+  line: number;
+};
+
+/** Packages and Go modules that were renamed or retired, with their replacement. */
+const DEPRECATED_SDKS: Record<string, string> = {
+  "@clerk/clerk-react": "@clerk/react",
+  "@clerk/clerk-expo": "@clerk/expo",
+  "@clerk/clerk-sdk-node": "@clerk/express",
+  "@clerk/remix": "@clerk/react-router",
+  "github.com/clerkinc/clerk-sdk-go": "github.com/clerk/clerk-sdk-go/v2",
+  "github.com/clerk/clerk-sdk-go": "github.com/clerk/clerk-sdk-go/v2",
+};
+
+/**
+ * Renamed env vars, matched by suffix so framework prefixes carry over
+ * (`NEXT_PUBLIC_CLERK_AFTER_SIGN_IN_URL` becomes
+ * `NEXT_PUBLIC_CLERK_SIGN_IN_FALLBACK_REDIRECT_URL`).
+ */
+const DEPRECATED_ENV_SUFFIXES: [string, string][] = [
+  ["CLERK_API_KEY", "CLERK_SECRET_KEY"],
+  ["CLERK_FRONTEND_API", "CLERK_PUBLISHABLE_KEY"],
+  ["CLERK_AFTER_SIGN_IN_URL", "CLERK_SIGN_IN_FALLBACK_REDIRECT_URL"],
+  ["CLERK_AFTER_SIGN_UP_URL", "CLERK_SIGN_UP_FALLBACK_REDIRECT_URL"],
+];
+
+const DEPRECATED_API_SCANS = [
+  {
+    pattern: "\\b(?:authMiddleware|withClerkMiddleware)\\s*\\(",
+    message: "authMiddleware() is deprecated",
+    remedy: "Use clerkMiddleware() and createRouteMatcher() instead.",
+  },
+  {
+    pattern: "\\bafterSign(?:In|Up)Url\\b",
+    message: "afterSignInUrl / afterSignUpUrl are deprecated",
+    remedy: "Use fallbackRedirectUrl or forceRedirectUrl instead.",
+  },
+  {
+    pattern: "\\bClerkExpress(?:WithAuth|RequireAuth)\\b",
+    message: "ClerkExpressWithAuth / ClerkExpressRequireAuth are deprecated",
+    remedy: "Use clerkMiddleware() and requireAuth() from @clerk/express instead.",
+  },
+].map((scan) => ({ ...scan, regex: new RegExp(scan.pattern, "m") }));
+
+const IGNORE_DIRS = new Set(["node_modules", ".next", "dist", ".git", "build", ".output", ".nuxt"]);
+
+const GO_SDK_RE = /^\s*(?:require\s+)?(github\.com\/clerk(?:inc)?\/clerk-sdk-go(?:\/v\d+)?)\s+(v\S+)/gm;
+
+function findLineNumber(content: string, matchIndex: number): number {
+  return content.slice(0, matchIndex).split("\n").length;
+}
+
+function isIgnored(relPath: string): boolean {
+  return relPath.split("/").some((seg) => IGNORE_DIRS.has(seg));
+}
+
+function deprecatedSdkFinding(sdk: SdkVersion): ScanFinding | undefined {
+  const replacement = DEPRECATED_SDKS[sdk.name];
+  if (!replacement) return undefined;
+  return {
+    category: "sdk",
+    level: "warn",
+    message: `${sdk.name} is deprecated`,
+    file: sdk.source,
+    remedy: `Migrate to ${replacement}.`,
+  };
+}
+
+/** Clerk SDKs declared in package.json and go.mod, plus any that are deprecated. */
+export async function scanSdks(
+  cwd: string,
+): Promise<{ sdks: SdkVersion[]; findings: ScanFinding[] }> {
+  const sdks: SdkVersion[] = [];
+
+  const deps = await readDeps(cwd);
+  for (const [name, version] of Object.entries(deps ?? {})) {
+    if (name.startsWith("@clerk/")) sdks.push({ name, version, source: "package.json" });
+  }
+
+  const goMod = Bun.file(join(cwd, "go.mod"));
+  if (await goMod.exists()) {
+    for (const match of (await goMod.text()).matchAll(GO_SDK_RE)) {
+      sdks.push({ name: match[1]!, version: match[2]!, source: "go.mod" });
+    }
+  }
+
+  const findings = sdks.flatMap((sdk) => deprecatedSdkFinding(sdk) ?? []);
+  return { sdks, findings };
+}
+
+/** Every entry in the env files the CLI reads, highest-priority file first. */
+export async function readEnvEntries(cwd: string): Promise<EnvEntry[]> {
+  const entries: EnvEntry[] = [];
+  for (const file of ENV_FILE_CANDIDATES) {
+    const handle = Bun.file(join(cwd, file));
+    if (!(await handle.exists())) continue;
+    for (const [index, line] of parseEnvFile(await handle.text()).entries()) {
+      if (line.type === "entry") {
+        entries.push({ key: line.key, value: line.value, file, line: index + 1 });
+      }
+    }
+  }
+  return entries;
+}
+
+export function scanEnvNames(entries: EnvEntry[]): ScanFinding[] {
+  const findings: ScanFinding[] = [];
+  for (const entry of entries) {
+    for (const [oldSuffix, newSuffix] of DEPRECATED_ENV_SUFFIXES) {
+      if (entry.key !== oldSuffix && !entry.key.endsWith(`_${oldSuffix}`)) continue;
+      const replacement = entry.key.slice(0, -oldSuffix.length) + newSuffix;
+      findings.push({
+        category: "env",
+        level: "warn",
+        message: `${entry.key} is deprecated`,
+        file: entry.file,
+        line: entry.line,
+        remedy: `Rename it to ${replacement}.`,
+      });
+    }
+  }
+  return findings;
+}
+
+export async function scanDeprecatedApis(cwd: string): Promise<ScanFinding[]> {
+  const glob = new Bun.Glob("**/*.{ts,tsx,js,jsx,mjs,cjs,vue,astro}");
+  const findings: ScanFinding[] = [];
+
+  for await (const relPath of glob.scan({ cwd })) {
+    if (isIgnored(relPath)) continue;
+
+    const content = await Bun.file(join(cwd, relPath)).text();
+    for (const scan of DEPRECATED_API_SCANS) {
+      const match = scan.regex.exec(content);
+      if (!match) continue;
+      findings.push({
+        category: "code",
+        level: "warn",
+        message: scan.message,
+        file: relPath,
+        line: findLineNumber(content, match.index),
+        remedy: scan.remedy,
+      });
+    }
+  }
+
+  return findings;
+}
+
+function firstEntry(entries: EnvEntry[], key: string): EnvEntry | undefined {
+  return entries.find((entry) => entry.key === key && entry.value !== "");
+}
+
+function keyEnvironment(key: string): "test" | "live" | undefined {
+  return /^[ps]k_(test|live)_/.exec(key)?.[1] as "test" | "live" | undefined;
+}
+
+function isOnDomain(hostname: string, domains: string[]): boolean {
+  return domains.some((domain) => hostname === domain || hostname.endsWith(`.${domain}`));
+}
+
+function redirectEntries(entries: EnvEntry[]): { entry: EnvEntry; url: URL }[] {
+  const seen = new Set<string>();
+  const result: { entry: EnvEntry; url: URL }[] = [];
+  for (const entry of entries) {
+    if (seen.has(entry.key) || !/CLERK_\w*(?:SIGN_IN|SIGN_UP|REDIRECT)\w*_URL$/.test(entry.key)) {
+      continue;
+    }
+    seen.add(entry.key);
+    if (!URL.canParse(entry.value)) continue;
+    result.push({ entry, url: new URL(entry.value) });
+  }
+  return result;
+}
+
+/**
+ * Compare the keys and absolute redirect URLs in the env files with the
+ * application's instances. Production redirect URLs must be on one of the
+ * application's domains; development redirect URLs should not point at them.
+ */
+export function crossCheckInstance(
+  entries: EnvEntry[],
+  keyNames: { publishable: string; secret: string },
+  app: Application,
+  productionDomains: string[],
+): ScanFinding[] {
+  const findings: ScanFinding[] = [];
+  const publishable = firstEntry(entries, keyNames.publishable);
+  const secret = firstEntry(entries, keyNames.secret);
+  const appLabel = app.name || app.application_id;
+
+  if (publishable && secret && keyEnvironment(publishable.value) !== keyEnvironment(secret.value)) {
+    findings.push({
+      category: "instance",
+      level: "warn",
+      message: `${publishable.key} and ${secret.key} are from different environments`,
+      file: secret.file,
+      line: secret.line,
+      remedy: "Run `clerk env pull` to write a matching pair.",
+    });
+  }
+
+  const instance = publishable
+    ? app.instances.find((entry) => entry.publishable_key === publishable.value)
+    : undefined;
+  if (!publishable) {
+    findings.push({
+      category: "instance",
+      level: "warn",
+      message: `No ${keyNames.publishable} in your env files`,
+      remedy: "Run `clerk env pull` to add your Clerk keys.",
+    });
+  } else if (!instance) {
+    findings.push({
+      category: "instance",
+      level: "warn",
+      message: `${publishable.key} does not belong to ${appLabel}`,
+      file: publishable.file,
+      line: publishable.line,
+      remedy: "Run `clerk env pull` to write this application's keys.",
+    });
+  } else {
+    const environment = instance.environment_type;
+    findings.push({
+      category: "instance",
+      level: "info",
+      message: `${publishable.key} matches the ${environment} instance of ${appLabel}`,
+      file: publishable.file,
+      line: publishable.line,
+    });
+  }
+
+  const secretOwner = secret
+    ? app.instances.find((entry) => entry.secret_key && entry.secret_key === secret.value)
+    : undefined;
+  if (secret && instance && secretOwner && secretOwner.instance_id !== instance.instance_id) {
+    findings.push({
+      category: "instance",
+      level: "warn",
+      message:
+        `${secret.key} is for the ${secretOwner.environment_type} instance, ` +
+        `but ${publishable!.key} is for ${instance.environment_type}`,
+      file: secret.file,
+      line: secret.line,
+      remedy: "Run `clerk env pull` to write a matching pair.",
+    });
+  }
+
+  if (!instance) return findings;
+  for (const { entry, url } of redirectEntries(entries)) {
+    const onProductionDomain = isOnDomain(url.hostname, productionDomains);
+    if (instance.environment_type === "production" && !onProductionDomain) {
+      findings.push({
+        category: "instance",
+        level: "warn",
+        message: `${entry.key} points at ${url.hostname}, which is not a domain of ${appLabel}`,
+        file: entry.file,
+        line: entry.line,
+        remedy: "Use a path, or a URL on your production domain.",
+      });
+    } else if (instance.environment_type === "development" && onProductionDomain) {
+      findings.push({
+        category: "instance",
+        level: "warn",
+        message: `${entry.key} points at production domain ${url.hostname} with development keys`,
+        file: entry.file,
+        line: entry.line,
+        remedy: "Use a path, or a URL on your development origin.",
+      });
+    }
+  }
+  return findings;
+}
@@ -0,0 +1,137 @@
+import type { Program } from "../../cli-program.ts";
+import { bold, cyan, dim, green, yellow } from "../../lib/color.ts";
+import { resolveProfile } from "../../lib/config.ts";
+import { errorMessage, EXIT_CODE } from "../../lib/errors.ts";
+import { detectPublishableKeyName, detectSecretKeyName } from "../../lib/framework.ts";
+import { log } from "../../lib/log.ts";
+import { fetchApplication, listApplicationDomains } from "../../lib/plapi.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import {
+  crossCheckInstance,
+  readEnvEntries,
+  scanDeprecatedApis,
+  scanEnvNames,
+  scanSdks,
+  type EnvEntry,
+  type ScanFinding,
+  type SdkVersion,
+} from "./checks.ts";
+
+type ScanOptions = {
+  app?: string;
+  json?: boolean;
+};
+
+const SDK_COLUMNS: TableColumn<SdkVersion>[] = [
+  { key: "name", header: "SDK", value: (sdk) => sdk.name, style: cyan },
+  { key: "version", header: "VERSION", value: (sdk) => sdk.version },
+  { key: "source", header: "SOURCE", value: (sdk) => sdk.source, style: dim },
+];
+
+async function scanInstance(
+  cwd: string,
+  entries: EnvEntry[],
+  appIdOption?: string,
+): Promise<ScanFinding[]> {
+  const appId = appIdOption ?? (await resolveProfile(cwd))?.profile.appId;
+  if (!appId) {
+    return [
+      {
+        category: "instance",
+        level: "info",
+        message: "Not linked to a Clerk application; skipped instance checks",
+        remedy: "Run `clerk link`, or pass --app <id>.",
+      },
+    ];
+  }
+
+  try {
+    const app = await fetchApplication(appId);
+    const hasProduction = app.instances.some((entry) => entry.environment_type === "production");
+    const domains = hasProduction ? (await listApplicationDomains(appId)).data : [];
+    const keyNames = {
+      publishable: await detectPublishableKeyName(cwd),
+      secret: await detectSecretKeyName(cwd),
+    };
+    return crossCheckInstance(entries, keyNames, app, domains.map((domain) => domain.name));
+  } catch (error) {
+    return [
+      {
+        category: "instance",
+        level: "warn",
+        message: `Could not load application ${appId}: ${errorMessage(error)}`,
+      },
+    ];
+  }
+}
+
+function formatFinding(finding: ScanFinding): string {
+  const icon = finding.level === "warn" ? yellow("!") : green("✓");
+  const location = finding.file
+    ? `  ${cyan(finding.line ? `${finding.file}:${finding.line}` : finding.file)}`
+    : "";
+  let line = `  ${icon} ${finding.message}${location}`;
+  if (finding.level === "warn" && finding.remedy) line += `\n      ${dim(finding.remedy)}`;
+  return line;
+}
+
+export async function scan(options: ScanOptions = {}): Promise<void> {
+  const cwd = process.cwd();
+  const json = Boolean(options.json || isAgent());
+
+  if (!json) intro("Scanning project");
+  const { sdks, findings } = await withSpinner("Scanning project...", async () => {
+    const [sdkScan, entries, codeFindings] = await Promise.all([
+      scanSdks(cwd),
+      readEnvEntries(cwd),
+      scanDeprecatedApis(cwd),
+    ]);
+    const instanceFindings = await scanInstance(cwd, entries, options.app);
+    return {
+      sdks: sdkScan.sdks,
+      findings: [
+        ...sdkScan.findings,
+        ...scanEnvNames(entries),
+        ...codeFindings,
+        ...instanceFindings,
+      ],
+    };
+  });
+
+  const warnings = findings.filter((finding) => finding.level === "warn");
+  process.exitCode = warnings.length > 0 ? EXIT_CODE.GENERAL : EXIT_CODE.SUCCESS;
+
+  if (json) {
+    log.data(JSON.stringify({ sdks, findings }, null, 2));
+    return;
+  }
+
+  log.info(bold("SDKs"));
+  if (sdks.length === 0) {
+    log.info(dim("  No Clerk SDKs in package.json or go.mod"));
+  } else {
+    for (const line of renderTable(sdks, SDK_COLUMNS)) log.info(`  ${line}`);
+  }
+  log.blank();
+  log.info(bold("Findings"));
+  for (const finding of findings) log.info(formatFinding(finding));
+  if (findings.length === 0) log.info(dim("  Nothing to report"));
+  log.blank();
+
+  outro(warnings.length ? `${warnings.length} warning(s)` : "No issues found");
+}
+
+export function registerScan(program: Program): void {
+  program
+    .command("scan")
+    .description("Check Clerk SDK versions, deprecated usage, and keys against your instance")
+    .option("--app <id>", "Application ID to check keys against (defaults to the linked app)")
+    .option("--json", "Output SDKs and findings as JSON")
+    .setExamples([
+      { command: "clerk scan", description: "Scan the current project" },
+      { command: "clerk scan --json", description: "Output findings as JSON for CI" },
+    ])
+    .action(scan);
+}
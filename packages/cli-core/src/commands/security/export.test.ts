@@ -0,0 +1,124 @@
+import { test, expect, describe, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const { formatCef, securityExport, userSecurityEvents } = await import("./export.ts");
+
+const BANNED = {
+  id: "user_banned",
+  banned: true,
+  primary_email_address_id: "idn_1",
+  email_addresses: [{ id: "idn_1", email_address: "mallory@example.com" }],
+  updated_at: Date.UTC(2024, 5, 1, 12),
+};
+const LOCKED = {
+  id: "user_locked",
+  locked: true,
+  lockout_expires_in_seconds: 1800,
+  updated_at: Date.UTC(2024, 5, 1, 10),
+};
+const QUIET = { id: "user_quiet", updated_at: Date.UTC(2024, 5, 1, 11) };
+const OLD = { id: "user_old", banned: true, updated_at: Date.UTC(2024, 4, 1) };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("userSecurityEvents", () => {
+  test("emits an event per ban and lockout", () => {
+    expect(userSecurityEvents({ ...BANNED, locked: true }).map((event) => event.type)).toEqual([
+      "user.banned",
+      "user.locked",
+    ]);
+    expect(userSecurityEvents(QUIET)).toEqual([]);
+  });
+
+  test("gives the same state the same ID on every export", () => {
+    const [first] = userSecurityEvents(BANNED);
+    const [again] = userSecurityEvents({ ...BANNED });
+    const [changed] = userSecurityEvents({ ...BANNED, updated_at: BANNED.updated_at + 1 });
+    expect(first!.id).toBe(`user_banned:user.banned:${BANNED.updated_at}`);
+    expect(again!.id).toBe(first!.id);
+    expect(changed!.id).not.toBe(first!.id);
+  });
+
+  test("includes the remaining lockout", () => {
+    const [event] = userSecurityEvents(LOCKED);
+    expect(event!.lockout_expires_in_seconds).toBe(1800);
+    expect(event!.email).toBeNull();
+  });
+});
+
+describe("formatCef", () => {
+  test("writes header and extension fields", () => {
+    const [event] = userSecurityEvents(LOCKED);
+    expect(formatCef(event!, "1.2.3")).toBe(
+      "CEF:0|Clerk|Clerk CLI|1.2.3|user.locked|User locked out|5|" +
+        `externalId=user_locked:user.locked:${LOCKED.updated_at} rt=${LOCKED.updated_at} ` +
+        "duid=user_locked cn1Label=lockoutSeconds cn1=1800",
+    );
+  });
+
+  test("escapes extension values", () => {
+    const [event] = userSecurityEvents({
+      ...BANNED,
+      email_addresses: [{ id: "idn_1", email_address: "a=b\\c@example.com" }],
+    });
+    expect(formatCef(event!, "1.2.3")).toContain("duser=a\\=b\\\\c@example.com");
+  });
+});
+
+describe("security export", () => {
+  const captured = useCaptureLog();
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+  });
+
+  test("prints events in the window as JSON Lines, oldest first", async () => {
+    mockBapiRequest.mockResolvedValue(respond([BANNED, QUIET, LOCKED, OLD]));
+
+    await securityExport({ since: Date.UTC(2024, 5, 1) });
+
+    const [{ path }] = mockBapiRequest.mock.calls[0] as [{ path: string }];
+    expect(path).toBe("/users?limit=500&offset=0&order_by=-updated_at");
+    const events = captured.out
+      .trim()
+      .split("\n")
+      .map((line) => JSON.parse(line) as { type: string; user_id: string; timestamp: string });
+    expect(events.map((event) => event.user_id)).toEqual(["user_locked", "user_banned"]);
+    expect(events[1]!.timestamp).toBe("2024-06-01T12:00:00.000Z");
+  });
+
+  test("stops paging once users predate the window", async () => {
+    const page = Array.from({ length: 500 }, (_, index) => ({
+      id: `user_${index}`,
+      updated_at: Date.UTC(2024, 5, 2),
+    }));
+    mockBapiRequest
+      .mockResolvedValueOnce(respond(page))
+      .mockResolvedValueOnce(respond([BANNED, OLD]));
+
+    await securityExport({ since: Date.UTC(2024, 5, 1), format: "cef" });
+
+    expect(mockBapiRequest).toHaveBeenCalledTimes(2);
+    expect(captured.out.trim().split("\n")).toHaveLength(1);
+    expect(captured.out).toStartWith("CEF:0|Clerk|Clerk CLI|");
+  });
+});
@@ -0,0 +1,170 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { getCurrentVersion } from "../../lib/update-check.ts";
+import { primaryEmail, type BapiUserSummary } from "../../lib/users.ts";
+
+export const EXPORT_FORMATS = ["jsonl", "cef"] as const;
+
+export type SecurityExportOptions = {
+  format?: (typeof EXPORT_FORMATS)[number];
+  since?: number;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/** BAPI's MaxLimit for `GET /users`. */
+const PAGE_SIZE = 500;
+
+type SecurityUser = BapiUserSummary & {
+  banned?: boolean;
+  locked?: boolean;
+  lockout_expires_in_seconds?: number | null;
+  updated_at?: number;
+};
+
+export type SecurityEventType = "user.banned" | "user.locked";
+
+/**
+ * A ban or lockout seen on a user. The Backend API has no audit log, so this
+ * is a snapshot of the user's current state rather than a record of the
+ * change, and later exports re-emit it while the state lasts.
+ */
+export type SecurityEvent = {
+  /**
+   * Stable across exports for the same state: the user, the event type, and
+   * the time the user last changed. Dedupe on it downstream.
+   */
+  id: string;
+  type: SecurityEventType;
+  /** When the user last changed, in ms. The Backend API has no per-event timestamp. */
+  timestamp: number;
+  user_id: string;
+  email: string | null;
+  /** CEF severity, 0-10. */
+  severity: number;
+  lockout_expires_in_seconds?: number;
+};
+
+const EVENT_NAMES: Record<SecurityEventType, string> = {
+  "user.banned": "User banned",
+  "user.locked": "User locked out",
+};
+
+/** Snapshot events for the user's current ban and lockout state. */
+export function userSecurityEvents(user: SecurityUser): SecurityEvent[] {
+  const timestamp = user.updated_at ?? 0;
+  const base = {
+    timestamp,
+    user_id: user.id,
+    email: primaryEmail(user) ?? null,
+  };
+  const events: SecurityEvent[] = [];
+  const eventId = (type: SecurityEventType) => `${user.id}:${type}:${timestamp}`;
+  if (user.banned) {
+    events.push({ id: eventId("user.banned"), type: "user.banned", severity: 7, ...base });
+  }
+  if (user.locked) {
+    events.push({
+      id: eventId("user.locked"),
+      type: "user.locked",
+      severity: 5,
+      ...base,
+      ...(typeof user.lockout_expires_in_seconds === "number" && {
+        lockout_expires_in_seconds: user.lockout_expires_in_seconds,
+      }),
+    });
+  }
+  return events;
+}
+
+/**
+ * Users changed since `since`, newest first. BAPI can't filter by
+ * `updated_at`, so pages sorted by it are read until one reaches older users.
+ */
+async function usersUpdatedSince(secretKey: string, since: number): Promise<SecurityUser[]> {
+  const users: SecurityUser[] = [];
+  for (let offset = 0; ; offset += PAGE_SIZE) {
+    const params = new URLSearchParams({
+      limit: String(PAGE_SIZE),
+      offset: String(offset),
+      order_by: "-updated_at",
+    });
+    const response = await bapiRequest({ method: "GET", path: `/users?${params}`, secretKey });
+    const page = Array.isArray(response.body) ? (response.body as SecurityUser[]) : [];
+    for (const user of page) {
+      if ((user.updated_at ?? 0) < since) return users;
+      users.push(user);
+    }
+    if (page.length < PAGE_SIZE) return users;
+  }
+}
+
+/** CEF header fields escape `\` and `|`. */
+function cefHeader(value: string): string {
+  return value.replace(/[\\|]/g, (char) => `\\${char}`);
+}
+
+/** CEF extension values escape `\`, `=`, and line breaks. */
+function cefValue(value: string): string {
+  return value
+    .replace(/[\\=]/g, (char) => `\\${char}`)
+    .replace(/\r/g, "\\r")
+    .replace(/\n/g, "\\n");
+}
+
+/**
+ * One ArcSight Common Event Format line. The event ID goes in `externalId`,
+ * users in `duid`/`duser` (the affected account), and the event time in `rt`,
+ * as epoch milliseconds.
+ */
+export function formatCef(event: SecurityEvent, version = getCurrentVersion()): string {
+  const header = ["CEF:0", "Clerk", "Clerk CLI", version, event.type, EVENT_NAMES[event.type]]
+    .map((field, index) => (index === 0 ? field : cefHeader(field)))
+    .join("|");
+  const extension: [string, string | number | null | undefined][] = [
+    ["externalId", event.id],
+    ["rt", event.timestamp],
+    ["duid", event.user_id],
+    ["duser", event.email],
+    ["cn1Label", event.lockout_expires_in_seconds !== undefined ? "lockoutSeconds" : undefined],
+    ["cn1", event.lockout_expires_in_seconds],
+  ];
+  const pairs = extension
+    .filter(([, value]) => value !== undefined && value !== null)
+    .map(([key, value]) => `${key}=${cefValue(String(value))}`);
+  return `${header}|${event.severity}|${pairs.join(" ")}`;
+}
+
+function formatJsonl(event: SecurityEvent): string {
+  return JSON.stringify({ ...event, timestamp: new Date(event.timestamp).toISOString() });
+}
+
+/**
+ * Print bans and lockouts of users changed since `--since` as JSON Lines or
+ * CEF, oldest first, for a SIEM to ingest. Output goes to stdout only, so a
+ * cron job can append it to whatever file its forwarder tails.
+ */
+export async function securityExport(options: SecurityExportOptions = {}): Promise<void> {
+  const since = options.since ?? Date.now() - DAY_MS;
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  const users = await withSpinner("Scanning users...", () =>
+    withApiContext(usersUpdatedSince(secretKey, since), "Failed to list users"),
+  );
+  const events = users.flatMap(userSecurityEvents).sort((a, b) => a.timestamp - b.timestamp);
+  log.debug(`security: ${users.length} users changed since ${new Date(since).toISOString()}`);
+
+  for (const event of events) {
+    log.data(options.format === "cef" ? formatCef(event) : formatJsonl(event));
+  }
+}
@@ -0,0 +1,36 @@
+import { createOption } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { parseTimeOption } from "../../lib/option-parsers.ts";
+import { EXPORT_FORMATS, securityExport } from "./export.ts";
+
+export function registerSecurity(program: Program): void {
+  const security = program
+    .command("security")
+    .description("Export security-relevant instance data")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  security
+    .command("export")
+    .description("Print banned and locked-out users as JSON Lines or CEF for a SIEM")
+    .addOption(
+      createOption("--format <format>", "Output format").choices(EXPORT_FORMATS).default("jsonl"),
+    )
+    .option("--since <time>", "Users changed since a time (default 24h)", (value) =>
+      parseTimeOption(value, "--since"),
+    )
+    .setExamples([
+      {
+        command: "clerk security export --since 24h",
+        description: "Print the last day's bans and lockouts as JSON Lines",
+      },
+      {
+        command: "clerk security export --format cef --since 1h >> /var/log/clerk/security.cef",
+        description: "Append CEF events from an hourly cron job for a forwarder to pick up",
+      },
+    ])
+    .action((_opts, cmd) =>
+      securityExport(cmd.optsWithGlobals() as Parameters<typeof securityExport>[0]),
+    );
+}
@@ -0,0 +1,156 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { DryRunRequest } from "../../lib/dry-run.ts";
+import { BapiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { sessionsGet } = await import("./get.ts");
+
+const SESSION = {
+  id: "sess_1",
+  status: "active",
+  user_id: "user_1",
+  client_id: "client_1",
+  actor: { sub: "user_admin", iss: "cli:admin@example.com" },
+  latest_activity: {
+    ip_address: "203.0.113.7",
+    city: "Austin",
+    country: "US",
+    browser_name: "Chrome",
+    browser_version: "126.0",
+    device_type: "Macintosh",
+    is_mobile: false,
+  },
+};
+
+const CLIENT = { id: "client_1", session_ids: ["sess_1", "sess_2"], sign_in_id: null };
+
+const CLAIMS = { sub: "user_1", sid: "sess_1", org_role: "org:admin" };
+const JWT = `e30.${Buffer.from(JSON.stringify(CLAIMS)).toString("base64url")}.sig`;
+
+type Call = { method: string; path: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+function routes(overrides: Record<string, () => unknown> = {}) {
+  const table: Record<string, () => unknown> = {
+    "GET /sessions/sess_1": () => SESSION,
+    "GET /clients/client_1": () => CLIENT,
+    "POST /sessions/sess_1/tokens": () => ({ object: "token", jwt: JWT }),
+    ...overrides,
+  };
+  mockBapiRequest.mockImplementation(async ({ method, path }: Call) => {
+    const handler = table[`${method} ${path}`];
+    if (!handler) throw new Error(`unexpected ${method} ${path}`);
+    return respond(handler());
+  });
+}
+
+describe("sessions get", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    routes();
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("--json combines the session, its client, and the token claims", async () => {
+    await sessionsGet("sess_1", { claims: true, json: true });
+
+    const detail = JSON.parse(captured.out);
+    expect(detail.id).toBe("sess_1");
+    expect(detail.client).toEqual(CLIENT);
+    expect(detail.token_claims).toEqual(CLAIMS);
+  });
+
+  test("shows activity, actor, other sessions, and claims", async () => {
+    await sessionsGet("sess_1", { claims: true });
+
+    expect(captured.err).toContain("Chrome 126.0 · Macintosh · desktop");
+    expect(captured.err).toContain("Austin, US");
+    expect(captured.err).toContain("user_admin");
+    expect(captured.err).toContain("sess_2");
+    expect(captured.err).toContain('"org_role": "org:admin"');
+  });
+
+  test("only reads without --claims", async () => {
+    await sessionsGet("sess_1");
+
+    const methods = mockBapiRequest.mock.calls.map(([args]) => (args as Call).method);
+    expect(new Set(methods)).toEqual(new Set(["GET"]));
+    expect(captured.err).toContain("pass --claims");
+  });
+
+  test("inactive sessions skip the token", async () => {
+    routes({ "GET /sessions/sess_1": () => ({ ...SESSION, status: "revoked" }) });
+
+    await sessionsGet("sess_1", { claims: true });
+
+    const methods = mockBapiRequest.mock.calls.map(([args]) => (args as Call).method);
+    expect(methods).not.toContain("POST");
+    expect(captured.err).toContain("only active sessions have one");
+  });
+
+  test("a failing client lookup leaves that section empty", async () => {
+    routes({
+      "GET /clients/client_1": () => {
+        throw new BapiError(404, '{"errors":[{"message":"not found"}]}', new Headers());
+      },
+    });
+
+    await sessionsGet("sess_1", { claims: true, json: true });
+
+    const detail = JSON.parse(captured.out);
+    expect(detail.client).toBeNull();
+    expect(detail.token_claims).toEqual(CLAIMS);
+  });
+
+  test("a --dry-run stop on the token still ends the command", async () => {
+    routes({
+      "POST /sessions/sess_1/tokens": () => {
+        throw new DryRunRequest("POST", "https://api.clerk.com/v1/sessions/sess_1/tokens");
+      },
+    });
+
+    await expect(sessionsGet("sess_1", { claims: true, json: true })).rejects.toBeInstanceOf(
+      DryRunRequest,
+    );
+    expect(captured.out).toBe("");
+  });
+
+  test("rejects IDs that aren't session IDs", async () => {
+    await expect(sessionsGet("user_1")).rejects.toThrow(/sess_/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,164 @@
+import { bold, dim } from "../../lib/color.ts";
+import { bestEffort } from "../../lib/dry-run.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import {
+  createSessionToken,
+  decodeJwtClaims,
+  fetchClient,
+  fetchSession,
+  type Session,
+  type SessionClient,
+} from "../../lib/sessions.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatFields, formatTimestamp } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import { resolveSessionsSecretKey, sessionLocation, type TargetingOptions } from "./list.ts";
+
+export type SessionsGetOptions = TargetingOptions & {
+  /** Mint a session token to read its claims. Off by default, so `get` only reads. */
+  claims?: boolean;
+  json?: boolean;
+};
+
+export type SessionDetail = Session & {
+  client: SessionClient | null;
+  /** Claims of a freshly minted session token, or `null` when none was minted. */
+  token_claims: Record<string, unknown> | null;
+};
+
+/**
+ * Client and claims are best-effort, like the counts on `orgs get`: a failure
+ * leaves the section empty instead of failing the whole view.
+ */
+async function bestEffortSection<T>(label: string, fetch: () => Promise<T>): Promise<T | null> {
+  const value = await bestEffort(fetch, (error) =>
+    log.debug(`sessions: could not fetch ${label}: ${String(error)}`),
+  );
+  return value ?? null;
+}
+
+function formatDevice(session: Session): string | undefined {
+  const activity = session.latest_activity;
+  const browser = [activity?.browser_name, activity?.browser_version].filter(Boolean).join(" ");
+  const parts = [browser, activity?.device_type].filter(Boolean);
+  if (typeof activity?.is_mobile === "boolean") {
+    parts.push(activity.is_mobile ? "mobile" : "desktop");
+  }
+  return parts.length ? parts.join(" · ") : undefined;
+}
+
+function formatActor(session: Session): string | undefined {
+  const actor = session.actor;
+  if (!actor?.sub) return undefined;
+  return actor.iss ? `${actor.sub} ${dim(`(${actor.iss})`)}` : actor.sub;
+}
+
+function formatJson(value: Record<string, unknown>): string[] {
+  return JSON.stringify(value, null, 2)
+    .split("\n")
+    .map((line) => `  ${line}`);
+}
+
+export function formatSessionDetail(detail: SessionDetail): string[] {
+  const lines = [
+    bold(detail.id),
+    ...formatFields([
+      ["Status", detail.status],
+      ["User", detail.user_id],
+      ["Impersonated by", formatActor(detail)],
+      ["Active organization", detail.last_active_organization_id ?? undefined],
+      ["Last active", formatTimestamp(detail.last_active_at)],
+      ["Created", formatTimestamp(detail.created_at)],
+      ["Expires", formatTimestamp(detail.expire_at)],
+      ["Abandons", formatTimestamp(detail.abandon_at)],
+    ]),
+    "",
+    bold("Latest activity"),
+    ...formatFields([
+      ["IP", detail.latest_activity?.ip_address ?? undefined],
+      ["Location", sessionLocation(detail)],
+      ["Device", formatDevice(detail)],
+    ]),
+    "",
+    bold("Client"),
+  ];
+
+  const client = detail.client;
+  if (client) {
+    const others = (client.session_ids ?? []).filter((id) => id !== detail.id);
+    lines.push(
+      ...formatFields([
+        ["ID", client.id],
+        ["Other sessions", others.length ? others.join(", ") : "none"],
+        ["Last active session", client.last_active_session_id ?? undefined],
+        ["Sign-in attempt", client.sign_in_id ?? undefined],
+        ["Sign-up attempt", client.sign_up_id ?? undefined],
+        ["Created", formatTimestamp(client.created_at)],
+        ["Updated", formatTimestamp(client.updated_at)],
+      ]),
+    );
+  } else {
+    lines.push(dim(`  ${detail.client_id ?? "(unknown)"}`));
+  }
+
+  lines.push("", bold("Token claims"));
+  if (detail.token_claims) {
+    lines.push(...formatJson(detail.token_claims));
+  } else {
+    const reason =
+      detail.status === "active"
+        ? "pass --claims to mint a token and show them"
+        : "only active sessions have one";
+    lines.push(dim(`  (${reason})`));
+  }
+  return lines;
+}
+
+/**
+ * Show one session with its client, latest activity, and actor. With
+ * `--claims`, also the claims its session token carries: they come from a
+ * token minted for this view (`POST /v1/sessions/{id}/tokens`), since BAPI
+ * doesn't return tokens it issued earlier, so they're opt-in.
+ */
+export async function sessionsGet(
+  sessionArg: string | undefined,
+  options: SessionsGetOptions = {},
+): Promise<void> {
+  const sessionId = (await requireArg(sessionArg, { label: "Session ID" })).trim();
+  if (!sessionId.startsWith("sess_")) {
+    throwUsageError(`Expected a session ID (sess_...), got \`${sessionId}\`.`);
+  }
+  const secretKey = await resolveSessionsSecretKey(options);
+
+  const detail = await withSpinner("Fetching session...", async () => {
+    const session = await withApiContext(
+      fetchSession(secretKey, sessionId),
+      `Failed to fetch session ${sessionId}`,
+    );
+    const mintToken = options.claims === true && session.status === "active";
+    const [client, jwt] = await Promise.all([
+      session.client_id
+        ? bestEffortSection("client", () => fetchClient(secretKey, session.client_id!))
+        : null,
+      mintToken
+        ? bestEffortSection("token", () => createSessionToken(secretKey, sessionId))
+        : null,
+    ]);
+    const result: SessionDetail = {
+      ...session,
+      client,
+      token_claims: jwt ? (decodeJwtClaims(jwt) ?? null) : null,
+    };
+    return result;
+  });
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(detail, null, 2));
+    return;
+  }
+
+  await printOutput(formatSessionDetail(detail), { page: false });
+}
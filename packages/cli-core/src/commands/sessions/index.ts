@@ -0,0 +1,104 @@
+import { createOption } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { parseIntegerOption, parseTimeOption } from "../../lib/option-parsers.ts";
+import { sessionsGet } from "./get.ts";
+import { CLIENT_TYPES, SESSION_STATUSES, sessionsList } from "./list.ts";
+import {
+  DEFAULT_MAX_SESSIONS,
+  DEFAULT_SCAN_USERS,
+  DEFAULT_SINCE_DAYS,
+  sessionsSuspicious,
+} from "./suspicious.ts";
+
+export function registerSessions(program: Program): void {
+  const sessions = program
+    .command("sessions")
+    .description("Inspect user sessions")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)");
+
+  sessions
+    .command("list")
+    .description("List a user's sessions, filtered by where they were used from")
+    .requiredOption("--user <user-id>", "User whose sessions to list")
+    .addOption(
+      createOption("--status <status>", "Only sessions in this status").choices(SESSION_STATUSES),
+    )
+    .option("--ip <address>", "Only sessions last used from this IP address")
+    .option("--country <code>", "Only sessions last used from this country (e.g. US)")
+    .addOption(
+      createOption("--client-type <type>", "Only mobile or desktop sessions").choices(CLIENT_TYPES),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk sessions list --user user_123 --status active",
+        description: "List a user's active sessions",
+      },
+      {
+        command: "clerk sessions list --user user_123 --country US --client-type mobile",
+        description: "Only mobile sessions last used from the US",
+      },
+      {
+        command: "clerk sessions list --user user_123 --ip 203.0.113.7 --json",
+        description: "Sessions from one IP address, as JSON",
+      },
+    ])
+    .action((_opts, cmd) =>
+      sessionsList(cmd.optsWithGlobals() as Parameters<typeof sessionsList>[0]),
+    );
+
+  sessions
+    .command("get")
+    .description("Show a session's client, latest activity, and actor")
+    .argument("[session-id]", "Session ID (sess_...)")
+    .option("--claims", "Mint a session token to show its claims (a write)")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk sessions get sess_123",
+        description: "Show where the session is used from",
+      },
+      {
+        command: "clerk sessions get sess_123 --claims --json | jq .token_claims",
+        description: "Print only the session token's claims",
+      },
+    ])
+    .action((sessionId, _opts, cmd) =>
+      sessionsGet(sessionId, cmd.optsWithGlobals() as Parameters<typeof sessionsGet>[1]),
+    );
+
+  sessions
+    .command("suspicious")
+    .description("Flag recently active users with many sessions or sessions in several countries")
+    .option(
+      "--since <time>",
+      `Scan users active since a time (default ${DEFAULT_SINCE_DAYS}d)`,
+      (value) => parseTimeOption(value, "--since"),
+    )
+    .option(
+      "--users <number>",
+      `Most recently active users to scan (1-500, default ${DEFAULT_SCAN_USERS})`,
+      (value) => parseIntegerOption(value, "--users", { min: 1, max: 500 }),
+    )
+    .option(
+      "--max-sessions <number>",
+      `Flag users with more active sessions than this (default ${DEFAULT_MAX_SESSIONS})`,
+      (value) => parseIntegerOption(value, "--max-sessions", { min: 1 }),
+    )
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command: "clerk sessions suspicious",
+        description: "Scan the 100 most recently active users",
+      },
+      {
+        command: "clerk sessions suspicious --since 24h --max-sessions 3",
+        description: "A tighter scan of the last day",
+      },
+    ])
+    .action((_opts, cmd) =>
+      sessionsSuspicious(cmd.optsWithGlobals() as Parameters<typeof sessionsSuspicious>[0]),
+    );
+}
@@ -0,0 +1,94 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { matchesSessionFilters, sessionsList } = await import("./list.ts");
+
+const SESSIONS = [
+  {
+    id: "sess_1",
+    status: "active",
+    latest_activity: { ip_address: "203.0.113.7", country: "US", city: "Austin", is_mobile: true },
+  },
+  {
+    id: "sess_2",
+    status: "active",
+    latest_activity: { ip_address: "198.51.100.2", country: "DE", is_mobile: false },
+  },
+  { id: "sess_3", status: "revoked", latest_activity: null },
+];
+
+describe("matchesSessionFilters", () => {
+  test("matches IP exactly and country case-insensitively", () => {
+    expect(matchesSessionFilters(SESSIONS[0]!, { ip: "203.0.113.7", country: "us" })).toBe(true);
+    expect(matchesSessionFilters(SESSIONS[1]!, { ip: "203.0.113.7" })).toBe(false);
+  });
+
+  test("excludes sessions without activity from client-type filters", () => {
+    expect(matchesSessionFilters(SESSIONS[1]!, { clientType: "desktop" })).toBe(true);
+    expect(matchesSessionFilters(SESSIONS[2]!, { clientType: "desktop" })).toBe(false);
+  });
+});
+
+describe("sessions list", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockResolvedValue({ status: 200, headers: new Headers(), body: SESSIONS });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("passes user and status to BAPI and filters the rest locally", async () => {
+    await sessionsList({ user: "user_123", status: "active", country: "DE", json: true });
+
+    expect(mockBapiRequest.mock.calls[0]![0]).toMatchObject({
+      method: "GET",
+      path: "/sessions?user_id=user_123&status=active",
+    });
+    expect((JSON.parse(captured.out) as { id: string }[]).map((s) => s.id)).toEqual(["sess_2"]);
+  });
+
+  test("renders a table with location and a filtered count", async () => {
+    await sessionsList({ user: "user_123", clientType: "mobile" });
+
+    expect(captured.err).toContain("sess_1");
+    expect(captured.err).toContain("Austin, US");
+    expect(captured.err).toContain("1 session (of 3)");
+  });
+
+  test("rejects a --user that is not a user ID", async () => {
+    await expect(sessionsList({ user: "alice@example.com" })).rejects.toThrow(
+      "--user expects a user ID",
+    );
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,131 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { listUserSessions, type Session } from "../../lib/sessions.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { printQuietIds } from "../../lib/quiet.ts";
+import { isAgent } from "../../mode.ts";
+
+export const SESSION_STATUSES = [
+  "active",
+  "ended",
+  "expired",
+  "removed",
+  "replaced",
+  "revoked",
+  "abandoned",
+  "pending",
+] as const;
+
+export const CLIENT_TYPES = ["mobile", "desktop"] as const;
+
+export type TargetingOptions = {
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type SessionFilters = {
+  ip?: string;
+  country?: string;
+  clientType?: (typeof CLIENT_TYPES)[number];
+};
+
+export type SessionsListOptions = TargetingOptions &
+  SessionFilters & {
+    user?: string;
+    status?: string;
+    json?: boolean;
+  };
+
+/**
+ * BAPI can only filter sessions by user, client, and status, so the activity
+ * filters are applied to the returned sessions.
+ */
+export function matchesSessionFilters(session: Session, filters: SessionFilters): boolean {
+  const activity = session.latest_activity;
+  if (filters.ip && activity?.ip_address !== filters.ip) return false;
+  if (filters.country && activity?.country?.toLowerCase() !== filters.country.toLowerCase()) {
+    return false;
+  }
+  if (filters.clientType) {
+    if (typeof activity?.is_mobile !== "boolean") return false;
+    if (activity.is_mobile !== (filters.clientType === "mobile")) return false;
+  }
+  return true;
+}
+
+export function sessionLocation(session: Session): string | undefined {
+  const activity = session.latest_activity;
+  const parts = [activity?.city, activity?.country].filter(Boolean);
+  return parts.length ? parts.join(", ") : undefined;
+}
+
+export function sessionClient(session: Session): string | undefined {
+  const activity = session.latest_activity;
+  const parts = [activity?.browser_name, activity?.device_type].filter(Boolean);
+  return parts.length ? parts.join(" · ") : undefined;
+}
+
+const SESSION_COLUMNS: TableColumn<Session>[] = [
+  { key: "id", header: "SESSION ID", value: (session) => session.id, style: cyan },
+  { key: "status", header: "STATUS", value: (session) => session.status },
+  {
+    key: "ip",
+    header: "IP",
+    value: (session) => session.latest_activity?.ip_address ?? undefined,
+  },
+  { key: "location", header: "LOCATION", value: sessionLocation },
+  { key: "client", header: "CLIENT", value: sessionClient, style: dim },
+  {
+    key: "last_active",
+    header: "LAST ACTIVE",
+    value: (session) => formatTimestamp(session.last_active_at),
+    sortValue: (session) => session.last_active_at,
+  },
+];
+
+export function resolveSessionsSecretKey(options: TargetingOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+export async function sessionsList(options: SessionsListOptions = {}): Promise<void> {
+  const user = options.user;
+  if (!user?.startsWith("user_")) {
+    throwUsageError(`--user expects a user ID (user_...), got \`${user ?? ""}\`.`);
+  }
+  const secretKey = await resolveSessionsSecretKey(options);
+
+  const all = await withSpinner("Fetching sessions...", () =>
+    withApiContext(
+      listUserSessions(secretKey, { userId: user, status: options.status }),
+      `Failed to list sessions for ${user}`,
+    ),
+  );
+  const sessions = all.filter((session) => matchesSessionFilters(session, options));
+
+  if (printQuietIds(sessions.map((session) => session.id))) return;
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(sessions, null, 2));
+    return;
+  }
+
+  if (sessions.length === 0) {
+    log.warn(
+      all.length ? `None of ${user}'s ${all.length} sessions match.` : `${user} has no sessions.`,
+    );
+    return;
+  }
+
+  const table = renderTable(sessions, SESSION_COLUMNS);
+  await printOutput(table);
+  const filtered = sessions.length < all.length ? ` (of ${all.length})` : "";
+  log.info(`\n${sessions.length} session${sessions.length === 1 ? "" : "s"}${filtered}`);
+}
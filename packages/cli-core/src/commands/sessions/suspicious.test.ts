@@ -0,0 +1,109 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { assessUserSessions, sessionsSuspicious } = await import("./suspicious.ts");
+
+function session(id: string, country: string, ip = "203.0.113.7") {
+  return { id, status: "active", latest_activity: { country, ip_address: ip } };
+}
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body };
+}
+
+describe("assessUserSessions", () => {
+  test("flags too many active sessions", () => {
+    const sessions = [1, 2, 3].map((n) => session(`sess_${n}`, "US"));
+    expect(assessUserSessions("user_1", sessions, 2)?.reasons).toEqual(["3 active sessions"]);
+    expect(assessUserSessions("user_1", sessions, 3)).toBeUndefined();
+  });
+
+  test("flags sessions from more than one country", () => {
+    const finding = assessUserSessions(
+      "user_1",
+      [session("sess_1", "US"), session("sess_2", "BR", "198.51.100.2")],
+      5,
+    );
+    expect(finding).toMatchObject({
+      countries: ["BR", "US"],
+      ip_addresses: ["198.51.100.2", "203.0.113.7"],
+      reasons: ["active in 2 countries"],
+    });
+  });
+});
+
+describe("sessions suspicious", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.startsWith("/users?")) return respond([{ id: "user_ok" }, { id: "user_odd" }]);
+      if (path.includes("user_odd")) {
+        return respond([session("sess_1", "US"), session("sess_2", "RU")]);
+      }
+      return respond([session("sess_3", "US")]);
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("scans recently active users and reports flagged ones as JSON", async () => {
+    await sessionsSuspicious({ since: 1_700_000_000_000, users: 2, json: true });
+
+    const paths = mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path);
+    expect(paths).toEqual([
+      "/users?limit=2&last_active_at_since=1700000000000&order_by=-last_active_at",
+      "/sessions?user_id=user_ok&status=active",
+      "/sessions?user_id=user_odd&status=active",
+    ]);
+    const output = JSON.parse(captured.out) as { scanned_users: number; flagged: unknown[] };
+    expect(output.scanned_users).toBe(2);
+    expect(output.flagged).toEqual([
+      {
+        user_id: "user_odd",
+        active_sessions: 2,
+        countries: ["RU", "US"],
+        ip_addresses: ["203.0.113.7"],
+        reasons: ["active in 2 countries"],
+      },
+    ]);
+  });
+
+  test("prints a table of flagged users in human mode", async () => {
+    await sessionsSuspicious();
+
+    expect(captured.err).toContain("user_odd");
+    expect(captured.err).toContain("active in 2 countries");
+    expect(captured.err).not.toContain("user_ok");
+  });
+});
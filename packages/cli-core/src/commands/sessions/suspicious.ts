@@ -0,0 +1,137 @@
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim, yellow } from "../../lib/color.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { withProgress } from "../../lib/progress.ts";
+import { listUserSessions, type Session } from "../../lib/sessions.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent } from "../../mode.ts";
+import { resolveSessionsSecretKey, type TargetingOptions } from "./list.ts";
+
+export type SuspiciousOptions = TargetingOptions & {
+  since?: number;
+  users?: number;
+  maxSessions?: number;
+  json?: boolean;
+};
+
+export type SuspiciousUser = {
+  user_id: string;
+  active_sessions: number;
+  countries: string[];
+  ip_addresses: string[];
+  reasons: string[];
+};
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+export const DEFAULT_SINCE_DAYS = 7;
+export const DEFAULT_SCAN_USERS = 100;
+export const DEFAULT_MAX_SESSIONS = 5;
+
+function distinct(values: (string | null | undefined)[]): string[] {
+  return [...new Set(values.filter((value): value is string => Boolean(value)))].sort();
+}
+
+/**
+ * Flag a user whose active sessions look unusual: more than `maxSessions` at
+ * once, or sessions last used from more than one country. Heuristics only;
+ * shared devices and travel trip both.
+ */
+export function assessUserSessions(
+  userId: string,
+  sessions: Session[],
+  maxSessions: number,
+): SuspiciousUser | undefined {
+  const countries = distinct(sessions.map((session) => session.latest_activity?.country));
+  const ipAddresses = distinct(sessions.map((session) => session.latest_activity?.ip_address));
+  const reasons: string[] = [];
+  if (sessions.length > maxSessions) reasons.push(`${sessions.length} active sessions`);
+  if (countries.length > 1) reasons.push(`active in ${countries.length} countries`);
+  if (reasons.length === 0) return undefined;
+  return {
+    user_id: userId,
+    active_sessions: sessions.length,
+    countries,
+    ip_addresses: ipAddresses,
+    reasons,
+  };
+}
+
+async function fetchRecentlyActiveUserIds(
+  secretKey: string,
+  since: number,
+  limit: number,
+): Promise<string[]> {
+  const params = new URLSearchParams({
+    limit: String(limit),
+    last_active_at_since: String(since),
+    order_by: "-last_active_at",
+  });
+  const response = await bapiRequest({ method: "GET", path: `/users?${params}`, secretKey });
+  const body = response.body;
+  return Array.isArray(body) ? (body as { id: string }[]).map((user) => user.id) : [];
+}
+
+const SUSPICIOUS_COLUMNS: TableColumn<SuspiciousUser>[] = [
+  { key: "user", header: "USER ID", value: (entry) => entry.user_id, style: cyan },
+  { key: "sessions", header: "ACTIVE", value: (entry) => String(entry.active_sessions) },
+  { key: "countries", header: "COUNTRIES", value: (entry) => entry.countries.join(", ") },
+  { key: "reasons", header: "WHY", value: (entry) => entry.reasons.join("; "), style: yellow },
+];
+
+export async function sessionsSuspicious(options: SuspiciousOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const since = options.since ?? Date.now() - DEFAULT_SINCE_DAYS * DAY_MS;
+  const limit = options.users ?? DEFAULT_SCAN_USERS;
+  const maxSessions = options.maxSessions ?? DEFAULT_MAX_SESSIONS;
+  const secretKey = await resolveSessionsSecretKey(options);
+
+  if (!json) intro("Scanning active sessions");
+  const userIds = await withSpinner("Fetching recently active users...", () =>
+    withApiContext(
+      fetchRecentlyActiveUserIds(secretKey, since, limit),
+      "Failed to list recently active users",
+    ),
+  );
+
+  const flagged: SuspiciousUser[] = [];
+  await withProgress("Fetching sessions...", userIds.length, async (progress) => {
+    for (const userId of userIds) {
+      const sessions = await withApiContext(
+        listUserSessions(secretKey, { userId, status: "active" }),
+        `Failed to list sessions for ${userId}`,
+      );
+      const finding = assessUserSessions(userId, sessions, maxSessions);
+      if (finding) flagged.push(finding);
+      progress.advance();
+    }
+  });
+
+  if (json) {
+    log.data(
+      JSON.stringify(
+        { since, scanned_users: userIds.length, max_sessions: maxSessions, flagged },
+        null,
+        2,
+      ),
+    );
+    return;
+  }
+
+  log.info(
+    dim(
+      `Checked ${userIds.length} user(s) active since ${formatTimestamp(since)}, ` +
+        "most recent first.",
+    ),
+  );
+  if (flagged.length > 0) {
+    log.blank();
+    for (const line of renderTable(flagged, SUSPICIOUS_COLUMNS)) log.info(line);
+    log.blank();
+    log.info(dim("Inspect one with `clerk sessions list --user <user_id>`."));
+  }
+  await outro(
+    flagged.length ? `${flagged.length} user(s) flagged` : "Nothing unusual in active sessions",
+  );
+}
@@ -0,0 +1,54 @@
+import { createArgument } from "@commander-js/extra-typings";
+import type { Program } from "../../cli-program.ts";
+import { SETTING_KEYS } from "../../lib/settings.ts";
+import { settingsGet, settingsList, settingsSet, settingsUnset } from "./manage.ts";
+
+function keyArgument() {
+  return createArgument("<key>", "Setting name (e.g. core.read-only)").choices(SETTING_KEYS);
+}
+
+export function registerSettings(program: Program): void {
+  const settings = program
+    .command("settings")
+    .description("Manage local CLI settings")
+    .setExamples([
+      { command: "clerk settings list", description: "Show all settings" },
+      {
+        command: "clerk settings set core.read-only true",
+        description: "Block mutating API requests",
+      },
+      { command: "clerk settings unset core.read-only", description: "Restore the default" },
+    ]);
+
+  settings
+    .command("list")
+    .description("Show all settings and their current values")
+    .option("--json", "Output JSON")
+    .action(settingsList);
+
+  settings
+    .command("get")
+    .description("Print the value of a setting")
+    .addArgument(keyArgument())
+    .option("--json", "Output JSON")
+    .action(settingsGet);
+
+  settings
+    .command("set")
+    .description("Set a setting")
+    .addArgument(keyArgument())
+    .argument("<value>", "New value")
+    .setExamples([
+      {
+        command: "clerk settings set core.read-only true",
+        description: "Block mutating API requests",
+      },
+    ])
+    .action(settingsSet);
+
+  settings
+    .command("unset")
+    .description("Remove a setting, restoring its default")
+    .addArgument(keyArgument())
+    .action(settingsUnset);
+}
@@ -0,0 +1,68 @@
+import { dim } from "../../lib/color.ts";
+import { throwUsageError } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import {
+  SETTINGS,
+  SETTING_KEYS,
+  getSetting,
+  isSettingKey,
+  listSettings,
+  setSetting,
+  unsetSetting,
+  type SettingKey,
+} from "../../lib/settings.ts";
+import { isAgent } from "../../mode.ts";
+
+interface JsonOption {
+  json?: boolean;
+}
+
+function requireSettingKey(key: string): SettingKey {
+  if (!isSettingKey(key)) {
+    throwUsageError(`Unknown setting "${key}". Known settings: ${SETTING_KEYS.join(", ")}`);
+  }
+  return key;
+}
+
+export async function settingsList(options: JsonOption = {}): Promise<void> {
+  const values = await listSettings();
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(values, null, 2));
+    return;
+  }
+
+  const width = Math.max(...SETTING_KEYS.map((key) => key.length));
+  for (const key of SETTING_KEYS) {
+    const value = values[key];
+    const shown = value ?? dim("(unset)");
+    log.data(`${key.padEnd(width)}  ${shown}  ${dim(SETTINGS[key].description)}`);
+  }
+}
+
+export async function settingsGet(rawKey: string, options: JsonOption = {}): Promise<void> {
+  const key = requireSettingKey(rawKey);
+  const value = await getSetting(key);
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify({ key, value: value ?? null }, null, 2));
+    return;
+  }
+  if (value === undefined) {
+    log.info(`${key} is not set.`);
+    return;
+  }
+  log.data(value);
+}
+
+export async function settingsSet(rawKey: string, value: string): Promise<void> {
+  const key = requireSettingKey(rawKey);
+  const stored = await setSetting(key, value);
+  log.success(`Set ${key} = ${stored}`);
+}
+
+export async function settingsUnset(rawKey: string): Promise<void> {
+  const key = requireSettingKey(rawKey);
+  await unsetSetting(key);
+  log.success(`Unset ${key}`);
+}
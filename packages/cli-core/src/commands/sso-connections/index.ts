@@ -0,0 +1,94 @@
+import type { Program } from "../../cli-program.ts";
+import { collectOptionValues } from "../../lib/option-parsers.ts";
+import {
+  ssoConnectionsDisable,
+  ssoConnectionsEnable,
+  ssoConnectionsList,
+  ssoConnectionsSetCredentials,
+} from "./manage.ts";
+
+export function registerSsoConnections(program: Program): void {
+  const connections = program
+    .command("sso-connections")
+    .description("Manage social login providers (Google, GitHub, Apple, ...)")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      { command: "clerk sso-connections list", description: "Show providers and their status" },
+      { command: "clerk sso-connections enable github", description: "Turn on GitHub sign-in" },
+      {
+        command:
+          "clerk sso-connections set-credentials google --client-id $ID --client-secret $SECRET",
+        description: "Use your own Google OAuth app",
+      },
+    ]);
+
+  connections
+    .command("list")
+    .description("List social providers, whether each is enabled, and its credentials")
+    .option("--json", "Output as JSON")
+    .action((_opts, cmd) =>
+      ssoConnectionsList(cmd.optsWithGlobals() as Parameters<typeof ssoConnectionsList>[0]),
+    );
+
+  connections
+    .command("enable")
+    .description("Enable sign-in with a social provider")
+    .argument("[provider]", "Provider slug (e.g. google, github). Omit to pick interactively.")
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--dry-run", "Show the patch that would be sent without applying it")
+    .action((provider, _opts, cmd) =>
+      ssoConnectionsEnable(
+        provider,
+        cmd.optsWithGlobals() as Parameters<typeof ssoConnectionsEnable>[1],
+      ),
+    );
+
+  connections
+    .command("disable")
+    .description("Disable sign-in with a social provider")
+    .argument("[provider]", "Provider slug (e.g. google, github). Omit to pick interactively.")
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--dry-run", "Show the patch that would be sent without applying it")
+    .action((provider, _opts, cmd) =>
+      ssoConnectionsDisable(
+        provider,
+        cmd.optsWithGlobals() as Parameters<typeof ssoConnectionsDisable>[1],
+      ),
+    );
+
+  connections
+    .command("set-credentials")
+    .description("Save custom OAuth credentials for a provider and enable it")
+    .argument("[provider]", "Provider slug (e.g. google, github). Omit to pick interactively.")
+    .option("--client-id <id>", "OAuth client ID")
+    .option("--client-secret <secret>", "OAuth client secret")
+    .option("--client-secret-file <path>", "Read the client secret from a file (e.g. Apple .p8)")
+    .option(
+      "--set <key=value>",
+      "Other provider fields, e.g. team_id or key_id for Apple (repeatable)",
+      collectOptionValues,
+      [],
+    )
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--dry-run", "Validate the credentials without saving them")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      {
+        command:
+          "clerk sso-connections set-credentials github --client-id $ID --client-secret $SECRET --yes",
+        description: "Set GitHub credentials from a bootstrap script",
+      },
+      {
+        command:
+          "clerk sso-connections set-credentials apple --client-id com.example.web --client-secret-file AuthKey.p8 --set team_id=ABC123 --set key_id=XYZ789",
+        description: "Configure Apple with its extra fields",
+      },
+    ])
+    .action((provider, _opts, cmd) =>
+      ssoConnectionsSetCredentials(
+        provider,
+        cmd.optsWithGlobals() as Parameters<typeof ssoConnectionsSetCredentials>[1],
+      ),
+    );
+}
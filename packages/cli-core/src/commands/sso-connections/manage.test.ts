@@ -0,0 +1,110 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+mock.module("../../lib/config.ts", () => ({
+  resolveAppContext: async () => ({
+    appId: "app_123",
+    appLabel: "My App",
+    instanceId: "ins_dev",
+    instanceLabel: "development",
+  }),
+}));
+
+const mockFetchInstanceConfig = mock();
+const mockPatchInstanceConfig = mock();
+mock.module("../../lib/plapi.ts", () => ({
+  fetchInstanceConfig: (...args: unknown[]) => mockFetchInstanceConfig(...args),
+  patchInstanceConfig: (...args: unknown[]) => mockPatchInstanceConfig(...args),
+  putInstanceConfig: async () => ({}),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { ssoConnectionsEnable, ssoConnectionsList, ssoConnectionsSetCredentials } = await import(
+  "./manage.ts"
+);
+
+const CONFIG = {
+  session: { lifetime: 3600 },
+  connection_oauth_google: { enabled: true, client_id: "" },
+  connection_oauth_github: { enabled: false, client_id: "gh_client" },
+};
+
+describe("sso-connections", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(true);
+    mockFetchInstanceConfig.mockResolvedValue(CONFIG);
+    mockPatchInstanceConfig.mockResolvedValue({});
+  });
+
+  afterEach(() => {
+    mockFetchInstanceConfig.mockReset();
+    mockPatchInstanceConfig.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("list reports each provider's status and credential source", async () => {
+    await ssoConnectionsList();
+
+    expect(JSON.parse(captured.out)).toEqual([
+      { provider: "github", name: "GitHub", enabled: false, custom_credentials: true },
+      { provider: "google", name: "Google", enabled: true, custom_credentials: false },
+    ]);
+  });
+
+  test("enable patches only the provider's enabled flag", async () => {
+    await ssoConnectionsEnable("GitHub");
+
+    expect(mockPatchInstanceConfig).toHaveBeenCalledWith(
+      "app_123",
+      "ins_dev",
+      { connection_oauth_github: { enabled: true } },
+      { dryRun: undefined },
+    );
+  });
+
+  test("rejects a provider the instance does not offer", async () => {
+    await expect(ssoConnectionsEnable("myspace")).rejects.toThrow(
+      "Available on this instance: github, google",
+    );
+    expect(mockPatchInstanceConfig).not.toHaveBeenCalled();
+  });
+
+  test("set-credentials enables the provider and never prints the secret", async () => {
+    await ssoConnectionsSetCredentials("google", {
+      clientId: "client.apps.googleusercontent.com",
+      clientSecret: "GOCSPX-supersecretvalue",
+    });
+
+    expect(mockPatchInstanceConfig.mock.calls[0]![2]).toEqual({
+      connection_oauth_google: {
+        enabled: true,
+        client_id: "client.apps.googleusercontent.com",
+        client_secret: "GOCSPX-supersecretvalue",
+      },
+    });
+    expect(captured.err).toContain("client_secret: ****alue");
+    expect(captured.err + captured.out).not.toContain("supersecret");
+  });
+
+  test("set-credentials needs at least one credential flag", async () => {
+    await expect(ssoConnectionsSetCredentials("google")).rejects.toThrow("--client-id");
+    expect(mockFetchInstanceConfig).not.toHaveBeenCalled();
+  });
+});
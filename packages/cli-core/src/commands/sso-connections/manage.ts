@@ -0,0 +1,272 @@
+import { homedir } from "node:os";
+import { join, resolve } from "node:path";
+import { cyan, dim, green } from "../../lib/color.ts";
+import { resolveAppContext } from "../../lib/config.ts";
+import { throwUsageError, throwUserAbort, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { fetchInstanceConfig, patchInstanceConfig } from "../../lib/plapi.ts";
+import { select } from "../../lib/listage.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { renderTable, type TableColumn } from "../../lib/table.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+import { applyConfigPatch } from "../config/apply-patch.ts";
+import { OAUTH_KEY_PREFIX, providerLabel } from "../deploy/providers.ts";
+
+export type SsoConnectionsOptions = {
+  app?: string;
+  instance?: string;
+  json?: boolean;
+  yes?: boolean;
+  dryRun?: boolean;
+};
+
+export type SetCredentialsOptions = SsoConnectionsOptions & {
+  clientId?: string;
+  clientSecret?: string;
+  clientSecretFile?: string;
+  set?: string[];
+};
+
+export type SsoConnection = {
+  provider: string;
+  name: string;
+  enabled: boolean;
+  /** Whether the instance uses its own OAuth app rather than Clerk's shared development one. */
+  custom_credentials: boolean;
+};
+
+const SSO_CONNECTION_COLUMNS: TableColumn<SsoConnection>[] = [
+  { key: "provider", header: "PROVIDER", value: (row) => row.provider, style: cyan },
+  { key: "name", header: "NAME", value: (row) => row.name },
+  {
+    key: "enabled",
+    header: "ENABLED",
+    value: (row) => (row.enabled ? "yes" : "no"),
+    style: (text) => (text.trim() === "yes" ? green(text) : dim(text)),
+  },
+  {
+    key: "credentials",
+    header: "CREDENTIALS",
+    value: (row) => (row.custom_credentials ? "custom" : "shared"),
+  },
+];
+
+/** Social connections configured on the instance, sorted by provider slug. */
+export function ssoConnectionsFromConfig(config: Record<string, unknown>): SsoConnection[] {
+  const connections: SsoConnection[] = [];
+  for (const [key, value] of Object.entries(config)) {
+    if (!key.startsWith(OAUTH_KEY_PREFIX) || !value || typeof value !== "object") continue;
+    const settings = value as Record<string, unknown>;
+    const provider = key.slice(OAUTH_KEY_PREFIX.length);
+    connections.push({
+      provider,
+      name: providerLabel(provider),
+      enabled: settings.enabled === true,
+      custom_credentials: typeof settings.client_id === "string" && settings.client_id.length > 0,
+    });
+  }
+  return connections.sort((a, b) => a.provider.localeCompare(b.provider));
+}
+
+function normalizeProvider(provider: string): string {
+  const slug = provider.trim().toLowerCase();
+  return slug.startsWith(OAUTH_KEY_PREFIX) ? slug.slice(OAUTH_KEY_PREFIX.length) : slug;
+}
+
+/** Without `providerArg`, a terminal user picks from the instance's providers. */
+async function resolveProvider(
+  config: Record<string, unknown>,
+  providerArg: string | undefined,
+): Promise<string> {
+  const provider = await requireArg(providerArg, {
+    label: "Provider",
+    prompt: () =>
+      select({
+        message: "Pick a provider:",
+        choices: ssoConnectionsFromConfig(config).map((connection) => ({
+          name: `${connection.name} ${dim(connection.enabled ? "(enabled)" : "(disabled)")}`,
+          value: connection.provider,
+        })),
+      }),
+  });
+  return normalizeProvider(provider);
+}
+
+/** Fail with the instance's known providers when `provider` is not one of them. */
+function requireKnownProvider(config: Record<string, unknown>, provider: string): string {
+  const configKey = `${OAUTH_KEY_PREFIX}${provider}`;
+  if (configKey in config) return configKey;
+  const known = ssoConnectionsFromConfig(config).map((connection) => connection.provider);
+  throwUsageError(
+    `Unknown provider "${provider}".` +
+      (known.length > 0 ? ` Available on this instance: ${known.join(", ")}.` : ""),
+  );
+}
+
+async function fetchConfig(ctx: { appId: string; instanceId: string }) {
+  return withSpinner("Fetching current config...", () =>
+    withApiContext(fetchInstanceConfig(ctx.appId, ctx.instanceId), "Failed to fetch config"),
+  );
+}
+
+export async function ssoConnectionsList(options: SsoConnectionsOptions = {}): Promise<void> {
+  const ctx = await resolveAppContext(options);
+  const connections = ssoConnectionsFromConfig(await fetchConfig(ctx));
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(connections, null, 2));
+    return;
+  }
+  if (connections.length === 0) {
+    log.info(`No social connections are available on ${ctx.appLabel} (${ctx.instanceLabel}).`);
+    return;
+  }
+  for (const line of renderTable(connections, SSO_CONNECTION_COLUMNS)) log.data(line);
+}
+
+async function setEnabled(
+  provider: string | undefined,
+  enabled: boolean,
+  options: SsoConnectionsOptions,
+): Promise<void> {
+  const ctx = await resolveAppContext(options);
+  const current = await fetchConfig(ctx);
+  const slug = await resolveProvider(current, provider);
+  const configKey = requireKnownProvider(current, slug);
+  const label = providerLabel(slug);
+
+  await applyConfigPatch({
+    ctx,
+    payload: { [configKey]: { enabled } },
+    verb: `${enabled ? "Enabling" : "Disabling"} ${label}`,
+    successMessage: `${label} sign-in ${enabled ? "enabled" : "disabled"}`,
+    failureContext: `Failed to ${enabled ? "enable" : "disable"} ${label}`,
+    yes: options.yes,
+    dryRun: options.dryRun,
+    currentConfig: current,
+  });
+}
+
+export function ssoConnectionsEnable(
+  provider: string | undefined,
+  options: SsoConnectionsOptions = {},
+) {
+  return setEnabled(provider, true, options);
+}
+
+export function ssoConnectionsDisable(
+  provider: string | undefined,
+  options: SsoConnectionsOptions = {},
+) {
+  return setEnabled(provider, false, options);
+}
+
+function expandPath(path: string): string {
+  if (path === "~") return homedir();
+  if (path.startsWith("~/")) return join(homedir(), path.slice(2));
+  return resolve(path);
+}
+
+async function readClientSecretFile(path: string): Promise<string> {
+  const file = Bun.file(expandPath(path.trim()));
+  if (!(await file.exists())) throwUsageError(`--client-secret-file: no file at ${path}.`);
+  return file.text();
+}
+
+/**
+ * Build the credential fields for a provider from flags. `--set key=value`
+ * covers provider-specific fields such as Apple's `team_id` and `key_id`.
+ */
+export async function buildCredentials(
+  options: SetCredentialsOptions,
+): Promise<Record<string, string>> {
+  if (options.clientSecret !== undefined && options.clientSecretFile !== undefined) {
+    throwUsageError("Pass either --client-secret or --client-secret-file, not both.");
+  }
+
+  const credentials: Record<string, string> = {};
+  for (const entry of options.set ?? []) {
+    const separator = entry.indexOf("=");
+    const key = entry.slice(0, separator).trim();
+    if (separator <= 0 || !key) {
+      throwUsageError(`--set expects key=value (e.g. team_id=ABC123), got "${entry}".`);
+    }
+    credentials[key] = entry.slice(separator + 1);
+  }
+  if (options.clientId !== undefined) credentials.client_id = options.clientId.trim();
+  if (options.clientSecret !== undefined) credentials.client_secret = options.clientSecret.trim();
+  if (options.clientSecretFile !== undefined) {
+    credentials.client_secret = await readClientSecretFile(options.clientSecretFile);
+  }
+
+  if (Object.keys(credentials).length === 0) {
+    throwUsageError("Pass --client-id and --client-secret (or --client-secret-file).");
+  }
+  return credentials;
+}
+
+function maskCredential(key: string, value: string): string {
+  if (key !== "client_secret") return value;
+  return value.length > 8 ? `****${value.trimEnd().slice(-4)}` : "****";
+}
+
+/**
+ * Save custom OAuth credentials for a provider and enable it. The diff shown
+ * by `applyConfigPatch` would echo secrets, so this prints a masked summary
+ * and patches directly.
+ */
+export async function ssoConnectionsSetCredentials(
+  provider: string | undefined,
+  options: SetCredentialsOptions = {},
+): Promise<void> {
+  const credentials = await buildCredentials(options);
+  const ctx = await resolveAppContext(options);
+  const current = await fetchConfig(ctx);
+  const slug = await resolveProvider(current, provider);
+  const configKey = requireKnownProvider(current, slug);
+  const label = providerLabel(slug);
+  const payload = { [configKey]: { enabled: true, ...credentials } };
+
+  const headline = options.dryRun
+    ? `[dry-run] Proposing PATCH on ${ctx.appLabel} (${ctx.instanceLabel}):`
+    : `Saving ${label} credentials on ${ctx.appLabel} (${ctx.instanceLabel}):`;
+  log.info(`\n${headline}\n`);
+  for (const [key, value] of Object.entries(credentials)) {
+    log.info(`  ${key}: ${maskCredential(key, value)}`);
+  }
+  log.blank();
+
+  if (!options.dryRun && isHuman() && !options.yes) {
+    if (!(await confirm({ message: "Proceed?" }))) throwUserAbort();
+  }
+
+  await withSpinner(`Saving ${label} credentials...`, () =>
+    withApiContext(
+      patchInstanceConfig(ctx.appId, ctx.instanceId, payload, { dryRun: options.dryRun }),
+      options.dryRun ? "Dry-run failed" : `Failed to save ${label} credentials`,
+    ),
+  );
+
+  if (options.json || isAgent()) {
+    log.data(
+      JSON.stringify(
+        {
+          provider: slug,
+          enabled: true,
+          fields: Object.keys(credentials),
+          dry_run: Boolean(options.dryRun),
+        },
+        null,
+        2,
+      ),
+    );
+    return;
+  }
+  log.success(
+    options.dryRun
+      ? "[dry-run] Validation passed — no changes applied"
+      : `Saved ${label} credentials and enabled ${label} sign-in`,
+  );
+}
@@ -104,7 +104,7 @@ describe("switch-env", () => {
 
     expect(mockSelect).toHaveBeenCalledTimes(1);
     expect(mockCurrentEnv).toBe("staging");
-    expect(captured.out).toContain("Switched from production to staging.");
+    expect(captured.err).toContain("Switched from production to staging.");
   });
 
   test("switches to a valid environment", async () => {
@@ -116,7 +116,7 @@ describe("switch-env", () => {
 
     expect(mockCurrentEnv).toBe("staging");
     expect(mockSetEnvironment).toHaveBeenCalledWith("staging");
-    expect(captured.out).toContain("Switched from production to staging.");
+    expect(captured.err).toContain("Switched from production to staging.");
   });
 
   test("reports already on environment when switching to current", async () => {
@@ -126,7 +126,7 @@ describe("switch-env", () => {
     logSpy = spyOn(console, "log").mockImplementation(() => {});
     await runSwitchEnv("production");
 
-    expect(captured.out).toContain("Already on production environment.");
+    expect(captured.err).toContain("Already on production environment.");
   });
 
   test("throws when no TTY is available in human mode", async () => {
@@ -147,7 +147,7 @@ describe("switch-env", () => {
     logSpy = spyOn(console, "log").mockImplementation(() => {});
     await runSwitchEnv("staging");
 
-    expect(captured.out).toContain("No credentials found for staging.");
+    expect(captured.err).toContain("No credentials found for staging.");
     expect(captured.err).toContain("clerk auth login");
   });
 
@@ -158,6 +158,6 @@ describe("switch-env", () => {
     logSpy = spyOn(console, "log").mockImplementation(() => {});
     await runSwitchEnv("staging");
 
-    expect(captured.out).not.toContain("No credentials found");
+    expect(captured.err).not.toContain("No credentials found");
   });
 });
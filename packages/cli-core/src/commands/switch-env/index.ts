@@ -67,7 +67,7 @@ export async function switchEnv(environmentArg: string | undefined): Promise<voi
   const previousEnv = getCurrentEnvName();
 
   if (previousEnv === target) {
-    log.data(`Already on ${target} environment.`);
+    log.info(`Already on ${target} environment.`);
     outro();
     return;
   }
@@ -76,11 +76,11 @@ export async function switchEnv(environmentArg: string | undefined): Promise<voi
   setCurrentEnv(target);
   await setEnvironment(target);
 
-  log.data(`Switched from ${previousEnv} to ${target}.`);
+  log.success(`Switched from ${previousEnv} to ${target}.`);
 
   const token = await getToken();
   if (!token) {
-    log.data(`No credentials found for ${target}.`);
+    log.warn(`No credentials found for ${target}.`);
     outro(NEXT_STEPS.SWITCH_ENV_NO_TOKEN);
     return;
   }
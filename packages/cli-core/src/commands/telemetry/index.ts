@@ -0,0 +1,75 @@
+import type { Program } from "../../cli-program.ts";
+import { dim } from "../../lib/color.ts";
+import { log } from "../../lib/log.ts";
+import { getBooleanSetting, setSetting } from "../../lib/settings.ts";
+import { telemetryEnvOverride } from "../../lib/telemetry.ts";
+import { isAgent } from "../../mode.ts";
+
+export type TelemetryStatusOptions = {
+  json?: boolean;
+};
+
+const COLLECTED = [
+  "the command's name (e.g. `users list`), without its arguments or options",
+  "how long it took",
+  "whether it exited 0",
+];
+
+export async function telemetryOn(): Promise<void> {
+  await setSetting("core.telemetry", "true");
+  log.success("Telemetry is on. Thanks for helping us decide what to build next.");
+  const override = telemetryEnvOverride();
+  if (override) log.warn(`${override} is set, so nothing is sent until it's removed.`);
+}
+
+export async function telemetryOff(): Promise<void> {
+  await setSetting("core.telemetry", "false");
+  log.success("Telemetry is off.");
+}
+
+export async function telemetryStatus(options: TelemetryStatusOptions = {}): Promise<void> {
+  const optedIn = await getBooleanSetting("core.telemetry");
+  const override = telemetryEnvOverride();
+  const enabled = optedIn && !override;
+
+  if (options.json || isAgent()) {
+    const status = { enabled, opted_in: optedIn, disabled_by: override ?? null };
+    log.data(JSON.stringify(status, null, 2));
+    return;
+  }
+  if (enabled) {
+    log.info("Telemetry is on. For each command run, the CLI sends:");
+    for (const item of COLLECTED) log.info(`  • ${item}`);
+    log.info(dim("No user, application, or machine identifier is sent."));
+    log.info("Turn it off with `clerk telemetry off`.");
+  } else if (optedIn) {
+    log.info(`Telemetry is on, but ${override} is set, so nothing is sent.`);
+  } else {
+    log.info("Telemetry is off. Nothing is sent. Opt in with `clerk telemetry on`.");
+  }
+}
+
+export function registerTelemetry(program: Program): void {
+  const telemetry = program
+    .command("telemetry")
+    .description("Manage anonymous usage telemetry (off unless you opt in)")
+    .setExamples([
+      { command: "clerk telemetry status", description: "Show whether telemetry is on" },
+      { command: "clerk telemetry on", description: "Opt in to anonymous usage telemetry" },
+    ]);
+
+  telemetry
+    .command("on")
+    .description("Opt in: send the name, duration, and outcome of each command")
+    .action(telemetryOn);
+
+  telemetry.command("off").description("Opt out: send nothing").action(telemetryOff);
+
+  telemetry
+    .command("status")
+    .description("Show whether telemetry is on and what it sends")
+    .option("--json", "Output as JSON")
+    .action((_opts, cmd) =>
+      telemetryStatus(cmd.optsWithGlobals() as Parameters<typeof telemetryStatus>[0]),
+    );
+}
@@ -90,7 +90,7 @@ describe("unlink", () => {
 
       expect(mockConfirm).not.toHaveBeenCalled();
       expect(mockRemoveProfile).toHaveBeenCalledWith(process.cwd());
-      expect(captured.out).toContain("Unlinked");
+      expect(captured.err).toContain("Unlinked");
     });
   });
 
@@ -159,7 +159,7 @@ describe("unlink", () => {
 
       await runUnlink({ yes: true });
 
-      expect(captured.out).toContain("Unlinked");
+      expect(captured.err).toContain("Unlinked");
     });
   });
 });
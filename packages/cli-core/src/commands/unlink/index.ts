@@ -44,7 +44,7 @@ export async function unlink(options: UnlinkOptions = {}): Promise<void> {
   }
 
   await removeProfile(existing.path);
-  log.data(`\nUnlinked ${cyan(label)} from ${dim(displayPath)}`);
+  log.success(`\nUnlinked ${cyan(label)} from ${dim(displayPath)}`);
   outro(NEXT_STEPS.UNLINK);
 }
 
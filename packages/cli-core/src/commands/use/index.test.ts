@@ -0,0 +1,161 @@
+import { test, expect, describe, beforeEach, afterEach, mock, spyOn } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockIsAgent = mock();
+
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => (mockIsAgent() ? "agent" : "human"),
+}));
+
+const { _setConfigDir, getDefaultContext, setDefaultContext, getDefaultOrg, setDefaultOrg } =
+  await import("../../lib/config.ts");
+const plapiModule = await import("../../lib/plapi.ts");
+const organizationsModule = await import("../../lib/organizations.ts");
+const bapiCommandModule = await import("../../lib/bapi-command.ts");
+const { use } = await import("./index.ts");
+
+const app = {
+  application_id: "app_123",
+  name: "My App",
+  instances: [
+    { instance_id: "ins_dev", environment_type: "development", publishable_key: "pk_test_1" },
+    { instance_id: "ins_prod", environment_type: "production", publishable_key: "pk_live_1" },
+  ],
+};
+
+describe("use", () => {
+  let tempDir: string;
+  let fetchApplicationSpy: ReturnType<typeof spyOn>;
+  const captured = useCaptureLog();
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-use-test-"));
+    _setConfigDir(tempDir);
+    mockIsAgent.mockReturnValue(false);
+    fetchApplicationSpy = spyOn(plapiModule, "fetchApplication").mockResolvedValue(
+      app as unknown as Awaited<ReturnType<typeof plapiModule.fetchApplication>>,
+    );
+  });
+
+  afterEach(async () => {
+    _setConfigDir(undefined);
+    mockIsAgent.mockReset();
+    fetchApplicationSpy.mockRestore();
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("stores the app and instance after validating them", async () => {
+    await use({ app: "app_123", instance: "prod" });
+
+    expect(fetchApplicationSpy).toHaveBeenCalledWith("app_123");
+    expect(await getDefaultContext()).toEqual({
+      app: "app_123",
+      appName: "My App",
+      instance: "prod",
+    });
+    expect(captured.err).toContain("Default app set to");
+  });
+
+  test("rejects an instance that does not belong to the app", async () => {
+    await expect(use({ app: "app_123", instance: "ins_missing" })).rejects.toMatchObject({
+      code: "instance_not_found",
+    });
+    expect(await getDefaultContext()).toBeUndefined();
+  });
+
+  test("--instance alone updates the instance of the stored app", async () => {
+    await setDefaultContext({ app: "app_123", appName: "My App" });
+
+    await use({ instance: "dev" });
+
+    expect(await getDefaultContext()).toEqual({
+      app: "app_123",
+      appName: "My App",
+      instance: "dev",
+    });
+  });
+
+  test("--instance alone without a stored app is a usage error", async () => {
+    await expect(use({ instance: "dev" })).rejects.toThrow("No default app set");
+  });
+
+  test("prints the stored default as JSON in agent mode", async () => {
+    mockIsAgent.mockReturnValue(true);
+    await setDefaultContext({ app: "app_123", instance: "prod" });
+
+    await use();
+
+    expect(JSON.parse(captured.out)).toEqual({ context: { app: "app_123", instance: "prod" } });
+  });
+
+  test("--clear removes the stored default", async () => {
+    await setDefaultContext({ app: "app_123" });
+
+    await use({ clear: true });
+
+    expect(await getDefaultContext()).toBeUndefined();
+  });
+
+  describe("--org", () => {
+    let fetchOrganizationSpy: ReturnType<typeof spyOn>;
+    let resolveSecretKeySpy: ReturnType<typeof spyOn>;
+
+    beforeEach(() => {
+      resolveSecretKeySpy = spyOn(bapiCommandModule, "resolveBapiSecretKey").mockResolvedValue(
+        "sk_test_123",
+      );
+      fetchOrganizationSpy = spyOn(organizationsModule, "fetchOrganization").mockResolvedValue({
+        id: "org_123",
+        name: "Acme Inc",
+        slug: "acme",
+      });
+    });
+
+    afterEach(() => {
+      resolveSecretKeySpy.mockRestore();
+      fetchOrganizationSpy.mockRestore();
+    });
+
+    test("looks up a slug and stores the organization ID", async () => {
+      await use({ org: "acme" });
+
+      expect(fetchOrganizationSpy).toHaveBeenCalledWith("sk_test_123", "acme");
+      expect(await getDefaultOrg()).toEqual({ id: "org_123", name: "Acme Inc" });
+      expect(await getDefaultContext()).toBeUndefined();
+      expect(captured.err).toContain("Default organization set to");
+    });
+
+    test("prints the default organization next to the app context", async () => {
+      mockIsAgent.mockReturnValue(true);
+      await setDefaultContext({ app: "app_123" });
+      await setDefaultOrg({ id: "org_123", name: "Acme Inc" });
+
+      await use();
+
+      expect(JSON.parse(captured.out)).toEqual({
+        context: { app: "app_123" },
+        org: { id: "org_123", name: "Acme Inc" },
+      });
+    });
+
+    test("--no-org removes only the organization; --clear removes both", async () => {
+      await setDefaultContext({ app: "app_123" });
+      await setDefaultOrg({ id: "org_123" });
+
+      await use({ org: false });
+      expect(await getDefaultOrg()).toBeUndefined();
+      expect(await getDefaultContext()).toEqual({ app: "app_123" });
+
+      await setDefaultOrg({ id: "org_123" });
+      await use({ clear: true });
+      expect(await getDefaultOrg()).toBeUndefined();
+      expect(fetchOrganizationSpy).not.toHaveBeenCalled();
+    });
+  });
+});
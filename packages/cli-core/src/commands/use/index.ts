@@ -0,0 +1,179 @@
+/**
+ * Set a default application/instance for commands run outside a linked project.
+ *
+ * The default is stored in the CLI config file and consulted by
+ * `resolveAppContext` after the `--app` flag and the linked profile, so a
+ * linked project always wins over the global default.
+ *
+ * `--org` stores a default organization the same way, for org-scoped commands
+ * (`orgs get`, `orgs members`, `orgs invitations`, ...) whose `[org]`
+ * argument is omitted.
+ */
+
+import type { Program } from "../../cli-program.ts";
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import {
+  clearDefaultContext,
+  clearDefaultOrg,
+  getDefaultContext,
+  getDefaultOrg,
+  resolveFetchedApplicationInstance,
+  setDefaultContext,
+  setDefaultOrg,
+  type DefaultContext,
+  type DefaultOrg,
+} from "../../lib/config.ts";
+import { fetchOrganization } from "../../lib/organizations.ts";
+import { fetchApplication } from "../../lib/plapi.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { CliError, ERROR_CODE, throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { isAgent } from "../../mode.ts";
+
+export interface UseOptions {
+  app?: string;
+  instance?: string;
+  /** Organization ID or slug to store, or `false` (`--no-org`) to remove it. */
+  org?: string | false;
+  secretKey?: string;
+  clear?: boolean;
+  json?: boolean;
+}
+
+function formatContext(context: DefaultContext): string {
+  const app = context.appName ? `${context.appName} (${context.app})` : context.app;
+  return `${cyan(app)} ${dim(`instance: ${context.instance ?? "development"}`)}`;
+}
+
+function formatOrg(org: DefaultOrg): string {
+  return cyan(org.name ? `${org.name} (${org.id})` : org.id);
+}
+
+function printContext(
+  context: DefaultContext | undefined,
+  org: DefaultOrg | undefined,
+  options: UseOptions,
+): void {
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify({ context: context ?? null, ...(org && { org }) }, null, 2));
+    return;
+  }
+  if (!context) {
+    log.info("No default app set. Run `clerk use --app <app_id>` to set one.");
+  } else {
+    log.data(formatContext(context));
+  }
+  if (org) log.data(`${dim("org:")} ${formatOrg(org)}`);
+}
+
+export async function use(options: UseOptions = {}): Promise<void> {
+  if (options.clear) {
+    if (options.app || options.instance || options.org !== undefined) {
+      throwUsageError("--clear cannot be combined with --app, --instance, or --org.");
+    }
+    await clearDefaultContext();
+    await clearDefaultOrg();
+    log.success("Cleared the default app and organization.");
+    return;
+  }
+
+  if (!options.app && !options.instance && options.org === undefined) {
+    printContext(await getDefaultContext(), await getDefaultOrg(), options);
+    return;
+  }
+
+  const context =
+    options.app || options.instance ? await storeAppDefault(options) : await getDefaultContext();
+  const org = options.org === undefined ? await getDefaultOrg() : await storeOrgDefault(options);
+
+  if (options.json || isAgent()) {
+    printContext(context, org, options);
+    return;
+  }
+  if (options.app || options.instance) {
+    log.success(`Default app set to ${formatContext(context!)}`);
+    log.info(dim("Linked projects still use their own app. Run `clerk use --clear` to reset."));
+  }
+  if (options.org === false) {
+    log.success("Cleared the default organization.");
+  } else if (org && options.org) {
+    log.success(`Default organization set to ${formatOrg(org)}`);
+  }
+}
+
+async function storeAppDefault(options: UseOptions): Promise<DefaultContext> {
+  const current = await getDefaultContext();
+  const appId = options.app ?? current?.app;
+  if (!appId) {
+    throwUsageError("No default app set. Pass --app <app_id> together with --instance.");
+  }
+
+  const app = await withSpinner("Fetching application...", () =>
+    withApiContext(fetchApplication(appId), "Failed to fetch application"),
+  );
+  const resolved = resolveFetchedApplicationInstance(appId, app, options.instance);
+  if (!resolved.found) {
+    throw new CliError(`Instance ${resolved.instanceId} not found in application ${appId}.`, {
+      code: ERROR_CODE.INSTANCE_NOT_FOUND,
+    });
+  }
+
+  const context: DefaultContext = {
+    app: appId,
+    ...(app.name && { appName: app.name }),
+    ...(options.instance && { instance: options.instance }),
+  };
+  await setDefaultContext(context);
+  return context;
+}
+
+/**
+ * Look the organization up on the target instance before storing it, so a
+ * slug is saved as its ID and a typo fails here rather than on every later
+ * command.
+ */
+async function storeOrgDefault(options: UseOptions): Promise<DefaultOrg | undefined> {
+  if (options.org === false) {
+    await clearDefaultOrg();
+    return undefined;
+  }
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const org = options.org!;
+  const organization = await withSpinner("Fetching organization...", () =>
+    withApiContext(fetchOrganization(secretKey, org), `Failed to fetch organization ${org}`),
+  );
+  const stored: DefaultOrg = { id: organization.id, name: organization.name };
+  await setDefaultOrg(stored);
+  return stored;
+}
+
+export function registerUse(program: Program): void {
+  program
+    .command("use")
+    .description("Set the default application and instance for commands")
+    .option("--app <id>", "Application ID to use by default")
+    .option("--instance <id>", "Instance to use by default (dev, prod, or instance ID)")
+    .option("--org <org>", "Organization ID or slug for org-scoped commands to use by default")
+    .option("--no-org", "Remove the default organization")
+    .option("--secret-key <key>", "Backend API secret key used to look up --org")
+    .option("--clear", "Remove the stored default app and organization")
+    .option("--json", "Output JSON")
+    .setExamples([
+      { command: "clerk use", description: "Show the current default app and instance" },
+      { command: "clerk use --app app_123", description: "Use an app's development instance" },
+      {
+        command: "clerk use --app app_123 --instance prod",
+        description: "Use an app's production instance",
+      },
+      { command: "clerk use --instance dev", description: "Switch the default instance only" },
+      { command: "clerk use --org acme", description: "Default org-scoped commands to acme" },
+      { command: "clerk use --no-org", description: "Remove the default organization" },
+      { command: "clerk use --clear", description: "Remove the stored default" },
+    ])
+    .action(use);
+}
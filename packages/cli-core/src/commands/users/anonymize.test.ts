@@ -0,0 +1,245 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { BapiError, CliError, ERROR_CODE } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockText = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  text: (...args: unknown[]) => mockText(...args),
+  confirm: async () => true,
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { anonymize, planAnonymization } = await import("./anonymize.ts");
+
+const USER = {
+  id: "user_2X9k",
+  first_name: "Alice",
+  last_name: "Example",
+  username: "alice",
+  has_image: true,
+  email_addresses: [{ id: "idn_1", email_address: "alice@example.com" }],
+  phone_numbers: [{ id: "phn_1", phone_number: "+15551234567" }],
+  public_metadata: { plan: "pro", home_address: "1 Main St" },
+  private_metadata: { crm_email: "alice@crm.test" },
+  unsafe_metadata: null,
+};
+
+const PLACEHOLDER = "example.invalid";
+
+type Call = { method: string; path: string; body?: string };
+
+function calls(): Call[] {
+  return mockBapiRequest.mock.calls.map(([args]) => args as Call);
+}
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("planAnonymization", () => {
+  test("removes identifiers, names, image, and matching metadata", () => {
+    const plan = planAnonymization(USER, {
+      metadataPattern: /email|address/i,
+      placeholderEmailDomain: "example.invalid",
+    });
+
+    expect(plan.steps.map((step) => `${step.method} ${step.path}`)).toEqual([
+      "POST /email_addresses",
+      "DELETE /email_addresses/idn_1",
+      "DELETE /phone_numbers/phn_1",
+      "PATCH /users/user_2X9k",
+      "DELETE /users/user_2X9k/profile_image",
+      "PATCH /users/user_2X9k/metadata",
+    ]);
+    expect(plan.steps[0]!.body).toEqual({
+      user_id: "user_2X9k",
+      email_address: "deleted_2x9k@example.invalid",
+      verified: true,
+      primary: true,
+    });
+    expect(plan.steps[3]!.body).toEqual({
+      first_name: null,
+      last_name: null,
+      username: "deleted_2x9k",
+    });
+    expect(plan.steps[5]!.body).toEqual({
+      public_metadata: { home_address: null },
+      private_metadata: { crm_email: null },
+    });
+    expect(plan.keptMetadataKeys).toEqual(["public_metadata.plan"]);
+  });
+
+  test("deletes connected accounts and Web3 wallets", () => {
+    const plan = planAnonymization(
+      {
+        id: "user_2X9k",
+        external_accounts: [
+          { id: "eac_1", provider: "oauth_google", email_address: "alice@gmail.com" },
+        ],
+        web3_wallets: [{ id: "idn_w1", web3_wallet: "0x0123456789abcdef" }],
+      },
+      { placeholderEmailDomain: "example.invalid" },
+    );
+
+    expect(plan.steps.map((step) => `${step.method} ${step.path}`)).toEqual([
+      "POST /email_addresses",
+      "DELETE /users/user_2X9k/external_accounts/eac_1",
+      "DELETE /users/user_2X9k/web3_wallets/idn_w1",
+    ]);
+  });
+
+  test("refuses to delete the last identification without a placeholder email", () => {
+    expect(() => planAnonymization(USER)).toThrow("--placeholder-email-domain");
+    expect(() => planAnonymization({ id: "user_1", web3_wallets: [{ id: "idn_w1" }] })).toThrow(
+      "--placeholder-email-domain",
+    );
+  });
+
+  test("leaves metadata alone without a pattern", () => {
+    const plan = planAnonymization(USER, { placeholderEmailDomain: "example.invalid" });
+
+    expect(plan.steps.some((step) => step.path.endsWith("/metadata"))).toBe(false);
+    expect(plan.keptMetadataKeys).toHaveLength(3);
+  });
+
+  test("plans nothing for a user without personal data", () => {
+    expect(planAnonymization({ id: "user_1" }).steps).toEqual([]);
+  });
+});
+
+describe("users anonymize", () => {
+  const captured = useCaptureLog();
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(() => {
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockText.mockResolvedValue("user_2X9k");
+    mockBapiRequest.mockImplementation(async ({ method }: Call) =>
+      respond(method === "GET" ? USER : {}),
+    );
+  });
+
+  afterEach(() => {
+    process.exitCode = originalExitCode;
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+    mockText.mockReset();
+  });
+
+  test("--dry-run reports the plan without changing anything", async () => {
+    await anonymize({
+      userId: "user_2X9k",
+      placeholderEmailDomain: PLACEHOLDER,
+      dryRun: true,
+      json: true,
+    });
+
+    expect(calls().map((call) => call.method)).toEqual(["GET"]);
+    const report = JSON.parse(captured.out);
+    expect(report.dry_run).toBe(true);
+    expect(report.steps).toHaveLength(6);
+    expect(mockText).not.toHaveBeenCalled();
+  });
+
+  test("asks for the user ID, then runs every step", async () => {
+    await anonymize({
+      userId: "user_2X9k",
+      metadataPattern: "address",
+      placeholderEmailDomain: PLACEHOLDER,
+    });
+
+    expect(mockText).toHaveBeenCalledTimes(1);
+    const writes = calls().filter((call) => call.method !== "GET");
+    expect(writes).toHaveLength(7);
+    expect(JSON.parse(writes.at(-1)!.body!)).toEqual({ public_metadata: { home_address: null } });
+    expect(process.exitCode).toBe(originalExitCode);
+  });
+
+  test("agent mode requires --yes", async () => {
+    mockIsAgent.mockReturnValue(true);
+
+    await expect(
+      anonymize({ userId: "user_2X9k", placeholderEmailDomain: PLACEHOLDER }),
+    ).rejects.toThrow(/--yes/);
+    expect(calls().filter((call) => call.method !== "GET")).toHaveLength(0);
+  });
+
+  test("reports failed steps and keeps going", async () => {
+    mockBapiRequest.mockImplementation(async ({ method, path }: Call) => {
+      if (method === "GET") return respond(USER);
+      if (path === "/email_addresses/idn_1") {
+        throw new BapiError(422, '{"errors":[{"message":"last identifier"}]}', new Headers());
+      }
+      return respond({});
+    });
+
+    await anonymize({
+      userId: "user_2X9k",
+      placeholderEmailDomain: PLACEHOLDER,
+      yes: true,
+      json: true,
+    });
+
+    const output = JSON.parse(captured.out) as { steps: { status: string; path: string }[] };
+    expect(output.steps.filter((step) => step.status === "failed").map((s) => s.path)).toEqual([
+      "/email_addresses/idn_1",
+    ]);
+    expect(output.steps).toHaveLength(6);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("stops on errors that would hit every step", async () => {
+    mockBapiRequest.mockImplementation(async ({ method }: Call) => {
+      if (method === "GET") return respond(USER);
+      throw new CliError("read-only", { code: ERROR_CODE.READ_ONLY });
+    });
+
+    await expect(
+      anonymize({ userId: "user_2X9k", placeholderEmailDomain: PLACEHOLDER, yes: true }),
+    ).rejects.toMatchObject({
+      code: "read_only",
+    });
+    expect(calls().filter((call) => call.method !== "GET")).toHaveLength(1);
+  });
+
+  test("refuses to start without a placeholder email, before any change", async () => {
+    await expect(anonymize({ userId: "user_2X9k", yes: true })).rejects.toThrow(
+      "--placeholder-email-domain",
+    );
+    expect(calls().map((call) => call.method)).toEqual(["GET"]);
+  });
+
+  test("rejects malformed IDs and patterns before any request", async () => {
+    await expect(anonymize({ userId: "alice" })).rejects.toThrow(/user_<id>/);
+    await expect(anonymize({ userId: "user_1", metadataPattern: "(" })).rejects.toThrow(
+      /--metadata-pattern/,
+    );
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,294 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { errorMessage, throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { attemptBulk, withProgress } from "../../lib/progress.ts";
+import { text } from "../../lib/prompts.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+
+export type UsersAnonymizeOptions = {
+  userId?: string;
+  metadataPattern?: string;
+  placeholderEmailDomain?: string;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+const METADATA_FIELDS = ["public_metadata", "private_metadata", "unsafe_metadata"] as const;
+
+type MetadataField = (typeof METADATA_FIELDS)[number];
+
+export type AnonymizableUser = {
+  id: string;
+  first_name?: string | null;
+  last_name?: string | null;
+  username?: string | null;
+  has_image?: boolean;
+  email_addresses?: { id: string; email_address?: string }[];
+  phone_numbers?: { id: string; phone_number?: string }[];
+  /** OAuth connections, which carry the provider's email, name, and avatar. */
+  external_accounts?: { id: string; provider?: string; email_address?: string }[];
+  web3_wallets?: { id: string; web3_wallet?: string }[];
+} & Partial<Record<MetadataField, Record<string, unknown> | null>>;
+
+export type AnonymizeStep = {
+  description: string;
+  method: "POST" | "PATCH" | "DELETE";
+  path: string;
+  body?: Record<string, unknown>;
+};
+
+export type AnonymizePlan = {
+  steps: AnonymizeStep[];
+  /** Metadata keys left in place because they don't match `--metadata-pattern`. */
+  keptMetadataKeys: string[];
+};
+
+type StepResult = AnonymizeStep & { status: "done" | "failed"; error?: string };
+
+function parseMetadataPattern(pattern: string | undefined): RegExp | undefined {
+  if (pattern === undefined) return undefined;
+  try {
+    return new RegExp(pattern, "i");
+  } catch (error) {
+    throwUsageError(`--metadata-pattern: invalid regular expression (${errorMessage(error)}).`);
+  }
+}
+
+/**
+ * Local part for identifiers the instance may require. Usernames must stay
+ * unique, and a placeholder email lets the last real address be deleted when
+ * sign-in requires an email.
+ */
+function placeholderLocalPart(userId: string): string {
+  return `deleted_${userId.replace(/^user_/, "").toLowerCase()}`;
+}
+
+/**
+ * The requests that strip `user`'s personal data, in the order they must run.
+ * The user record, its ID, and non-matching metadata keys are kept. Throws a
+ * usage error when the plan would delete the user's last identification and
+ * no placeholder email replaces it, since the API refuses that deletion.
+ */
+export function planAnonymization(
+  user: AnonymizableUser,
+  options: { metadataPattern?: RegExp; placeholderEmailDomain?: string } = {},
+): AnonymizePlan {
+  const userPath = `/users/${encodeURIComponent(user.id)}`;
+  const steps: AnonymizeStep[] = [];
+  const identifications =
+    (user.email_addresses?.length ?? 0) +
+    (user.phone_numbers?.length ?? 0) +
+    (user.external_accounts?.length ?? 0) +
+    (user.web3_wallets?.length ?? 0);
+  if (identifications > 0 && !options.placeholderEmailDomain) {
+    throwUsageError(
+      `Anonymizing ${user.id} would delete their last email, phone, or sign-in account, ` +
+        "which the API refuses. Pass --placeholder-email-domain <domain> to keep a placeholder.",
+    );
+  }
+
+  if (options.placeholderEmailDomain) {
+    const address = `${placeholderLocalPart(user.id)}@${options.placeholderEmailDomain}`;
+    steps.push({
+      description: `Add placeholder email ${address}`,
+      method: "POST",
+      path: "/email_addresses",
+      body: { user_id: user.id, email_address: address, verified: true, primary: true },
+    });
+  }
+  // Connected accounts go first: they carry a copy of the provider's email,
+  // name, and avatar, and some link to the email addresses deleted below.
+  for (const account of user.external_accounts ?? []) {
+    const label = account.email_address ?? account.id;
+    steps.push({
+      description: `Delete ${account.provider ?? "external"} account ${label}`,
+      method: "DELETE",
+      path: `${userPath}/external_accounts/${encodeURIComponent(account.id)}`,
+    });
+  }
+  for (const wallet of user.web3_wallets ?? []) {
+    steps.push({
+      description: `Delete Web3 wallet ${wallet.web3_wallet ?? wallet.id}`,
+      method: "DELETE",
+      path: `${userPath}/web3_wallets/${encodeURIComponent(wallet.id)}`,
+    });
+  }
+  for (const email of user.email_addresses ?? []) {
+    steps.push({
+      description: `Delete email ${email.email_address ?? email.id}`,
+      method: "DELETE",
+      path: `/email_addresses/${encodeURIComponent(email.id)}`,
+    });
+  }
+  for (const phone of user.phone_numbers ?? []) {
+    steps.push({
+      description: `Delete phone ${phone.phone_number ?? phone.id}`,
+      method: "DELETE",
+      path: `/phone_numbers/${encodeURIComponent(phone.id)}`,
+    });
+  }
+
+  const profile: Record<string, unknown> = {};
+  if (user.first_name) profile.first_name = null;
+  if (user.last_name) profile.last_name = null;
+  if (user.username) profile.username = placeholderLocalPart(user.id);
+  if (Object.keys(profile).length > 0) {
+    steps.push({
+      description: `Clear ${Object.keys(profile).join(", ")}`,
+      method: "PATCH",
+      path: userPath,
+      body: profile,
+    });
+  }
+
+  if (user.has_image) {
+    steps.push({
+      description: "Delete profile image",
+      method: "DELETE",
+      path: `${userPath}/profile_image`,
+    });
+  }
+
+  const metadata: Partial<Record<MetadataField, Record<string, null>>> = {};
+  const keptMetadataKeys: string[] = [];
+  for (const field of METADATA_FIELDS) {
+    for (const key of Object.keys(user[field] ?? {})) {
+      if (options.metadataPattern?.test(key)) {
+        metadata[field] = { ...metadata[field], [key]: null };
+      } else {
+        keptMetadataKeys.push(`${field}.${key}`);
+      }
+    }
+  }
+  const stripped = Object.entries(metadata).flatMap(([field, keys]) =>
+    Object.keys(keys).map((key) => `${field}.${key}`),
+  );
+  if (stripped.length > 0) {
+    // The metadata endpoint deep-merges, and a null value removes the key.
+    steps.push({
+      description: `Remove metadata ${stripped.join(", ")}`,
+      method: "PATCH",
+      path: `${userPath}/metadata`,
+      body: metadata,
+    });
+  }
+
+  return { steps, keptMetadataKeys };
+}
+
+async function confirmIrreversible(userId: string, yes: boolean | undefined): Promise<void> {
+  if (yes) return;
+  if (!isHuman()) {
+    throwUsageError("Anonymizing a user cannot be undone. Pass --yes to confirm.");
+  }
+  // The prompt re-asks until the ID matches; Ctrl+C aborts.
+  await text({
+    message: `This cannot be undone. Type ${userId} to confirm:`,
+    validate: (value) => (value === userId ? undefined : `Type ${userId} exactly to continue.`),
+  });
+}
+
+async function runSteps(secretKey: string, steps: AnonymizeStep[]): Promise<StepResult[]> {
+  const results: StepResult[] = [];
+  await withProgress("Anonymizing user...", steps.length, async (progress) => {
+    for (const step of steps) {
+      const attempt = await attemptBulk(progress, () =>
+        bapiRequest({
+          method: step.method,
+          path: step.path,
+          secretKey,
+          ...(step.body && { body: JSON.stringify(step.body) }),
+        }),
+      );
+      results.push(
+        attempt.ok
+          ? { ...step, status: "done" }
+          : { ...step, status: "failed", error: attempt.error },
+      );
+    }
+  });
+  return results;
+}
+
+/**
+ * Strip a user's personal data (names, emails, phones, connected accounts,
+ * Web3 wallets, profile image, and metadata keys matching
+ * `--metadata-pattern`) while keeping the user record
+ * and its ID, so references from other systems stay valid.
+ */
+export async function anonymize(options: UsersAnonymizeOptions = {}): Promise<void> {
+  const userId = options.userId;
+  if (!userId || !/^user_[A-Za-z0-9]+$/.test(userId)) {
+    throwUsageError(`Invalid user ID '${userId ?? ""}'. Expected format: user_<id>.`);
+  }
+  const json = Boolean(options.json || isAgent());
+  const metadataPattern = parseMetadataPattern(options.metadataPattern);
+
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const user = await withSpinner("Fetching user...", async () => {
+    const response = await withApiContext(
+      bapiRequest({ method: "GET", path: `/users/${encodeURIComponent(userId)}`, secretKey }),
+      `Failed to fetch user ${userId}`,
+    );
+    return response.body as AnonymizableUser;
+  });
+
+  const plan = planAnonymization(user, {
+    metadataPattern,
+    placeholderEmailDomain: options.placeholderEmailDomain,
+  });
+
+  if (options.dryRun) {
+    if (json) {
+      log.data(JSON.stringify({ user_id: userId, dry_run: true, ...plan }, null, 2));
+      return;
+    }
+    log.info(`[dry-run] Anonymizing ${cyan(userId)} would:`);
+    for (const step of plan.steps) {
+      log.info(`  ${step.description}  ${dim(`${step.method} /v1${step.path}`)}`);
+    }
+    if (plan.steps.length === 0) log.info("  Nothing — no personal data found.");
+    if (plan.keptMetadataKeys.length > 0) {
+      log.info(dim(`Kept metadata: ${plan.keptMetadataKeys.join(", ")}`));
+    }
+    return;
+  }
+
+  if (plan.steps.length === 0) {
+    if (json) log.data(JSON.stringify({ user_id: userId, steps: [] }, null, 2));
+    else log.info(`${userId} has no personal data to remove.`);
+    return;
+  }
+
+  if (!json) {
+    intro(`Anonymizing ${userId}`);
+    for (const step of plan.steps) log.info(`  ${step.description}`);
+  }
+  await confirmIrreversible(userId, options.yes);
+
+  const results = await runSteps(secretKey, plan.steps);
+  const failed = results.filter((result) => result.status === "failed");
+  if (failed.length > 0) process.exitCode = 1;
+
+  if (json) {
+    log.data(JSON.stringify({ user_id: userId, steps: results }, null, 2));
+    return;
+  }
+  for (const result of failed) log.error(`${result.description}: ${result.error}`);
+  await outro(
+    failed.length
+      ? `Anonymized ${userId} with ${failed.length} failed step(s)`
+      : `Anonymized ${userId}`,
+  );
+}
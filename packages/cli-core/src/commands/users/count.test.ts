@@ -0,0 +1,82 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { count, buildUsersCountPath } = await import("./count.ts");
+
+describe("buildUsersCountPath", () => {
+  test("has no query string without filters", () => {
+    expect(buildUsersCountPath({})).toBe("/users/count");
+  });
+
+  test("combines named filters with --where pass-through", () => {
+    const path = buildUsersCountPath({
+      query: " alice ",
+      lastActiveSince: 1_700_000_000_000,
+      where: ["email_address=a@example.com", "email_address=b@example.com", "banned=true"],
+    });
+    const url = new URL(path, "https://api.clerk.test");
+    expect(url.pathname).toBe("/users/count");
+    expect(url.searchParams.get("query")).toBe("alice");
+    expect(url.searchParams.get("last_active_at_since")).toBe("1700000000000");
+    expect(url.searchParams.getAll("email_address")).toEqual(["a@example.com", "b@example.com"]);
+    expect(url.searchParams.get("banned")).toBe("true");
+  });
+
+  test("rejects a malformed --where before any request", () => {
+    expect(() => buildUsersCountPath({ where: ["banned"] })).toThrow(/Invalid --where value/);
+  });
+});
+
+describe("users count", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockResolvedValue({
+      status: 200,
+      headers: new Headers(),
+      body: { object: "total_count", total_count: 42 },
+      rawBody: "",
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("prints the bare count to stdout", async () => {
+    await count({});
+    expect(captured.out).toBe("42");
+  });
+
+  test("prints JSON with --json", async () => {
+    await count({ json: true });
+    expect(JSON.parse(captured.out)).toEqual({ total_count: 42 });
+  });
+});
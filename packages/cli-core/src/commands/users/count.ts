@@ -0,0 +1,46 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { parseWhereOption } from "../../lib/option-parsers.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { appendUserTimeFilters, type UserTimeFilters } from "./list.ts";
+import { printUsersJson } from "./output.ts";
+
+export type UsersCountOptions = UserTimeFilters & {
+  query?: string;
+  where?: string[];
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export function buildUsersCountPath(options: UsersCountOptions): string {
+  const searchParams = new URLSearchParams();
+  if (options.query?.trim()) searchParams.set("query", options.query.trim());
+  appendUserTimeFilters(searchParams, options);
+  for (const [key, value] of parseWhereOption(options.where)) searchParams.append(key, value);
+  const query = searchParams.toString();
+  return query ? `/users/count?${query}` : "/users/count";
+}
+
+/** Print the number of users matching the filters: a bare number on stdout, or JSON. */
+export async function count(options: UsersCountOptions = {}): Promise<void> {
+  const path = buildUsersCountPath(options);
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  const response = await withSpinner("Counting users...", () =>
+    withApiContext(bapiRequest({ method: "GET", path, secretKey }), "Failed to count users"),
+  );
+  const body = response.body;
+  const total = isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+
+  if (printUsersJson({ total_count: total }, options)) return;
+  log.data(String(total));
+}
@@ -8,6 +8,7 @@ import {
 } from "../../lib/fapi.ts";
 import { log } from "../../lib/log.ts";
 import { withSpinner } from "../../lib/spinner.ts";
+import { validateEmail, validatePhoneNumber } from "../../lib/validate.ts";
 import { isEnabled, isRequired, type AttributeName } from "./interactive/attributes.ts";
 import { resolveUsersInstanceContext } from "./interactive/instance-context.ts";
 
@@ -42,11 +43,13 @@ type FieldDef = {
   key: keyof CreateWizardFields;
   message: string;
   isPassword?: boolean;
+  /** Format check for a non-empty answer. */
+  check?: (value: string) => string | undefined;
 };
 
 const ALL_FIELDS: FieldDef[] = [
-  { attr: "email_address", key: "email", message: "Email address" },
-  { attr: "phone_number", key: "phone", message: "Phone number" },
+  { attr: "email_address", key: "email", message: "Email address", check: validateEmail },
+  { attr: "phone_number", key: "phone", message: "Phone number", check: validatePhoneNumber },
   { attr: "username", key: "username", message: "Username" },
   { attr: "password", key: "password", message: "Password", isPassword: true },
   { attr: "first_name", key: "firstName", message: "First name" },
@@ -100,9 +103,11 @@ async function loadSettings(
 
 async function promptField(field: FieldDef, required: boolean): Promise<string> {
   const message = required ? `${field.message} *` : `${field.message} (optional)`;
-  const validate = required
-    ? (value: string | undefined) => (value?.trim() ? undefined : `${field.message} is required`)
-    : undefined;
+  const validate = (value: string | undefined) => {
+    const trimmed = value?.trim();
+    if (!trimmed) return required ? `${field.message} is required` : undefined;
+    return field.check?.(trimmed);
+  };
   if (field.isPassword) {
     return password({ message, validate });
   }
@@ -260,4 +260,20 @@ describe("users create", () => {
     expect(mockResolveBapiSecretKey).not.toHaveBeenCalled();
     expect(mockBapiRequest).not.toHaveBeenCalled();
   });
+
+  test("rejects a malformed --email or --phone before calling BAPI", async () => {
+    const emailError = await runCreate({ email: "alice@", yes: true }).catch((caught) => caught);
+    expect(emailError).toBeInstanceOf(CliError);
+    expect(emailError.code).toBe(ERROR_CODE.USAGE_ERROR);
+    expect(emailError.message).toBe('--email: "alice@" is not an email address.');
+
+    const phoneError = await runCreate({ phone: "+1 555 555 0100", yes: true }).catch(
+      (caught) => caught,
+    );
+    expect(phoneError.message).toBe(
+      '--phone: "+1 555 555 0100" must be in E.164 format without separators (+15555550100).',
+    );
+    expect(mockResolveBapiSecretKey).not.toHaveBeenCalled();
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
 });
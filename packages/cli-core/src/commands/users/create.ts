@@ -8,9 +8,11 @@ import {
   readUsersPayloadInput,
   redactUsersDisplayPayload,
 } from "../../lib/users.ts";
+import { validateEmail, validatePhoneNumber } from "../../lib/validate.ts";
 import { isAgent, isHuman } from "../../mode.ts";
 import { bapiRequest } from "../../lib/bapi.ts";
 import { withSpinner, intro, outro, pausedOutro } from "../../lib/spinner.ts";
+import { isQuiet, printQuietIds } from "../../lib/quiet.ts";
 import { handleUsersBapiError, printUsersMutationResult } from "./output.ts";
 import { registerUsersAction } from "./registry.ts";
 import { runCreateWizard } from "./create-wizard.ts";
@@ -42,7 +44,7 @@ export async function create(options: CreateUserOptions): Promise<void> {
   const { payload, resolved } = await resolveCreate(options);
 
   const nested = isInsideGutter();
-  const shouldWrap = !nested && !resolved.json && !isAgent();
+  const shouldWrap = !nested && !resolved.json && !isAgent() && !isQuiet();
 
   if (resolved.dryRun) {
     if (shouldWrap) intro("Creating user");
@@ -71,9 +73,11 @@ export async function create(options: CreateUserOptions): Promise<void> {
       }),
     );
 
-    printUsersMutationResult("Created user", response.body, resolved);
+    const userId = extractUserId(response.body);
+    if (!printQuietIds(userId ? [userId] : [])) {
+      printUsersMutationResult("Created user", response.body, resolved);
+    }
     if (shouldWrap) {
-      const userId = extractUserId(response.body);
       if (userId) {
         outro([`Run \`clerk users open ${userId}\` to view this user in the dashboard`]);
       } else {
@@ -106,6 +110,7 @@ function extractUserId(body: unknown): string | undefined {
 
 async function resolveCreate(options: CreateUserOptions): Promise<ResolvedCreate> {
   const { basePayload, resolved } = await resolveBasePayload(options);
+  validateCreateFlags(resolved);
   return {
     payload: mergeUsersPayload(basePayload, buildCreateUserPayload(resolved)),
     resolved,
@@ -120,6 +125,7 @@ async function resolveBasePayload(options: CreateUserOptions): Promise<{
     return {
       basePayload: parseUsersPayload(
         await readUsersPayloadInput({ data: options.data, file: options.file }),
+        options.data ? "--data" : options.file,
       ),
       resolved: options,
     };
@@ -144,6 +150,14 @@ async function resolveBasePayload(options: CreateUserOptions): Promise<{
   throwUsageError(noInputMessage());
 }
 
+/** Catch malformed identifiers locally, so the error names the flag instead of a 422. */
+function validateCreateFlags(options: CreateUserOptions): void {
+  const emailError = options.email ? validateEmail(options.email) : undefined;
+  if (emailError) throwUsageError(`--email: ${emailError}.`);
+  const phoneError = options.phone ? validatePhoneNumber(options.phone) : undefined;
+  if (phoneError) throwUsageError(`--phone: ${phoneError}.`);
+}
+
 function noInputMessage(): string {
   return (
     "No input provided. Pass curated flags, -d <json>, or --file <path>.\n" +
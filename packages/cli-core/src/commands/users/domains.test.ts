@@ -0,0 +1,152 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockMultiselect = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  multiselect: (...args: unknown[]) => mockMultiselect(...args),
+  confirm: async () => true,
+  text: async () => "",
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { aggregateDomains, domainsReport, emailDomain } = await import("./domains.ts");
+
+const user = (id: string, ...emails: string[]) => ({
+  primary_email_address_id: `${id}_0`,
+  email_addresses: emails.map((email_address, i) => ({ id: `${id}_${i}`, email_address })),
+});
+
+const USERS = [
+  user("a", "ann@acme.com"),
+  user("b", "bob@Acme.com"),
+  user("c", "cat@mailinator.com"),
+  user("d", "dan@yopmail.com"),
+  user("e"),
+];
+
+type Call = { method: string; path: string; body?: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+function posts(): Call[] {
+  return mockBapiRequest.mock.calls
+    .map(([args]) => args as Call)
+    .filter((call) => call.method === "POST");
+}
+
+describe("emailDomain", () => {
+  test("uses the primary address, falling back to the first", () => {
+    expect(
+      emailDomain({
+        primary_email_address_id: "idn_2",
+        email_addresses: [
+          { id: "idn_1", email_address: "a@old.example" },
+          { id: "idn_2", email_address: "a@New.example" },
+        ],
+      }),
+    ).toBe("new.example");
+    expect(emailDomain({ email_addresses: [{ email_address: "a@first.example" }] })).toBe(
+      "first.example",
+    );
+    expect(emailDomain({ email_addresses: [] })).toBeUndefined();
+  });
+});
+
+describe("aggregateDomains", () => {
+  test("counts users per domain, largest first, with flags", () => {
+    const report = aggregateDomains(
+      USERS,
+      new Set(["mailinator.com", "yopmail.com"]),
+      new Set(["yopmail.com"]),
+    );
+
+    expect(report.total_users).toBe(5);
+    expect(report.without_email).toBe(1);
+    expect(report.domains).toEqual([
+      { domain: "acme.com", users: 2, share: 50, disposable: false, blocked: false },
+      { domain: "mailinator.com", users: 1, share: 25, disposable: true, blocked: false },
+      { domain: "yopmail.com", users: 1, share: 25, disposable: true, blocked: true },
+    ]);
+  });
+});
+
+describe("users domains report", () => {
+  const captured = useCaptureLog();
+  const originalExitCode = process.exitCode;
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockBapiRequest.mockImplementation(async (call: Call) => {
+      if (call.path.startsWith("/users")) return respond(USERS);
+      if (call.method === "POST") return respond({});
+      return respond({ data: [{ id: "blid_1", identifier: "*@yopmail.com" }] });
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockMultiselect.mockReset();
+    mockIsAgent.mockReset();
+    process.exitCode = originalExitCode;
+  });
+
+  test("offers unblocked disposable domains and blocks the chosen ones", async () => {
+    mockMultiselect.mockImplementation(async (config: { initialValues: string[] }) => [
+      ...config.initialValues,
+    ]);
+
+    await domainsReport();
+
+    expect(mockMultiselect).toHaveBeenCalledTimes(1);
+    expect(posts().map((call) => JSON.parse(call.body!))).toEqual([
+      { identifier: "*@mailinator.com" },
+    ]);
+    expect(captured.err).toContain("acme.com");
+  });
+
+  test("--json never prompts or blocks", async () => {
+    await domainsReport({ json: true, top: 1 });
+
+    expect(mockMultiselect).not.toHaveBeenCalled();
+    expect(posts()).toEqual([]);
+    const report = JSON.parse(captured.out) as { domains: { domain: string }[] };
+    expect(report.domains.map((row) => row.domain)).toEqual(["acme.com"]);
+  });
+
+  test("--block blocks every candidate and reports it", async () => {
+    mockIsAgent.mockReturnValue(true);
+
+    await domainsReport({ block: true });
+
+    expect(posts()).toHaveLength(1);
+    const report = JSON.parse(captured.out) as { newly_blocked: string[] };
+    expect(report.newly_blocked).toEqual(["mailinator.com"]);
+  });
+});
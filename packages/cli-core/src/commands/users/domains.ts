@@ -0,0 +1,254 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim, yellow } from "../../lib/color.ts";
+import { ApiError, errorMessage, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { multiselect } from "../../lib/prompts.ts";
+import { createIdentifier, listIdentifiers } from "../../lib/restrictions.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { renderTable, type TableColumn } from "../../lib/table.ts";
+import { primaryEmail, type BapiUserSummary } from "../../lib/users.ts";
+import { isHuman } from "../../mode.ts";
+import { parseFeed, readFeedSource } from "../restrictions/sync.ts";
+import { shouldPrintUsersJson } from "./output.ts";
+
+export type UsersDomainsReportOptions = {
+  top?: number;
+  disposableList?: string;
+  block?: boolean;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export const DEFAULT_TOP = 20;
+
+/** BAPI's MaxLimit for `GET /users`. */
+const PAGE_SIZE = 500;
+
+/**
+ * Well-known disposable email providers, used when `--disposable-list` isn't
+ * given. Deliberately short: a maintained feed catches far more.
+ */
+export const DISPOSABLE_DOMAINS: ReadonlySet<string> = new Set([
+  "10minutemail.com",
+  "burnermail.io",
+  "discard.email",
+  "dispostable.com",
+  "emailondeck.com",
+  "fakeinbox.com",
+  "getnada.com",
+  "guerrillamail.com",
+  "guerrillamail.net",
+  "mailcatch.com",
+  "maildrop.cc",
+  "mailinator.com",
+  "mailnesia.com",
+  "mintemail.com",
+  "mohmal.com",
+  "moakt.com",
+  "sharklasers.com",
+  "spamgourmet.com",
+  "temp-mail.org",
+  "tempmail.com",
+  "tempr.email",
+  "throwawaymail.com",
+  "trash-mail.com",
+  "trashmail.com",
+  "yopmail.com",
+]);
+
+type ReportUser = Pick<BapiUserSummary, "primary_email_address_id" | "email_addresses">;
+
+export type DomainRow = {
+  domain: string;
+  users: number;
+  /** Percentage of users with an email address. */
+  share: number;
+  disposable: boolean;
+  blocked: boolean;
+};
+
+export type DomainReport = {
+  total_users: number;
+  without_email: number;
+  domains: DomainRow[];
+};
+
+/** The domain of the user's primary email address (or their first one). */
+export function emailDomain(user: ReportUser): string | undefined {
+  const email = primaryEmail(user);
+  const at = email?.lastIndexOf("@") ?? -1;
+  return at >= 0 ? email!.slice(at + 1).toLowerCase() : undefined;
+}
+
+/** Count users per domain, largest first; ties sort by domain name. */
+export function aggregateDomains(
+  users: ReportUser[],
+  disposable: ReadonlySet<string>,
+  blocked: ReadonlySet<string>,
+): DomainReport {
+  const counts = new Map<string, number>();
+  let withoutEmail = 0;
+  for (const user of users) {
+    const domain = emailDomain(user);
+    if (domain) counts.set(domain, (counts.get(domain) ?? 0) + 1);
+    else withoutEmail++;
+  }
+  const withEmail = users.length - withoutEmail;
+  const domains = [...counts]
+    .map(([domain, count]) => ({
+      domain,
+      users: count,
+      share: Math.round((count / withEmail) * 1000) / 10,
+      disposable: disposable.has(domain),
+      blocked: blocked.has(domain),
+    }))
+    .sort((a, b) => b.users - a.users || a.domain.localeCompare(b.domain));
+  return { total_users: users.length, without_email: withoutEmail, domains };
+}
+
+async function fetchAllUsers(
+  secretKey: string,
+  onProgress: (fetched: number) => void,
+): Promise<ReportUser[]> {
+  const users: ReportUser[] = [];
+  for (let offset = 0; ; offset += PAGE_SIZE) {
+    const params = new URLSearchParams({ limit: String(PAGE_SIZE), offset: String(offset) });
+    const response = await bapiRequest({ method: "GET", path: `/users?${params}`, secretKey });
+    const page = Array.isArray(response.body) ? (response.body as ReportUser[]) : [];
+    users.push(...page);
+    onProgress(users.length);
+    if (page.length < PAGE_SIZE) return users;
+  }
+}
+
+async function loadDisposableDomains(source: string | undefined): Promise<ReadonlySet<string>> {
+  if (!source) return DISPOSABLE_DOMAINS;
+  const feed = parseFeed(await readFeedSource(source), "email-domain");
+  return new Set(feed.identifiers.map((identifier) => identifier.slice(2)));
+}
+
+/** Domains blocked outright with a `*@domain` blocklist entry. */
+async function fetchBlockedDomains(secretKey: string): Promise<Set<string>> {
+  const entries = await listIdentifiers(secretKey, "blocklist");
+  return new Set(
+    entries
+      .map((entry) => entry.identifier.toLowerCase())
+      .filter((identifier) => identifier.startsWith("*@"))
+      .map((identifier) => identifier.slice(2)),
+  );
+}
+
+const DOMAIN_COLUMNS: TableColumn<DomainRow>[] = [
+  { key: "domain", header: "DOMAIN", value: (row) => row.domain, style: cyan },
+  { key: "users", header: "USERS", value: (row) => String(row.users) },
+  { key: "share", header: "SHARE", value: (row) => `${row.share}%`, style: dim },
+  {
+    key: "flags",
+    header: "FLAGS",
+    value: (row) =>
+      [row.disposable && "disposable", row.blocked && "blocked"].filter(Boolean).join(", "),
+    style: yellow,
+  },
+];
+
+/**
+ * The flagged domains to block: every unblocked disposable domain with
+ * `--block`, or the ones picked in the checklist (all preselected, so Enter
+ * blocks them). Without either, none.
+ */
+async function chooseDomainsToBlock(
+  candidates: DomainRow[],
+  options: UsersDomainsReportOptions,
+): Promise<string[]> {
+  if (candidates.length === 0) return [];
+  if (options.block) return candidates.map((row) => row.domain);
+  if (!isHuman() || options.json) return [];
+  return multiselect({
+    message: "Block these disposable domains? (Enter blocks the selected ones)",
+    options: candidates.map((row) => ({
+      value: row.domain,
+      label: row.domain,
+      hint: `${row.users} user(s)`,
+    })),
+    initialValues: candidates.map((row) => row.domain),
+    required: false,
+  });
+}
+
+/**
+ * Aggregate users by email domain, flag known disposable domains, and offer
+ * to add blocklist entries for the flagged ones. Existing users on a blocked
+ * domain keep their accounts; the entry stops new sign-ups.
+ */
+export async function domainsReport(options: UsersDomainsReportOptions = {}): Promise<void> {
+  const json = shouldPrintUsersJson(options);
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const disposable = await loadDisposableDomains(options.disposableList);
+
+  const [users, blocked] = await withSpinner("Fetching users...", (spinner) =>
+    Promise.all([
+      withApiContext(
+        fetchAllUsers(secretKey, (fetched) =>
+          spinner.update(`Fetching users... ${dim(`${fetched} fetched`)}`),
+        ),
+        "Failed to list users",
+      ),
+      withApiContext(fetchBlockedDomains(secretKey), "Failed to fetch the blocklist"),
+    ]),
+  );
+  const report = aggregateDomains(users, disposable, blocked);
+  const top = report.domains.slice(0, options.top ?? DEFAULT_TOP);
+  const candidates = report.domains.filter((row) => row.disposable && !row.blocked);
+
+  if (!json) {
+    intro(`Email domains of ${report.total_users} user(s)`);
+    if (top.length > 0) for (const line of renderTable(top, DOMAIN_COLUMNS)) log.info(line);
+    if (report.domains.length > top.length) {
+      log.info(dim(`  ... and ${report.domains.length - top.length} more domain(s)`));
+    }
+    if (report.without_email) log.info(dim(`  ${report.without_email} user(s) have no email`));
+    if (candidates.length > 0) {
+      const flaggedUsers = candidates.reduce((sum, row) => sum + row.users, 0);
+      log.warn(
+        `${flaggedUsers} user(s) signed up with ` +
+          `${candidates.length} unblocked disposable domain(s).`,
+      );
+    }
+  }
+
+  const toBlock = await chooseDomainsToBlock(candidates, options);
+  const newlyBlocked: string[] = [];
+  const failed: { domain: string; error: string }[] = [];
+  for (const domain of toBlock) {
+    try {
+      await createIdentifier(secretKey, "blocklist", `*@${domain}`);
+      newlyBlocked.push(domain);
+    } catch (error) {
+      if (!(error instanceof ApiError)) throw error;
+      failed.push({ domain, error: errorMessage(error) });
+    }
+  }
+  if (failed.length > 0) process.exitCode = 1;
+
+  if (json) {
+    const output = { ...report, domains: top };
+    const blocking = options.block ? { newly_blocked: newlyBlocked, failed } : {};
+    log.data(JSON.stringify({ ...output, ...blocking }, null, 2));
+    return;
+  }
+  for (const failure of failed) log.error(`Failed to block ${failure.domain}: ${failure.error}`);
+  if (newlyBlocked.length > 0) {
+    await outro(`Blocked ${newlyBlocked.length} domain(s): ${newlyBlocked.join(", ")}`);
+  } else {
+    await outro(
+      candidates.length ? "No domains blocked" : "No unblocked disposable domains found",
+    );
+  }
+}
@@ -0,0 +1,152 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+const mockResolveBapiSecretKey = mock();
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: (...args: unknown[]) => mockResolveBapiSecretKey(...args),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: () => Promise<unknown>) => fn(),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { get, parseUserIncludes } = await import("./get.ts");
+
+const USER = {
+  id: "user_123",
+  first_name: "Alice",
+  last_name: "Example",
+  primary_email_address_id: "idn_1",
+  email_addresses: [
+    { id: "idn_2", email_address: "alice@work.example" },
+    { id: "idn_1", email_address: "alice@example.com" },
+  ],
+  phone_numbers: [{ id: "phn_1", phone_number: "+15551234567", reserved_for_second_factor: true }],
+  external_accounts: [{ id: "eac_1", provider: "oauth_google", email_address: "alice@gmail.com" }],
+  two_factor_enabled: true,
+  totp_enabled: true,
+  backup_code_enabled: false,
+  banned: false,
+  created_at: 1_700_000_000_000,
+};
+
+const SESSIONS = [{ id: "sess_1", status: "active", last_active_at: 1_700_000_000_000 }];
+const MEMBERSHIPS = {
+  data: [{ id: "orgmem_1", role: "org:admin", organization: { id: "org_1", name: "Acme" } }],
+  total_count: 1,
+};
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("parseUserIncludes", () => {
+  test("parses a comma-separated list", () => {
+    expect([...parseUserIncludes("sessions, mfa")]).toEqual(["sessions", "mfa"]);
+  });
+
+  test("expands all", () => {
+    expect([...parseUserIncludes("all")]).toEqual([
+      "sessions",
+      "orgs",
+      "external-accounts",
+      "mfa",
+    ]);
+  });
+
+  test("rejects unknown values", () => {
+    expect(() => parseUserIncludes("sessions,devices")).toThrow(
+      'Unknown --include value "devices"',
+    );
+  });
+});
+
+describe("users get", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockResolveBapiSecretKey.mockResolvedValue("sk_test_123");
+    mockBapiRequest.mockImplementation(async ({ path }: { path: string }) => {
+      if (path.startsWith("/sessions")) return respond(SESSIONS);
+      if (path.includes("/organization_memberships")) return respond(MEMBERSHIPS);
+      return respond(USER);
+    });
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockResolveBapiSecretKey.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  function requestedPaths(): string[] {
+    return mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path);
+  }
+
+  test("rejects a malformed user ID before resolving credentials", async () => {
+    await expect(get({ userId: "alice" })).rejects.toThrow("Invalid user ID 'alice'");
+    expect(mockResolveBapiSecretKey).not.toHaveBeenCalled();
+  });
+
+  test("only fetches the user without --include", async () => {
+    await get({ userId: "user_123" });
+
+    expect(requestedPaths()).toEqual(["/users/user_123"]);
+    expect(captured.err).toContain("Alice Example");
+    expect(captured.err).toContain("alice@example.com");
+    expect(captured.err).toContain("(+1 more)");
+    expect(captured.err).not.toContain("Active sessions");
+  });
+
+  test("fetches included resources alongside the user", async () => {
+    await get({ userId: "user_123", include: "sessions,orgs" });
+
+    expect(requestedPaths().sort()).toEqual([
+      "/sessions?user_id=user_123&status=active",
+      "/users/user_123",
+      "/users/user_123/organization_memberships?limit=100",
+    ]);
+    expect(captured.err).toContain("Active sessions (1)");
+    expect(captured.err).toContain("sess_1");
+    expect(captured.err).toContain("Organizations (1)");
+    expect(captured.err).toContain("org:admin");
+  });
+
+  test("renders external accounts and MFA from the user object", async () => {
+    await get({ userId: "user_123", include: "external-accounts,mfa" });
+
+    expect(requestedPaths()).toEqual(["/users/user_123"]);
+    expect(captured.err).toContain("External accounts (1)");
+    expect(captured.err).toContain("oauth_google");
+    expect(captured.err).toContain("Multi-factor authentication");
+    expect(captured.err).toContain("+15551234567");
+  });
+
+  test("adds included resources to the JSON output", async () => {
+    await get({ userId: "user_123", include: "all", json: true });
+
+    expect(JSON.parse(captured.out)).toEqual({
+      ...USER,
+      sessions: SESSIONS,
+      organization_memberships: MEMBERSHIPS.data,
+    });
+  });
+});
@@ -0,0 +1,256 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { bold, cyan, dim } from "../../lib/color.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { listUserSessions, type Session } from "../../lib/sessions.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatFields, formatTimestamp } from "../../lib/table.ts";
+import { pickUser } from "./interactive/pick-user.ts";
+import { printUsersJson } from "./output.ts";
+
+export const USER_INCLUDES = ["sessions", "orgs", "external-accounts", "mfa"] as const;
+
+export type UserInclude = (typeof USER_INCLUDES)[number];
+
+export type UsersGetOptions = {
+  userId?: string;
+  include?: string;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+type Identifier = {
+  id?: string;
+  email_address?: string;
+  phone_number?: string;
+  reserved_for_second_factor?: boolean;
+};
+
+type ExternalAccount = {
+  id?: string;
+  provider?: string;
+  email_address?: string;
+  username?: string | null;
+};
+
+type BapiUserDetail = {
+  id: string;
+  first_name?: string | null;
+  last_name?: string | null;
+  username?: string | null;
+  external_id?: string | null;
+  primary_email_address_id?: string | null;
+  primary_phone_number_id?: string | null;
+  email_addresses?: Identifier[];
+  phone_numbers?: Identifier[];
+  external_accounts?: ExternalAccount[];
+  two_factor_enabled?: boolean;
+  totp_enabled?: boolean;
+  backup_code_enabled?: boolean;
+  banned?: boolean;
+  locked?: boolean;
+  created_at?: number;
+  last_sign_in_at?: number | null;
+  last_active_at?: number | null;
+};
+
+type BapiMembership = {
+  id: string;
+  role?: string;
+  organization?: { id: string; name?: string; slug?: string | null };
+};
+
+type UserDetail = {
+  user: BapiUserDetail;
+  sessions?: Session[];
+  memberships?: BapiMembership[];
+};
+
+/** Parse `--include`, accepting a comma-separated list or `all`. */
+export function parseUserIncludes(value: string | undefined): Set<UserInclude> {
+  if (!value) return new Set();
+  const includes = new Set<UserInclude>();
+  for (const part of value.split(",")) {
+    const item = part.trim();
+    if (!item) continue;
+    if (item === "all") return new Set(USER_INCLUDES);
+    if (!(USER_INCLUDES as readonly string[]).includes(item)) {
+      throwUsageError(
+        `Unknown --include value "${item}". Expected one of: ${USER_INCLUDES.join(", ")}, all.`,
+      );
+    }
+    includes.add(item as UserInclude);
+  }
+  return includes;
+}
+
+async function fetchUserDetail(
+  secretKey: string,
+  userId: string,
+  includes: Set<UserInclude>,
+): Promise<UserDetail> {
+  const encoded = encodeURIComponent(userId);
+  const get = (path: string) => bapiRequest({ method: "GET", path, secretKey });
+
+  // Related resources only need the user ID, so fetch them alongside the user.
+  const [userResponse, sessions, membershipsResponse] = await Promise.all([
+    withApiContext(get(`/users/${encoded}`), `Failed to fetch user ${userId}`),
+    includes.has("sessions")
+      ? withApiContext(
+          listUserSessions(secretKey, { userId, status: "active" }),
+          "Failed to fetch sessions",
+        )
+      : undefined,
+    includes.has("orgs")
+      ? withApiContext(
+          get(`/users/${encoded}/organization_memberships?limit=100`),
+          "Failed to fetch organization memberships",
+        )
+      : undefined,
+  ]);
+
+  const detail: UserDetail = { user: userResponse.body as BapiUserDetail };
+  if (sessions) detail.sessions = sessions;
+  if (membershipsResponse) {
+    const body = membershipsResponse.body as { data?: BapiMembership[] } | undefined;
+    detail.memberships = Array.isArray(body?.data) ? body.data : [];
+  }
+  return detail;
+}
+
+function primaryValue(
+  items: Identifier[] | undefined,
+  primaryId: string | null | undefined,
+  key: "email_address" | "phone_number",
+): string | undefined {
+  if (!items?.length) return undefined;
+  const primary = items.find((item) => item.id === primaryId) ?? items[0]!;
+  const value = primary[key];
+  if (!value) return undefined;
+  return items.length > 1 ? `${value} ${dim(`(+${items.length - 1} more)`)}` : value;
+}
+
+function userStatus(user: BapiUserDetail): string {
+  if (user.banned) return "banned";
+  if (user.locked) return "locked";
+  return "active";
+}
+
+function formatToggle(value: boolean | undefined): string | undefined {
+  if (value === undefined) return undefined;
+  return value ? "enabled" : "disabled";
+}
+
+function formatSession(session: Session): string {
+  const lastActive = formatTimestamp(session.last_active_at) ?? "-";
+  const expires = formatTimestamp(session.expire_at) ?? "-";
+  return `  ${cyan(session.id)}  ${dim("last active")} ${lastActive}  ${dim("expires")} ${expires}`;
+}
+
+function formatMembership(membership: BapiMembership): string {
+  const org = membership.organization;
+  const label = org?.name ? `${org.name} ${dim(`(${org.id})`)}` : (org?.id ?? membership.id);
+  return `  ${label}  ${membership.role ?? ""}`.trimEnd();
+}
+
+function formatExternalAccount(account: ExternalAccount): string {
+  const identity = account.email_address || account.username || "";
+  return `  ${cyan(account.provider ?? "unknown")}  ${identity}`.trimEnd();
+}
+
+function section(title: string, lines: string[]): string[] {
+  return ["", bold(title), ...(lines.length ? lines : [dim("  (none)")])];
+}
+
+export function formatUserDetail(detail: UserDetail, includes: Set<UserInclude>): string[] {
+  const { user } = detail;
+  const name = [user.first_name, user.last_name].filter(Boolean).join(" ").trim();
+
+  const lines = [
+    bold(name || user.username || user.id),
+    ...formatFields([
+      ["ID", user.id],
+      [
+        "Email",
+        primaryValue(user.email_addresses, user.primary_email_address_id, "email_address"),
+      ],
+      ["Phone", primaryValue(user.phone_numbers, user.primary_phone_number_id, "phone_number")],
+      ["Username", user.username ?? undefined],
+      ["External ID", user.external_id ?? undefined],
+      ["Status", userStatus(user)],
+      ["Created", formatTimestamp(user.created_at)],
+      ["Last sign-in", formatTimestamp(user.last_sign_in_at)],
+      ["Last active", formatTimestamp(user.last_active_at)],
+    ]),
+  ];
+
+  if (includes.has("sessions")) {
+    const sessions = detail.sessions ?? [];
+    lines.push(...section(`Active sessions (${sessions.length})`, sessions.map(formatSession)));
+  }
+
+  if (includes.has("orgs")) {
+    const memberships = detail.memberships ?? [];
+    lines.push(
+      ...section(`Organizations (${memberships.length})`, memberships.map(formatMembership)),
+    );
+  }
+
+  if (includes.has("external-accounts")) {
+    const accounts = user.external_accounts ?? [];
+    lines.push(
+      ...section(`External accounts (${accounts.length})`, accounts.map(formatExternalAccount)),
+    );
+  }
+
+  if (includes.has("mfa")) {
+    const secondFactorPhones = (user.phone_numbers ?? [])
+      .filter((phone) => phone.reserved_for_second_factor && phone.phone_number)
+      .map((phone) => phone.phone_number!);
+    lines.push(
+      ...section(
+        "Multi-factor authentication",
+        formatFields([
+          ["Two-factor", formatToggle(user.two_factor_enabled)],
+          ["Authenticator app", formatToggle(user.totp_enabled)],
+          ["Backup codes", formatToggle(user.backup_code_enabled)],
+          ["SMS codes", secondFactorPhones.join(", ") || undefined],
+        ]),
+      ),
+    );
+  }
+
+  return lines;
+}
+
+export async function get(options: UsersGetOptions = {}): Promise<void> {
+  if (options.userId !== undefined && !/^user_[A-Za-z0-9]+$/.test(options.userId)) {
+    throwUsageError(`Invalid user ID '${options.userId}'. Expected format: user_<id>.`);
+  }
+  const includes = parseUserIncludes(options.include);
+
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+  const userId = await requireArg(options.userId, {
+    label: "User ID",
+    prompt: () => pickUser({ secretKey, message: "Pick a user to show:" }),
+  });
+
+  const detail = await withSpinner("Fetching user...", () =>
+    fetchUserDetail(secretKey, userId, includes),
+  );
+
+  const json: Record<string, unknown> = { ...detail.user };
+  if (detail.sessions) json.sessions = detail.sessions;
+  if (detail.memberships) json.organization_memberships = detail.memberships;
+  if (printUsersJson(json, options)) return;
+
+  await printOutput(formatUserDetail(detail, includes), { page: false });
+}
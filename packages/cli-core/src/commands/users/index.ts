@@ -1,9 +1,22 @@
 import { createOption, createArgument } from "@commander-js/extra-typings";
 import type { Program } from "../../cli-program.ts";
-import { parseIntegerOption, collectOptionValues } from "../../lib/option-parsers.ts";
+import {
+  parseIntegerOption,
+  parseTimeOption,
+  collectOptionValues,
+} from "../../lib/option-parsers.ts";
+import { impersonate } from "../impersonate/impersonate.ts";
+import { addImpersonateOptions } from "../impersonate/index.ts";
+import { anonymize } from "./anonymize.ts";
+import { count } from "./count.ts";
 import { create } from "./create.ts";
+import { DEFAULT_TOP, domainsReport } from "./domains.ts";
+import { get, USER_INCLUDES } from "./get.ts";
 import { list } from "./list.ts";
 import { usersMenu } from "./menu.ts";
+import { DEFAULT_CONCURRENCY, MAX_CONCURRENCY, metadataBulkSet } from "./metadata.ts";
+import { DEFAULT_MAP_FILE, migrate } from "./migrate.ts";
+import { notesAdd, notesList } from "./notes.ts";
 import { open } from "./open.ts";
 
 export type { UsersActionTargeting, UsersAction } from "./registry.ts";
@@ -14,9 +27,13 @@ export {
 } from "./registry.ts";
 
 const users = {
+  anonymize,
+  count,
   create,
+  get,
   list,
   menu: usersMenu,
+  migrate,
   open,
 };
 
@@ -60,6 +77,13 @@ export function registerUsers(program: Program): void {
     .command("list")
     .description("List users")
     .option("--json", "Output as JSON")
+    .addOption(
+      createOption(
+        "--jsonl",
+        "Same as -o jsonl: stream every matching user as JSON Lines, fetching all pages",
+      ).conflicts(["json", "limit"]),
+    )
+    .option("--resume <file>", "With --jsonl, checkpoint progress to a file and resume from it")
     .option("--limit <number>", "Maximum users to return (1-250, default 100)", (value) =>
       parseIntegerOption(value, "--limit", { min: 1, max: 250 }),
     )
@@ -103,6 +127,37 @@ export function registerUsers(program: Program): void {
         "Order by a supported field, optionally prefixed with + or -",
       ).choices(USER_LIST_ORDER_BY_CHOICES),
     )
+    .option(
+      "--created-after <time>",
+      "Only users created after a time (e.g. 30d, 2024-01-01)",
+      (value) => parseTimeOption(value, "--created-after"),
+    )
+    .option("--created-before <time>", "Only users created before a time", (value) =>
+      parseTimeOption(value, "--created-before"),
+    )
+    .option("--last-active-since <time>", "Only users active since a time (e.g. 7d)", (value) =>
+      parseTimeOption(value, "--last-active-since"),
+    )
+    .option(
+      "--last-sign-in-before <time>",
+      "Only users whose last sign-in is before a time",
+      (value) => parseTimeOption(value, "--last-sign-in-before"),
+    )
+    .option("--banned", "Only banned users")
+    .option("--no-banned", "Only users who are not banned")
+    .option("--locked", "Only locked users (filtered locally)")
+    .option("--no-locked", "Only users who are not locked (filtered locally)")
+    .option("--password-enabled", "Only users with a password (filtered locally)")
+    .option("--no-password-enabled", "Only users without a password (filtered locally)")
+    .option("--two-factor-enabled", "Only users with 2FA enabled (filtered locally)")
+    .option("--no-two-factor-enabled", "Only users without 2FA (filtered locally)")
+    .option("--columns <list>", "Table columns to show, comma-separated (e.g. id,email)")
+    .option("--sort <column[:dir]>", "Sort the returned page by a column, e.g. created_at:desc")
+    .option("--wide", "Show every table column")
+    .option(
+      "--fields <list>",
+      "JSON fields to keep per user, comma-separated; dots reach nested fields",
+    )
     .option("--secret-key <key>", "Backend API secret key to use")
     .option("--app <id>", "Application ID to target (works from any directory)")
     .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
@@ -117,9 +172,112 @@ export function registerUsers(program: Program): void {
           "clerk users list --email-address alice@example.com --external-id crm_123 --order-by -last_sign_in_at",
         description: "Filter by common identifiers and sort by recent sign-in",
       },
+      {
+        command: 'clerk users list --created-after "last monday" --last-active-since 7d',
+        description: "Filter by relative or absolute times",
+      },
+      {
+        command: "clerk users list --banned --jsonl | jq -r .id",
+        description: "Export the IDs of every banned user",
+      },
+      {
+        command: "clerk users list --password-enabled --no-two-factor-enabled",
+        description: "Find password users who haven't turned on 2FA",
+      },
+      {
+        command: "clerk users list --columns id,email,last_sign_in --sort last_sign_in:desc",
+        description: "Pick table columns and sort the page locally",
+      },
+      {
+        command: "clerk users list --jsonl | jq -r .id",
+        description: "Stream every user, one JSON object per line",
+      },
+      {
+        command: "clerk users list --jsonl --fields id,email_addresses.email_address",
+        description: "Export only IDs and email addresses",
+      },
+      {
+        command: "clerk users list --jsonl --resume users.state >> users.jsonl",
+        description: "Export every user; rerun the same command to continue after an interruption",
+      },
     ])
     .action((_opts, cmd) => users.list(cmd.optsWithGlobals() as Parameters<typeof users.list>[0]));
 
+  usersCommand
+    .command("count")
+    .description("Count users, optionally filtered")
+    .option("--query <query>", "Search across common user fields")
+    .option(
+      "--created-after <time>",
+      "Only users created after a time (e.g. 30d, 2024-01-01)",
+      (value) => parseTimeOption(value, "--created-after"),
+    )
+    .option("--created-before <time>", "Only users created before a time", (value) =>
+      parseTimeOption(value, "--created-before"),
+    )
+    .option("--last-active-since <time>", "Only users active since a time (e.g. 7d)", (value) =>
+      parseTimeOption(value, "--last-active-since"),
+    )
+    .option(
+      "--last-sign-in-before <time>",
+      "Only users whose last sign-in is before a time",
+      (value) => parseTimeOption(value, "--last-sign-in-before"),
+    )
+    .option(
+      "--where <key=value>",
+      "Any other Backend API filter (repeatable), e.g. email_address=a@b.com",
+      collectOptionValues,
+      [],
+    )
+    .option("--json", "Output as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      { command: "clerk users count", description: "Total users on the instance" },
+      {
+        command: "clerk users count --last-active-since 30d",
+        description: "Monthly active users",
+      },
+      {
+        command: "clerk users count --where banned=true",
+        description: "Pass any /users/count filter through",
+      },
+    ])
+    .action((_opts, cmd) =>
+      users.count(cmd.optsWithGlobals() as Parameters<typeof users.count>[0]),
+    );
+
+  usersCommand
+    .command("get")
+    .description("Show a user's details, optionally with related resources")
+    .argument("[user-id]", "User ID to show. Omit to pick interactively.")
+    .option(
+      "--include <list>",
+      `Related resources to add, comma-separated (${USER_INCLUDES.join(", ")}, or all)`,
+    )
+    .option("--json", "Output as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      { command: "clerk users get user_2x9k", description: "Show a user's profile" },
+      {
+        command: "clerk users get user_2x9k --include sessions,orgs",
+        description: "Add active sessions and organization memberships",
+      },
+      {
+        command: "clerk users get user_2x9k --include all --json",
+        description: "Everything, as JSON",
+      },
+    ])
+    .action((userId, _opts, cmd) =>
+      users.get({
+        ...(cmd.optsWithGlobals() as Parameters<typeof users.get>[0]),
+        userId,
+      }),
+    );
+
   usersCommand
     .command("create")
     .description("Create a user")
@@ -182,4 +340,227 @@ export function registerUsers(program: Program): void {
         userId,
       }),
     );
+
+  addImpersonateOptions(
+    usersCommand
+      .command("impersonate")
+      .description("Sign in as a user with a short-lived actor token (same as `clerk impersonate`)")
+      .addArgument(
+        createArgument("[user]", "User ID (user_...), exact email, or search term. Omit to pick."),
+      ),
+  )
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      {
+        command: "clerk users impersonate alice@example.com --open",
+        description: "Land in the user's session in your browser in one step",
+      },
+      {
+        command: "clerk users impersonate user_2x9k --actor TICKET-123 --open --yes",
+        description: "Stamp the support ticket on the session and skip the prompt",
+      },
+    ])
+    .action((user, _opts, cmd) =>
+      impersonate({
+        ...(cmd.optsWithGlobals() as Parameters<typeof impersonate>[0]),
+        user,
+      }),
+    );
+
+  usersCommand
+    .command("anonymize")
+    .description("Strip a user's personal data, keeping the user record and ID")
+    .argument("<user-id>", "User ID to anonymize")
+    .option(
+      "--metadata-pattern <regex>",
+      "Also remove metadata keys matching this case-insensitive pattern",
+    )
+    .option(
+      "--placeholder-email-domain <domain>",
+      "Add a verified placeholder email at this domain first, so the last real one can go",
+    )
+    .option("--dry-run", "Show what would be removed without changing anything")
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--json", "Output as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      {
+        command:
+          "clerk users anonymize user_2x9k --placeholder-email-domain example.invalid --dry-run",
+        description: "Report what would be removed",
+      },
+      {
+        command:
+          "clerk users anonymize user_2x9k --placeholder-email-domain example.invalid --metadata-pattern 'email|address'",
+        description: "Also remove matching metadata keys",
+      },
+      {
+        command: "clerk users anonymize user_2x9k --placeholder-email-domain example.invalid --yes",
+        description: "Anonymize without the confirmation prompt",
+      },
+    ])
+    .action((userId, _opts, cmd) =>
+      users.anonymize({
+        ...(cmd.optsWithGlobals() as Parameters<typeof users.anonymize>[0]),
+        userId,
+      }),
+    );
+
+  usersCommand
+    .command("migrate")
+    .description("Copy users from one instance to another, with an ID map for resuming")
+    .option("--from-secret-key <key>", "Secret key of the source instance")
+    .option("--from-app <id>", "Application of the source instance")
+    .option("--from-instance <id>", "Source instance (dev, prod, or a full instance ID)")
+    .option("--to-secret-key <key>", "Secret key of the target instance")
+    .option("--to-app <id>", "Application of the target instance")
+    .option("--to-instance <id>", "Target instance (dev, prod, or a full instance ID)")
+    .option("--query <query>", "Only migrate source users matching this search")
+    .option(
+      "--password-export <csv>",
+      "User export CSV with password_digest and password_hasher columns, to copy passwords",
+    )
+    .option(
+      "--map-file <path>",
+      "JSON Lines file recording each source ID and its new ID; rerun to resume",
+      DEFAULT_MAP_FILE,
+    )
+    .option("--resume <file>", "Checkpoint the source page reached to a file, and resume from it")
+    .option("--dry-run", "Count the users that would be migrated without creating any")
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--json", "Output a summary as JSON")
+    .setExamples([
+      {
+        command: "clerk users migrate --from-instance dev --to-instance prod --dry-run",
+        description: "Count the users a migration would create",
+      },
+      {
+        command:
+          "clerk users migrate --from-app app_staging --from-instance prod --to-app app_main --to-instance prod",
+        description: "Copy every user between applications",
+      },
+      {
+        command:
+          "clerk users migrate --from-instance dev --to-instance prod --query @acme.com --map-file acme.jsonl",
+        description: "Migrate matching users; rerun the same command to resume",
+      },
+      {
+        command:
+          "clerk users migrate --from-instance dev --to-instance prod --password-export users.csv",
+        description: "Copy password hashes from a dashboard user export",
+      },
+    ])
+    .action((_opts, cmd) =>
+      users.migrate(cmd.optsWithGlobals() as Parameters<typeof users.migrate>[0]),
+    );
+
+  const domains = usersCommand.command("domains").description("Analyze users' email domains");
+
+  domains
+    .command("report")
+    .description("Count users per email domain and flag disposable ones")
+    .option("--top <n>", `Domains to show (default ${DEFAULT_TOP})`, (value) =>
+      parseIntegerOption(value, "--top", { min: 1 }),
+    )
+    .option(
+      "--disposable-list <url|file>",
+      "Feed of disposable domains to flag (default: a built-in list of common providers)",
+    )
+    .option("--block", "Add a blocklist entry for every unblocked disposable domain found")
+    .option("--json", "Output as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      {
+        command: "clerk users domains report",
+        description: "Show the top domains, then pick disposable ones to block",
+      },
+      {
+        command: "clerk users domains report --disposable-list disposable.txt --block",
+        description: "Flag domains from your own feed and block them without prompting",
+      },
+    ])
+    .action((_opts, cmd) =>
+      domainsReport(cmd.optsWithGlobals() as Parameters<typeof domainsReport>[0]),
+    );
+
+  const notes = usersCommand
+    .command("notes")
+    .description("Support notes stored on a user's private metadata");
+
+  notes
+    .command("list")
+    .description("Show a user's support notes, oldest first")
+    .argument("[user-id]", "User ID whose notes to show")
+    .option("--json", "Output as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([{ command: "clerk users notes list user_2x9k", description: "Show notes" }])
+    .action((userId, _opts, cmd) =>
+      notesList({ ...(cmd.optsWithGlobals() as Parameters<typeof notesList>[0]), userId }),
+    );
+
+  notes
+    .command("add")
+    .description("Add a support note, signed with your login email and the time")
+    .argument("[user-id]", "User ID to annotate")
+    .argument("[note]", "Note text")
+    .option("--author <name>", "Sign the note with this name instead of your login email")
+    .option("--json", "Output the new note as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      {
+        command: 'clerk users notes add user_2x9k "Refunded March invoice, see TICKET-123"',
+        description: "Record support context on the user",
+      },
+      {
+        command: 'clerk users notes add user_2x9k "Verified by phone" --author support-bot',
+        description: "Sign the note from an automation",
+      },
+    ])
+    .action((userId, note, _opts, cmd) =>
+      notesAdd({ ...(cmd.optsWithGlobals() as Parameters<typeof notesAdd>[0]), userId, note }),
+    );
+
+  const metadata = usersCommand.command("metadata").description("Bulk-edit user metadata");
+
+  metadata
+    .command("bulk-set")
+    .description("Merge metadata into many users from a JSON Lines file")
+    .requiredOption(
+      "--file <path>",
+      'JSON Lines file; each line is {"user_id": ..., "public_metadata": {...}}',
+    )
+    .option(
+      "--concurrency <n>",
+      `Requests in flight at once (default ${DEFAULT_CONCURRENCY})`,
+      (value) => parseIntegerOption(value, "--concurrency", { min: 1, max: MAX_CONCURRENCY }),
+    )
+    .option("--dry-run", "Validate the file and count the users without updating any")
+    .option("--yes", "Skip the confirmation prompt")
+    .option("--json", "Output per-line results as JSON")
+    .option("--secret-key <key>", "Backend API secret key to use")
+    .option("--app <id>", "Application ID to target (works from any directory)")
+    .option("--instance <id>", "Instance to target (dev, prod, or a full instance ID)")
+    .setExamples([
+      {
+        command: "clerk users metadata bulk-set --file entitlements.jsonl --dry-run",
+        description: "Check every line before sending anything",
+      },
+      {
+        command: "clerk users metadata bulk-set --file entitlements.jsonl --yes --json",
+        description: "Backfill from a script and collect per-line results",
+      },
+    ])
+    .action((_opts, cmd) =>
+      metadataBulkSet(cmd.optsWithGlobals() as Parameters<typeof metadataBulkSet>[0]),
+    );
 }
@@ -1,6 +1,11 @@
 import { test, expect, describe, beforeEach, afterEach, mock, spyOn } from "bun:test";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
 import { CliError, ERROR_CODE } from "../../lib/errors.ts";
-import { popPrefix, pushPrefix } from "../../lib/log.ts";
+import { popPrefix, pushPrefix, setPlain } from "../../lib/log.ts";
+import { setOutputFormat } from "../../lib/output-format.ts";
+import { setQuiet } from "../../lib/quiet.ts";
 import { useCaptureLog } from "../../test/lib/stubs.ts";
 
 const mockBapiRequest = mock();
@@ -150,6 +155,22 @@ describe("users list", () => {
     expect(url.searchParams.get("offset")).toBe("50");
   });
 
+  test("serializes time filters as Unix milliseconds", async () => {
+    await runList({
+      createdAfter: 1_704_067_200_000,
+      createdBefore: 1_706_745_600_000,
+      lastActiveSince: 1_705_000_000_000,
+      lastSignInBefore: 1_700_000_000_000,
+    });
+
+    const request = mockBapiRequest.mock.calls[0]?.[0] as { path: string } | undefined;
+    const url = new URL(request!.path, "https://api.clerk.test");
+    expect(url.searchParams.get("created_at_after")).toBe("1704067200000");
+    expect(url.searchParams.get("created_at_before")).toBe("1706745600000");
+    expect(url.searchParams.get("last_active_at_since")).toBe("1705000000000");
+    expect(url.searchParams.get("last_sign_in_at_before")).toBe("1700000000000");
+  });
+
   test("prints a concise human-readable table by default", async () => {
     await runList();
 
@@ -161,6 +182,34 @@ describe("users list", () => {
     expect(captured.err).toContain("2 users returned");
   });
 
+  test("--columns picks and orders table columns", async () => {
+    await runList({ columns: "id,email" });
+
+    expect(captured.err).toContain("USER ID");
+    expect(captured.err).toContain("EMAIL");
+    expect(captured.err).not.toContain("Alice Example");
+    const err = captured.err;
+    expect(err.indexOf("user_123")).toBeLessThan(err.indexOf("alice@example.com"));
+  });
+
+  test("--sort orders the returned rows", async () => {
+    await runList({ columns: "id", sort: "id:desc" });
+
+    expect(captured.err.indexOf("user_456")).toBeLessThan(captured.err.indexOf("user_123"));
+  });
+
+  test("--wide adds the extra columns", async () => {
+    await runList({ wide: true });
+
+    expect(captured.err).toContain("USERNAME");
+    expect(captured.err).toContain("LAST SIGN-IN");
+  });
+
+  test("rejects an unknown --columns entry before calling the API", async () => {
+    await expect(runList({ columns: "id,nope" })).rejects.toThrow('Unknown column "nope"');
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
   test("prints a helpful message when no users are returned", async () => {
     mockBapiRequest.mockResolvedValue({
       status: 200,
@@ -182,6 +231,47 @@ describe("users list", () => {
     expect(captured.err).toBe("");
   });
 
+  test("--fields trims each user in JSON output", async () => {
+    await runList({ json: true, fields: "id,email_addresses.email_address" });
+
+    expect(JSON.parse(captured.out)).toEqual({
+      data: [
+        { id: "user_123", email_addresses: [{ email_address: "alice@example.com" }] },
+        { id: "user_456" },
+      ],
+      hasMore: false,
+    });
+  });
+
+  test("--fields is rejected for the human table before calling the API", async () => {
+    await expect(runList({ fields: "id" })).rejects.toThrow("Use --columns for the table");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("prints only user IDs in quiet mode, even with --json", async () => {
+    setQuiet(true);
+    try {
+      await runList({ json: true });
+    } finally {
+      setQuiet(false);
+    }
+
+    expect(captured.out).toBe("user_123\nuser_456");
+  });
+
+  test("--plain prints the table rows on stdout without styling", async () => {
+    setPlain(true);
+    try {
+      await runList();
+    } finally {
+      setPlain(false);
+    }
+
+    expect(captured.out).toContain("user_123");
+    expect(captured.out).toContain("user_456");
+    expect(captured.out).not.toContain("\x1b[");
+  });
+
   test("outputs JSON in agent mode", async () => {
     mockIsAgent.mockReturnValue(true);
 
@@ -298,6 +388,181 @@ describe("users list", () => {
     expect(mockResolveUsersInstanceContext).not.toHaveBeenCalled();
   });
 
+  test("--jsonl streams every page as one JSON object per line", async () => {
+    const fullPage = Array.from({ length: 500 }, (_, i) => ({ id: `user_${i}` }));
+    mockBapiRequest
+      .mockResolvedValueOnce({ status: 200, headers: new Headers(), body: fullPage })
+      .mockResolvedValueOnce({ status: 200, headers: new Headers(), body: mockUsers });
+
+    await runList({ jsonl: true, offset: 10, query: "ali" });
+
+    expect(mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path)).toEqual([
+      "/users?limit=500&offset=10&query=ali",
+      "/users?limit=500&offset=510&query=ali",
+    ]);
+    const lines = captured.out.split("\n");
+    expect(lines).toHaveLength(502);
+    expect(JSON.parse(lines[0]!)).toEqual({ id: "user_0" });
+    expect(JSON.parse(lines[501]!).id).toBe("user_456");
+    expect(mockWithSpinner).not.toHaveBeenCalled();
+  });
+
+  test("-o jsonl streams like --jsonl and rejects --limit", async () => {
+    mockBapiRequest.mockResolvedValue({ status: 200, headers: new Headers(), body: mockUsers });
+    setOutputFormat("jsonl");
+    try {
+      await expect(runList({ limit: 10 })).rejects.toThrow("Drop --limit");
+      await runList();
+    } finally {
+      setOutputFormat("table");
+    }
+
+    expect((mockBapiRequest.mock.calls[0]![0] as { path: string }).path).toBe(
+      "/users?limit=500&offset=0",
+    );
+    expect(captured.out.split("\n").map((line) => JSON.parse(line).id)).toEqual([
+      "user_123",
+      "user_456",
+    ]);
+  });
+
+  test("--jsonl prints only user IDs in quiet mode", async () => {
+    mockBapiRequest.mockResolvedValue({ status: 200, headers: new Headers(), body: mockUsers });
+    setQuiet(true);
+    try {
+      await runList({ jsonl: true });
+    } finally {
+      setQuiet(false);
+    }
+
+    expect(captured.out).toBe("user_123\nuser_456");
+  });
+
+  test("--jsonl rejects --resume with --offset before calling the API", async () => {
+    await expect(runList({ jsonl: true, resume: "users.state", offset: 10 })).rejects.toThrow(
+      "Drop --offset",
+    );
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("--jsonl --resume continues after the last complete page", async () => {
+    const dir = await mkdtemp(join(tmpdir(), "clerk-users-list-"));
+    const resume = join(dir, "users.state");
+    const fullPage = Array.from({ length: 500 }, (_, i) => ({ id: `user_${i}` }));
+    try {
+      mockBapiRequest
+        .mockResolvedValueOnce({ status: 200, headers: new Headers(), body: fullPage })
+        .mockRejectedValueOnce(new Error("connection reset"));
+      await expect(runList({ jsonl: true, resume })).rejects.toThrow("connection reset");
+      expect(JSON.parse(await Bun.file(resume).text())).toMatchObject({
+        cursor: { offset: 500 },
+        done: 500,
+      });
+
+      mockBapiRequest.mockResolvedValueOnce({
+        status: 200,
+        headers: new Headers(),
+        body: mockUsers,
+      });
+      await runList({ jsonl: true, resume });
+
+      expect(mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path)).toEqual([
+        "/users?limit=500&offset=0",
+        "/users?limit=500&offset=500",
+        "/users?limit=500&offset=500",
+      ]);
+      expect(await Bun.file(resume).exists()).toBe(false);
+    } finally {
+      await rm(dir, { recursive: true, force: true });
+    }
+  });
+
+  test("--jsonl --resume skips the lines already written from a partial page", async () => {
+    const dir = await mkdtemp(join(tmpdir(), "clerk-users-list-"));
+    const resume = join(dir, "users.state");
+    try {
+      await Bun.write(
+        resume,
+        JSON.stringify({
+          version: 1,
+          job: "users list --jsonl",
+          params: {},
+          cursor: { offset: 0, skip: 1 },
+          done: 1,
+          failed: 0,
+          updated_at: "2026-01-01T00:00:00.000Z",
+        }),
+      );
+      mockBapiRequest.mockResolvedValueOnce({
+        status: 200,
+        headers: new Headers(),
+        body: mockUsers,
+      });
+
+      await runList({ jsonl: true, resume });
+
+      expect(captured.out.split("\n").map((line) => JSON.parse(line).id)).toEqual(["user_456"]);
+      expect(await Bun.file(resume).exists()).toBe(false);
+    } finally {
+      await rm(dir, { recursive: true, force: true });
+    }
+  });
+
+  test("--banned and --no-banned are sent to the API", async () => {
+    await runList({ json: true, banned: true });
+    await runList({ json: true, banned: false });
+
+    expect(mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path)).toEqual([
+      "/users?limit=101&banned=true",
+      "/users?limit=101&banned=false",
+    ]);
+  });
+
+  test("local state filters scan pages until the page is full", async () => {
+    mockWithSpinner.mockImplementation(
+      (_msg: string, fn: (controls?: unknown) => Promise<unknown>) => fn({ update: () => {} }),
+    );
+    const firstPage = Array.from({ length: 500 }, (_, i) => ({
+      id: `user_${i}`,
+      two_factor_enabled: i === 7,
+    }));
+    const secondPage = [
+      { id: "user_a", two_factor_enabled: true },
+      { id: "user_b", two_factor_enabled: false },
+      { id: "user_c", two_factor_enabled: true },
+    ];
+    mockBapiRequest
+      .mockResolvedValueOnce({ status: 200, headers: new Headers(), body: firstPage })
+      .mockResolvedValueOnce({ status: 200, headers: new Headers(), body: secondPage });
+
+    await runList({ limit: 2, twoFactorEnabled: true });
+
+    expect(mockBapiRequest.mock.calls.map(([args]) => (args as { path: string }).path)).toEqual([
+      "/users?limit=500&offset=0",
+      "/users?limit=500&offset=500",
+    ]);
+    expect(captured.err).toContain("user_7");
+    expect(captured.err).toContain("user_a");
+    expect(captured.err).not.toContain("user_c");
+    // user_c is the first match that didn't fit, at API offset 502.
+    expect(captured.err).toContain("re-run with `--offset 502`");
+  });
+
+  test("--jsonl applies local state filters to each streamed user", async () => {
+    mockBapiRequest.mockResolvedValueOnce({
+      status: 200,
+      headers: new Headers(),
+      body: [
+        { id: "user_1", locked: true },
+        { id: "user_2", locked: false },
+      ],
+    });
+
+    await runList({ jsonl: true, locked: false });
+
+    expect(captured.out).toBe(JSON.stringify({ id: "user_2", locked: false }));
+  });
+
   test.each([
     { label: "--app", options: { app: "app_123" } },
     { label: "--instance", options: { instance: "prod" } },
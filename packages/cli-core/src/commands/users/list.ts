@@ -1,15 +1,50 @@
 import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
 import { dim, cyan } from "../../lib/color.ts";
-import { CliError, ERROR_CODE, UserAbortError, isPromptExitError } from "../../lib/errors.ts";
+import {
+  CliError,
+  ERROR_CODE,
+  UserAbortError,
+  isPromptExitError,
+  throwUsageError,
+} from "../../lib/errors.ts";
 import { isInsideGutter, log } from "../../lib/log.ts";
 import { isAgent, isHuman } from "../../mode.ts";
 import { withSpinner, intro, outro, pausedOutro } from "../../lib/spinner.ts";
 import { bapiRequest } from "../../lib/bapi.ts";
+import { parseFieldsOption, pickFields } from "../../lib/fields.ts";
+import { openJob } from "../../lib/job-state.ts";
+import { getOutputFormat } from "../../lib/output-format.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { isQuiet, printQuietIds } from "../../lib/quiet.ts";
+import {
+  formatTimestamp,
+  renderTable,
+  validateTableOptions,
+  type TableColumn,
+} from "../../lib/table.ts";
+import { primaryEmail, primaryFirst, type UserIdentifier } from "../../lib/users.ts";
 import { resolveUsersInstanceContext } from "./interactive/instance-context.ts";
 import { registerUsersAction } from "./registry.ts";
 
+export type UserTimeFilters = {
+  createdAfter?: number;
+  createdBefore?: number;
+  lastActiveSince?: number;
+  lastSignInBefore?: number;
+};
+
+/** Account-state filters. `banned` is a `GET /users` parameter; the rest are applied locally. */
+type UserStateFilters = {
+  banned?: boolean;
+  locked?: boolean;
+  passwordEnabled?: boolean;
+  twoFactorEnabled?: boolean;
+};
+
 type UsersListOptions = {
   json?: boolean;
+  jsonl?: boolean;
+  resume?: string;
   secretKey?: string;
   app?: string;
   instance?: string;
@@ -22,9 +57,12 @@ type UsersListOptions = {
   userId?: string[];
   externalId?: string[];
   orderBy?: string;
-};
-
-type UserIdentifier = { id?: string; email_address?: string; phone_number?: string };
+  columns?: string;
+  sort?: string;
+  wide?: boolean;
+  fields?: string;
+} & UserTimeFilters &
+  UserStateFilters;
 
 type BapiUser = {
   id: string;
@@ -33,13 +71,22 @@ type BapiUser = {
   username?: string | null;
   primary_email_address_id?: string | null;
   primary_phone_number_id?: string | null;
+  external_id?: string | null;
+  created_at?: number;
+  last_sign_in_at?: number | null;
   email_addresses?: UserIdentifier[];
   phone_numbers?: UserIdentifier[];
+  banned?: boolean;
+  locked?: boolean;
+  password_enabled?: boolean;
+  two_factor_enabled?: boolean;
 };
 
-const COLUMN_PADDING = 2;
 const DEFAULT_LIMIT = 100;
 
+/** BAPI's MaxLimit. `--jsonl` fetches the largest pages the API allows. */
+const STREAM_PAGE_SIZE = 500;
+
 function printJson(data: unknown, options: UsersListOptions = {}): boolean {
   if (!options.json && !isAgent()) return false;
   log.data(JSON.stringify(data, null, 2));
@@ -61,6 +108,22 @@ function appendMultiValueParam(
   }
 }
 
+/** Shared by `users list` and `users count`, which accept the same time filters. */
+export function appendUserTimeFilters(
+  searchParams: URLSearchParams,
+  options: UserTimeFilters,
+): void {
+  const timeFilters: [string, number | undefined][] = [
+    ["created_at_after", options.createdAfter],
+    ["created_at_before", options.createdBefore],
+    ["last_active_at_since", options.lastActiveSince],
+    ["last_sign_in_at_before", options.lastSignInBefore],
+  ];
+  for (const [key, value] of timeFilters) {
+    if (typeof value === "number") searchParams.set(key, String(value));
+  }
+}
+
 function buildUsersListPath(options: UsersListOptions, requestLimit: number): string {
   const searchParams = new URLSearchParams();
 
@@ -80,55 +143,137 @@ function buildUsersListPath(options: UsersListOptions, requestLimit: number): st
   appendMultiValueParam(searchParams, "username", options.username);
   appendMultiValueParam(searchParams, "user_id", options.userId);
   appendMultiValueParam(searchParams, "external_id", options.externalId);
+  appendUserTimeFilters(searchParams, options);
+  if (typeof options.banned === "boolean") {
+    searchParams.set("banned", String(options.banned));
+  }
 
   const query = searchParams.toString();
   return query ? `/users?${query}` : "/users";
 }
 
-function userDisplayName(user: BapiUser): string {
-  const fullName = [user.first_name, user.last_name].filter(Boolean).join(" ").trim();
-  return fullName || user.username || primaryIdentifier(user) || user.id;
-}
+/**
+ * State filters `GET /users` has no parameter for. They are checked against
+ * each user as pages arrive, so a filtered page may take several requests.
+ */
+const LOCAL_STATE_FILTERS = [
+  { option: "locked", field: "locked" },
+  { option: "passwordEnabled", field: "password_enabled" },
+  { option: "twoFactorEnabled", field: "two_factor_enabled" },
+] as const;
 
-function primaryIdentifier(user: BapiUser): string {
-  const primaryEmail = user.email_addresses?.find(
-    (email) => email.id && email.id === user.primary_email_address_id,
-  );
-  if (primaryEmail?.email_address) return primaryEmail.email_address;
-
-  const firstEmail = user.email_addresses?.find((email) => email.email_address);
-  if (firstEmail?.email_address) return firstEmail.email_address;
-
-  const primaryPhone = user.phone_numbers?.find(
-    (phone) => phone.id && phone.id === user.primary_phone_number_id,
-  );
-  if (primaryPhone?.phone_number) return primaryPhone.phone_number;
+function localStateFilter(options: UserStateFilters): ((user: BapiUser) => boolean) | undefined {
+  const active = LOCAL_STATE_FILTERS.filter(({ option }) => typeof options[option] === "boolean");
+  if (active.length === 0) return undefined;
+  return (user) => active.every(({ option, field }) => Boolean(user[field]) === options[option]);
+}
 
-  const firstPhone = user.phone_numbers?.find((phone) => phone.phone_number);
-  if (firstPhone?.phone_number) return firstPhone.phone_number;
+type UsersPage = {
+  users: BapiUser[];
+  hasMore: boolean;
+  /** The `--offset` that continues after this page. */
+  nextOffset: number;
+};
 
-  if (user.username) return user.username;
+async function fetchUsersPage(
+  secretKey: string,
+  options: UsersListOptions,
+  limit: number,
+  offset: number,
+): Promise<UsersPage> {
+  // Request one extra row so we can detect whether more pages exist without
+  // a separate /users/count round-trip. The CLI's --limit caps at 250, so
+  // pageSize + 1 always fits under BAPI's MaxLimit of 500.
+  const response = await bapiRequest({
+    method: "GET",
+    path: buildUsersListPath(options, limit + 1),
+    secretKey,
+  });
+  const body = response.body;
+  const allUsers = Array.isArray(body) ? (body as BapiUser[]) : [];
+  const hasMore = allUsers.length > limit;
+  return {
+    users: hasMore ? allUsers.slice(0, limit) : allUsers,
+    hasMore,
+    nextOffset: offset + limit,
+  };
+}
 
-  return user.id;
+/**
+ * Collect up to `limit` users that pass `matches`, scanning from `offset` in
+ * the largest pages the API allows. `nextOffset` points at the first match
+ * that didn't fit, so `--offset` still resumes exactly where this page ended.
+ */
+async function scanUsersPage(
+  secretKey: string,
+  options: UsersListOptions,
+  matches: (user: BapiUser) => boolean,
+  limit: number,
+  offset: number,
+  onScanned: (scanned: number) => void,
+): Promise<UsersPage> {
+  const users: BapiUser[] = [];
+  let cursor = offset;
+  while (true) {
+    const response = await bapiRequest({
+      method: "GET",
+      path: buildUsersListPath({ ...options, offset: cursor }, STREAM_PAGE_SIZE),
+      secretKey,
+    });
+    const page = Array.isArray(response.body) ? (response.body as BapiUser[]) : [];
+    for (const [index, user] of page.entries()) {
+      if (!matches(user)) continue;
+      if (users.length === limit) return { users, hasMore: true, nextOffset: cursor + index };
+      users.push(user);
+    }
+    cursor += page.length;
+    onScanned(cursor - offset);
+    if (page.length < STREAM_PAGE_SIZE) return { users, hasMore: false, nextOffset: cursor };
+  }
 }
 
-function formatUsersTable(users: BapiUser[]): void {
-  const nameWidth =
-    Math.max("NAME".length, ...users.map((user) => userDisplayName(user).length)) + COLUMN_PADDING;
-  const idWidth =
-    Math.max("USER ID".length, ...users.map((user) => user.id.length)) + COLUMN_PADDING;
+function userDisplayName(user: BapiUser): string {
+  const fullName = [user.first_name, user.last_name].filter(Boolean).join(" ").trim();
+  return fullName || user.username || primaryIdentifier(user) || user.id;
+}
 
-  log.info(
-    `${dim("NAME".padEnd(nameWidth))}${dim("USER ID".padEnd(idWidth))}${dim("PRIMARY IDENTIFIER")}`,
-  );
+function primaryPhone(user: BapiUser): string | undefined {
+  return primaryFirst(user.phone_numbers, user.primary_phone_number_id, "phone_number")[0];
+}
 
-  for (const user of users) {
-    const name = cyan(userDisplayName(user).padEnd(nameWidth));
-    const id = dim(user.id.padEnd(idWidth));
-    log.info(`${name}${id}${primaryIdentifier(user)}`);
-  }
+function primaryIdentifier(user: BapiUser): string {
+  return primaryEmail(user) || primaryPhone(user) || user.username || user.id;
 }
 
+const USER_COLUMNS: TableColumn<BapiUser>[] = [
+  { key: "name", header: "NAME", value: userDisplayName, style: cyan },
+  { key: "id", header: "USER ID", value: (user) => user.id, style: dim },
+  { key: "identifier", header: "PRIMARY IDENTIFIER", value: primaryIdentifier },
+  { key: "email", header: "EMAIL", value: primaryEmail, wide: true },
+  { key: "phone", header: "PHONE", value: primaryPhone, wide: true },
+  { key: "username", header: "USERNAME", value: (user) => user.username ?? undefined, wide: true },
+  {
+    key: "external_id",
+    header: "EXTERNAL ID",
+    value: (user) => user.external_id ?? undefined,
+    wide: true,
+  },
+  {
+    key: "created_at",
+    header: "CREATED",
+    value: (user) => formatTimestamp(user.created_at),
+    sortValue: (user) => user.created_at,
+    wide: true,
+  },
+  {
+    key: "last_sign_in",
+    header: "LAST SIGN-IN",
+    value: (user) => formatTimestamp(user.last_sign_in_at),
+    sortValue: (user) => user.last_sign_in_at ?? undefined,
+    wide: true,
+  },
+];
+
 async function resolveListSecretKey(options: UsersListOptions): Promise<string> {
   try {
     return await resolveBapiSecretKey({
@@ -161,9 +306,114 @@ async function resolveListSecretKey(options: UsersListOptions): Promise<string>
   }
 }
 
+/**
+ * The Backend API has no field selection for `GET /users`, so `--fields` is
+ * applied to each user after it arrives. That shrinks the output, not the
+ * download.
+ */
+function resolveFieldProjection(options: UsersListOptions): (user: BapiUser) => unknown {
+  if (options.fields === undefined) return (user) => user;
+  if (!options.json && !options.jsonl && !isAgent()) {
+    throwUsageError("--fields applies to --json and --jsonl output. Use --columns for the table.");
+  }
+  const tree = parseFieldsOption(options.fields);
+  return (user) => pickFields(user, tree);
+}
+
+/**
+ * The options that decide which users a `--jsonl` export returns, recorded in
+ * its `--resume` checkpoint. Output-only options (`--fields`) and targeting
+ * are left out.
+ */
+function exportParams(options: UsersListOptions) {
+  const list = (values?: string[]) => (values?.length ? values.join(",") : null);
+  return {
+    query: options.query?.trim() || null,
+    email_address: list(options.emailAddress),
+    phone_number: list(options.phoneNumber),
+    username: list(options.username),
+    user_id: list(options.userId),
+    external_id: list(options.externalId),
+    order_by: options.orderBy ?? null,
+    created_after: options.createdAfter ?? null,
+    created_before: options.createdBefore ?? null,
+    last_active_since: options.lastActiveSince ?? null,
+    last_sign_in_before: options.lastSignInBefore ?? null,
+    banned: options.banned ?? null,
+    locked: options.locked ?? null,
+    password_enabled: options.passwordEnabled ?? null,
+    two_factor_enabled: options.twoFactorEnabled ?? null,
+  };
+}
+
+/**
+ * Where a `--jsonl` export stands: the page offset, and how many matching
+ * users of that page were already written.
+ */
+type StreamCursor = { offset: number; skip?: number };
+
+/**
+ * Write every matching user to stdout as JSON Lines (or just their IDs with
+ * `--quiet`), one page at a time, so memory stays flat however many users the
+ * instance has. With `--resume`, the position is saved after each written
+ * line so an interrupted export can continue (append to the same file with
+ * `>>`) without repeating the lines of a partly written page.
+ */
+async function streamUsers(options: UsersListOptions): Promise<void> {
+  if (options.resume && options.offset !== undefined) {
+    throwUsageError("--resume continues from its saved offset. Drop --offset.");
+  }
+  const project = resolveFieldProjection(options);
+  const matches = localStateFilter(options) ?? (() => true);
+  const secretKey = await resolveListSecretKey(options);
+  const job = await openJob<StreamCursor>(
+    options.resume,
+    "users list --jsonl",
+    exportParams(options),
+    { offset: options.offset ?? 0 },
+  );
+  let offset = job.cursor.offset;
+  let skip = job.cursor.skip ?? 0;
+  let written = job.done;
+  while (true) {
+    const response = await bapiRequest({
+      method: "GET",
+      path: buildUsersListPath({ ...options, offset }, STREAM_PAGE_SIZE),
+      secretKey,
+    });
+    const page = Array.isArray(response.body) ? (response.body as BapiUser[]) : [];
+    log.debug(`users: streamed ${page.length} users at offset ${offset}`);
+    let lines = 0;
+    for (const user of page) {
+      if (!matches(user)) continue;
+      lines += 1;
+      if (lines <= skip) continue;
+      log.data(isQuiet() ? user.id : JSON.stringify(project(user)));
+      written += 1;
+      await job.save({ offset, skip: lines }, { done: written, failed: 0 });
+    }
+    skip = 0;
+    offset += page.length;
+    if (page.length < STREAM_PAGE_SIZE) return job.finish();
+    await job.save({ offset }, { done: written, failed: 0 });
+  }
+}
+
 export async function list(options: UsersListOptions = {}): Promise<void> {
+  // `--jsonl` is shorthand for `-o jsonl`; `--json` still wins over `-o`, as
+  // it does for every other list.
+  if (options.jsonl || (!options.json && getOutputFormat() === "jsonl")) {
+    if (options.limit !== undefined) {
+      throwUsageError("JSON Lines output streams every matching user. Drop --limit.");
+    }
+    return streamUsers({ ...options, jsonl: true });
+  }
+  if (options.resume) throwUsageError("--resume applies to --jsonl exports.");
+
   const nested = isInsideGutter();
-  const shouldWrap = !nested && !options.json && !isAgent();
+  const shouldWrap = !nested && !options.json && !isAgent() && !isQuiet();
+  validateTableOptions(USER_COLUMNS, options);
+  const project = resolveFieldProjection(options);
   if (shouldWrap) intro("Listing users");
   let closeStatus: "success" | "failed" | "paused" | undefined;
 
@@ -171,23 +421,17 @@ export async function list(options: UsersListOptions = {}): Promise<void> {
     const secretKey = await resolveListSecretKey(options);
     const limit = options.limit ?? DEFAULT_LIMIT;
     const offset = options.offset ?? 0;
-    // Request one extra row so we can detect whether more pages exist without
-    // a separate /users/count round-trip. The CLI's --limit caps at 250, so
-    // pageSize + 1 always fits under BAPI's MaxLimit of 500.
-    const response = await withSpinner("Fetching users...", () =>
-      bapiRequest({
-        method: "GET",
-        path: buildUsersListPath(options, limit + 1),
-        secretKey,
-      }),
+    const matches = localStateFilter(options);
+    const { users, hasMore, nextOffset } = await withSpinner("Fetching users...", (spinner) =>
+      matches
+        ? scanUsersPage(secretKey, options, matches, limit, offset, (scanned) =>
+            spinner.update(`Fetching users... ${dim(`${scanned} checked`)}`),
+          )
+        : fetchUsersPage(secretKey, options, limit, offset),
     );
 
-    const body = response.body;
-    const allUsers = Array.isArray(body) ? (body as BapiUser[]) : [];
-    const hasMore = allUsers.length > limit;
-    const users = hasMore ? allUsers.slice(0, limit) : allUsers;
-
-    if (printJson({ data: users, hasMore }, options)) {
+    if (printQuietIds(users.map((user) => user.id))) return;
+    if (printJson({ data: users.map(project), hasMore }, options)) {
       return;
     }
 
@@ -199,10 +443,11 @@ export async function list(options: UsersListOptions = {}): Promise<void> {
       return;
     }
 
-    formatUsersTable(users);
+    const table = renderTable(users, USER_COLUMNS, options);
+    await printOutput(table);
     const summary = `\n${users.length} user${users.length === 1 ? "" : "s"} returned`;
     if (hasMore) {
-      log.info(`${summary} (more available, re-run with \`--offset ${offset + limit}\`)`);
+      log.info(`${summary} (more available, re-run with \`--offset ${nextOffset}\`)`);
     } else {
       log.info(summary);
     }
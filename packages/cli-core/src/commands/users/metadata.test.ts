@@ -0,0 +1,192 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { BapiError, CliError, ERROR_CODE } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { metadataBulkSet, parseMetadataUpdates } = await import("./metadata.ts");
+
+type Call = { method: string; path: string; body?: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("parseMetadataUpdates", () => {
+  test("reads user IDs and metadata patches, skipping blank lines", () => {
+    const text = [
+      '{"user_id":"user_a","public_metadata":{"plan":"pro"}}',
+      "",
+      '{"id":"user_b","private_metadata":{"seats":5},"unsafe_metadata":{"beta":null}}',
+    ].join("\n");
+    expect(parseMetadataUpdates(text)).toEqual({
+      updates: [
+        { line: 1, userId: "user_a", patch: { public_metadata: { plan: "pro" } } },
+        {
+          line: 3,
+          userId: "user_b",
+          patch: { private_metadata: { seats: 5 }, unsafe_metadata: { beta: null } },
+        },
+      ],
+      errors: [],
+    });
+  });
+
+  test("reports every invalid line with its number", () => {
+    const text = [
+      "not json",
+      '{"public_metadata":{}}',
+      '{"user_id":"user_a"}',
+      '{"user_id":"user_a","public_metadata":[]}',
+      '{"user_id":"user_a","public_metadata":{}}',
+      '{"user_id":"user_a","private_metadata":{}}',
+    ].join("\n");
+    expect(parseMetadataUpdates(text).errors).toEqual([
+      "line 1: not valid JSON",
+      'line 2: missing "user_id"',
+      "line 3: no public_metadata, private_metadata, unsafe_metadata to set",
+      'line 4: "public_metadata" must be an object',
+      "line 6: user_a is already updated on line 5",
+    ]);
+  });
+});
+
+describe("users metadata bulk-set", () => {
+  const captured = useCaptureLog();
+  let dir: string;
+  let file: string;
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(async () => {
+    dir = await mkdtemp(join(tmpdir(), "clerk-metadata-"));
+    file = join(dir, "updates.jsonl");
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockConfirm.mockResolvedValue(true);
+    mockBapiRequest.mockImplementation(async () => respond({}));
+  });
+
+  afterEach(async () => {
+    process.exitCode = originalExitCode;
+    mockBapiRequest.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReset();
+    await rm(dir, { recursive: true, force: true });
+  });
+
+  async function writeUpdates(count: number): Promise<void> {
+    const lines = Array.from({ length: count }, (_, i) =>
+      JSON.stringify({ user_id: `user_${i + 1}`, public_metadata: { plan: "pro" } }),
+    );
+    await Bun.write(file, `${lines.join("\n")}\n`);
+  }
+
+  test("patches every user and reports results in file order", async () => {
+    await writeUpdates(12);
+
+    await metadataBulkSet({ file, concurrency: 4, yes: true, json: true });
+
+    const calls = mockBapiRequest.mock.calls.map(([args]) => args as Call);
+    expect(calls).toHaveLength(12);
+    expect(calls[0]).toMatchObject({
+      method: "PATCH",
+      path: "/users/user_1/metadata",
+      body: '{"public_metadata":{"plan":"pro"}}',
+    });
+    const report = JSON.parse(captured.out);
+    expect(report).toMatchObject({ updated: 12, failed: 0 });
+    expect(report.results.map((result: { line: number }) => result.line)).toEqual(
+      Array.from({ length: 12 }, (_, i) => i + 1),
+    );
+  });
+
+  test("keeps going past failed lines and exits 1", async () => {
+    await writeUpdates(3);
+    mockBapiRequest.mockImplementation(async ({ path }: Call) => {
+      if (path.includes("user_2")) {
+        throw new BapiError(404, '{"errors":[{"message":"Not found"}]}', new Headers());
+      }
+      return respond({});
+    });
+
+    await metadataBulkSet({ file, yes: true, json: true });
+
+    const report = JSON.parse(captured.out);
+    expect(report).toMatchObject({ updated: 2, failed: 1 });
+    expect(report.results[1]).toMatchObject({ line: 2, user_id: "user_2" });
+    expect(report.results[1].error).toContain("Not found");
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("stops every worker on an error that would fail each line", async () => {
+    await writeUpdates(12);
+    const readOnly = new CliError("read-only", { code: ERROR_CODE.READ_ONLY });
+    mockBapiRequest.mockImplementation(async () => {
+      throw readOnly;
+    });
+
+    await expect(metadataBulkSet({ file, concurrency: 4, yes: true, json: true })).rejects.toBe(
+      readOnly,
+    );
+    // Only the first request of each worker went out.
+    expect(mockBapiRequest).toHaveBeenCalledTimes(4);
+  });
+
+  test("sends nothing when any line is invalid", async () => {
+    await Bun.write(file, '{"user_id":"user_1","public_metadata":{}}\n{"user_id":""}\n');
+
+    await expect(metadataBulkSet({ file, yes: true })).rejects.toThrow(
+      "1 invalid line(s); nothing was changed.",
+    );
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("--dry-run validates without sending", async () => {
+    await writeUpdates(2);
+
+    await metadataBulkSet({ file, dryRun: true, json: true });
+
+    expect(JSON.parse(captured.out)).toEqual({ dry_run: true, file, users: 2 });
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("requires --yes in agent mode", async () => {
+    await writeUpdates(1);
+    mockIsAgent.mockReturnValue(true);
+
+    await expect(metadataBulkSet({ file })).rejects.toThrow("Pass --yes");
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+});
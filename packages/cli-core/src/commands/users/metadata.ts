@@ -0,0 +1,222 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan } from "../../lib/color.ts";
+import { ERROR_CODE, throwUsageError, throwUserAbort } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { attemptBulk, withProgress } from "../../lib/progress.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { intro, outro } from "../../lib/spinner.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+
+export type UsersMetadataBulkSetOptions = {
+  file?: string;
+  concurrency?: number;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+/** Requests in flight at once. Rate-limited requests are retried by `loggedFetch`. */
+export const DEFAULT_CONCURRENCY = 8;
+export const MAX_CONCURRENCY = 50;
+
+const METADATA_FIELDS = ["public_metadata", "private_metadata", "unsafe_metadata"] as const;
+
+/** Invalid lines listed in the usage error before the rest are summarized. */
+const MAX_REPORTED_ERRORS = 5;
+
+export type MetadataUpdate = {
+  /** 1-based line number in the input file. */
+  line: number;
+  userId: string;
+  patch: Partial<Record<(typeof METADATA_FIELDS)[number], Record<string, unknown>>>;
+};
+
+export type MetadataResult = { line: number; user_id: string; error?: string };
+
+/**
+ * Parse an updates file: one JSON object per line with `user_id` (or `id`)
+ * and at least one of `public_metadata`, `private_metadata`, and
+ * `unsafe_metadata`. Blank lines are ignored. Every line is checked before
+ * anything is sent, so a typo on line 9,000 doesn't leave a half-applied
+ * backfill; a user may only appear once, since two patches to the same user
+ * would race.
+ */
+export function parseMetadataUpdates(text: string): {
+  updates: MetadataUpdate[];
+  errors: string[];
+} {
+  const updates: MetadataUpdate[] = [];
+  const errors: string[] = [];
+  const seen = new Map<string, number>();
+  text.split(/\r?\n/).forEach((raw, index) => {
+    const line = index + 1;
+    if (!raw.trim()) return;
+    let value: unknown;
+    try {
+      value = JSON.parse(raw);
+    } catch {
+      errors.push(`line ${line}: not valid JSON`);
+      return;
+    }
+    if (!isRecord(value)) {
+      errors.push(`line ${line}: expected a JSON object`);
+      return;
+    }
+    const userId = value.user_id ?? value.id;
+    if (typeof userId !== "string" || !userId) {
+      errors.push(`line ${line}: missing "user_id"`);
+      return;
+    }
+    const patch: MetadataUpdate["patch"] = {};
+    for (const field of METADATA_FIELDS) {
+      if (value[field] === undefined) continue;
+      const metadata = value[field];
+      if (!isRecord(metadata)) {
+        errors.push(`line ${line}: "${field}" must be an object`);
+        return;
+      }
+      patch[field] = metadata;
+    }
+    if (Object.keys(patch).length === 0) {
+      errors.push(`line ${line}: no ${METADATA_FIELDS.join(", ")} to set`);
+      return;
+    }
+    const firstLine = seen.get(userId);
+    if (firstLine !== undefined) {
+      errors.push(`line ${line}: ${userId} is already updated on line ${firstLine}`);
+      return;
+    }
+    seen.set(userId, line);
+    updates.push({ line, userId, patch });
+  });
+  return { updates, errors };
+}
+
+async function readUpdates(path: string): Promise<MetadataUpdate[]> {
+  const file = Bun.file(path);
+  if (!(await file.exists())) {
+    throwUsageError(`File not found: ${path}`, undefined, ERROR_CODE.FILE_NOT_FOUND);
+  }
+  const { updates, errors } = parseMetadataUpdates(await file.text());
+  if (errors.length > 0) {
+    const shown = errors.slice(0, MAX_REPORTED_ERRORS).map((error) => `  ${path} ${error}`);
+    const more = errors.length - shown.length;
+    throwUsageError(
+      [
+        `${errors.length} invalid line(s); nothing was changed.`,
+        ...shown,
+        ...(more > 0 ? [`  ... and ${more} more`] : []),
+      ].join("\n"),
+    );
+  }
+  if (updates.length === 0) throwUsageError(`${path} has no updates.`);
+  return updates;
+}
+
+/**
+ * Run `task` over `items` with at most `concurrency` in flight. The first
+ * error stops every worker from taking another item; it is rethrown once the
+ * requests already in flight have settled.
+ */
+export async function forEachConcurrently<T>(
+  items: T[],
+  concurrency: number,
+  task: (item: T) => Promise<void>,
+): Promise<void> {
+  let next = 0;
+  let stopped = false;
+  const worker = async () => {
+    while (!stopped && next < items.length) {
+      try {
+        await task(items[next++]!);
+      } catch (error) {
+        stopped = true;
+        throw error;
+      }
+    }
+  };
+  const workers = Array.from({ length: Math.min(concurrency, items.length) }, worker);
+  const rejected = (await Promise.allSettled(workers)).find(
+    (result) => result.status === "rejected",
+  );
+  if (rejected) throw rejected.reason;
+}
+
+/**
+ * Apply metadata patches from a JSON Lines file. Each line is sent as
+ * `PATCH /users/{id}/metadata`, which deep-merges objects into the existing
+ * metadata (a `null` value removes a key), so lines only need the keys they
+ * change.
+ */
+export async function metadataBulkSet(options: UsersMetadataBulkSetOptions = {}): Promise<void> {
+  if (!options.file) throwUsageError("Pass the updates with --file <path>.");
+  const json = Boolean(options.json || isAgent());
+  const updates = await readUpdates(options.file);
+
+  if (options.dryRun) {
+    if (json) {
+      log.data(JSON.stringify({ dry_run: true, file: options.file, users: updates.length }));
+      return;
+    }
+    log.info(`[dry-run] ${options.file} is valid: ${updates.length} user(s) would be updated`);
+    return;
+  }
+
+  const secretKey = await resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+
+  if (!options.yes) {
+    if (!isHuman()) throwUsageError("Pass --yes to update user metadata.");
+    intro("Updating user metadata");
+    if (!(await confirm({ message: `Update metadata for ${updates.length} user(s)?` }))) {
+      throwUserAbort();
+    }
+  } else if (!json) {
+    intro("Updating user metadata");
+  }
+
+  const results: MetadataResult[] = [];
+  await withProgress("Updating metadata...", updates.length, async (progress) => {
+    await forEachConcurrently(
+      updates,
+      options.concurrency ?? DEFAULT_CONCURRENCY,
+      async ({ line, userId, patch }) => {
+        const attempt = await attemptBulk(progress, () =>
+          bapiRequest({
+            method: "PATCH",
+            path: `/users/${encodeURIComponent(userId)}/metadata`,
+            secretKey,
+            body: JSON.stringify(patch),
+          }),
+        );
+        results.push({ line, user_id: userId, ...(!attempt.ok && { error: attempt.error }) });
+      },
+    );
+  });
+  results.sort((a, b) => a.line - b.line);
+  const failed = results.filter((result) => result.error);
+  const updated = results.length - failed.length;
+  if (failed.length > 0) process.exitCode = 1;
+
+  if (json) {
+    const report = { file: options.file, updated, failed: failed.length, results };
+    log.data(JSON.stringify(report, null, 2));
+    return;
+  }
+  for (const result of failed) {
+    log.error(`Line ${result.line} (${result.user_id}): ${result.error}`);
+  }
+  await outro(
+    `Updated ${updated} user(s)` +
+      (failed.length ? `, ${failed.length} failed` : "") +
+      ` from ${cyan(options.file)}`,
+  );
+}
@@ -0,0 +1,306 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { BapiError } from "../../lib/errors.ts";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async (options: { instance?: string; secretKey?: string }) =>
+    options.secretKey ?? `sk_test_${options.instance}`,
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockConfirm = mock();
+mock.module("../../lib/prompts.ts", () => ({
+  confirm: (...args: unknown[]) => mockConfirm(...args),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { buildMigratedUserPayload, migrate, parsePasswordExport, readMigratedIds } = await import(
+  "./migrate.ts"
+);
+
+const SOURCE = [
+  {
+    id: "user_a",
+    first_name: "Alice",
+    primary_email_address_id: "idn_2",
+    email_addresses: [
+      { id: "idn_1", email_address: "old@example.com" },
+      { id: "idn_2", email_address: "alice@example.com" },
+    ],
+    password_enabled: true,
+    created_at: Date.UTC(2024, 0, 2),
+  },
+  { id: "user_b", email_addresses: [{ id: "idn_3", email_address: "bob@example.com" }] },
+];
+
+type Call = { method: string; path: string; secretKey: string; body?: string };
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+const HEADER = '{"from_instance_id":"ins_dev","to_instance_id":"ins_prod"}';
+
+function instance(secretKey: string) {
+  return { id: secretKey === "sk_test_prod" ? "ins_prod" : "ins_dev" };
+}
+
+function creates(): Call[] {
+  return mockBapiRequest.mock.calls
+    .map(([args]) => args as Call)
+    .filter((call) => call.method === "POST");
+}
+
+describe("buildMigratedUserPayload", () => {
+  test("puts the primary email first and skips password requirements", () => {
+    expect(buildMigratedUserPayload(SOURCE[0]!)).toEqual({
+      email_address: ["alice@example.com", "old@example.com"],
+      first_name: "Alice",
+      created_at: "2024-01-02T00:00:00.000Z",
+      skip_password_requirement: true,
+    });
+  });
+
+  test("passes an exported password digest through instead of skipping the password", () => {
+    const password = { password_digest: "$2a$10$abc", password_hasher: "bcrypt" };
+
+    const payload = buildMigratedUserPayload(SOURCE[0]!, password);
+
+    expect(payload).toMatchObject(password);
+    expect(payload).not.toHaveProperty("skip_password_requirement");
+  });
+});
+
+describe("parsePasswordExport", () => {
+  test("reads digests by user ID and skips rows without one", () => {
+    const csv = [
+      "id,first_name,password_digest,password_hasher",
+      'user_a,Alice,"$2a$10$abc",bcrypt',
+      "user_b,Bob,,",
+    ].join("\n");
+
+    expect(parsePasswordExport(csv, "users.csv")).toEqual(
+      new Map([["user_a", { password_digest: "$2a$10$abc", password_hasher: "bcrypt" }]]),
+    );
+  });
+
+  test("requires the digest and hasher columns", () => {
+    expect(() => parsePasswordExport("id,email\nuser_a,a@example.com\n", "users.csv")).toThrow(
+      /password_digest/,
+    );
+  });
+});
+
+describe("users migrate", () => {
+  const captured = useCaptureLog();
+  let dir: string;
+  let mapFile: string;
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(async () => {
+    dir = await mkdtemp(join(tmpdir(), "clerk-migrate-"));
+    mapFile = join(dir, "map.jsonl");
+    originalExitCode = process.exitCode;
+    mockIsAgent.mockReturnValue(false);
+    mockConfirm.mockResolvedValue(true);
+    mockBapiRequest.mockImplementation(async ({ method, path, body, secretKey }: Call) => {
+      if (path === "/instance") return respond(instance(secretKey));
+      if (path.startsWith("/users/count")) return respond({ total_count: SOURCE.length });
+      if (method === "GET") return respond(SOURCE);
+      const email = (JSON.parse(body!) as { email_address: string[] }).email_address[0];
+      return respond({ id: `user_new_${email!.split("@")[0]}` });
+    });
+  });
+
+  afterEach(async () => {
+    process.exitCode = originalExitCode;
+    mockBapiRequest.mockReset();
+    mockConfirm.mockReset();
+    mockIsAgent.mockReset();
+    await rm(dir, { recursive: true, force: true });
+  });
+
+  test("creates each user on the target and writes the ID map", async () => {
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true, json: true });
+
+    expect(creates().map((call) => call.secretKey)).toEqual(["sk_test_prod", "sk_test_prod"]);
+    expect(await Bun.file(mapFile).text()).toBe(
+      [
+        HEADER,
+        '{"source_id":"user_a","password_reset_required":true,"target_id":"user_new_alice"}',
+        '{"source_id":"user_b","target_id":"user_new_bob"}',
+        "",
+      ].join("\n"),
+    );
+    expect(JSON.parse(captured.out)).toMatchObject({ migrated: 2, password_reset_required: 1 });
+  });
+
+  test("resumes from the map file and retries failures", async () => {
+    await Bun.write(
+      mapFile,
+      [
+        HEADER,
+        '{"source_id":"user_a","target_id":"user_new_alice"}',
+        '{"source_id":"user_b","error":"x"}',
+        "",
+      ].join("\n"),
+    );
+
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true });
+
+    expect(creates()).toHaveLength(1);
+    expect(JSON.parse(creates()[0]!.body!).email_address).toEqual(["bob@example.com"]);
+    expect((await readMigratedIds(mapFile)).get("user_b")).toBe("user_new_bob");
+  });
+
+  test("--password-export copies digests and only flags users without one", async () => {
+    const passwordExport = join(dir, "users.csv");
+    await Bun.write(
+      passwordExport,
+      "id,password_digest,password_hasher\nuser_a,$2a$10$abc,bcrypt\n",
+    );
+
+    await migrate({
+      fromInstance: "dev",
+      toInstance: "prod",
+      mapFile,
+      passwordExport,
+      yes: true,
+      json: true,
+    });
+
+    expect(JSON.parse(creates()[0]!.body!)).toMatchObject({
+      password_digest: "$2a$10$abc",
+      password_hasher: "bcrypt",
+    });
+    expect(await Bun.file(mapFile).text()).not.toContain("password_reset_required");
+    expect(JSON.parse(captured.out)).toMatchObject({
+      passwords_copied: 1,
+      password_reset_required: 0,
+    });
+  });
+
+  test("refuses a map file written for other instances", async () => {
+    await Bun.write(
+      mapFile,
+      '{"from_instance_id":"ins_staging","to_instance_id":"ins_prod"}\n' +
+        '{"source_id":"user_a","target_id":"user_new_alice"}\n',
+    );
+
+    await expect(
+      migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true }),
+    ).rejects.toThrow(/from ins_staging to ins_prod, not ins_dev to ins_prod/);
+    expect(creates()).toHaveLength(0);
+  });
+
+  test("refuses a non-empty map file without a header", async () => {
+    await Bun.write(mapFile, '{"source_id":"user_a","target_id":"user_new_alice"}\n');
+
+    await expect(
+      migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true }),
+    ).rejects.toThrow(/doesn't record which instances/);
+    expect(creates()).toHaveLength(0);
+  });
+
+  test("--resume continues from the saved source page", async () => {
+    const resume = join(dir, "migrate.state");
+    await Bun.write(
+      resume,
+      JSON.stringify({
+        version: 1,
+        job: "users migrate",
+        params: { from_instance: "dev", to_instance: "prod", map_file: mapFile },
+        cursor: { offset: 500 },
+        done: 500,
+        failed: 0,
+        updated_at: "2026-01-01T00:00:00.000Z",
+      }),
+    );
+
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, resume, yes: true });
+
+    const listed = mockBapiRequest.mock.calls
+      .map(([args]) => (args as Call).path)
+      .filter((path) => path.startsWith("/users?"));
+    expect(listed).toEqual([expect.stringContaining("offset=500")]);
+    expect(await Bun.file(resume).exists()).toBe(false);
+  });
+
+  test("records failed users and exits 1", async () => {
+    mockBapiRequest.mockImplementation(async ({ method, path, secretKey }: Call) => {
+      if (path === "/instance") return respond(instance(secretKey));
+      if (path.startsWith("/users/count")) return respond({ total_count: SOURCE.length });
+      if (method === "GET") return respond(SOURCE);
+      throw new BapiError(422, '{"errors":[{"message":"Email taken"}]}', new Headers());
+    });
+
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true, json: true });
+
+    expect(JSON.parse(captured.out)).toMatchObject({ migrated: 0, failed: 2 });
+    expect((await readMigratedIds(mapFile)).size).toBe(0);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("records a failure when the target returns no user ID", async () => {
+    mockBapiRequest.mockImplementation(async ({ method, path, secretKey }: Call) => {
+      if (path === "/instance") return respond(instance(secretKey));
+      if (path.startsWith("/users/count")) return respond({ total_count: SOURCE.length });
+      if (method === "GET") return respond(SOURCE);
+      return respond({ object: "user" });
+    });
+
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, yes: true, json: true });
+
+    expect(JSON.parse(captured.out)).toMatchObject({ migrated: 0, failed: 2 });
+    expect(await Bun.file(mapFile).text()).not.toContain("undefined");
+    expect((await readMigratedIds(mapFile)).size).toBe(0);
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("--dry-run only counts", async () => {
+    await migrate({ fromInstance: "dev", toInstance: "prod", mapFile, dryRun: true, json: true });
+
+    expect(creates()).toHaveLength(0);
+    expect(JSON.parse(captured.out)).toMatchObject({ source_users: 2, to_migrate: 2 });
+  });
+
+  test("requires both sides, on different instances", async () => {
+    await expect(migrate({ fromInstance: "dev", mapFile })).rejects.toThrow(/--to-instance/);
+    await expect(
+      migrate({ fromSecretKey: "sk_test_x", toSecretKey: "sk_test_x", mapFile }),
+    ).rejects.toThrow(/same instance/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("agent mode requires --yes", async () => {
+    mockIsAgent.mockReturnValue(true);
+
+    await expect(migrate({ fromInstance: "dev", toInstance: "prod", mapFile })).rejects.toThrow(
+      /--yes/,
+    );
+    expect(creates()).toHaveLength(0);
+  });
+});
@@ -0,0 +1,415 @@
+import { appendFile } from "node:fs/promises";
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { parseCsv } from "../../lib/csv.ts";
+import {
+  CliError,
+  ERROR_CODE,
+  throwUsageError,
+  throwUserAbort,
+  withApiContext,
+} from "../../lib/errors.ts";
+import { openJob } from "../../lib/job-state.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { attemptBulk, BulkItemError, withProgress } from "../../lib/progress.ts";
+import { confirm } from "../../lib/prompts.ts";
+import { intro, outro, withSpinner } from "../../lib/spinner.ts";
+import { primaryFirst, type UserIdentifier } from "../../lib/users.ts";
+import { isAgent, isHuman } from "../../mode.ts";
+
+export type UsersMigrateOptions = {
+  fromSecretKey?: string;
+  fromApp?: string;
+  fromInstance?: string;
+  toSecretKey?: string;
+  toApp?: string;
+  toInstance?: string;
+  query?: string;
+  passwordExport?: string;
+  mapFile?: string;
+  resume?: string;
+  dryRun?: boolean;
+  yes?: boolean;
+  json?: boolean;
+};
+
+export const DEFAULT_MAP_FILE = "users-migrate.jsonl";
+
+/** BAPI's MaxLimit for `GET /users`. */
+const PAGE_SIZE = 500;
+
+export type SourceUser = {
+  id: string;
+  external_id?: string | null;
+  username?: string | null;
+  first_name?: string | null;
+  last_name?: string | null;
+  primary_email_address_id?: string | null;
+  primary_phone_number_id?: string | null;
+  email_addresses?: UserIdentifier[];
+  phone_numbers?: UserIdentifier[];
+  password_enabled?: boolean;
+  public_metadata?: Record<string, unknown> | null;
+  private_metadata?: Record<string, unknown> | null;
+  unsafe_metadata?: Record<string, unknown> | null;
+  created_at?: number;
+};
+
+/** One line of the map file: where a source user ended up, or why it didn't. */
+export type MigrationRecord = {
+  source_id: string;
+  target_id?: string;
+  error?: string;
+  /** The user had a password, which the Backend API can't read back, and no exported digest. */
+  password_reset_required?: boolean;
+};
+
+/** A password hash from the dashboard's user export, which `POST /users` accepts as is. */
+export type PasswordDigest = { password_digest: string; password_hasher: string };
+
+const USER_ID_COLUMNS = ["id", "user_id"];
+
+/**
+ * Parse a user export CSV (as downloaded from the dashboard) into password
+ * digests by source user ID. Rows without a digest are left out; other
+ * columns are ignored.
+ */
+export function parsePasswordExport(text: string, source: string): Map<string, PasswordDigest> {
+  const [header = [], ...rows] = parseCsv(text);
+  const columns = header.map((cell) => cell.trim().toLowerCase());
+  const idColumn = columns.findIndex((cell) => USER_ID_COLUMNS.includes(cell));
+  const digestColumn = columns.indexOf("password_digest");
+  const hasherColumn = columns.indexOf("password_hasher");
+  if (idColumn === -1 || digestColumn === -1 || hasherColumn === -1) {
+    throwUsageError(
+      `${source} needs a header row with "id", "password_digest", and "password_hasher" columns.`,
+    );
+  }
+
+  const digests = new Map<string, PasswordDigest>();
+  for (const [index, cells] of rows.entries()) {
+    const id = cells[idColumn]?.trim();
+    const digest = cells[digestColumn]?.trim();
+    const hasher = cells[hasherColumn]?.trim();
+    if (!digest) continue;
+    if (!id || !hasher) {
+      throwUsageError(`${source}: row ${index + 2} has a password digest without an ID or hasher.`);
+    }
+    digests.set(id, { password_digest: digest, password_hasher: hasher });
+  }
+  return digests;
+}
+
+async function readPasswordExport(path: string | undefined): Promise<Map<string, PasswordDigest>> {
+  if (!path) return new Map();
+  const file = Bun.file(path);
+  if (!(await file.exists())) {
+    throwUsageError(`File not found: ${path}`, undefined, ERROR_CODE.FILE_NOT_FOUND);
+  }
+  return parsePasswordExport(await file.text(), path);
+}
+
+/**
+ * The `POST /users` body that recreates `user` on another instance. The
+ * Backend API never returns password digests, so a password is only copied
+ * when `password` comes from a user export; otherwise the user is created
+ * without one and has to reset it (or sign in another way).
+ */
+export function buildMigratedUserPayload(
+  user: SourceUser,
+  password?: PasswordDigest,
+): Record<string, unknown> {
+  const emails = primaryFirst(user.email_addresses, user.primary_email_address_id, "email_address");
+  const phones = primaryFirst(user.phone_numbers, user.primary_phone_number_id, "phone_number");
+  return {
+    ...(emails.length > 0 && { email_address: emails }),
+    ...(phones.length > 0 && { phone_number: phones }),
+    ...(user.username && { username: user.username }),
+    ...(user.first_name && { first_name: user.first_name }),
+    ...(user.last_name && { last_name: user.last_name }),
+    ...(user.external_id && { external_id: user.external_id }),
+    ...(user.public_metadata && { public_metadata: user.public_metadata }),
+    ...(user.private_metadata && { private_metadata: user.private_metadata }),
+    ...(user.unsafe_metadata && { unsafe_metadata: user.unsafe_metadata }),
+    ...(user.created_at && { created_at: new Date(user.created_at).toISOString() }),
+    ...(password ?? { skip_password_requirement: true }),
+  };
+}
+
+/** The first line of a map file: the instances its IDs belong to. */
+export type MapFileHeader = { from_instance_id: string; to_instance_id: string };
+
+export type MapFile = {
+  /** Missing for a new (or empty) file. */
+  header?: MapFileHeader;
+  /** Source ID to target ID. Failed records don't count, so a rerun retries them. */
+  migrated: Map<string, string>;
+};
+
+function isMapFileHeader(value: unknown): value is MapFileHeader {
+  return (
+    isRecord(value) &&
+    typeof value.from_instance_id === "string" &&
+    typeof value.to_instance_id === "string"
+  );
+}
+
+export async function readMapFile(path: string): Promise<MapFile> {
+  const map: MapFile = { migrated: new Map() };
+  const file = Bun.file(path);
+  if (!(await file.exists())) return map;
+  for (const [index, line] of (await file.text()).split("\n").entries()) {
+    if (!line.trim()) continue;
+    let record: unknown;
+    try {
+      record = JSON.parse(line);
+    } catch {
+      throwUsageError(`${path}:${index + 1}: not a JSON line. Is this a users migrate map file?`);
+    }
+    if (isMapFileHeader(record)) {
+      map.header ??= record;
+    } else if (isRecord(record) && typeof record.source_id === "string") {
+      if (typeof record.target_id === "string") {
+        map.migrated.set(record.source_id, record.target_id);
+      }
+    }
+  }
+  return map;
+}
+
+/** Source IDs already migrated according to the map file at `path`. */
+export async function readMigratedIds(path: string): Promise<Map<string, string>> {
+  return (await readMapFile(path)).migrated;
+}
+
+/**
+ * A map file only resumes the migration it was written for: its IDs mean
+ * nothing between other instances, and skipping users on their account would
+ * silently leave the new target without them.
+ */
+function assertMapFileMatches(path: string, map: MapFile, instances: MapFileHeader): void {
+  const { header } = map;
+  if (!header) {
+    if (map.migrated.size === 0) return;
+    throwUsageError(
+      `${path} doesn't record which instances it maps. Pass a new --map-file for this migration.`,
+    );
+  }
+  if (
+    header.from_instance_id !== instances.from_instance_id ||
+    header.to_instance_id !== instances.to_instance_id
+  ) {
+    throwUsageError(
+      `${path} maps users from ${header.from_instance_id} to ${header.to_instance_id}, ` +
+        `not ${instances.from_instance_id} to ${instances.to_instance_id}. ` +
+        "Pass a different --map-file for this migration.",
+    );
+  }
+}
+
+async function fetchInstanceId(secretKey: string): Promise<string> {
+  const response = await bapiRequest({ method: "GET", path: "/instance", secretKey });
+  const id = isRecord(response.body) ? response.body.id : undefined;
+  if (typeof id !== "string" || !id) {
+    throw new CliError("The Backend API didn't return an instance ID.", {
+      code: ERROR_CODE.INSTANCE_NOT_FOUND,
+    });
+  }
+  return id;
+}
+
+function sourceListPath(query: string | undefined, offset: number): string {
+  const params = new URLSearchParams({
+    limit: String(PAGE_SIZE),
+    offset: String(offset),
+    // Oldest first, so users created mid-run land on later pages.
+    order_by: "+created_at",
+  });
+  if (query) params.set("query", query);
+  return `/users?${params}`;
+}
+
+async function countSourceUsers(secretKey: string, query: string | undefined): Promise<number> {
+  const path = query ? `/users/count?${new URLSearchParams({ query })}` : "/users/count";
+  const response = await bapiRequest({ method: "GET", path, secretKey });
+  const body = response.body;
+  return isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+}
+
+async function fetchSourcePage(
+  secretKey: string,
+  query: string | undefined,
+  offset: number,
+): Promise<SourceUser[]> {
+  const response = await bapiRequest({
+    method: "GET",
+    path: sourceListPath(query, offset),
+    secretKey,
+  });
+  return Array.isArray(response.body) ? (response.body as SourceUser[]) : [];
+}
+
+/** Create `user` on the target and return its new ID. */
+async function createTargetUser(
+  secretKey: string,
+  user: SourceUser,
+  password: PasswordDigest | undefined,
+): Promise<string> {
+  const response = await bapiRequest({
+    method: "POST",
+    path: "/users",
+    secretKey,
+    body: JSON.stringify(buildMigratedUserPayload(user, password)),
+  });
+  const targetId = isRecord(response.body) ? response.body.id : undefined;
+  if (typeof targetId !== "string" || !targetId) {
+    // Without an ID there's nothing to map; a rerun would try this user again.
+    throw new BulkItemError("The target returned no user ID. Check it before rerunning.");
+  }
+  return targetId;
+}
+
+async function resolveKeys(options: UsersMigrateOptions): Promise<{ from: string; to: string }> {
+  const hasFrom = options.fromSecretKey || options.fromApp || options.fromInstance;
+  const hasTo = options.toSecretKey || options.toApp || options.toInstance;
+  if (!hasFrom || !hasTo) {
+    throwUsageError(
+      "Name both instances, e.g. --from-instance dev --to-instance prod. " +
+        "Each side accepts --*-secret-key, --*-app, and --*-instance.",
+    );
+  }
+  const from = await resolveBapiSecretKey({
+    secretKey: options.fromSecretKey,
+    app: options.fromApp,
+    instance: options.fromInstance,
+  });
+  const to = await resolveBapiSecretKey({
+    secretKey: options.toSecretKey,
+    app: options.toApp,
+    instance: options.toInstance,
+  });
+  if (from === to) throwUsageError("The source and target are the same instance.");
+  return { from, to };
+}
+
+/**
+ * Copy users from one instance to another. Each result is appended to the
+ * map file as it happens, so rerunning the same command skips users that
+ * already have a target ID. `--resume` also saves the source page reached,
+ * so a rerun doesn't have to list the pages it already finished.
+ */
+export async function migrate(options: UsersMigrateOptions = {}): Promise<void> {
+  const json = Boolean(options.json || isAgent());
+  const mapFile = options.mapFile ?? DEFAULT_MAP_FILE;
+  const query = options.query?.trim() || undefined;
+
+  const passwords = await readPasswordExport(options.passwordExport);
+  const keys = await resolveKeys(options);
+  const instances: MapFileHeader = await withSpinner("Checking instances...", async () => ({
+    from_instance_id: await withApiContext(fetchInstanceId(keys.from), "Failed to read source"),
+    to_instance_id: await withApiContext(fetchInstanceId(keys.to), "Failed to read target"),
+  }));
+  const map = await readMapFile(mapFile);
+  assertMapFileMatches(mapFile, map, instances);
+  const migrated = map.migrated;
+  const job = await openJob(
+    options.resume,
+    "users migrate",
+    {
+      from_app: options.fromApp ?? null,
+      from_instance: options.fromInstance ?? null,
+      to_app: options.toApp ?? null,
+      to_instance: options.toInstance ?? null,
+      query: query ?? null,
+      map_file: mapFile,
+    },
+    { offset: 0 },
+  );
+  const total = await withSpinner("Counting source users...", () =>
+    withApiContext(countSourceUsers(keys.from, query), "Failed to count source users"),
+  );
+  const remaining = Math.max(0, total - migrated.size);
+
+  if (options.dryRun) {
+    const summary = { source_users: total, already_migrated: migrated.size, to_migrate: remaining };
+    if (json) {
+      log.data(JSON.stringify({ dry_run: true, map_file: mapFile, ...summary }, null, 2));
+      return;
+    }
+    log.info(`[dry-run] ${total} source user(s) match${query ? ` "${query}"` : ""}`);
+    log.info(`  ${migrated.size} already migrated according to ${mapFile}`);
+    log.info(`  ${remaining} would be created on the target instance`);
+    return;
+  }
+
+  if (remaining === 0) {
+    if (json) log.data(JSON.stringify({ map_file: mapFile, migrated: 0, failed: 0 }, null, 2));
+    else log.info(`Nothing to migrate. Every matching user is in ${mapFile}.`);
+    return;
+  }
+
+  if (!options.yes) {
+    if (!isHuman()) throwUsageError("Pass --yes to create users on the target instance.");
+    intro("Migrating users");
+    if (!(await confirm({ message: `Create ${remaining} user(s) on the target instance?` }))) {
+      throwUserAbort();
+    }
+  } else if (!json) {
+    intro("Migrating users");
+  }
+
+  if (!map.header) await appendFile(mapFile, `${JSON.stringify(instances)}\n`);
+  const counts = { migrated: 0, failed: 0, passwords_copied: 0, password_reset_required: 0 };
+  await withProgress("Migrating users...", remaining, async (progress) => {
+    let offset = job.cursor.offset;
+    while (true) {
+      const page = await fetchSourcePage(keys.from, query, offset);
+      for (const user of page) {
+        if (migrated.has(user.id)) continue;
+        const record: MigrationRecord = { source_id: user.id };
+        const password = user.password_enabled ? passwords.get(user.id) : undefined;
+        if (user.password_enabled && !password) record.password_reset_required = true;
+        const attempt = await attemptBulk(progress, () =>
+          createTargetUser(keys.to, user, password),
+        );
+        if (attempt.ok) {
+          record.target_id = attempt.value;
+          counts.migrated += 1;
+          if (password) counts.passwords_copied += 1;
+          if (record.password_reset_required) counts.password_reset_required += 1;
+        } else {
+          record.error = attempt.error;
+          counts.failed += 1;
+        }
+        await appendFile(mapFile, `${JSON.stringify(record)}\n`);
+      }
+      offset += page.length;
+      await job.save(
+        { offset },
+        { done: job.done + counts.migrated, failed: job.failed + counts.failed },
+      );
+      if (page.length < PAGE_SIZE) break;
+    }
+  });
+  await job.finish();
+
+  if (counts.failed > 0) process.exitCode = 1;
+  if (json) {
+    log.data(JSON.stringify({ map_file: mapFile, ...counts }, null, 2));
+    return;
+  }
+  if (counts.password_reset_required > 0) {
+    log.warn(
+      `${counts.password_reset_required} migrated user(s) had a password that wasn't in ` +
+        "--password-export. They'll need to reset it.",
+    );
+  }
+  if (counts.failed > 0) {
+    log.warn(`${counts.failed} user(s) failed. See the "error" lines in ${mapFile}.`);
+  }
+  await outro(
+    `Migrated ${counts.migrated} user(s). ID map: ${cyan(mapFile)} ${dim("(rerun to resume)")}`,
+  );
+}
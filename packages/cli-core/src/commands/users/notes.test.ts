@@ -0,0 +1,131 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import {
+  credentialStoreStubs,
+  tokenExchangeStubs,
+  useCaptureLog,
+} from "../../test/lib/stubs.ts";
+
+const mockBapiRequest = mock();
+mock.module("../../lib/bapi.ts", () => ({
+  bapiRequest: (...args: unknown[]) => mockBapiRequest(...args),
+}));
+
+mock.module("../../lib/bapi-command.ts", () => ({
+  resolveBapiSecretKey: async () => "sk_test_123",
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  intro: () => {},
+  outro: () => {},
+  pausedOutro: () => {},
+  bar: () => {},
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+const mockGetValidToken = mock();
+mock.module("../../lib/credential-store.ts", () => ({
+  ...credentialStoreStubs,
+  getValidToken: (...args: unknown[]) => mockGetValidToken(...args),
+}));
+
+mock.module("../../lib/token-exchange.ts", () => ({
+  ...tokenExchangeStubs,
+  fetchUserInfo: async () => ({ email: "agent@support.test" }),
+}));
+
+const mockIsAgent = mock();
+mock.module("../../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { notesAdd, notesList, readNotes } = await import("./notes.ts");
+
+const EARLIER = {
+  text: "Asked about SSO pricing",
+  author: "sam@support.test",
+  created_at: "2026-10-01T09:00:00.000Z",
+};
+
+type Call = { method: string; path: string; body?: string };
+
+function calls(): Call[] {
+  return mockBapiRequest.mock.calls.map(([args]) => args as Call);
+}
+
+function respond(body: unknown) {
+  return { status: 200, headers: new Headers(), body, rawBody: JSON.stringify(body) };
+}
+
+describe("readNotes", () => {
+  test("reads the reserved namespace and drops malformed entries", () => {
+    expect(
+      readNotes({ plan: "pro", clerk_cli: { notes: [EARLIER, { text: "no author" }, "x"] } }),
+    ).toEqual([EARLIER]);
+    expect(readNotes({ plan: "pro" })).toEqual([]);
+    expect(readNotes(null)).toEqual([]);
+  });
+});
+
+describe("users notes", () => {
+  const captured = useCaptureLog();
+
+  beforeEach(() => {
+    mockIsAgent.mockReturnValue(false);
+    mockGetValidToken.mockResolvedValue("oauth_token");
+    const privateMetadata = { plan: "pro", clerk_cli: { notes: [EARLIER] } };
+    const user = { id: "user_1", private_metadata: privateMetadata };
+    mockBapiRequest.mockImplementation(async (call: Call) =>
+      respond(call.method === "GET" ? user : {}),
+    );
+  });
+
+  afterEach(() => {
+    mockBapiRequest.mockReset();
+    mockGetValidToken.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("add appends a note signed with the login email", async () => {
+    await notesAdd({ userId: "user_1", note: "  Refunded March invoice  ", json: true });
+
+    const patch = calls().find((call) => call.method === "PATCH")!;
+    expect(patch.path).toBe("/users/user_1/metadata");
+    const notes = JSON.parse(patch.body!).private_metadata.clerk_cli.notes;
+    expect(notes).toHaveLength(2);
+    expect(notes[0]).toEqual(EARLIER);
+    expect(notes[1]).toMatchObject({
+      text: "Refunded March invoice",
+      author: "agent@support.test",
+    });
+    expect(JSON.parse(captured.out)).toEqual(notes[1]);
+  });
+
+  test("add prefers --author and needs one when not logged in", async () => {
+    await notesAdd({ userId: "user_1", note: "Verified by phone", author: "support-bot" });
+    const patch = calls().find((call) => call.method === "PATCH")!;
+    expect(JSON.parse(patch.body!).private_metadata.clerk_cli.notes[1].author).toBe("support-bot");
+
+    mockBapiRequest.mockClear();
+    mockGetValidToken.mockResolvedValue(null);
+    await expect(notesAdd({ userId: "user_1", note: "Anonymous" })).rejects.toThrow(/--author/);
+    expect(mockBapiRequest).not.toHaveBeenCalled();
+  });
+
+  test("list prints the notes as JSON", async () => {
+    await notesList({ userId: "user_1", json: true });
+
+    expect(JSON.parse(captured.out)).toEqual([EARLIER]);
+  });
+
+  test("list says when there are no notes", async () => {
+    mockBapiRequest.mockResolvedValue(respond({ id: "user_1", private_metadata: {} }));
+
+    await notesList({ userId: "user_1" });
+
+    expect(captured.err).toContain("No notes on user_1");
+  });
+});
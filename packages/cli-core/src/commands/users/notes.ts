@@ -0,0 +1,157 @@
+import { resolveBapiSecretKey } from "../../lib/bapi-command.ts";
+import { bapiRequest } from "../../lib/bapi.ts";
+import { cyan, dim } from "../../lib/color.ts";
+import { getValidToken } from "../../lib/credential-store.ts";
+import { throwUsageError, withApiContext } from "../../lib/errors.ts";
+import { log } from "../../lib/log.ts";
+import { isRecord } from "../../lib/objects.ts";
+import { printOutput } from "../../lib/pager.ts";
+import { requireArg } from "../../lib/require-arg.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import { formatTimestamp, renderTable, type TableColumn } from "../../lib/table.ts";
+import { fetchUserInfo } from "../../lib/token-exchange.ts";
+import { isAgent } from "../../mode.ts";
+
+export type UsersNotesOptions = {
+  userId?: string;
+  json?: boolean;
+  secretKey?: string;
+  app?: string;
+  instance?: string;
+};
+
+export type UsersNotesAddOptions = UsersNotesOptions & {
+  note?: string;
+  author?: string;
+};
+
+/**
+ * Notes live under this private-metadata key, so they travel with the user
+ * record (and show in the Dashboard) without mixing with the app's own keys.
+ * Private metadata is never sent to the frontend.
+ */
+export const NOTES_NAMESPACE = "clerk_cli";
+
+export type SupportNote = {
+  text: string;
+  author: string;
+  /** ISO 8601, so the note reads naturally in the Dashboard's metadata view. */
+  created_at: string;
+};
+
+/** The notes stored on a user's private metadata, oldest first. Malformed entries are dropped. */
+export function readNotes(privateMetadata: unknown): SupportNote[] {
+  const namespace = isRecord(privateMetadata) ? privateMetadata[NOTES_NAMESPACE] : undefined;
+  const notes = isRecord(namespace) ? namespace.notes : undefined;
+  if (!Array.isArray(notes)) return [];
+  return notes.filter(
+    (note): note is SupportNote =>
+      isRecord(note) &&
+      typeof note.text === "string" &&
+      typeof note.author === "string" &&
+      typeof note.created_at === "string",
+  );
+}
+
+async function fetchNotes(secretKey: string, userId: string): Promise<SupportNote[]> {
+  const response = await bapiRequest({
+    method: "GET",
+    path: `/users/${encodeURIComponent(userId)}`,
+    secretKey,
+  });
+  const user = response.body;
+  return readNotes(isRecord(user) ? user.private_metadata : undefined);
+}
+
+/**
+ * `--author`, or the email of the Clerk account the CLI is logged in as. A
+ * secret key alone doesn't say who is writing, so one of the two is required.
+ */
+async function resolveAuthor(flag: string | undefined): Promise<string> {
+  if (flag?.trim()) return flag.trim();
+  const token = await getValidToken();
+  if (token) {
+    try {
+      return (await fetchUserInfo(token)).email;
+    } catch {
+      // Fall through to the usage error: an expired session shouldn't be
+      // silently replaced by an anonymous author.
+    }
+  }
+  throwUsageError("Pass --author <name>, or run `clerk auth login` to sign notes with your email.");
+}
+
+function resolveSecretKey(options: UsersNotesOptions): Promise<string> {
+  return resolveBapiSecretKey({
+    secretKey: options.secretKey,
+    app: options.app,
+    instance: options.instance,
+  });
+}
+
+const NOTE_COLUMNS: TableColumn<SupportNote>[] = [
+  {
+    key: "created",
+    header: "CREATED",
+    value: (note) => formatTimestamp(Date.parse(note.created_at)),
+    style: dim,
+  },
+  { key: "author", header: "AUTHOR", value: (note) => note.author, style: cyan },
+  { key: "note", header: "NOTE", value: (note) => note.text },
+];
+
+export async function notesList(options: UsersNotesOptions = {}): Promise<void> {
+  const userId = await requireArg(options.userId, { label: "User ID" });
+  const secretKey = await resolveSecretKey(options);
+  const notes = await withSpinner("Fetching notes...", () =>
+    withApiContext(fetchNotes(secretKey, userId), `Failed to fetch user ${userId}`),
+  );
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(notes, null, 2));
+    return;
+  }
+  if (notes.length === 0) {
+    log.info(`No notes on ${userId}. Add one with \`clerk users notes add ${userId} "..."\`.`);
+    return;
+  }
+  await printOutput(renderTable(notes, NOTE_COLUMNS), { page: false });
+}
+
+/**
+ * Append a note. The metadata endpoint deep-merges objects but replaces
+ * arrays, so the current notes are read and written back with the new one;
+ * two notes added to the same user at the same moment can race.
+ */
+export async function notesAdd(options: UsersNotesAddOptions = {}): Promise<void> {
+  const userId = await requireArg(options.userId, { label: "User ID" });
+  const text = (await requireArg(options.note, { label: "Note" })).trim();
+  if (!text) throwUsageError("The note is empty.");
+  const author = await resolveAuthor(options.author);
+  const secretKey = await resolveSecretKey(options);
+
+  const note: SupportNote = { text, author, created_at: new Date().toISOString() };
+  const notes = await withSpinner("Adding note...", async () => {
+    const current = await withApiContext(
+      fetchNotes(secretKey, userId),
+      `Failed to fetch user ${userId}`,
+    );
+    const updated = [...current, note];
+    await withApiContext(
+      bapiRequest({
+        method: "PATCH",
+        path: `/users/${encodeURIComponent(userId)}/metadata`,
+        secretKey,
+        body: JSON.stringify({ private_metadata: { [NOTES_NAMESPACE]: { notes: updated } } }),
+      }),
+      `Failed to add a note to ${userId}`,
+    );
+    return updated;
+  });
+
+  if (options.json || isAgent()) {
+    log.data(JSON.stringify(note, null, 2));
+    return;
+  }
+  log.success(`Added note to ${cyan(userId)} (${notes.length} total)`);
+}
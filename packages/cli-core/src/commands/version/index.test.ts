@@ -0,0 +1,160 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { useCaptureLog } from "../../test/lib/stubs.ts";
+
+const mockFetchLatestVersion = mock();
+const mockWriteUpdateCache = mock();
+let currentVersion = "2.1.0";
+mock.module("../../lib/update-check.ts", () => ({
+  compareSemver: (a: string, b: string) => Bun.semver.order(a, b),
+  fetchLatestVersion: (...args: unknown[]) => mockFetchLatestVersion(...args),
+  formatChannelFlag: (channel: string) => (channel !== "latest" ? ` --channel ${channel}` : ""),
+  formatChannelLabel: (channel: string) => (channel !== "latest" ? ` (${channel})` : ""),
+  getCurrentVersion: () => currentVersion,
+  getUpdateChannel: () => "latest",
+  isDevVersion: (version: string) => version === "0.0.0-dev",
+  writeUpdateCache: (...args: unknown[]) => mockWriteUpdateCache(...args),
+}));
+
+mock.module("../../lib/spinner.ts", () => ({
+  withSpinner: async (_msg: string, fn: (controls: unknown) => Promise<unknown>) =>
+    fn({ update: () => {} }),
+}));
+
+mock.module("../../mode.ts", () => ({
+  isAgent: () => false,
+  isHuman: () => true,
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const mockLoggedFetch = mock();
+mock.module("../../lib/fetch.ts", () => ({
+  loggedFetch: (...args: unknown[]) => mockLoggedFetch(...args),
+}));
+
+const { parseChangelog, releasesBetween, version } = await import("./index.ts");
+
+const CHANGELOG = `# clerk
+
+## 2.3.0
+
+### Minor Changes
+
+- Add \`clerk mcp install\` to connect the MCP server. Clients are registered by shelling out. ([#307](https://github.com/clerk/cli/pull/307)) by [@someone](https://github.com/someone)
+
+### Patch Changes
+
+- Stop scaffolding deprecated route protection. ([#387](https://github.com/clerk/cli/pull/387)) by [@someone](https://github.com/someone)
+
+## 2.2.0
+
+### Minor Changes
+
+- Revoke live impersonation sessions with \`clerk imp revoke\`, e.g. after a mistake. ([#382](https://github.com/clerk/cli/pull/382)) by [@someone](https://github.com/someone)
+
+  - A sub-bullet that belongs to the change above.
+
+## 2.1.0
+
+### Major Changes
+
+- Remove the bundled skill command. ([#315](https://github.com/clerk/cli/pull/315)) by [@someone](https://github.com/someone)
+`;
+
+describe("parseChangelog", () => {
+  test("keeps the first sentence of major and minor changes and counts fixes", () => {
+    expect(parseChangelog(CHANGELOG)).toEqual([
+      {
+        version: "2.3.0",
+        highlights: ["Add `clerk mcp install` to connect the MCP server."],
+        fixes: 1,
+      },
+      {
+        version: "2.2.0",
+        highlights: [
+          "Revoke live impersonation sessions with `clerk imp revoke`, e.g. after a mistake.",
+        ],
+        fixes: 0,
+      },
+      { version: "2.1.0", highlights: ["Remove the bundled skill command."], fixes: 0 },
+    ]);
+  });
+});
+
+describe("releasesBetween", () => {
+  test("includes releases after the current version up to the latest", () => {
+    const versions = releasesBetween(parseChangelog(CHANGELOG), "2.1.0", "2.3.0").map(
+      (release) => release.version,
+    );
+    expect(versions).toEqual(["2.3.0", "2.2.0"]);
+  });
+});
+
+describe("clerk version", () => {
+  const captured = useCaptureLog();
+  let originalExitCode: typeof process.exitCode;
+
+  beforeEach(() => {
+    originalExitCode = process.exitCode;
+    currentVersion = "2.1.0";
+    mockLoggedFetch.mockImplementation(async () => new Response(CHANGELOG));
+  });
+
+  afterEach(() => {
+    process.exitCode = originalExitCode;
+    mockFetchLatestVersion.mockReset();
+    mockWriteUpdateCache.mockReset();
+    mockLoggedFetch.mockReset();
+  });
+
+  test("prints the version without checking", async () => {
+    await version();
+
+    expect(captured.out).toBe("2.1.0");
+    expect(mockFetchLatestVersion).not.toHaveBeenCalled();
+  });
+
+  test("--check lists the releases in between and exits 1 when outdated", async () => {
+    mockFetchLatestVersion.mockResolvedValue("2.3.0");
+
+    await version({ check: true, json: true });
+
+    const report = JSON.parse(captured.out);
+    expect(report).toMatchObject({ version: "2.1.0", latest: "2.3.0", outdated: true });
+    expect(report.releases.map((release: { version: string }) => release.version)).toEqual([
+      "2.3.0",
+      "2.2.0",
+    ]);
+    expect(process.exitCode).toBe(1);
+    expect(mockWriteUpdateCache).toHaveBeenCalledWith(
+      expect.objectContaining({ latest: "2.3.0", distTag: "latest" }),
+    );
+  });
+
+  test("--check exits 0 on the latest release", async () => {
+    mockFetchLatestVersion.mockResolvedValue("2.1.0");
+
+    await version({ check: true });
+
+    expect(captured.err).toContain("clerk 2.1.0 is the latest release.");
+    expect(process.exitCode).toBe(originalExitCode);
+    expect(mockLoggedFetch).not.toHaveBeenCalled();
+  });
+
+  test("--check still fails when the release notes can't be fetched", async () => {
+    mockFetchLatestVersion.mockResolvedValue("2.3.0");
+    mockLoggedFetch.mockImplementation(async () => new Response("", { status: 503 }));
+
+    await version({ check: true });
+
+    expect(captured.err).toContain("clerk 2.1.0 is out of date: 2.3.0 is available.");
+    expect(captured.err).toContain("Release notes are unavailable right now.");
+    expect(process.exitCode).toBe(1);
+  });
+
+  test("--check fails when the registry is unreachable", async () => {
+    mockFetchLatestVersion.mockRejectedValue(new Error("timeout"));
+
+    await expect(version({ check: true })).rejects.toThrow("Could not reach npm registry");
+  });
+});
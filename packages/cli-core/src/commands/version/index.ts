@@ -0,0 +1,186 @@
+import semver from "semver";
+import type { Program } from "../../cli-program.ts";
+import { bold, dim } from "../../lib/color.ts";
+import { CHANGELOG_URL } from "../../lib/constants.ts";
+import { CliError, EXIT_CODE } from "../../lib/errors.ts";
+import { loggedFetch } from "../../lib/fetch.ts";
+import { log } from "../../lib/log.ts";
+import { withSpinner } from "../../lib/spinner.ts";
+import {
+  compareSemver,
+  fetchLatestVersion,
+  formatChannelFlag,
+  formatChannelLabel,
+  getCurrentVersion,
+  getUpdateChannel,
+  isDevVersion,
+  writeUpdateCache,
+} from "../../lib/update-check.ts";
+import { isAgent } from "../../mode.ts";
+
+export type VersionOptions = {
+  check?: boolean;
+  channel?: string;
+  json?: boolean;
+};
+
+/** `--check` runs on purpose, so it can wait longer than the background notifier. */
+const CHECK_TIMEOUT_MS = 5_000;
+
+export type ReleaseNotes = {
+  version: string;
+  /** The first sentence of each major and minor change. */
+  highlights: string[];
+  /** Number of patch changes, summarized rather than listed. */
+  fixes: number;
+};
+
+/** Drop the changesets attribution: ` ([#307](...)) by [@user](...)`. */
+function stripAttribution(text: string): string {
+  return text.replace(/\s*\(\[#\d+\]\([^)]*\)\)(?:\s+by\s+.*)?$/, "").trim();
+}
+
+function firstSentence(text: string): string {
+  const match = /^.*?[.!?](?=\s+[A-Z]|$)/.exec(text);
+  return match ? match[0] : text;
+}
+
+/**
+ * Parse the changesets-generated changelog into one entry per released
+ * version, newest first (the file's order). Only top-level bullets count;
+ * indented sub-bullets belong to the change above them.
+ */
+export function parseChangelog(markdown: string): ReleaseNotes[] {
+  const releases: ReleaseNotes[] = [];
+  let current: ReleaseNotes | undefined;
+  let kind: "highlight" | "fix" | undefined;
+  for (const line of markdown.split(/\r?\n/)) {
+    const heading = /^## (\S+)/.exec(line);
+    if (heading) {
+      current = semver.valid(heading[1]!)
+        ? { version: heading[1]!, highlights: [], fixes: 0 }
+        : undefined;
+      if (current) releases.push(current);
+      kind = undefined;
+      continue;
+    }
+    if (line.startsWith("### ")) {
+      kind = /Patch/i.test(line) ? "fix" : /Major|Minor/i.test(line) ? "highlight" : undefined;
+      continue;
+    }
+    if (!current || !kind || !line.startsWith("- ")) continue;
+    if (kind === "fix") current.fixes += 1;
+    else current.highlights.push(firstSentence(stripAttribution(line.slice(2))));
+  }
+  return releases;
+}
+
+/** Releases newer than `current`, up to and including `latest`, newest first. */
+export function releasesBetween(
+  releases: ReleaseNotes[],
+  current: string,
+  latest: string,
+): ReleaseNotes[] {
+  return releases
+    .filter((release) => semver.gt(release.version, current) && semver.lte(release.version, latest))
+    .sort((a, b) => compareSemver(b.version, a.version));
+}
+
+async function fetchReleaseNotes(): Promise<ReleaseNotes[]> {
+  const response = await loggedFetch(CHANGELOG_URL, {
+    tag: "update-check",
+    signal: AbortSignal.timeout(CHECK_TIMEOUT_MS),
+  });
+  if (!response.ok) throw new Error(`changelog HTTP ${response.status}`);
+  return parseChangelog(await response.text());
+}
+
+function printReleaseNotes(releases: ReleaseNotes[]): void {
+  for (const release of releases) {
+    log.info(bold(release.version));
+    for (const highlight of release.highlights) log.info(`  • ${highlight}`);
+    if (release.fixes > 0) log.info(dim(`  + ${release.fixes} fix(es)`));
+  }
+}
+
+/**
+ * Print the CLI's version. With `--check`, compare it against the latest
+ * release on the channel, list what changed since, and exit 1 when outdated
+ * so CI images that must track the latest release fail their build.
+ */
+export async function version(options: VersionOptions = {}): Promise<void> {
+  const current = getCurrentVersion();
+  const json = Boolean(options.json || isAgent());
+  const channel = options.channel ?? getUpdateChannel();
+
+  if (!options.check) {
+    if (json) log.data(JSON.stringify({ version: current, channel }, null, 2));
+    else log.data(current);
+    return;
+  }
+  if (isDevVersion(current)) {
+    log.warn(`This is a development build (${current}); there is no release to compare it with.`);
+    return;
+  }
+
+  const latest = await withSpinner("Checking for updates...", () =>
+    fetchLatestVersion(channel, CHECK_TIMEOUT_MS),
+  ).catch((error) => {
+    log.debug(`Update check failed: ${error}`);
+    throw new CliError("Could not reach npm registry. Check your network connection.");
+  });
+  await writeUpdateCache({ checkedAt: Date.now(), latest, distTag: channel });
+
+  const outdated = compareSemver(latest, current) > 0;
+  let releases: ReleaseNotes[] | undefined;
+  if (outdated) {
+    process.exitCode = EXIT_CODE.GENERAL;
+    try {
+      releases = releasesBetween(await fetchReleaseNotes(), current, latest);
+    } catch (error) {
+      // The verdict doesn't depend on the notes; report it without them.
+      log.debug(`Failed to fetch release notes: ${error}`);
+    }
+  }
+
+  if (json) {
+    const report = { version: current, latest, channel, outdated, releases: releases ?? [] };
+    log.data(JSON.stringify(report, null, 2));
+    return;
+  }
+  if (!outdated) {
+    log.success(`clerk ${current} is the latest release${formatChannelLabel(channel)}.`);
+    return;
+  }
+  const label = formatChannelLabel(channel);
+  log.warn(`clerk ${current} is out of date: ${latest} is available${label}.`);
+  if (releases?.length) {
+    log.blank();
+    printReleaseNotes(releases);
+    log.blank();
+  } else if (!releases) {
+    log.info(dim("Release notes are unavailable right now."));
+  }
+  log.info(`Run \`clerk update${formatChannelFlag(channel)}\` to update.`);
+}
+
+export function registerVersion(program: Program): void {
+  program
+    .command("version")
+    .description("Print the CLI version, or check it against the latest release")
+    .option("--check", "Compare with the latest release and exit 1 when outdated")
+    .option("--channel <tag>", "Release channel to check (e.g. latest, canary)")
+    .option("--json", "Output as JSON")
+    .setExamples([
+      { command: "clerk version", description: "Print the installed version" },
+      {
+        command: "clerk version --check",
+        description: "Show what's new since this version; exits 1 when outdated",
+      },
+      {
+        command: "clerk version --check --json",
+        description: "Gate a CI image on running the latest release",
+      },
+    ])
+    .action((_opts, cmd) => version(cmd.optsWithGlobals() as Parameters<typeof version>[0]));
+}
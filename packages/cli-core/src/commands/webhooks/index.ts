@@ -80,9 +80,9 @@ export function registerWebhooks(program: Program): void {
     .option("--secret <whsec>", "Signing secret (whsec_...), always required")
     .option(
       "--delivery <file>",
-      "One `listen` event NDJSON line as @file or - for stdin (alternative to the four explicit flags)",
+      "One `listen` event NDJSON line as a file path, @file, or - for stdin (instead of the four flags)",
     )
-    .option("--payload <file>", "Raw request body as @file or - for stdin")
+    .option("--payload <file>", "Raw request body as a file path, @file, or - for stdin")
     .option("--id <msg_id>", "The svix-id header value")
     .option("--timestamp <seconds>", "The svix-timestamp header value (Unix epoch seconds)")
     .option("--signature <sig>", "The raw svix-signature header value (may hold multiple entries)")
@@ -94,7 +94,7 @@ export function registerWebhooks(program: Program): void {
       },
       {
         command:
-          "clerk webhooks verify --secret whsec_... --payload @body.json --id msg_2xyz --timestamp 1717935000 --signature v1,abc...",
+          "clerk webhooks verify --secret whsec_... --payload body.json --id msg_2xyz --timestamp 1717935000 --signature v1,abc...",
         description: "Verify from the four header values",
       },
     ])
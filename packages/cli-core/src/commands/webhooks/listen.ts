@@ -118,8 +118,9 @@ export async function webhooksListen(options: WebhooksListenOptions = {}): Promi
   }
 
   // Persist the token so the inbox URL stays stable across runs; --token pins
-  // an explicit one. No Clerk backend is involved.
-  const existing = await getRelayEntry(RELAY_KEY);
+  // an explicit one (without reading, so it also replaces a token that can no
+  // longer be decrypted). No Clerk backend is involved.
+  const existing = options.token ? undefined : await getRelayEntry(RELAY_KEY);
   const token = options.token ?? existing?.token ?? generateRelayToken();
   if (token !== existing?.token) await setRelayEntry(RELAY_KEY, { token });
 
@@ -224,6 +224,38 @@ describe("webhooks verify command", () => {
     ).rejects.toThrow("Signature verification failed");
   });
 
+  test("reads --payload from a bare file path", async () => {
+    const payloadPath = await writeTempFile("body.json", PAYLOAD);
+
+    await webhooksVerify({ ...explicitFlags(), payload: payloadPath });
+
+    expect(captured.err).toContain("Signature verified.");
+  });
+
+  test.skipIf(process.platform === "win32")(
+    "reads a bare --payload path that is a character device",
+    async () => {
+      // /dev/null stands in for `<(...)` and /dev/stdin: it's read, not refused.
+      await expect(
+        webhooksVerify({ ...explicitFlags(), payload: "/dev/null" }),
+      ).rejects.toMatchObject({ code: ERROR_CODE.INVALID_WEBHOOK_SIGNATURE });
+    },
+  );
+
+  test("a bare --payload path that doesn't exist is a usage error", async () => {
+    await expect(
+      webhooksVerify({ ...explicitFlags(), payload: join(tempDir, "missing.json") }),
+    ).rejects.toThrow("is not a file");
+  });
+
+  test("a mismatch reports the signature expected for the inputs", async () => {
+    const payloadPath = await writeTempFile("body.json", PAYLOAD);
+
+    await expect(
+      webhooksVerify({ ...explicitFlags(), signature: "v1,bm9wZQ==", payload: payloadPath }),
+    ).rejects.toThrow(`Expected signature for these inputs: ${VALID_SIGNATURE}.`);
+  });
+
   test("signature mismatch carries invalid_webhook_signature for agent discrimination", async () => {
     const payloadPath = await writeTempFile("body.json", PAYLOAD + "tampered");
 
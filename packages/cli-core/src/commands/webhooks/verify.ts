@@ -34,25 +34,43 @@ export function decodeWebhookSecret(secret: string): Buffer | null {
   return key;
 }
 
+interface SignedFields {
+  id: string;
+  timestamp: string;
+  payload: string;
+}
+
+interface SignedContent extends SignedFields {
+  secret: string;
+}
+
+function signContent(key: Buffer, input: SignedFields): Buffer {
+  return createHmac("sha256", key)
+    .update(`${input.id}.${input.timestamp}.${input.payload}`, "utf8")
+    .digest();
+}
+
+/**
+ * The `v1,<base64>` signature Svix would send for these inputs, so a failed
+ * check can be compared against what a handler received. Null when the secret
+ * is malformed.
+ */
+export function computeWebhookSignature(input: SignedContent): string | null {
+  const key = decodeWebhookSecret(input.secret);
+  return key ? `v1,${signContent(key, input).toString("base64")}` : null;
+}
+
 /**
  * Verify a Svix signature: HMAC-SHA256 over `{id}.{timestamp}.{payload}` with
  * the decoded secret, compared constant-time against every space-separated
  * `v1,<base64>` entry in the header (any match wins). During the 24h rotation
  * grace window the header carries multiple entries — that's why any-match matters.
  */
-export function verifyWebhookSignature(input: {
-  secret: string;
-  id: string;
-  timestamp: string;
-  payload: string;
-  signature: string;
-}): boolean {
+export function verifyWebhookSignature(input: SignedContent & { signature: string }): boolean {
   const key = decodeWebhookSecret(input.secret);
   if (!key) return false;
 
-  const expected = createHmac("sha256", key)
-    .update(`${input.id}.${input.timestamp}.${input.payload}`, "utf8")
-    .digest();
+  const expected = signContent(key, input);
 
   return input.signature
     .split(/\s+/)
@@ -117,8 +135,24 @@ async function readFileOrStdin(value: string, flag: string): Promise<string> {
       throw new CliError(`Could not read ${path}${reason}`, { code: ERROR_CODE.FILE_NOT_FOUND });
     }
   }
+  // A bare path works too. Read it directly for the same reason as above
+  // (process substitution and /dev/stdin are character devices); only a path
+  // that doesn't exist is taken for an inline value, which shells mangle.
+  if (value) {
+    try {
+      return await Bun.file(value).text();
+    } catch (err) {
+      if ((err as NodeJS.ErrnoException).code !== "ENOENT") {
+        const reason = err instanceof Error ? `: ${err.message}` : "";
+        throw new CliError(`Could not read ${value}${reason}`, {
+          code: ERROR_CODE.FILE_NOT_FOUND,
+        });
+      }
+    }
+  }
   return throwUsageError(
-    `${flag} takes @file or - for stdin (inline values get mangled by shells).`,
+    `${flag} "${value}" is not a file. Pass a file path, @file, or - for stdin ` +
+      "(inline values get mangled by shells).",
   );
 }
 
@@ -177,33 +211,29 @@ export async function webhooksVerify(options: WebhooksVerifyOptions = {}): Promi
     !signature && "--signature",
     !hasPayload && "--payload",
   ].filter(Boolean);
-  if (missing.length > 0) {
+  if (!id || !timestamp || !signature || !hasPayload) {
     throwUsageError(
       `Missing ${missing.join(", ")}. Pass --delivery @event.json or all four explicit flags.`,
     );
   }
 
-  if (!/^\d+$/.test(timestamp!)) {
+  if (!/^\d+$/.test(timestamp)) {
     throwUsageError(
       `Invalid --timestamp "${timestamp}". Expected Unix epoch seconds (the raw svix-timestamp header value).`,
     );
   }
 
   // Nullish-coalesce, not truthiness: an explicit empty `--payload` must reach
-  // readFileOrStdin (which rejects it as neither @file nor -) instead of
-  // silently falling through to the --delivery body or an `undefined` HMAC.
+  // readFileOrStdin (which rejects it as neither a file nor -) instead of
+  // silently falling through to the --delivery body. Without `--payload`, the
+  // missing-input check above guarantees the --delivery body is set.
   const payload =
     options.payload !== undefined
       ? await readFileOrStdin(options.payload, "--payload")
-      : fields.payload;
+      : (fields.payload ?? "");
+  const signed = { secret: options.secret, id, timestamp, payload };
 
-  const valid = verifyWebhookSignature({
-    secret: options.secret,
-    id: id!,
-    timestamp: timestamp!,
-    payload: payload!,
-    signature: signature!,
-  });
+  const valid = verifyWebhookSignature({ ...signed, signature });
 
   if (!valid) {
     let message = "Signature verification failed: no signature entry matched.";
@@ -211,7 +241,7 @@ export async function webhooksVerify(options: WebhooksVerifyOptions = {}): Promi
     // plausible v1 HMAC-SHA256 (32 bytes) — otherwise the failure is a malformed
     // signature, not a timestamp problem, and a skew note would mislead.
     const HMAC_SHA256_BYTES = 32;
-    const hasStructuralCandidate = signature!
+    const hasStructuralCandidate = signature
       .split(/\s+/)
       .filter(Boolean)
       .some((entry) => {
@@ -224,10 +254,14 @@ export async function webhooksVerify(options: WebhooksVerifyOptions = {}): Promi
       message += ` Note: the timestamp is ${humanizeSkew(deltaSeconds)} — make sure it is the raw svix-timestamp header from the same delivery as the signature.`;
     }
     // Trailing-newline footgun: `echo` adds one, but the HMAC is byte-exact.
-    if (options.payload !== undefined && typeof payload === "string" && payload.endsWith("\n")) {
+    if (options.payload !== undefined && payload.endsWith("\n")) {
       message +=
         " Note: the --payload file ends with a trailing newline; the HMAC is byte-exact. Write the raw body with no trailing newline (use printf, not echo), or use --delivery from a captured listen event.";
     }
+    // What the sender would have signed, for comparison with the header a
+    // handler received when it rejected the same delivery.
+    const expected = computeWebhookSignature(signed);
+    if (expected) message += ` Expected signature for these inputs: ${expected}.`;
     throw new CliError(message, { code: ERROR_CODE.INVALID_WEBHOOK_SIGNATURE });
   }
 
@@ -0,0 +1,64 @@
+/**
+ * Backend API helpers for instance API keys (the machine credentials users
+ * and organizations create to call your backend, not the Clerk secret key).
+ */
+
+import { bapiRequest } from "./bapi.ts";
+import { isRecord } from "./objects.ts";
+
+/** The subset of BAPI's APIKey object the CLI consumes. The secret is never included. */
+export interface BapiApiKey {
+  id: string;
+  name?: string;
+  description?: string | null;
+  /** The user or organization the key acts as. */
+  subject?: string;
+  scopes?: string[];
+  /** The user who created the key, or `null` for keys created through the Backend API. */
+  created_by?: string | null;
+  revoked?: boolean;
+  expired?: boolean;
+  expiration?: number | null;
+  last_used_at?: number | null;
+  created_at?: number;
+  updated_at?: number;
+}
+
+const API_KEYS_PAGE_SIZE = 100;
+
+/**
+ * Every API key on the instance, optionally for one subject, following
+ * pagination. Revoked and expired keys are included.
+ */
+export async function listApiKeys(secretKey: string, subject?: string): Promise<BapiApiKey[]> {
+  const keys: BapiApiKey[] = [];
+  for (let offset = 0; ; offset += API_KEYS_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      limit: String(API_KEYS_PAGE_SIZE),
+      offset: String(offset),
+      include_invalid: "true",
+    });
+    if (subject) params.set("subject", subject);
+    const response = await bapiRequest({ method: "GET", path: `/api_keys?${params}`, secretKey });
+    const body = response.body;
+    const page = Array.isArray(body)
+      ? body
+      : isRecord(body) && Array.isArray(body.data)
+        ? body.data
+        : [];
+    keys.push(...(page as BapiApiKey[]));
+    if (page.length < API_KEYS_PAGE_SIZE) return keys;
+  }
+}
+
+/** `active`, `revoked`, or `expired`. */
+export function apiKeyStatus(key: BapiApiKey): string {
+  if (key.revoked) return "revoked";
+  if (key.expired) return "expired";
+  return "active";
+}
+
+/** When the key was last used, or created if it never was. */
+export function apiKeyLastActivity(key: BapiApiKey): number {
+  return key.last_used_at ?? key.created_at ?? 0;
+}
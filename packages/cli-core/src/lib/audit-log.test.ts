@@ -0,0 +1,40 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { mkdtemp, rm, stat } from "node:fs/promises";
+import { join } from "node:path";
+import { tmpdir } from "node:os";
+import { _setConfigDir } from "./config.ts";
+import { appendAuditEntry, getAuditLogFile } from "./audit-log.ts";
+
+describe("appendAuditEntry", () => {
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-audit-test-"));
+    _setConfigDir(join(tempDir, "cli"));
+  });
+
+  afterEach(async () => {
+    _setConfigDir(undefined);
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("appends timestamped JSON lines next to the config file", async () => {
+    const path = await appendAuditEntry({ event: "impersonation.created", user_id: "user_1" });
+    await appendAuditEntry({ event: "impersonation.created", user_id: "user_2" });
+
+    expect(path).toBe(join(tempDir, "cli", "audit.jsonl"));
+    expect(getAuditLogFile()).toBe(path!);
+    const entries = (await Bun.file(path!).text())
+      .trim()
+      .split("\n")
+      .map((line) => JSON.parse(line) as { at: string; user_id: string });
+    expect(entries.map((entry) => entry.user_id)).toEqual(["user_1", "user_2"]);
+    expect(Number.isNaN(Date.parse(entries[0]!.at))).toBe(false);
+  });
+
+  test.skipIf(process.platform === "win32")("creates the log readable only by you", async () => {
+    const path = await appendAuditEntry({ event: "impersonation.created" });
+
+    expect((await stat(path!)).mode & 0o777).toBe(0o600);
+  });
+});
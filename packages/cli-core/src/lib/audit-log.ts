@@ -0,0 +1,45 @@
+/**
+ * Local audit log of sensitive CLI actions, appended as JSON Lines next to the
+ * CLI config file. Clerk's own record lives on the affected objects (e.g. an
+ * impersonation session's `actor`); this one answers "what did I run, and
+ * against which instance" from the operator's machine.
+ */
+
+import { appendFile, mkdir } from "node:fs/promises";
+import { dirname, join } from "node:path";
+import { getConfigFile } from "./config.ts";
+import { errorMessage } from "./errors.ts";
+import { withHomeFsAccess } from "./host-execution.ts";
+import { log } from "./log.ts";
+
+export type AuditEntry = {
+  event: string;
+} & Record<string, unknown>;
+
+export function getAuditLogFile(): string {
+  return join(dirname(getConfigFile()), "audit.jsonl");
+}
+
+/**
+ * Append one entry, stamped with the current time. Returns the log's path, or
+ * `undefined` (after a warning) when it can't be written: by then the action
+ * has already happened, so failing the command would only hide its result.
+ */
+export async function appendAuditEntry(entry: AuditEntry): Promise<string | undefined> {
+  const path = getAuditLogFile();
+  const line = JSON.stringify({ at: new Date().toISOString(), ...entry });
+  try {
+    await withHomeFsAccess(
+      { operation: "write", target: path, label: "CLI audit log" },
+      async () => {
+        await mkdir(dirname(path), { recursive: true });
+        await appendFile(path, `${line}\n`, { mode: 0o600 });
+      },
+    );
+    log.debug(`audit: recorded ${entry.event} in ${path}`);
+    return path;
+  } catch (error) {
+    log.warn(`Could not write the audit log at ${path}: ${errorMessage(error)}`);
+    return undefined;
+  }
+}
@@ -1,5 +1,6 @@
 import { test, expect, describe, beforeEach, afterEach, spyOn } from "bun:test";
 import { BapiError, CliError, ERROR_CODE } from "./errors.ts";
+import { setLogLevel } from "./log.ts";
 import { useCaptureLog } from "../test/lib/stubs.ts";
 
 const configModule = await import("./config.ts");
@@ -200,6 +201,19 @@ describe("bapi-command", () => {
     expect(fetchApplicationSpy).not.toHaveBeenCalled();
   });
 
+  test("traces where the secret key came from at debug level", async () => {
+    process.env.CLERK_SECRET_KEY = "sk_env_123";
+    setLogLevel("debug");
+    try {
+      await resolveBapiSecretKey({});
+    } finally {
+      setLogLevel("info");
+    }
+
+    expect(captured.err).toContain("auth: secret key from CLERK_SECRET_KEY environment variable");
+    expect(captured.err).not.toContain("sk_env_123");
+  });
+
   test("remaps not-linked app context errors to a no-secret-key usage error", async () => {
     resolveAppContextSpy.mockRejectedValue(
       new CliError("linked profile missing", {
@@ -37,6 +37,7 @@ export async function describeBapiTarget(
 export async function resolveBapiSecretKey(options: ResolveBapiSecretKeyOptions): Promise<string> {
   if (options.secretKey) {
     validateKeyPrefix(options.secretKey, "sk_");
+    log.debug("auth: secret key from --secret-key");
     return options.secretKey;
   }
 
@@ -55,11 +56,13 @@ export async function resolveBapiSecretKey(options: ResolveBapiSecretKeyOptions)
         docsUrl: "https://clerk.com/docs/guides/development/clerk-environment-variables",
       });
     }
+    log.debug(`auth: secret key from --app ${options.app} (${resolved.instanceLabel} instance)`);
     return resolved.instance.secret_key;
   }
 
   if (process.env.CLERK_SECRET_KEY) {
     validateKeyPrefix(process.env.CLERK_SECRET_KEY, "sk_");
+    log.debug("auth: secret key from CLERK_SECRET_KEY environment variable");
     return process.env.CLERK_SECRET_KEY;
   }
 
@@ -94,6 +97,7 @@ export async function resolveBapiSecretKey(options: ResolveBapiSecretKeyOptions)
       docsUrl: "https://clerk.com/docs/guides/development/clerk-environment-variables",
     });
   }
+  log.debug(`auth: secret key from ${ctx.appLabel} (${ctx.instanceLabel} instance)`);
   return instance.secret_key;
 }
 
@@ -0,0 +1,70 @@
+/**
+ * Backend API helpers for Clerk Billing subscriptions. A subscription belongs
+ * to a payer, either a user or an organization, and holds one item per plan
+ * the payer has been on or is moving to.
+ */
+
+import { bapiRequest } from "./bapi.ts";
+import { ApiError } from "./errors.ts";
+
+export interface BillingFeature {
+  id?: string;
+  slug: string;
+  name?: string;
+}
+
+export interface BillingPlan {
+  id: string;
+  name: string;
+  slug: string;
+  features?: BillingFeature[];
+}
+
+/**
+ * A price in the currency's minor unit (cents for USD, whole yen for JPY).
+ * `amount_formatted` is the API's own rendering, in US conventions.
+ */
+export interface BillingMoney {
+  amount: number;
+  amount_formatted?: string;
+  currency: string;
+  currency_symbol?: string;
+}
+
+export interface BillingSubscriptionItem {
+  id: string;
+  status: string;
+  plan_period?: string;
+  plan?: BillingPlan | null;
+  amount?: BillingMoney | null;
+  period_start?: number | null;
+  period_end?: number | null;
+}
+
+export interface BillingSubscription {
+  id: string;
+  status: string;
+  subscription_items?: BillingSubscriptionItem[];
+}
+
+/**
+ * The billing subscription of a user (`user_...`) or organization (`org_...`),
+ * or `undefined` when the payer has never subscribed.
+ */
+export async function fetchBillingSubscription(
+  secretKey: string,
+  payerId: string,
+): Promise<BillingSubscription | undefined> {
+  const collection = payerId.startsWith("org_") ? "organizations" : "users";
+  try {
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/${collection}/${encodeURIComponent(payerId)}/billing/subscription`,
+      secretKey,
+    });
+    return response.body as BillingSubscription;
+  } catch (error) {
+    if (error instanceof ApiError && error.status === 404) return undefined;
+    throw error;
+  }
+}
@@ -0,0 +1,55 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import { accentSpan, bold, cyan, dim, parseColorOverrides, setTheme, yellow } from "./color.ts";
+
+describe("color themes", () => {
+  const originalEnv = { ...process.env };
+
+  afterEach(() => {
+    process.env = { ...originalEnv };
+    setTheme("dark");
+  });
+
+  test("dark is the default palette", () => {
+    delete process.env.NO_COLOR;
+    setTheme("dark");
+    expect(cyan("x")).toBe("\x1b[36mx\x1b[0m");
+    expect(accentSpan("x")).toBe("\x1b[36mx\x1b[39m");
+  });
+
+  test("light swaps yellow and cyan for shades readable on white", () => {
+    delete process.env.NO_COLOR;
+    setTheme("light");
+    expect(yellow("warn")).toBe("\x1b[38;5;130mwarn\x1b[0m");
+    expect(cyan("id")).toBe("\x1b[38;5;25mid\x1b[0m");
+  });
+
+  test("mono drops hues but keeps attributes", () => {
+    delete process.env.NO_COLOR;
+    setTheme("mono");
+    expect(cyan("x")).toBe("x");
+    expect(bold("x")).toBe("\x1b[1mx\x1b[0m");
+  });
+
+  test("NO_COLOR drops hues and attributes", () => {
+    process.env.NO_COLOR = "1";
+    setTheme("light", { accent: "35" });
+    expect(cyan("x")).toBe("x");
+    expect(bold("x")).toBe("x");
+    expect(dim("x")).toBe("x");
+  });
+
+  test("overrides replace single roles", () => {
+    delete process.env.NO_COLOR;
+    const parsed = parseColorOverrides("accent=magenta, warning=none");
+    if (!("overrides" in parsed)) throw new Error(parsed.error);
+    setTheme("dark", parsed.overrides);
+    expect(cyan("x")).toBe("\x1b[35mx\x1b[0m");
+    expect(yellow("x")).toBe("x");
+  });
+
+  test("rejects unknown roles and colors", () => {
+    expect(parseColorOverrides("links=blue")).toHaveProperty("error");
+    expect(parseColorOverrides("accent=teal")).toHaveProperty("error");
+    expect(parseColorOverrides("accent=300")).toHaveProperty("error");
+  });
+});
@@ -1,8 +1,139 @@
-export const dim = (s: string) => `\x1b[2m${s}\x1b[0m`;
-export const dimNeutral = (s: string) => `\x1b[39m\x1b[2m${s}\x1b[0m`;
-export const bold = (s: string) => `\x1b[1m${s}\x1b[0m`;
-export const cyan = (s: string) => `\x1b[36m${s}\x1b[0m`;
-export const green = (s: string) => `\x1b[32m${s}\x1b[0m`;
-export const yellow = (s: string) => `\x1b[33m${s}\x1b[0m`;
-export const red = (s: string) => `\x1b[31m${s}\x1b[0m`;
-export const blue = (s: string) => `\x1b[34m${s}\x1b[0m`;
+/**
+ * ANSI styling for human-mode output.
+ *
+ * The hue helpers (`cyan`, `green`, ...) are named after the default dark
+ * palette but paint a role: accent, success, warning, error, and info. The
+ * `output.theme` setting swaps the palette (`light` uses darker shades that
+ * stay readable on a white background, `mono` drops hues entirely), and
+ * `output.colors` overrides single roles. `bold` and `dim` are attributes,
+ * not colors, so every theme keeps them. `NO_COLOR` drops both hues and
+ * attributes unless `FORCE_COLOR` is set.
+ *
+ * Must not import other lib modules (settings and log import it).
+ */
+
+export const THEMES = ["dark", "light", "mono"] as const;
+export type Theme = (typeof THEMES)[number];
+
+export const COLOR_ROLES = ["accent", "success", "warning", "error", "info"] as const;
+export type ColorRole = (typeof COLOR_ROLES)[number];
+
+/** SGR foreground parameters per role; `null` leaves text uncolored. */
+type Palette = Record<ColorRole, string | null>;
+
+const PALETTES: Record<Theme, Palette> = {
+  dark: { accent: "36", success: "32", warning: "33", error: "31", info: "34" },
+  light: { accent: "38;5;25", success: "38;5;28", warning: "38;5;130", error: "31", info: "34" },
+  mono: { accent: null, success: null, warning: null, error: null, info: null },
+};
+
+const NAMED_COLORS: Record<string, string> = {
+  black: "30",
+  red: "31",
+  green: "32",
+  yellow: "33",
+  blue: "34",
+  magenta: "35",
+  cyan: "36",
+  white: "37",
+  gray: "90",
+  "bright-red": "91",
+  "bright-green": "92",
+  "bright-yellow": "93",
+  "bright-blue": "94",
+  "bright-magenta": "95",
+  "bright-cyan": "96",
+  "bright-white": "97",
+  none: "",
+};
+
+let palette: Palette = PALETTES.dark;
+/** Whether `bold` and `dim` emit escapes; only `NO_COLOR` turns them off. */
+let attributes = !noColor();
+
+function colorCode(value: string): string | undefined {
+  const name = value.trim().toLowerCase();
+  if (Object.hasOwn(NAMED_COLORS, name)) return NAMED_COLORS[name];
+  if (/^\d{1,3}$/.test(name) && Number(name) <= 255) return `38;5;${Number(name)}`;
+  return undefined;
+}
+
+/**
+ * Parse `role=color` pairs such as `accent=magenta,warning=208`. Colors are
+ * ANSI names, `bright-` variants, `none`, or a 256-color index. Returns an
+ * error message instead of throwing so callers pick their own error type.
+ */
+export function parseColorOverrides(
+  value: string,
+): { overrides: Partial<Record<ColorRole, string>> } | { error: string } {
+  const overrides: Partial<Record<ColorRole, string>> = {};
+  for (const pair of value.split(",")) {
+    if (!pair.trim()) continue;
+    const [role = "", color = "", ...rest] = pair.split("=").map((part) => part.trim());
+    if (!(COLOR_ROLES as readonly string[]).includes(role) || rest.length > 0) {
+      const roles = COLOR_ROLES.join(", ");
+      return { error: `"${pair.trim()}" must be <role>=<color>, with role one of ${roles}` };
+    }
+    const code = colorCode(color);
+    if (code === undefined) {
+      return { error: `unknown color "${color}" (use an ANSI name, none, or 0-255)` };
+    }
+    overrides[role as ColorRole] = code;
+  }
+  return { overrides };
+}
+
+function forceColor(): boolean {
+  const v = process.env.FORCE_COLOR;
+  return v != null && v !== "0" && v !== "false" && v !== "";
+}
+
+function noColor(): boolean {
+  return "NO_COLOR" in process.env && !forceColor();
+}
+
+/** Pick the palette for this invocation. Overrides apply on top of any theme but `NO_COLOR`. */
+export function setTheme(theme: Theme, overrides: Partial<Record<ColorRole, string>> = {}): void {
+  attributes = !noColor();
+  if (!attributes) {
+    palette = PALETTES.mono;
+    return;
+  }
+  palette = { ...PALETTES[theme] };
+  for (const role of COLOR_ROLES) {
+    const code = overrides[role];
+    if (code !== undefined) palette[role] = code || null;
+  }
+}
+
+// eslint-disable-next-line no-control-regex
+const ANSI_PATTERN = /\x1b\[[0-9;]*m/g;
+
+/** Remove the styling these helpers add, for output that must be plain. */
+export function stripAnsi(text: string): string {
+  return text.replace(ANSI_PATTERN, "");
+}
+
+function paint(role: ColorRole, s: string, reset = "0"): string {
+  const code = palette[role];
+  return code ? `\x1b[${code}m${s}\x1b[${reset}m` : s;
+}
+
+/** Accent color that resets only the foreground, so surrounding styles survive. */
+export const accentSpan = (s: string) => paint("accent", s, "39");
+
+function attribute(code: string, s: string, reset = "0"): string {
+  return attributes ? `\x1b[${code}m${s}\x1b[${reset}m` : s;
+}
+
+/** Dim text that resets only the intensity, so surrounding colors survive. */
+export const dimSpan = (s: string) => attribute("2", s, "22");
+
+export const dim = (s: string) => attribute("2", s);
+export const dimNeutral = (s: string) => (attributes ? `\x1b[39m\x1b[2m${s}\x1b[0m` : s);
+export const bold = (s: string) => attribute("1", s);
+export const cyan = (s: string) => paint("accent", s);
+export const green = (s: string) => paint("success", s);
+export const yellow = (s: string) => paint("warning", s);
+export const red = (s: string) => paint("error", s);
+export const blue = (s: string) => paint("info", s);
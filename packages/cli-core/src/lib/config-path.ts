@@ -0,0 +1,49 @@
+/**
+ * Where this invocation keeps its config file. Defaults to the platform config
+ * directory, or `CLERK_CONFIG_DIR` when set; the global `--config` flag
+ * overrides both. A leaf module so the credential store and host probes can
+ * follow `--config` without importing the whole config layer.
+ */
+
+import { dirname, extname, join, resolve } from "node:path";
+import { CONFIG_FILE } from "./constants.ts";
+import { log } from "./log.ts";
+
+let overrideConfigFile: string | undefined;
+let flagConfigDir: string | undefined;
+
+/** Test-only: override the config file path. Pass undefined to reset. */
+export function _setConfigDir(dir: string | undefined): void {
+  overrideConfigFile = dir ? join(dir, "config.json") : undefined;
+  flagConfigDir = undefined;
+}
+
+/**
+ * Point this invocation at another config directory, or at a `.json` config
+ * file whose directory then holds the rest (credentials). Sets
+ * `CLERK_CONFIG_DIR` so the credential store and child processes follow.
+ */
+export function setConfigPath(path: string): void {
+  const absolute = resolve(path);
+  const isFile = extname(absolute) === ".json";
+  flagConfigDir = isFile ? dirname(absolute) : absolute;
+  process.env.CLERK_CONFIG_DIR = flagConfigDir;
+  overrideConfigFile = isFile ? absolute : undefined;
+  log.debug(`config: using ${isFile ? "config file" : "config directory"} ${absolute} (--config)`);
+}
+
+/**
+ * The directory chosen with `--config`, if any. `CLERK_CONFIG_DIR` alone
+ * doesn't count: it predates the flag, and its users' keychain entries aren't
+ * scoped by directory.
+ */
+export function getFlagConfigDir(): string | undefined {
+  return flagConfigDir;
+}
+
+export function getConfigFile(): string {
+  return (
+    overrideConfigFile ??
+    (process.env.CLERK_CONFIG_DIR ? join(process.env.CLERK_CONFIG_DIR, "config.json") : CONFIG_FILE)
+  );
+}
@@ -16,7 +16,18 @@ const {
   resolveInstanceId,
   resolveAppContext,
   resolveFetchedApplicationInstance,
+  getRelayEntry,
+  setRelayEntry,
+  getDefaultContext,
+  setDefaultContext,
+  clearDefaultContext,
+  listInvitationPresets,
+  getInvitationPreset,
+  setInvitationPreset,
+  removeInvitationPreset,
   _setConfigDir,
+  getConfigFile,
+  setConfigPath,
 } = await import("./config.ts");
 type Profile =
   Awaited<ReturnType<typeof getProfile>> extends infer T ? Exclude<T, undefined> : never;
@@ -109,6 +120,21 @@ describe("config", () => {
     expect(Object.keys(profiles)).toEqual(["/projects/app-a", "/projects/app-b"]);
   });
 
+  test("concurrent profile writes all persist", async () => {
+    const paths = Array.from({ length: 8 }, (_, i) => `/projects/app-${i}`);
+    await Promise.all(
+      paths.map((path, i) =>
+        setProfile(path, {
+          workspaceId: "org_1",
+          appId: `app_${i}`,
+          instances: { development: `ins_${i}` },
+        }),
+      ),
+    );
+    expect(Object.keys(await listProfiles()).sort()).toEqual([...paths].sort());
+    expect(await Bun.file(join(tempDir, "config.json.lock")).exists()).toBe(false);
+  });
+
   test("resolveProfile finds exact match", async () => {
     await setProfile("/projects/my-app", {
       workspaceId: "org_1",
@@ -331,4 +357,165 @@ describe("config", () => {
       });
     });
   });
+
+  describe("resolveAppContext (default context)", () => {
+    const app = {
+      application_id: "app_default",
+      name: "Default App",
+      instances: [
+        {
+          instance_id: "ins_dev",
+          environment_type: "development",
+          publishable_key: "pk_test_dev",
+        },
+        {
+          instance_id: "ins_prod",
+          environment_type: "production",
+          publishable_key: "pk_live_prod",
+        },
+      ],
+    };
+
+    test("setDefaultContext and clearDefaultContext roundtrip", async () => {
+      await setDefaultContext({ app: "app_default", appName: "Default App", instance: "prod" });
+      expect(await getDefaultContext()).toEqual({
+        app: "app_default",
+        appName: "Default App",
+        instance: "prod",
+      });
+
+      await clearDefaultContext();
+      expect(await getDefaultContext()).toBeUndefined();
+    });
+
+    test("falls back to the default context when the directory is not linked", async () => {
+      fetchApplicationSpy.mockResolvedValue(app);
+      await setDefaultContext({ app: "app_default", instance: "prod" });
+
+      await expect(resolveAppContext({ cwd: join(tempDir, "unlinked") })).resolves.toEqual({
+        appId: "app_default",
+        appLabel: "Default App",
+        instanceId: "ins_prod",
+        instanceLabel: "production",
+      });
+    });
+
+    test("--instance overrides the default instance", async () => {
+      fetchApplicationSpy.mockResolvedValue(app);
+      await setDefaultContext({ app: "app_default", instance: "prod" });
+
+      const ctx = await resolveAppContext({ cwd: join(tempDir, "unlinked"), instance: "dev" });
+      expect(ctx.instanceId).toBe("ins_dev");
+    });
+
+    test("linked profile takes precedence over the default context", async () => {
+      const projectDir = join(tempDir, "linked");
+      await setProfile(projectDir, {
+        workspaceId: "org_1",
+        appId: "app_linked",
+        instances: { development: "ins_linked_dev" },
+      });
+      await setDefaultContext({ app: "app_default" });
+
+      const ctx = await resolveAppContext({ cwd: projectDir });
+      expect(ctx.appId).toBe("app_linked");
+      expect(fetchApplicationSpy).not.toHaveBeenCalled();
+    });
+
+    test("throws NOT_LINKED when there is no link and no default", async () => {
+      await expect(resolveAppContext({ cwd: join(tempDir, "unlinked") })).rejects.toMatchObject({
+        code: "not_linked",
+      });
+    });
+  });
+
+  describe("invitation presets", () => {
+    test("set, list, and remove roundtrip", async () => {
+      await setInvitationPreset("contractor", {
+        role: "org:contractor",
+        publicMetadata: { type: "contractor" },
+      });
+      await setInvitationPreset("admin", { role: "org:admin" });
+
+      expect(await getInvitationPreset("contractor")).toEqual({
+        role: "org:contractor",
+        publicMetadata: { type: "contractor" },
+      });
+      expect(Object.keys(await listInvitationPresets())).toEqual(["contractor", "admin"]);
+
+      expect(await removeInvitationPreset("contractor")).toBe(true);
+      expect(await removeInvitationPreset("contractor")).toBe(false);
+      expect(await getInvitationPreset("contractor")).toBeUndefined();
+    });
+
+    test("drops malformed fields when reading", async () => {
+      await writeConfig({
+        profiles: {},
+        invitationPresets: {
+          vendor: { role: 42, redirectUrl: "https://app.test", publicMetadata: [1] },
+        },
+      } as unknown as Parameters<typeof writeConfig>[0]);
+
+      expect(await getInvitationPreset("vendor")).toEqual({ redirectUrl: "https://app.test" });
+    });
+  });
+
+  describe("relay entries", () => {
+    test("are stored in plaintext without a passphrase", async () => {
+      await setRelayEntry("default", { token: "c_Plaintext1" });
+
+      expect((await readConfig()).relay).toEqual({ default: { token: "c_Plaintext1" } });
+      expect(await getRelayEntry("default")).toEqual({ token: "c_Plaintext1" });
+    });
+
+    test("seal the token with the credentials passphrase", async () => {
+      process.env.CLERK_CREDENTIALS_PASSPHRASE = "test passphrase";
+      try {
+        await setRelayEntry("default", { token: "c_Sealed1234" });
+        const raw = await Bun.file(getConfigFile()).text();
+        expect(raw).toContain("clerk-sealed:v1:");
+        expect(raw).not.toContain("c_Sealed1234");
+        expect(await getRelayEntry("default")).toEqual({ token: "c_Sealed1234" });
+
+        delete process.env.CLERK_CREDENTIALS_PASSPHRASE;
+        await expect(getRelayEntry("default")).rejects.toThrow(/--token/);
+      } finally {
+        delete process.env.CLERK_CREDENTIALS_PASSPHRASE;
+      }
+    });
+  });
+
+  describe("setConfigPath", () => {
+    const originalConfigDir = process.env.CLERK_CONFIG_DIR;
+
+    afterEach(() => {
+      if (originalConfigDir === undefined) delete process.env.CLERK_CONFIG_DIR;
+      else process.env.CLERK_CONFIG_DIR = originalConfigDir;
+    });
+
+    test("a directory holds config.json and the credentials", async () => {
+      setConfigPath(join(tempDir, "ci"));
+
+      expect(getConfigFile()).toBe(join(tempDir, "ci", "config.json"));
+      expect(process.env.CLERK_CONFIG_DIR).toBe(join(tempDir, "ci"));
+      await writeConfig({ profiles: {} });
+      expect(await Bun.file(join(tempDir, "ci", "config.json")).exists()).toBe(true);
+    });
+
+    test("only --config scopes the keychain account, not CLERK_CONFIG_DIR", async () => {
+      const { getFlagConfigDir } = await import("./config-path.ts");
+      process.env.CLERK_CONFIG_DIR = join(tempDir, "env");
+      expect(getFlagConfigDir()).toBeUndefined();
+
+      setConfigPath(join(tempDir, "ci"));
+      expect(getFlagConfigDir()).toBe(join(tempDir, "ci"));
+    });
+
+    test("a .json file is used as the config file", () => {
+      setConfigPath(join(tempDir, "staging.json"));
+
+      expect(getConfigFile()).toBe(join(tempDir, "staging.json"));
+      expect(process.env.CLERK_CONFIG_DIR).toBe(tempDir);
+    });
+  });
 });
@@ -5,27 +5,18 @@
 
 import { dirname, join } from "node:path";
 import { mkdir } from "node:fs/promises";
-import { CONFIG_FILE } from "./constants.ts";
+import { getConfigFile } from "./config-path.ts";
 import { getCurrentEnvName } from "./environment.ts";
 import { getGitRepoIdentifier, getGitNormalizedRemote } from "./git.ts";
 import { CliError, ERROR_CODE } from "./errors.ts";
+import { withFileLock, writeFileAtomic } from "./file-lock.ts";
 import { withHomeFsAccess } from "./host-execution.ts";
 import { log } from "./log.ts";
+import { isRecord } from "./objects.ts";
+import { getPassphrase, isSealed, seal, unseal } from "./secret-box.ts";
 import type { Application, ApplicationInstance } from "./plapi.ts";
 
-let overrideConfigFile: string | undefined;
-
-/** Test-only: override the config file path. Pass undefined to reset. */
-export function _setConfigDir(dir: string | undefined): void {
-  overrideConfigFile = dir ? join(dir, "config.json") : undefined;
-}
-
-export function getConfigFile(): string {
-  return (
-    overrideConfigFile ??
-    (process.env.CLERK_CONFIG_DIR ? join(process.env.CLERK_CONFIG_DIR, "config.json") : CONFIG_FILE)
-  );
-}
+export { _setConfigDir, getConfigFile, setConfigPath } from "./config-path.ts";
 
 interface Auth {
   userId: string;
@@ -45,16 +36,54 @@ export function profileLabel(profile: Profile): string {
   return profile.appName ? `${profile.appName} (${profile.appId})` : profile.appId;
 }
 
-/** Persisted Svix relay state for `clerk webhooks listen`. */
+/**
+ * Persisted Svix relay state for `clerk webhooks listen`. The token is sealed
+ * on disk when a credentials passphrase is set, since anyone holding it can
+ * read the relayed webhooks.
+ */
 interface RelayEntry {
   token: string;
 }
 
+/**
+ * Default app/instance set by `clerk use`. Applied when no `--app` flag is
+ * passed and the working directory is not linked to a project.
+ */
+interface DefaultContext {
+  app: string;
+  appName?: string;
+  instance?: string;
+}
+
+/**
+ * Default organization set by `clerk use --org`. Org-scoped commands use it
+ * when their `[org]` argument is omitted.
+ */
+interface DefaultOrg {
+  id: string;
+  name?: string;
+}
+
+/**
+ * Named defaults for `clerk orgs invitations create --preset`, managed by
+ * `clerk orgs invitations presets`.
+ */
+interface InvitationPreset {
+  role?: string;
+  redirectUrl?: string;
+  publicMetadata?: Record<string, unknown>;
+}
+
 interface ClerkConfig {
   environment?: string;
   auth?: Record<string, Auth>;
   profiles: Record<string, Profile>;
   relay?: Record<string, RelayEntry>;
+  context?: DefaultContext;
+  org?: DefaultOrg;
+  invitationPresets?: Record<string, InvitationPreset>;
+  /** CLI preferences managed by `clerk settings`, keyed by setting name. */
+  settings?: Record<string, string>;
 }
 
 function defaultConfig(): ClerkConfig {
@@ -86,6 +115,48 @@ function migrateRawConfig(raw: Record<string, unknown>): ClerkConfig {
     config.relay = relay;
   }
 
+  if (raw.context && typeof raw.context === "object" && !Array.isArray(raw.context)) {
+    const context = raw.context as Record<string, unknown>;
+    if (typeof context.app === "string") {
+      config.context = {
+        app: context.app,
+        ...(typeof context.appName === "string" && { appName: context.appName }),
+        ...(typeof context.instance === "string" && { instance: context.instance }),
+      };
+    }
+  }
+
+  if (raw.org && typeof raw.org === "object" && !Array.isArray(raw.org)) {
+    const org = raw.org as Record<string, unknown>;
+    if (typeof org.id === "string") {
+      config.org = {
+        id: org.id,
+        ...(typeof org.name === "string" && { name: org.name }),
+      };
+    }
+  }
+
+  if (isRecord(raw.invitationPresets)) {
+    const presets: Record<string, InvitationPreset> = {};
+    for (const [name, val] of Object.entries(raw.invitationPresets)) {
+      if (!isRecord(val)) continue;
+      presets[name] = {
+        ...(typeof val.role === "string" && { role: val.role }),
+        ...(typeof val.redirectUrl === "string" && { redirectUrl: val.redirectUrl }),
+        ...(isRecord(val.publicMetadata) && { publicMetadata: val.publicMetadata }),
+      };
+    }
+    config.invitationPresets = presets;
+  }
+
+  if (raw.settings && typeof raw.settings === "object" && !Array.isArray(raw.settings)) {
+    const settings: Record<string, string> = {};
+    for (const [key, val] of Object.entries(raw.settings as Record<string, unknown>)) {
+      if (typeof val === "string") settings[key] = val;
+    }
+    config.settings = settings;
+  }
+
   if (raw.auth && typeof raw.auth === "object") {
     const auth = raw.auth as Record<string, unknown>;
     if (typeof auth.userId === "string") {
@@ -117,14 +188,40 @@ export async function readConfig(): Promise<ClerkConfig> {
   );
 }
 
+/**
+ * Replace the whole config file. Prefer {@link updateConfig}, which re-reads
+ * under the lock so a concurrent CLI run's changes aren't overwritten.
+ */
 export async function writeConfig(config: ClerkConfig): Promise<void> {
   const path = getConfigFile();
+  await withHomeFsAccess(
+    { operation: "write", target: path, label: "CLI config directory" },
+    () => writeConfigFile(path, config),
+  );
+}
+
+async function writeConfigFile(path: string, config: ClerkConfig): Promise<void> {
   log.debug(`config: writing ${path}`);
+  await mkdir(dirname(path), { recursive: true });
+  await writeFileAtomic(path, JSON.stringify(config, null, 2) + "\n");
+}
+
+/**
+ * Read-modify-write the config file while holding its lock, so parallel CLI
+ * invocations each apply their change to the latest file instead of the last
+ * writer clobbering the rest.
+ */
+export async function updateConfig(mutate: (config: ClerkConfig) => void): Promise<void> {
+  const path = getConfigFile();
   await withHomeFsAccess(
     { operation: "write", target: path, label: "CLI config directory" },
     async () => {
       await mkdir(dirname(path), { recursive: true });
-      await Bun.write(path, JSON.stringify(config, null, 2) + "\n");
+      await withFileLock(path, async () => {
+        const config = await readConfig();
+        mutate(config);
+        await writeConfigFile(path, config);
+      });
     },
   );
 }
@@ -136,21 +233,21 @@ export async function getAuth(): Promise<Auth | undefined> {
 }
 
 export async function setAuth(auth: Auth): Promise<void> {
-  const config = await readConfig();
-  if (!config.auth) config.auth = {};
-  config.auth[getCurrentEnvName()] = auth;
-  await writeConfig(config);
+  await updateConfig((config) => {
+    if (!config.auth) config.auth = {};
+    config.auth[getCurrentEnvName()] = auth;
+  });
 }
 
 export async function clearAuth(): Promise<void> {
-  const config = await readConfig();
-  if (config.auth) {
-    delete config.auth[getCurrentEnvName()];
-    if (Object.keys(config.auth).length === 0) {
-      delete config.auth;
+  await updateConfig((config) => {
+    if (config.auth) {
+      delete config.auth[getCurrentEnvName()];
+      if (Object.keys(config.auth).length === 0) {
+        delete config.auth;
+      }
     }
-  }
-  await writeConfig(config);
+  });
 }
 
 export async function getEnvironment(): Promise<string | undefined> {
@@ -159,9 +256,9 @@ export async function getEnvironment(): Promise<string | undefined> {
 }
 
 export async function setEnvironment(envName: string): Promise<void> {
-  const config = await readConfig();
-  config.environment = envName;
-  await writeConfig(config);
+  await updateConfig((config) => {
+    config.environment = envName;
+  });
 }
 
 export async function getProfile(path: string): Promise<Profile | undefined> {
@@ -170,24 +267,24 @@ export async function getProfile(path: string): Promise<Profile | undefined> {
 }
 
 export async function setProfile(path: string, profile: Profile): Promise<void> {
-  const config = await readConfig();
-  config.profiles[path] = profile;
-  await writeConfig(config);
+  await updateConfig((config) => {
+    config.profiles[path] = profile;
+  });
 }
 
 export async function removeProfile(path: string): Promise<void> {
-  const config = await readConfig();
-  delete config.profiles[path];
-  await writeConfig(config);
+  await updateConfig((config) => {
+    delete config.profiles[path];
+  });
 }
 
 export async function moveProfile(oldKey: string, newKey: string): Promise<void> {
-  const config = await readConfig();
-  const profile = config.profiles[oldKey];
-  if (!profile) return;
-  config.profiles[newKey] = profile;
-  delete config.profiles[oldKey];
-  await writeConfig(config);
+  await updateConfig((config) => {
+    const profile = config.profiles[oldKey];
+    if (!profile) return;
+    config.profiles[newKey] = profile;
+    delete config.profiles[oldKey];
+  });
 }
 
 export async function listProfiles(): Promise<Record<string, Profile>> {
@@ -197,14 +294,83 @@ export async function listProfiles(): Promise<Record<string, Profile>> {
 
 export async function getRelayEntry(key: string): Promise<RelayEntry | undefined> {
   const config = await readConfig();
-  return config.relay?.[key];
+  const entry = config.relay?.[key];
+  if (!entry || !isSealed(entry.token)) return entry;
+  const token = await unseal(
+    entry.token,
+    getPassphrase(),
+    "pass --token to `clerk webhooks listen` to replace the relay token",
+  );
+  return { ...entry, token };
 }
 
 export async function setRelayEntry(key: string, entry: RelayEntry): Promise<void> {
+  const passphrase = getPassphrase();
+  const token = passphrase ? await seal(entry.token, passphrase) : entry.token;
+  await updateConfig((config) => {
+    if (!config.relay) config.relay = {};
+    config.relay[key] = { ...entry, token };
+  });
+}
+
+export async function getDefaultContext(): Promise<DefaultContext | undefined> {
+  const config = await readConfig();
+  return config.context;
+}
+
+export async function setDefaultContext(context: DefaultContext): Promise<void> {
+  await updateConfig((config) => {
+    config.context = context;
+  });
+}
+
+export async function clearDefaultContext(): Promise<void> {
+  await updateConfig((config) => {
+    delete config.context;
+  });
+}
+
+export async function getDefaultOrg(): Promise<DefaultOrg | undefined> {
+  const config = await readConfig();
+  return config.org;
+}
+
+export async function setDefaultOrg(org: DefaultOrg): Promise<void> {
+  await updateConfig((config) => {
+    config.org = org;
+  });
+}
+
+export async function clearDefaultOrg(): Promise<void> {
+  await updateConfig((config) => {
+    delete config.org;
+  });
+}
+
+export async function listInvitationPresets(): Promise<Record<string, InvitationPreset>> {
   const config = await readConfig();
-  if (!config.relay) config.relay = {};
-  config.relay[key] = entry;
-  await writeConfig(config);
+  return config.invitationPresets ?? {};
+}
+
+export async function getInvitationPreset(name: string): Promise<InvitationPreset | undefined> {
+  return (await listInvitationPresets())[name];
+}
+
+export async function setInvitationPreset(name: string, preset: InvitationPreset): Promise<void> {
+  await updateConfig((config) => {
+    config.invitationPresets = { ...config.invitationPresets, [name]: preset };
+  });
+}
+
+/** Remove a preset. Returns `false` when there was no preset by that name. */
+export async function removeInvitationPreset(name: string): Promise<boolean> {
+  let removed = false;
+  await updateConfig((config) => {
+    if (!config.invitationPresets?.[name]) return;
+    delete config.invitationPresets[name];
+    removed = true;
+  });
+  return removed;
 }
 
 type ResolvedVia = "remote" | "git-common-dir" | "directory";
@@ -347,47 +513,63 @@ export function resolveFetchedApplicationInstance(
   };
 }
 
+async function resolveExplicitAppContext(
+  appId: string,
+  instance?: string,
+): Promise<{ appId: string; appLabel: string; instanceId: string; instanceLabel: string }> {
+  const { fetchApplication } = await import("./plapi.ts");
+  const app = await fetchApplication(appId);
+  const appLabel = app.name || appId;
+  const resolved = resolveFetchedApplicationInstance(appId, app, instance);
+  if (!resolved.found) {
+    throw new CliError(`Instance ${resolved.instanceId} not found in application ${appId}.`, {
+      code: ERROR_CODE.INSTANCE_NOT_FOUND,
+    });
+  }
+
+  return {
+    appId,
+    appLabel,
+    instanceId: resolved.instanceId,
+    instanceLabel: resolved.instanceLabel,
+  };
+}
+
 /**
- * Resolve app context from explicit flags or linked profile.
+ * Resolve app context from explicit flags, linked profile, or `clerk use` default.
  * This is the isomorphic resolution chain used by profile-dependent commands:
  *   1. Explicit --app flag (works from any directory)
  *   2. resolveProfile(cwd) (project-aware, existing behavior)
- *   3. Error with helpful message
+ *   3. Default context stored by `clerk use`
+ *   4. Error with helpful message
  */
 export async function resolveAppContext(
   options: AppContextOptions,
 ): Promise<{ appId: string; appLabel: string; instanceId: string; instanceLabel: string }> {
   if (options.app) {
-    const { fetchApplication } = await import("./plapi.ts");
-    const app = await fetchApplication(options.app);
-    const appLabel = app.name || options.app;
-    const resolved = resolveFetchedApplicationInstance(options.app, app, options.instance);
-    if (!resolved.found) {
-      throw new CliError(
-        `Instance ${resolved.instanceId} not found in application ${options.app}.`,
-        { code: ERROR_CODE.INSTANCE_NOT_FOUND },
-      );
-    }
-
-    return {
-      appId: options.app,
-      appLabel,
-      instanceId: resolved.instanceId,
-      instanceLabel: resolved.instanceLabel,
-    };
+    log.debug(`config: using --app ${options.app}`);
+    return resolveExplicitAppContext(options.app, options.instance);
   }
 
   const resolved = await resolveProfile(options.cwd ?? process.cwd());
   if (!resolved) {
+    const context = await getDefaultContext();
+    if (context) {
+      log.debug(`config: using default context ${context.app} from \`clerk use\``);
+      return resolveExplicitAppContext(context.app, options.instance ?? context.instance);
+    }
+
     throw new CliError(
       "No Clerk project linked to this directory.\n" +
         "Either:\n" +
         "  - Run `clerk link` from your project directory\n" +
-        "  - Pass --app <app_id> to target an app directly",
+        "  - Pass --app <app_id> to target an app directly\n" +
+        "  - Run `clerk use --app <app_id>` to set a default app",
       { code: ERROR_CODE.NOT_LINKED },
     );
   }
 
+  log.debug(`config: using profile ${resolved.path} (matched by ${resolved.resolvedVia})`);
   const instance = resolveInstanceId(resolved.profile, options.instance);
   return {
     appId: resolved.profile.appId,
@@ -397,4 +579,12 @@ export async function resolveAppContext(
   };
 }
 
-export type { Auth, Profile, ClerkConfig, AppContextOptions };
+export type {
+  Auth,
+  Profile,
+  ClerkConfig,
+  AppContextOptions,
+  DefaultContext,
+  DefaultOrg,
+  InvitationPreset,
+};
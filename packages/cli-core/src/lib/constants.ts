@@ -49,3 +49,6 @@ export const CACHE_TTL_MS = 60 * 60 * 1000; // 1 hour
 export const UPDATE_PACKAGE_NAME = "clerk";
 export const UPDATE_CACHE_FILE = join(CLERK_CACHE_DIR, "update-check.json");
 export const NPM_REGISTRY_URL = "https://registry.npmjs.org/";
+/** The published package's changelog, generated by changesets on each release. */
+export const CHANGELOG_URL =
+  "https://raw.githubusercontent.com/clerk/cli/main/packages/cli/CHANGELOG.md";
@@ -29,8 +29,15 @@ mock.module("./token-exchange.ts", () => ({
   refreshAccessToken: (...args: unknown[]) => mockRefreshAccessToken(...args),
 }));
 
-const { createOAuthSession, deleteToken, getStoredSession, getToken, getValidToken, storeToken } =
-  await import("./credential-store.ts");
+const {
+  createOAuthSession,
+  deleteToken,
+  getStoredSession,
+  getToken,
+  getValidToken,
+  hasStoredCredentials,
+  storeToken,
+} = await import("./credential-store.ts");
 
 async function writeLegacyToken(value: string): Promise<void> {
   await writeFile(join(tempDir, "credentials"), value, { mode: 0o600 });
@@ -72,6 +79,30 @@ describe("credential-store", () => {
     expect(await getStoredSession()).toEqual(session);
   });
 
+  test("encrypts the fallback file when a passphrase is set", async () => {
+    const session = {
+      accessToken: "sealed-access-token",
+      refreshToken: "sealed-refresh-token",
+      expiresAt: Date.now() + 60_000,
+      tokenType: "Bearer",
+    };
+    process.env.CLERK_CREDENTIALS_PASSPHRASE = "test passphrase";
+    try {
+      await storeToken(session);
+      const raw = await Bun.file(join(tempDir, "credentials")).text();
+      expect(raw).toStartWith("clerk-sealed:v1:");
+      expect(raw).not.toContain("sealed-access-token");
+      expect(await getStoredSession()).toEqual(session);
+
+      delete process.env.CLERK_CREDENTIALS_PASSPHRASE;
+      await expect(getToken()).rejects.toThrow(/CLERK_CREDENTIALS_PASSPHRASE/);
+      // Presence checks don't decrypt, so they work without the passphrase.
+      expect(await hasStoredCredentials()).toBe(true);
+    } finally {
+      delete process.env.CLERK_CREDENTIALS_PASSPHRASE;
+    }
+  });
+
   test("getValidToken uses stored expiresAt before attempting refresh", async () => {
     const session = {
       accessToken: "opaque-access-token",
@@ -1,15 +1,18 @@
 /**
  * Credential store for persisting the OAuth session.
  * Uses platform keyring as primary (via @napi-rs/keyring), falls back to a plaintext file with chmod 600.
+ * When `CLERK_CREDENTIALS_PASSPHRASE` is set, the fallback file is encrypted with it instead
+ * (see secret-box.ts) and only decrypted when a command reads the session.
  *
  * Sessions are stored per-environment so switching environments preserves auth state.
- * Keychain account: "oauth-access-token:<envName>"
+ * Keychain account: "oauth-access-token:<envName>", suffixed "@<dir>" under --config
  * File fallback: "credentials.<envName>"
  */
 
 import { setTimeout as sleep } from "node:timers/promises";
 import { dirname, join } from "node:path";
 import { mkdir, chmod, writeFile, unlink } from "node:fs/promises";
+import { getFlagConfigDir } from "./config-path.ts";
 import { CREDENTIALS_FILE } from "./constants.ts";
 import { getCurrentEnvName } from "./environment.ts";
 import { ApiError, AuthError, CliError, ERROR_CODE, errorMessage } from "./errors.ts";
@@ -19,6 +22,7 @@ import {
   withKeychainAccess,
 } from "./host-execution.ts";
 import { log } from "./log.ts";
+import { getPassphrase, isSealed, seal, unseal } from "./secret-box.ts";
 import { refreshAccessToken, type TokenResponse } from "./token-exchange.ts";
 import { resolveCliVersion } from "./version.ts";
 
@@ -37,13 +41,20 @@ export interface OAuthSession {
   tokenType: string;
 }
 
+/**
+ * Keychain entries are machine-wide, so a `--config` directory gets its own
+ * account; otherwise isolated setups would share one session. Plain
+ * `CLERK_CONFIG_DIR` keeps the unscoped account it has always used.
+ */
 function keychainAccount(): string {
   const envName = getCurrentEnvName();
-  if (envName === "production") return KEYCHAIN_ACCOUNT;
-  return `${KEYCHAIN_ACCOUNT}:${envName}`;
+  const account = envName === "production" ? KEYCHAIN_ACCOUNT : `${KEYCHAIN_ACCOUNT}:${envName}`;
+  const configDir = getFlagConfigDir();
+  return configDir ? `${account}@${configDir}` : account;
 }
 
-function credentialsFile(): string {
+/** Plaintext fallback file for the active environment, used when no keyring is available. */
+export function credentialsFile(): string {
   const basePath = process.env.CLERK_CONFIG_DIR
     ? join(process.env.CLERK_CONFIG_DIR, "credentials")
     : CREDENTIALS_FILE;
@@ -191,12 +202,14 @@ async function keyringDelete(): Promise<boolean> {
 
 async function fileStore(value: string): Promise<void> {
   const path = credentialsFile();
-  log.debug(`credentials: storing session in file ${path}`);
+  const passphrase = getPassphrase();
+  log.debug(`credentials: storing ${passphrase ? "encrypted " : ""}session in file ${path}`);
+  const contents = passphrase ? await seal(value, passphrase) : value;
   await withHomeFsAccess(
     { operation: "write", target: path, label: "credential fallback directory" },
     async () => {
       await mkdir(dirname(path), { recursive: true, mode: 0o700 });
-      await writeFile(path, value, { mode: 0o600 });
+      await writeFile(path, contents, { mode: 0o600 });
       // We keep the chmod because if the file permission had changed
       // `writeFile` wouldn't set it back to 0o600
       await chmod(path, 0o600);
@@ -302,7 +315,8 @@ function isInvalidGrant(error: unknown): boolean {
   );
 }
 
-async function readStoredValue(): Promise<string | null> {
+/** The stored value as kept on disk or in the keyring, still sealed if encrypted. */
+async function readRawStoredValue(): Promise<string | null> {
   if (tokenOverride !== undefined) return tokenOverride;
 
   const value = await keyringGet();
@@ -311,6 +325,12 @@ async function readStoredValue(): Promise<string | null> {
   return fileGet();
 }
 
+async function readStoredValue(): Promise<string | null> {
+  const value = await readRawStoredValue();
+  // Decrypt lazily: only commands that actually need the session pay for scrypt.
+  return value && isSealed(value) ? unseal(value, getPassphrase()) : value;
+}
+
 async function getValidAccessToken(session: OAuthSession): Promise<string> {
   if (!isExpiredSession(session)) {
     return session.accessToken;
@@ -432,19 +452,20 @@ export async function getStoredSession(): Promise<OAuthSession | null> {
   return parseStoredSession(value);
 }
 
+/**
+ * Whether a session is stored, without decrypting it: presence checks must
+ * work (and stay cheap) even when the passphrase isn't set.
+ */
 export async function hasStoredCredentials(): Promise<boolean> {
-  return (await readStoredValue()) !== null;
+  return (await readRawStoredValue()) !== null;
 }
 
 export async function getValidToken(): Promise<string | null> {
-  const session = await getStoredSession();
-  if (!session) {
-    if (await hasStoredCredentials()) {
-      throw sessionExpiredError();
-    }
-    return null;
-  }
+  const value = await readStoredValue();
+  if (!value) return null;
 
+  const session = parseStoredSession(value);
+  if (!session) throw sessionExpiredError();
   return getValidAccessToken(session);
 }
 
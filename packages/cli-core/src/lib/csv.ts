@@ -0,0 +1,60 @@
+/**
+ * CSV for the export and import commands: a header row, then one row per
+ * record, with RFC 4180 quoting for cells that contain commas, quotes, or
+ * newlines.
+ */
+
+function csvCell(value: string): string {
+  return /[",\r\n]/.test(value) ? `"${value.replaceAll('"', '""')}"` : value;
+}
+
+export function formatCsv<T extends Record<string, string>>(
+  header: readonly (keyof T & string)[],
+  rows: readonly T[],
+): string {
+  const lines = [header.join(",")];
+  for (const row of rows) lines.push(header.map((key) => csvCell(row[key])).join(","));
+  return `${lines.join("\n")}\n`;
+}
+
+/**
+ * Parse RFC 4180 CSV into rows of cells. Quoted cells may hold commas,
+ * doubled quotes, and line breaks; blank lines are skipped.
+ */
+export function parseCsv(text: string): string[][] {
+  const rows: string[][] = [];
+  let row: string[] = [];
+  let cell = "";
+  let quoted = false;
+  const endRow = () => {
+    row.push(cell);
+    if (row.some((value) => value !== "")) rows.push(row);
+    row = [];
+    cell = "";
+  };
+  for (let i = 0; i < text.length; i++) {
+    const char = text[i];
+    if (quoted) {
+      if (char === '"' && text[i + 1] === '"') {
+        cell += '"';
+        i++;
+      } else if (char === '"') {
+        quoted = false;
+      } else {
+        cell += char;
+      }
+    } else if (char === '"') {
+      quoted = true;
+    } else if (char === ",") {
+      row.push(cell);
+      cell = "";
+    } else if (char === "\n" || char === "\r") {
+      if (char === "\r" && text[i + 1] === "\n") i++;
+      endRow();
+    } else {
+      cell += char;
+    }
+  }
+  endRow();
+  return rows;
+}
@@ -4,6 +4,7 @@
  */
 
 import { join } from "node:path";
+import { log } from "./log.ts";
 
 /**
  * Env file candidates in Next.js/Vite development load order (highest priority first).
@@ -23,8 +24,12 @@ export const ENV_FILE_CANDIDATES = [
  */
 export async function findExistingEnvFile(cwd: string, fallback: string): Promise<string> {
   for (const candidate of ENV_FILE_CANDIDATES) {
-    if (await Bun.file(join(cwd, candidate)).exists()) return candidate;
+    if (await Bun.file(join(cwd, candidate)).exists()) {
+      log.debug(`dotenv: found ${join(cwd, candidate)}`);
+      return candidate;
+    }
   }
+  log.debug(`dotenv: no env file in ${cwd}; will use ${fallback}`);
   return fallback;
 }
 
@@ -0,0 +1,109 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import {
+  DryRunRequest,
+  bestEffort,
+  describeBody,
+  interceptDryRun,
+  setRequestDryRun,
+} from "./dry-run.ts";
+import { setShowSecrets } from "./redact.ts";
+
+describe("describeBody", () => {
+  afterEach(() => {
+    setShowSecrets(false);
+  });
+
+  test("parses JSON and masks credential fields at any depth", () => {
+    const body = JSON.stringify({
+      email_address: ["jane@example.com"],
+      password: "hunter22",
+      settings: { signing_secret: "s3cret", enabled: true },
+    });
+    expect(describeBody(body)).toEqual({
+      email_address: ["jane@example.com"],
+      password: "********",
+      settings: { signing_secret: "********", enabled: true },
+    });
+  });
+
+  test("--show-secrets keeps credential fields", () => {
+    setShowSecrets(true);
+    expect(describeBody(JSON.stringify({ password: "hunter22" }))).toEqual({
+      password: "hunter22",
+    });
+  });
+
+  test("passes non-JSON text through and names other body types", () => {
+    expect(describeBody("a=1&b=2")).toBe("a=1&b=2");
+    expect(describeBody(new FormData())).toBe("<FormData body>");
+    expect(describeBody(undefined)).toBeUndefined();
+  });
+});
+
+describe("interceptDryRun", () => {
+  afterEach(() => {
+    setRequestDryRun(false);
+  });
+
+  test("does nothing when dry-run mode is off", () => {
+    const url = "https://api.clerk.test/v1/users";
+    expect(() => interceptDryRun("bapi", "POST", url, "{}")).not.toThrow();
+  });
+
+  test("throws the request for mutating Clerk API calls", () => {
+    setRequestDryRun(true);
+    let thrown: unknown;
+    try {
+      interceptDryRun("bapi", "patch", "https://api.clerk.test/v1/users/u_1", '{"first_name":"A"}');
+    } catch (error) {
+      thrown = error;
+    }
+    expect(thrown).toBeInstanceOf(DryRunRequest);
+    expect(thrown).toMatchObject({
+      method: "PATCH",
+      url: "https://api.clerk.test/v1/users/u_1",
+      body: { first_name: "A" },
+    });
+  });
+
+  test.each([
+    ["bapi", "GET", "https://api.clerk.test/v1/users"],
+    ["bapi", "POST", "https://api.clerk.test/v1/users?dry_run=true"],
+    ["oauth", "POST", "https://clerk.test/oauth/token"],
+  ])("lets %s %s %s through", (tag, method, url) => {
+    setRequestDryRun(true);
+    expect(() => interceptDryRun(tag, method, url, undefined)).not.toThrow();
+  });
+});
+
+describe("bestEffort", () => {
+  test("hands a failure to onError and resolves undefined", async () => {
+    const errors: unknown[] = [];
+    const failure = new Error("not enabled");
+
+    const value = await bestEffort(
+      async () => {
+        throw failure;
+      },
+      (error) => errors.push(error),
+    );
+
+    expect(value).toBeUndefined();
+    expect(errors).toEqual([failure]);
+  });
+
+  test("lets a dry-run stop through", async () => {
+    const stop = new DryRunRequest("POST", "https://api.clerk.com/v1/sessions/sess_1/tokens");
+    const errors: unknown[] = [];
+
+    await expect(
+      bestEffort(
+        async () => {
+          throw stop;
+        },
+        (error) => errors.push(error),
+      ),
+    ).rejects.toBe(stop);
+    expect(errors).toEqual([]);
+  });
+});
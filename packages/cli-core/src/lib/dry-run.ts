@@ -0,0 +1,101 @@
+/**
+ * Request dry-run mode: with the global `--dry-run`, the first mutating
+ * request to a Clerk API is printed (method, URL, and JSON body) instead of
+ * sent, and the command stops there with exit code 0. Reads still go out, so
+ * the command gets far enough to build the real request.
+ *
+ * Commands that define their own `--dry-run` (a plan, a diff, a server-side
+ * validation) keep that behavior; the preAction hook hands the flag to them
+ * instead of turning this mode on.
+ */
+
+import { isShowingSecrets } from "./redact.ts";
+import { isMutatingApiRequest } from "./read-only.ts";
+
+let requestDryRun = false;
+
+export function setRequestDryRun(enabled: boolean): void {
+  requestDryRun = enabled;
+}
+
+export function isRequestDryRun(): boolean {
+  return requestDryRun;
+}
+
+/** JSON body fields that hold credentials rather than data. */
+const SENSITIVE_FIELD = /password|secret|token|private_key/i;
+const FIELD_MASK = "********";
+
+/**
+ * Thrown by `loggedFetch` in place of sending the request. `runProgram` prints
+ * it once spinners have settled and exits cleanly. Code that swallows errors
+ * should do so through {@link bestEffort}, which lets it through.
+ */
+export class DryRunRequest extends Error {
+  constructor(
+    public readonly method: string,
+    public readonly url: string,
+    public readonly body?: unknown,
+  ) {
+    super(`Dry run: ${method} ${url} was not sent`);
+    this.name = "DryRunRequest";
+  }
+}
+
+/**
+ * Run an optional lookup, such as a count or related record added to a view,
+ * and hand any failure to `onError` instead of failing the command. A dry-run
+ * stop isn't a failed lookup, so it still propagates and ends the command.
+ */
+export async function bestEffort<T>(
+  fetch: () => Promise<T>,
+  onError: (error: unknown) => void,
+): Promise<T | undefined> {
+  try {
+    return await fetch();
+  } catch (error) {
+    if (error instanceof DryRunRequest) throw error;
+    onError(error);
+    return undefined;
+  }
+}
+
+function maskSensitiveFields(value: unknown): unknown {
+  if (Array.isArray(value)) return value.map(maskSensitiveFields);
+  if (value === null || typeof value !== "object") return value;
+  return Object.fromEntries(
+    Object.entries(value).map(([key, field]) => [
+      key,
+      SENSITIVE_FIELD.test(key) && field !== null && typeof field !== "object"
+        ? FIELD_MASK
+        : maskSensitiveFields(field),
+    ]),
+  );
+}
+
+/**
+ * The request body as it would be sent: parsed JSON with credential fields
+ * masked (unless `--show-secrets`), the raw text otherwise, or a placeholder
+ * for form and binary uploads.
+ */
+export function describeBody(body: BodyInit | null | undefined): unknown {
+  if (body === null || body === undefined) return undefined;
+  if (typeof body !== "string") return `<${body.constructor.name} body>`;
+  try {
+    const parsed: unknown = JSON.parse(body);
+    return isShowingSecrets() ? parsed : maskSensitiveFields(parsed);
+  } catch {
+    return body;
+  }
+}
+
+/** Throw a {@link DryRunRequest} when dry-run mode is on and the request would mutate state. */
+export function interceptDryRun(
+  tag: string,
+  method: string,
+  url: string,
+  body: BodyInit | null | undefined,
+): void {
+  if (!requestDryRun || !isMutatingApiRequest(tag, method, url)) return;
+  throw new DryRunRequest(method.toUpperCase(), url, describeBody(body));
+}
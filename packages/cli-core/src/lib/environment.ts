@@ -95,9 +95,20 @@ export function setCurrentEnv(name: string): void {
     throw new Error(`Unknown environment "${name}". Available environments: ${available}`);
   }
   currentEnvName = name;
-  const profile = profiles[name]!;
-  const platformApiUrl = process.env.CLERK_PLATFORM_API_URL ?? profile.platformApiUrl;
-  log.debug(`env: active environment is "${name}" (platformApiUrl=${platformApiUrl})`);
+  logActiveEnv();
+}
+
+/** Trace the active environment and where each API base URL comes from (`--verbose`). */
+export function logActiveEnv(): void {
+  log.debug(`env: active environment is "${getCurrentEnvName()}"`);
+  const urls = [
+    ["platformApiUrl", "CLERK_PLATFORM_API_URL", getPlapiBaseUrl()],
+    ["backendApiUrl", "CLERK_BACKEND_API_URL", getBapiBaseUrl()],
+  ] as const;
+  for (const [name, envVar, url] of urls) {
+    const source = process.env[envVar] ? envVar : "environment profile";
+    log.debug(`env: ${name}=${url} (from ${source})`);
+  }
 }
 
 /** Get the name of the active environment. Defaults to "production". */
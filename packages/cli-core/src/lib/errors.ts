@@ -78,6 +78,14 @@ export const ERROR_CODE = {
   MCP_CLIENT_CLI_NOT_FOUND: "mcp_client_cli_not_found",
   /** The target client's own CLI exited non-zero or timed out while registering/removing the entry. */
   MCP_CLIENT_CLI_FAILED: "mcp_client_cli_failed",
+  /** A mutating request was blocked because read-only mode is enabled. */
+  READ_ONLY: "read_only",
+  /** Requests to a host were stopped after repeated server errors. */
+  CIRCUIT_OPEN: "circuit_open",
+  /** The named domain is not attached to the application. */
+  DOMAIN_NOT_FOUND: "domain_not_found",
+  /** Another CLI process held a file lock for longer than the wait timeout. */
+  FILE_LOCKED: "file_locked",
 } as const;
 
 export type ErrorCode = (typeof ERROR_CODE)[keyof typeof ERROR_CODE];
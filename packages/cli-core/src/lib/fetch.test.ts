@@ -1,11 +1,15 @@
 import { test, expect, describe, afterEach, mock } from "bun:test";
+import { DryRunRequest, setRequestDryRun } from "./dry-run.ts";
 import { loggedFetch } from "./fetch.ts";
+import { setReadOnly } from "./read-only.ts";
 
 const originalFetch = globalThis.fetch;
 
 describe("loggedFetch", () => {
   afterEach(() => {
     globalThis.fetch = originalFetch;
+    setReadOnly(false);
+    setRequestDryRun(false);
   });
 
   test("sets a Clerk-CLI User-Agent on outbound requests", async () => {
@@ -41,4 +45,31 @@ describe("loggedFetch", () => {
     expect(init.headers.get("Authorization")).toBe("Bearer abc");
     expect(init.headers.get("User-Agent")).toMatch(/^Clerk-CLI\//);
   });
+
+  test("refuses mutating Clerk API requests in read-only mode without calling fetch", async () => {
+    const fetchMock = mock(async () => new Response("ok", { status: 200 }));
+    globalThis.fetch = fetchMock as unknown as typeof fetch;
+    setReadOnly(true);
+
+    await expect(
+      loggedFetch("https://api.clerk.test/v1/users", { tag: "bapi", method: "POST" }),
+    ).rejects.toMatchObject({ code: "read_only" });
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  test("stops at the first mutating Clerk API request in dry-run mode", async () => {
+    const fetchMock = mock(async () => new Response("[]", { status: 200 }));
+    globalThis.fetch = fetchMock as unknown as typeof fetch;
+    setRequestDryRun(true);
+
+    await loggedFetch("https://api.clerk.test/v1/users", { tag: "bapi" });
+    await expect(
+      loggedFetch("https://api.clerk.test/v1/users", {
+        tag: "bapi",
+        method: "POST",
+        body: JSON.stringify({ email_address: ["jane@example.com"] }),
+      }),
+    ).rejects.toBeInstanceOf(DryRunRequest);
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
 });
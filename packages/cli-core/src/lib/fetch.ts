@@ -6,11 +6,17 @@
  * All outbound HTTP calls in library code must go through this helper so
  * that `--verbose` surfaces the URL, method, and server response body for
  * every network error. See `.claude/rules/debug-logging.md`.
+ *
+ * Requests to Clerk APIs are retried on rate limits and gateway errors; see
+ * `./http-retry.ts`.
  */
 
 import { log } from "./log.ts";
 import { withNetworkAccess } from "./host-execution.ts";
 import { buildUserAgent } from "./user-agent.ts";
+import { assertRequestAllowed } from "./read-only.ts";
+import { interceptDryRun } from "./dry-run.ts";
+import { withRetries } from "./http-retry.ts";
 
 const USER_AGENT = buildUserAgent();
 
@@ -32,17 +38,21 @@ export async function loggedFetch(url: URL | string, options: LoggedFetchInit):
   const { tag, ...init } = options;
   const method = init.method ?? "GET";
   const urlStr = url.toString();
+  interceptDryRun(tag, method, urlStr, init.body);
+  assertRequestAllowed(tag, method, urlStr);
   const headers = new Headers(init.headers);
   if (!headers.has("user-agent")) headers.set("User-Agent", USER_AGENT);
-  log.debug(`${tag}: ${method} ${urlStr}`);
-  const response = await withNetworkAccess(
-    { operation: "connect", target: urlStr, label: tag },
-    async () => fetch(url, { ...init, headers }),
-  );
-  if (!response.ok) {
-    // Clone so the caller can still consume the body for error construction.
-    const body = await response.clone().text();
-    log.debug(`${tag}: ${response.status} ${method} ${urlStr} — ${body}`);
-  }
-  return response;
+  return withRetries(tag, method, urlStr, init, async () => {
+    log.debug(`${tag}: ${method} ${urlStr}`);
+    const response = await withNetworkAccess(
+      { operation: "connect", target: urlStr, label: tag },
+      async () => fetch(url, { ...init, headers }),
+    );
+    if (!response.ok) {
+      // Clone so the caller can still consume the body for error construction.
+      const body = await response.clone().text();
+      log.debug(`${tag}: ${response.status} ${method} ${urlStr} — ${body}`);
+    }
+    return response;
+  });
 }
@@ -0,0 +1,46 @@
+import { test, expect, describe } from "bun:test";
+import { parseFieldsOption, pickFields } from "./fields.ts";
+
+const user = {
+  id: "user_1",
+  first_name: "Ada",
+  email_addresses: [
+    { id: "idn_1", email_address: "ada@example.com", verification: { status: "verified" } },
+    { id: "idn_2", email_address: "ada@work.example", verification: null },
+  ],
+  public_metadata: { plan: "pro", seats: 3 },
+};
+
+describe("fields", () => {
+  test("keeps only the named top-level keys", () => {
+    expect(pickFields(user, parseFieldsOption("id,first_name"))).toEqual({
+      id: "user_1",
+      first_name: "Ada",
+    });
+  });
+
+  test("dotted paths map over arrays and merge siblings", () => {
+    const tree = parseFieldsOption("id, email_addresses.email_address, email_addresses.id");
+    expect(pickFields(user, tree)).toEqual({
+      id: "user_1",
+      email_addresses: [
+        { email_address: "ada@example.com", id: "idn_1" },
+        { email_address: "ada@work.example", id: "idn_2" },
+      ],
+    });
+  });
+
+  test("a whole-field path wins over a nested one", () => {
+    const tree = parseFieldsOption("public_metadata,public_metadata.plan");
+    expect(pickFields(user, tree)).toEqual({ public_metadata: { plan: "pro", seats: 3 } });
+  });
+
+  test("missing fields are omitted", () => {
+    expect(pickFields(user, parseFieldsOption("id,nope"))).toEqual({ id: "user_1" });
+  });
+
+  test("rejects empty and malformed values", () => {
+    expect(() => parseFieldsOption(" , ")).toThrow("--fields needs at least one field name");
+    expect(() => parseFieldsOption("email_addresses..id")).toThrow("Invalid --fields entry");
+  });
+});
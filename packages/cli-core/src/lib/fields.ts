@@ -0,0 +1,54 @@
+/**
+ * `--fields` projection for JSON output: keep only the named keys of each
+ * record so large exports carry just what the caller needs. Dotted paths
+ * reach into nested objects and map over arrays, e.g.
+ * `id,email_addresses.email_address`.
+ */
+
+import { throwUsageError } from "./errors.ts";
+
+type FieldTree = Map<string, FieldTree | true>;
+
+/** Parse a comma-separated `--fields` value into a projection tree. */
+export function parseFieldsOption(value: string, flag = "--fields"): FieldTree {
+  const tree: FieldTree = new Map();
+  const paths = value
+    .split(",")
+    .map((path) => path.trim())
+    .filter(Boolean);
+  if (paths.length === 0) throwUsageError(`${flag} needs at least one field name.`);
+
+  for (const path of paths) {
+    const segments = path.split(".");
+    if (segments.some((segment) => !segment)) {
+      throwUsageError(`Invalid ${flag} entry "${path}". Use field or field.subfield.`);
+    }
+    let node = tree;
+    for (const [index, segment] of segments.entries()) {
+      const existing = node.get(segment);
+      if (existing === true) break; // A shorter path already keeps the whole value.
+      if (index === segments.length - 1) {
+        node.set(segment, true);
+        break;
+      }
+      const child: FieldTree = existing ?? new Map();
+      node.set(segment, child);
+      node = child;
+    }
+  }
+  return tree;
+}
+
+/** Keep only the fields in `tree`. Keys absent from the record are left out. */
+export function pickFields(value: unknown, tree: FieldTree): unknown {
+  if (Array.isArray(value)) return value.map((item) => pickFields(item, tree));
+  if (!value || typeof value !== "object") return value;
+
+  const record = value as Record<string, unknown>;
+  const picked: Record<string, unknown> = {};
+  for (const [key, child] of tree) {
+    if (!Object.hasOwn(record, key)) continue;
+    picked[key] = child === true ? record[key] : pickFields(record[key], child);
+  }
+  return picked;
+}
@@ -0,0 +1,81 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { chmod, mkdtemp, readdir, rm, stat, utimes, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { withFileLock, writeFileAtomic } from "./file-lock.ts";
+
+describe("file-lock", () => {
+  let tempDir: string;
+  let path: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-file-lock-test-"));
+    path = join(tempDir, "config.json");
+  });
+
+  afterEach(async () => {
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("serializes critical sections on the same file", async () => {
+    const events: string[] = [];
+    await Promise.all(
+      ["a", "b", "c"].map((name) =>
+        withFileLock(path, async () => {
+          events.push(`${name}:start`);
+          await Bun.sleep(5);
+          events.push(`${name}:end`);
+        }),
+      ),
+    );
+    for (let i = 0; i < events.length; i += 2) {
+      expect(events[i]!.replace(":start", "")).toBe(events[i + 1]!.replace(":end", ""));
+    }
+    expect(await Bun.file(`${path}.lock`).exists()).toBe(false);
+  });
+
+  test("releases the lock when the callback throws", async () => {
+    await expect(
+      withFileLock(path, async () => {
+        throw new Error("boom");
+      }),
+    ).rejects.toThrow("boom");
+    expect(await withFileLock(path, async () => "ok")).toBe("ok");
+  });
+
+  test("breaks a stale lock left by a crashed process", async () => {
+    await writeFile(`${path}.lock`, "99999\n");
+    const old = new Date(Date.now() - 60_000);
+    await utimes(`${path}.lock`, old, old);
+    expect(await withFileLock(path, async () => "ran")).toBe("ran");
+  });
+
+  test.skipIf(process.platform === "win32")(
+    "breaks a fresh lock whose holder has exited",
+    async () => {
+      const { pid } = Bun.spawnSync(["true"]);
+      await writeFile(`${path}.lock`, `${pid}\n`);
+      const started = Date.now();
+      expect(await withFileLock(path, async () => "ran")).toBe("ran");
+      expect(Date.now() - started).toBeLessThan(1_000);
+    },
+  );
+
+  test("waits on a fresh lock held by a live process", async () => {
+    await writeFile(`${path}.lock`, `${process.ppid}\n`);
+    setTimeout(() => void rm(`${path}.lock`, { force: true }), 50);
+    expect(await withFileLock(path, async () => "ran")).toBe("ran");
+  });
+
+  test.skipIf(process.platform === "win32")(
+    "atomic writes keep the existing mode and leave no temp files",
+    async () => {
+      await writeFile(path, "old");
+      await chmod(path, 0o600);
+      await writeFileAtomic(path, "new");
+      expect(await Bun.file(path).text()).toBe("new");
+      expect((await stat(path)).mode & 0o777).toBe(0o600);
+      expect(await readdir(tempDir)).toEqual(["config.json"]);
+    },
+  );
+});
@@ -0,0 +1,130 @@
+/**
+ * Advisory cross-process locking and atomic replacement for small files the
+ * CLI rewrites in full (the config file). Parallel invocations (CI matrices,
+ * several terminals) serialize their read-modify-write cycles through a
+ * `<file>.lock` sibling instead of letting the last writer win.
+ *
+ * The lock is advisory: it only excludes other callers of {@link withFileLock}.
+ * A lock left behind by a crashed process is broken as soon as its PID is
+ * gone, or once it is older than {@link STALE_LOCK_MS} when the PID can't be
+ * checked (e.g. another machine sharing the directory).
+ */
+
+import { open, readFile, rename, rm, stat, writeFile } from "node:fs/promises";
+import { dirname, basename, join } from "node:path";
+import { CliError, ERROR_CODE } from "./errors.ts";
+import { log } from "./log.ts";
+import { sleep } from "./sleep.ts";
+
+/** How long to wait for another process before giving up. */
+const LOCK_TIMEOUT_MS = 10_000;
+/**
+ * Locks older than this belong to a process that died mid-write. Config writes
+ * take milliseconds, and this must stay below {@link LOCK_TIMEOUT_MS} so a
+ * waiter breaks a dead holder's lock instead of timing out on it.
+ */
+const STALE_LOCK_MS = 5_000;
+const RETRY_DELAY_MS = 25;
+const MAX_RETRY_DELAY_MS = 250;
+
+function lockPath(path: string): string {
+  return `${path}.lock`;
+}
+
+/** Whether `pid` names a running process. EPERM means it runs as another user. */
+function isProcessAlive(pid: number): boolean {
+  try {
+    process.kill(pid, 0);
+    return true;
+  } catch (error) {
+    return (error as NodeJS.ErrnoException).code === "EPERM";
+  }
+}
+
+async function isStale(lock: string): Promise<boolean> {
+  try {
+    const pid = Number.parseInt(await readFile(lock, "utf8"), 10);
+    // The holder writes its PID right after creating the file, so an empty
+    // lock is only trusted for as long as a live one would be.
+    if (pid > 0 && pid !== process.pid && !isProcessAlive(pid)) return true;
+    return Date.now() - (await stat(lock)).mtimeMs > STALE_LOCK_MS;
+  } catch {
+    // Vanished between our attempt and the read: retry right away.
+    return true;
+  }
+}
+
+async function acquire(path: string): Promise<() => Promise<void>> {
+  const lock = lockPath(path);
+  const deadline = Date.now() + LOCK_TIMEOUT_MS;
+  let delay = RETRY_DELAY_MS;
+  while (true) {
+    try {
+      const handle = await open(lock, "wx");
+      await handle.writeFile(`${process.pid}\n`);
+      await handle.close();
+      return () => rm(lock, { force: true });
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code !== "EEXIST") throw error;
+    }
+    if (await isStale(lock)) {
+      log.debug(`file-lock: breaking stale lock ${lock}`);
+      await rm(lock, { force: true });
+      continue;
+    }
+    if (Date.now() >= deadline) {
+      throw new CliError(
+        `Timed out waiting for another clerk process to release ${lock}. ` +
+          "If no other clerk command is running, delete the lock file and retry.",
+        { code: ERROR_CODE.FILE_LOCKED },
+      );
+    }
+    await sleep(delay + Math.random() * delay);
+    delay = Math.min(delay * 2, MAX_RETRY_DELAY_MS);
+  }
+}
+
+// Callers in this process queue here first, so they don't spin on a lock
+// their own sibling holds.
+const inProcess = new Map<string, Promise<unknown>>();
+
+/** Run `fn` while holding the advisory lock for `path`. */
+export async function withFileLock<T>(path: string, fn: () => Promise<T>): Promise<T> {
+  const previous = inProcess.get(path) ?? Promise.resolve();
+  const run = previous
+    .catch(() => {})
+    .then(async () => {
+      const release = await acquire(path);
+      try {
+        return await fn();
+      } finally {
+        await release();
+      }
+    });
+  inProcess.set(path, run);
+  try {
+    return await run;
+  } finally {
+    if (inProcess.get(path) === run) inProcess.delete(path);
+  }
+}
+
+/**
+ * Replace `path` with `contents` via a temp file and rename, so readers see
+ * either the old file or the new one, never a partial write. An existing
+ * file's permission bits carry over.
+ */
+export async function writeFileAtomic(path: string, contents: string): Promise<void> {
+  const temp = join(dirname(path), `.${basename(path)}.${process.pid}.${Date.now()}.tmp`);
+  const mode = await stat(path).then(
+    (s) => s.mode & 0o777,
+    () => undefined,
+  );
+  try {
+    await writeFile(temp, contents, mode === undefined ? undefined : { mode });
+    await rename(temp, path);
+  } catch (error) {
+    await rm(temp, { force: true });
+    throw error;
+  }
+}
@@ -38,6 +38,11 @@ Command.prototype.setExamples = function (examples: Example[]) {
   return this;
 };
 
+/** Examples registered on `cmd` with `.setExamples()`, or `[]`. */
+export function getExamples(cmd: object): Example[] {
+  return examplesMap.get(cmd) ?? [];
+}
+
 /**
  * Custom help formatter with three improvements over Commander defaults:
  *
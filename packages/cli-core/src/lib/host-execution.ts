@@ -2,7 +2,8 @@ import { randomUUID } from "node:crypto";
 import { mkdir, unlink, writeFile } from "node:fs/promises";
 import { dirname, join } from "node:path";
 import { isAgent } from "../mode.ts";
-import { CONFIG_FILE, CREDENTIALS_FILE } from "./constants.ts";
+import { getConfigFile } from "./config-path.ts";
+import { CREDENTIALS_FILE } from "./constants.ts";
 import { errorMessage } from "./errors.ts";
 import { log } from "./log.ts";
 
@@ -37,7 +38,8 @@ let warnedAboutSandbox = false;
 
 function getProbeTargets(): Array<{ label: string; dir: string }> {
   const clerkConfigDir = process.env.CLERK_CONFIG_DIR;
-  const configFile = clerkConfigDir ? join(clerkConfigDir, "config.json") : CONFIG_FILE;
+  // Follows `--config`, including one that names a `.json` file.
+  const configFile = getConfigFile();
   const credentialsFile = clerkConfigDir ? join(clerkConfigDir, "credentials") : CREDENTIALS_FILE;
 
   return [
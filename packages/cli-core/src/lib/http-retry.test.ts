@@ -0,0 +1,146 @@
+import { test, expect, describe, afterEach, beforeEach, mock } from "bun:test";
+import { loggedFetch } from "./fetch.ts";
+import {
+  computeRetryDelay,
+  parseRetryAfter,
+  resetHttpRetryState,
+  setRetryPolicy,
+} from "./http-retry.ts";
+
+const originalFetch = globalThis.fetch;
+const API_URL = "https://api.clerk.test/v1/users";
+
+function stubResponses(...responses: (Response | Error)[]) {
+  const fetchMock = mock(async () => {
+    const next = responses.length > 1 ? responses.shift()! : responses[0]!;
+    if (next instanceof Error) throw next;
+    return next.clone();
+  });
+  globalThis.fetch = fetchMock as unknown as typeof fetch;
+  return fetchMock;
+}
+
+describe("parseRetryAfter", () => {
+  test("parses delta-seconds", () => {
+    expect(parseRetryAfter("3")).toBe(3000);
+  });
+
+  test("parses an HTTP date relative to now", () => {
+    const now = Date.parse("Wed, 21 Oct 2026 07:28:00 GMT");
+    expect(parseRetryAfter("Wed, 21 Oct 2026 07:28:05 GMT", now)).toBe(5000);
+  });
+
+  test.each([null, "", "soon"])("returns undefined for %p", (value) => {
+    expect(parseRetryAfter(value)).toBeUndefined();
+  });
+});
+
+describe("computeRetryDelay", () => {
+  test("backs off exponentially with jitter, capped at the max delay", () => {
+    expect(computeRetryDelay(0, undefined, 10_000, () => 0)).toBe(250);
+    expect(computeRetryDelay(2, undefined, 10_000, () => 0)).toBe(1000);
+    expect(computeRetryDelay(10, undefined, 10_000, () => 0)).toBe(10_000);
+  });
+
+  test("uses Retry-After from the response", () => {
+    const response = new Response(null, { status: 429, headers: { "Retry-After": "2" } });
+    expect(computeRetryDelay(0, response, 10_000)).toBe(2000);
+  });
+
+  test("gives up when Retry-After exceeds the max delay", () => {
+    const response = new Response(null, { status: 429, headers: { "Retry-After": "60" } });
+    expect(computeRetryDelay(0, response, 10_000)).toBeUndefined();
+  });
+});
+
+describe("loggedFetch retries", () => {
+  beforeEach(() => {
+    setRetryPolicy({ retries: 2, maxDelayMs: 0 });
+  });
+
+  afterEach(() => {
+    globalThis.fetch = originalFetch;
+    resetHttpRetryState();
+  });
+
+  test("retries a rate-limited request until it succeeds", async () => {
+    const fetchMock = stubResponses(
+      new Response("slow down", { status: 429 }),
+      new Response("ok", { status: 200 }),
+    );
+
+    const response = await loggedFetch(API_URL, { tag: "bapi", method: "POST", body: "{}" });
+
+    expect(response.status).toBe(200);
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+  });
+
+  test("retries gateway errors for idempotent methods only", async () => {
+    const getMock = stubResponses(new Response("", { status: 503 }));
+    expect((await loggedFetch(API_URL, { tag: "bapi" })).status).toBe(503);
+    expect(getMock).toHaveBeenCalledTimes(3);
+
+    resetHttpRetryState();
+    setRetryPolicy({ retries: 2, maxDelayMs: 0 });
+    const postMock = stubResponses(new Response("", { status: 503 }));
+    expect((await loggedFetch(API_URL, { tag: "bapi", method: "POST" })).status).toBe(503);
+    expect(postMock).toHaveBeenCalledTimes(1);
+  });
+
+  test("does not retry a plain 500", async () => {
+    const fetchMock = stubResponses(new Response("boom", { status: 500 }));
+    await loggedFetch(API_URL, { tag: "plapi" });
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  test("retries network failures for idempotent methods", async () => {
+    const fetchMock = stubResponses(new TypeError("socket hang up"), new Response("ok"));
+    expect((await loggedFetch(API_URL, { tag: "bapi" })).status).toBe(200);
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+  });
+
+  test("respects http.retries = 0", async () => {
+    setRetryPolicy({ retries: 0, maxDelayMs: 0 });
+    const fetchMock = stubResponses(new Response("", { status: 429 }));
+    await loggedFetch(API_URL, { tag: "bapi" });
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  test("leaves non-Clerk API tags alone", async () => {
+    const fetchMock = stubResponses(new Response("", { status: 503 }));
+    await loggedFetch("https://example.test/x", { tag: "update-check" });
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  test("fails fast once the circuit opens after repeated server errors", async () => {
+    setRetryPolicy({ retries: 0, maxDelayMs: 0 });
+    const fetchMock = stubResponses(new Response("boom", { status: 500 }));
+
+    for (let i = 0; i < 5; i++) await loggedFetch(API_URL, { tag: "bapi" });
+    await expect(loggedFetch(API_URL, { tag: "bapi" })).rejects.toMatchObject({
+      code: "circuit_open",
+    });
+    expect(fetchMock).toHaveBeenCalledTimes(5);
+  });
+
+  test("never opens the circuit when the breaker is off", async () => {
+    setRetryPolicy({ retries: 0, maxDelayMs: 0, circuitBreaker: false });
+    const fetchMock = stubResponses(new Response("boom", { status: 500 }));
+
+    for (let i = 0; i < 8; i++) await loggedFetch(API_URL, { tag: "bapi" });
+    expect(fetchMock).toHaveBeenCalledTimes(8);
+  });
+
+  test("a success resets the failure count", async () => {
+    setRetryPolicy({ retries: 0, maxDelayMs: 0 });
+    const fetchMock = stubResponses(
+      ...Array.from({ length: 4 }, () => new Response("", { status: 500 })),
+      new Response("ok"),
+      ...Array.from({ length: 4 }, () => new Response("", { status: 500 })),
+      new Response("ok"),
+    );
+
+    for (let i = 0; i < 10; i++) await loggedFetch(API_URL, { tag: "bapi" });
+    expect(fetchMock).toHaveBeenCalledTimes(10);
+  });
+});
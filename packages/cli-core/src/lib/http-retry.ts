@@ -0,0 +1,183 @@
+/**
+ * Retry budget and circuit breaker for requests to Clerk APIs, applied in
+ * `loggedFetch`.
+ *
+ * - Rate limits (429) are retried for every method, since the server rejected
+ *   the request without processing it.
+ * - Gateway errors (502, 503, 504) and network failures are retried only for
+ *   idempotent methods. A plain 500 is usually deterministic, so it is not.
+ * - `Retry-After` on the response is honored. When the server asks for a
+ *   longer wait than `http.max-delay`, the response is returned as-is rather
+ *   than retrying early.
+ * - After {@link BREAKER_THRESHOLD} consecutive 5xx responses or network
+ *   failures from one host, further requests to that host fail fast for
+ *   {@link BREAKER_COOLDOWN_MS}, so bulk jobs stop hammering a struggling API.
+ *
+ * Configured once per invocation from the `http.retries` and `http.max-delay`
+ * settings in the preAction hook.
+ */
+
+import { CliError, ERROR_CODE } from "./errors.ts";
+import { log } from "./log.ts";
+import { sleep } from "./sleep.ts";
+
+export const DEFAULT_RETRIES = 2;
+export const DEFAULT_MAX_DELAY_MS = 10_000;
+
+const BASE_DELAY_MS = 250;
+const BREAKER_THRESHOLD = 5;
+const BREAKER_COOLDOWN_MS = 30_000;
+
+/** Fetch tags that talk to Clerk APIs. OAuth, MCP and update checks keep their own handling. */
+const RETRY_TAGS = new Set(["bapi", "plapi", "fapi"]);
+const IDEMPOTENT_METHODS = new Set(["GET", "HEAD", "OPTIONS", "PUT", "DELETE"]);
+const RETRYABLE_STATUSES = new Set([502, 503, 504]);
+
+export interface RetryPolicy {
+  retries: number;
+  maxDelayMs: number;
+  /** Fail fast after repeated server errors (default on). `clerk bench` turns it off. */
+  circuitBreaker?: boolean;
+}
+
+let policy: RetryPolicy = { retries: DEFAULT_RETRIES, maxDelayMs: DEFAULT_MAX_DELAY_MS };
+
+type BreakerState = { failures: number; openUntil: number };
+const breakers = new Map<string, BreakerState>();
+
+export function setRetryPolicy(next: RetryPolicy): void {
+  policy = next;
+}
+
+export function getRetryPolicy(): RetryPolicy {
+  return policy;
+}
+
+/** Test-only: restore the default policy and close every breaker. */
+export function resetHttpRetryState(): void {
+  policy = { retries: DEFAULT_RETRIES, maxDelayMs: DEFAULT_MAX_DELAY_MS };
+  breakers.clear();
+}
+
+/**
+ * Parse a `Retry-After` header (delta-seconds or an HTTP date) into
+ * milliseconds, or `undefined` when absent or unparseable.
+ */
+export function parseRetryAfter(value: string | null, now = Date.now()): number | undefined {
+  if (!value) return undefined;
+  const trimmed = value.trim();
+  if (/^\d+$/.test(trimmed)) return Number(trimmed) * 1000;
+  const date = Date.parse(trimmed);
+  if (Number.isNaN(date)) return undefined;
+  return Math.max(0, date - now);
+}
+
+/**
+ * Delay before retry number `attempt` (0-based): the server's `Retry-After`
+ * when given, otherwise exponential backoff with jitter, capped at
+ * `maxDelayMs`. Returns `undefined` when the server asks for more than the cap.
+ */
+export function computeRetryDelay(
+  attempt: number,
+  response: Response | undefined,
+  maxDelayMs: number,
+  random: () => number = Math.random,
+): number | undefined {
+  const retryAfter = parseRetryAfter(response?.headers.get("retry-after") ?? null);
+  if (retryAfter !== undefined) return retryAfter <= maxDelayMs ? retryAfter : undefined;
+  const backoff = BASE_DELAY_MS * 2 ** attempt;
+  return Math.min(maxDelayMs, backoff + Math.floor(random() * BASE_DELAY_MS));
+}
+
+function isReplayable(init: RequestInit): boolean {
+  return init.body === undefined || init.body === null || typeof init.body === "string";
+}
+
+function shouldRetryResponse(method: string, response: Response): boolean {
+  if (response.status === 429) return true;
+  return RETRYABLE_STATUSES.has(response.status) && IDEMPOTENT_METHODS.has(method);
+}
+
+function hostOf(url: string): string {
+  try {
+    return new URL(url).host;
+  } catch {
+    return url;
+  }
+}
+
+function assertBreakerClosed(tag: string, host: string): void {
+  if (policy.circuitBreaker === false) return;
+  const state = breakers.get(host);
+  if (!state || state.openUntil <= Date.now()) return;
+  log.debug(`${tag}: circuit open for ${host}, failing fast`);
+  throw new CliError(
+    `Stopped sending requests to ${host} after ${BREAKER_THRESHOLD} server errors in a row. ` +
+      "The API may be degraded; wait a minute and try again.",
+    { code: ERROR_CODE.CIRCUIT_OPEN },
+  );
+}
+
+function recordOutcome(tag: string, host: string, serverFailure: boolean): void {
+  if (policy.circuitBreaker === false) return;
+  if (!serverFailure) {
+    breakers.delete(host);
+    return;
+  }
+  const state = breakers.get(host) ?? { failures: 0, openUntil: 0 };
+  state.failures++;
+  if (state.failures >= BREAKER_THRESHOLD) {
+    state.openUntil = Date.now() + BREAKER_COOLDOWN_MS;
+    log.debug(`${tag}: ${state.failures} consecutive server errors from ${host}, opening circuit`);
+  }
+  breakers.set(host, state);
+}
+
+/**
+ * Run `send` under the retry policy. `send` performs one attempt and must be
+ * safe to call again (the caller guarantees a replayable body).
+ */
+export async function withRetries(
+  tag: string,
+  rawMethod: string,
+  url: string,
+  init: RequestInit,
+  send: () => Promise<Response>,
+): Promise<Response> {
+  if (!RETRY_TAGS.has(tag)) return send();
+
+  const method = rawMethod.toUpperCase();
+  const host = hostOf(url);
+  const retries = isReplayable(init) ? policy.retries : 0;
+
+  for (let attempt = 0; ; attempt++) {
+    assertBreakerClosed(tag, host);
+
+    let response: Response;
+    try {
+      response = await send();
+    } catch (error) {
+      recordOutcome(tag, host, true);
+      if (attempt >= retries || !IDEMPOTENT_METHODS.has(method)) throw error;
+      const delay = computeRetryDelay(attempt, undefined, policy.maxDelayMs)!;
+      log.debug(`${tag}: ${method} ${url} failed (${String(error)}), retrying in ${delay}ms`);
+      await sleep(delay);
+      continue;
+    }
+
+    recordOutcome(tag, host, response.status >= 500);
+    if (attempt >= retries || !shouldRetryResponse(method, response)) return response;
+
+    const delay = computeRetryDelay(attempt, response, policy.maxDelayMs);
+    if (delay === undefined) {
+      log.debug(`${tag}: Retry-After exceeds http.max-delay, not retrying ${method} ${url}`);
+      return response;
+    }
+    log.debug(
+      `${tag}: ${response.status} ${method} ${url}, retry ${attempt + 1}/${retries} in ${delay}ms`,
+    );
+    // Release the connection before sleeping.
+    await response.body?.cancel();
+    await sleep(delay);
+  }
+}
@@ -1,4 +1,5 @@
 import { throwUsageError, ERROR_CODE } from "./errors.ts";
+import { parseJsonInput } from "./json-parse.ts";
 
 const INPUT_JSON_FLAG = "--input-json";
 const FILE_PREFIX = "@";
@@ -86,15 +87,7 @@ function resolveJsonValue(raw: string): Promise<string> | string {
 }
 
 function parseJsonString(jsonStr: string): unknown {
-  try {
-    return JSON.parse(jsonStr);
-  } catch {
-    throwUsageError(
-      "Invalid JSON in --input-json. Please provide valid JSON.",
-      undefined,
-      ERROR_CODE.INVALID_JSON,
-    );
-  }
+  return parseJsonInput(jsonStr, "--input-json");
 }
 
 function assertJsonObject(parsed: unknown): asserts parsed is JsonObject {
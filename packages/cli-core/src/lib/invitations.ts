@@ -0,0 +1,68 @@
+/**
+ * Backend API helpers for application invitations (sign-up invitations to the
+ * instance, as opposed to organization invitations).
+ */
+
+import { bapiRequest } from "./bapi.ts";
+import { isRecord } from "./objects.ts";
+
+export const INVITATION_STATUSES = ["pending", "accepted", "revoked", "expired"] as const;
+export type InvitationStatus = (typeof INVITATION_STATUSES)[number];
+
+export interface BapiInvitation {
+  id: string;
+  email_address: string;
+  status: InvitationStatus | string;
+  url?: string | null;
+  expires_at?: number | null;
+  created_at?: number;
+  updated_at?: number;
+}
+
+const INVITATIONS_PAGE_SIZE = 100;
+
+/**
+ * Every invitation on the instance, optionally in one status, following
+ * pagination. The endpoint answers with a bare array or, on newer API
+ * versions, a `{ data, total_count }` page; both are accepted.
+ */
+export async function listInvitations(
+  secretKey: string,
+  status?: string,
+): Promise<BapiInvitation[]> {
+  const invitations: BapiInvitation[] = [];
+  for (let offset = 0; ; offset += INVITATIONS_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      limit: String(INVITATIONS_PAGE_SIZE),
+      offset: String(offset),
+    });
+    if (status) params.set("status", status);
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/invitations?${params}`,
+      secretKey,
+    });
+    const body = response.body;
+    const page = Array.isArray(body)
+      ? body
+      : isRecord(body) && Array.isArray(body.data)
+        ? body.data
+        : [];
+    invitations.push(...(page as BapiInvitation[]));
+    if (page.length < INVITATIONS_PAGE_SIZE) return invitations;
+  }
+}
+
+/**
+ * How many invitations the instance has, optionally in one status. Newer API
+ * versions return `total_count`, which costs one request; with a bare array
+ * every page is listed instead.
+ */
+export async function countInvitations(secretKey: string, status?: string): Promise<number> {
+  const params = new URLSearchParams({ limit: "1", offset: "0" });
+  if (status) params.set("status", status);
+  const response = await bapiRequest({ method: "GET", path: `/invitations?${params}`, secretKey });
+  const body = response.body;
+  if (isRecord(body) && typeof body.total_count === "number") return body.total_count;
+  return (await listInvitations(secretKey, status)).length;
+}
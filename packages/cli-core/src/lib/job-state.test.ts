@@ -0,0 +1,83 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { useCaptureLog } from "../test/lib/stubs.ts";
+import { openJob } from "./job-state.ts";
+
+describe("job-state", () => {
+  useCaptureLog();
+  let tempDir: string;
+  let path: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-job-state-test-"));
+    path = join(tempDir, "job.state");
+  });
+
+  afterEach(async () => {
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("starts from the initial cursor when there is no checkpoint", async () => {
+    const job = await openJob(path, "export", { query: "a" }, { offset: 0 });
+
+    expect(job).toMatchObject({ cursor: { offset: 0 }, resumed: false, done: 0, failed: 0 });
+    expect(await Bun.file(path).exists()).toBe(false);
+  });
+
+  test("resumes from the saved cursor and counts", async () => {
+    const first = await openJob(path, "export", { query: "a" }, { offset: 0 });
+    await first.save({ offset: 500 }, { done: 480, failed: 2 });
+
+    const second = await openJob(path, "export", { query: "a" }, { offset: 0 });
+    expect(second).toMatchObject({ cursor: { offset: 500 }, resumed: true, done: 480, failed: 2 });
+  });
+
+  test("finish removes the checkpoint", async () => {
+    const job = await openJob(path, "export", {}, { offset: 0 });
+    await job.save({ offset: 10 }, { done: 10, failed: 0 });
+    await job.finish();
+
+    expect(await Bun.file(path).exists()).toBe(false);
+  });
+
+  test("refuses a checkpoint from another job or other parameters", async () => {
+    const job = await openJob(path, "export", { query: "a" }, { offset: 0 });
+    await job.save({ offset: 10 }, { done: 10, failed: 0 });
+
+    await expect(openJob(path, "import", { query: "a" }, { offset: 0 })).rejects.toThrow(
+      /checkpoint for `export`/,
+    );
+    await expect(openJob(path, "export", { query: "b" }, { offset: 0 })).rejects.toThrow(
+      /different options/,
+    );
+  });
+
+  test("treats missing and null parameters as the same", async () => {
+    const job = await openJob(path, "export", { query: null }, { offset: 0 });
+    await job.save({ offset: 10 }, { done: 10, failed: 0 });
+
+    expect((await openJob(path, "export", {}, { offset: 0 })).resumed).toBe(true);
+  });
+
+  test("rejects files that aren't checkpoints", async () => {
+    await Bun.write(path, "not json");
+    await expect(openJob(path, "export", {}, { offset: 0 })).rejects.toThrow(
+      /not a job checkpoint/,
+    );
+
+    await Bun.write(path, JSON.stringify({ hello: "world" }));
+    await expect(openJob(path, "export", {}, { offset: 0 })).rejects.toThrow(
+      /not a job checkpoint/,
+    );
+  });
+
+  test("does nothing without a path", async () => {
+    const job = await openJob(undefined, "export", {}, { offset: 3 });
+    await job.save({ offset: 10 }, { done: 1, failed: 0 });
+    await job.finish();
+
+    expect(job.cursor).toEqual({ offset: 3 });
+  });
+});
@@ -0,0 +1,118 @@
+/**
+ * Checkpoint files for long-running bulk jobs (exports, imports, migrations).
+ * A job saves its cursor and counts after each unit of work, so running the
+ * same command again with `--resume <file>` continues where an interrupted
+ * run stopped instead of starting over. The file is removed once the job
+ * finishes.
+ *
+ * The file also records the job's name and the parameters that define its
+ * scope. Resuming with a different command or different parameters is an
+ * error: the saved cursor would point into a different result set.
+ */
+
+import { rm } from "node:fs/promises";
+import { ERROR_CODE, throwUsageError } from "./errors.ts";
+import { writeFileAtomic } from "./file-lock.ts";
+import { log } from "./log.ts";
+import { isRecord } from "./objects.ts";
+
+const JOB_STATE_VERSION = 1;
+
+/** Scope of a job. Keep secrets out: the file is plain JSON on disk. */
+export type JobParams = Record<string, string | number | boolean | null>;
+
+export type JobCounts = {
+  done: number;
+  failed: number;
+};
+
+export type JobStateFile<C> = JobCounts & {
+  version: typeof JOB_STATE_VERSION;
+  job: string;
+  params: JobParams;
+  cursor: C;
+  updated_at: string;
+};
+
+export type Job<C> = JobCounts & {
+  /** Where to start: the saved cursor when resuming, otherwise the initial one. */
+  cursor: C;
+  resumed: boolean;
+  /** Record progress up to `cursor`. `counts` are totals for the whole job, not deltas. */
+  save(cursor: C, counts: JobCounts): Promise<void>;
+  /** Remove the checkpoint; the job has nothing left to resume. */
+  finish(): Promise<void>;
+};
+
+function sameParams(a: JobParams, b: unknown): boolean {
+  if (!isRecord(b)) return false;
+  const keys = new Set([...Object.keys(a), ...Object.keys(b)]);
+  return [...keys].every((key) => (a[key] ?? null) === (b[key] ?? null));
+}
+
+async function readJobState<C>(
+  path: string,
+  job: string,
+  params: JobParams,
+): Promise<JobStateFile<C> | undefined> {
+  const file = Bun.file(path);
+  if (!(await file.exists())) return undefined;
+
+  let state: unknown;
+  try {
+    state = JSON.parse(await file.text());
+  } catch {
+    throwUsageError(`${path} is not a job checkpoint file.`, undefined, ERROR_CODE.INVALID_JSON);
+  }
+  if (!isRecord(state) || state.version !== JOB_STATE_VERSION || !("cursor" in state)) {
+    throwUsageError(`${path} is not a job checkpoint file.`);
+  }
+  if (state.job !== job) {
+    throwUsageError(`${path} is a checkpoint for \`${String(state.job)}\`, not \`${job}\`.`);
+  }
+  if (!sameParams(params, state.params)) {
+    throwUsageError(
+      `${path} was saved with different options (${JSON.stringify(state.params)}). ` +
+        "Rerun with the same options, or delete the file to start over.",
+    );
+  }
+  return state as JobStateFile<C>;
+}
+
+/**
+ * Open the job checkpoint at `path`, resuming it when the file exists. Without
+ * a path the job runs without checkpoints and `save`/`finish` do nothing.
+ */
+export async function openJob<C>(
+  path: string | undefined,
+  job: string,
+  params: JobParams,
+  initialCursor: C,
+): Promise<Job<C>> {
+  const saved = path ? await readJobState<C>(path, job, params) : undefined;
+  if (saved) {
+    log.info(`Resuming from ${path} (${saved.done} done, ${saved.failed} failed so far)`);
+  }
+
+  return {
+    cursor: saved?.cursor ?? initialCursor,
+    resumed: Boolean(saved),
+    done: saved?.done ?? 0,
+    failed: saved?.failed ?? 0,
+    async save(cursor, counts) {
+      if (!path) return;
+      const state: JobStateFile<C> = {
+        version: JOB_STATE_VERSION,
+        job,
+        params,
+        cursor,
+        ...counts,
+        updated_at: new Date().toISOString(),
+      };
+      await writeFileAtomic(path, `${JSON.stringify(state, null, 2)}\n`);
+    },
+    async finish() {
+      if (path) await rm(path, { force: true });
+    },
+  };
+}
@@ -0,0 +1,69 @@
+import { test, expect, describe } from "bun:test";
+import { locateJsonSyntaxError, locateJsonValue, parseJsonInput } from "./json-parse.ts";
+import { CliError, EXIT_CODE } from "./errors.ts";
+
+describe("locateJsonValue", () => {
+  const text = '{\n  "users": [\n    {"id": "user_1"},\n    {"id": 2}\n  ],\n  "a.b": true\n}';
+
+  test("finds nested values by path", () => {
+    expect(locateJsonValue(text, ["users", 1, "id"])).toMatchObject({ line: 4, column: 12 });
+    expect(locateJsonValue(text, ["a.b"])).toMatchObject({ line: 6, column: 10 });
+    expect(locateJsonValue(text, [])).toMatchObject({ line: 1, column: 1 });
+  });
+
+  test("returns undefined for a missing path", () => {
+    expect(locateJsonValue(text, ["users", 2])).toBeUndefined();
+  });
+});
+
+describe("locateJsonSyntaxError", () => {
+  test.each([
+    ['{"a": 1}'],
+    ["[1, 2.5, -3e10, true, false, null]"],
+    ['  {"nested": {"list": ["\\u00e9", "\\n"]}}  '],
+    ['"just a string"'],
+  ])("returns undefined for valid JSON %#", (text) => {
+    expect(locateJsonSyntaxError(text)).toBeUndefined();
+  });
+
+  test.each([
+    ['{"a": 1,}', 1, 9, "trailing comma before '}'"],
+    ['{\n  "a": 1\n  "b": 2\n}', 3, 3, "expected ',' or '}' but found character \"\\\"\""],
+    ["{a: 1}", 1, 2, "expected a double-quoted property name"],
+    ['{"a" 1}', 1, 6, "expected ':' after property name"],
+    ['["x", ]', 1, 7, "trailing comma before ']'"],
+    ['{"a": "unterminated', 1, 20, "unterminated string"],
+    ['{"a": 01}', 1, 8, "expected ',' or '}' but found character \"1\""],
+    ["", 1, 1, "unexpected end of input"],
+    ['{"a": 1} extra', 1, 10, "unexpected content after JSON value"],
+    ['{"a": tru}', 1, 7, 'unexpected character "t"'],
+  ] as const)("locates %p at line %d, column %d", (text, line, column, reason) => {
+    expect(locateJsonSyntaxError(text)).toMatchObject({ line, column, reason });
+  });
+});
+
+describe("parseJsonInput", () => {
+  test("returns parsed value for valid JSON", () => {
+    expect(parseJsonInput('{"a": [1, 2]}', "input.json")).toEqual({ a: [1, 2] });
+  });
+
+  test("throws a usage error naming the source, line and column", () => {
+    let caught: unknown;
+    try {
+      parseJsonInput('{\n  "a": 1,\n}', "config.json");
+    } catch (error) {
+      caught = error;
+    }
+
+    expect(caught).toBeInstanceOf(CliError);
+    const error = caught as CliError;
+    expect(error.code).toBe("invalid_json");
+    expect(error.exitCode).toBe(EXIT_CODE.USAGE);
+    expect(error.message).toContain("Invalid JSON in config.json at line 3, column 1");
+    expect(error.message).toContain("3 | }");
+  });
+
+  test("omits the source when none is given", () => {
+    expect(() => parseJsonInput("{")).toThrow("Invalid JSON at line 1, column 2");
+  });
+});
@@ -0,0 +1,242 @@
+/**
+ * JSON parsing for user-supplied input (`--file`, `--json`, `-d`, stdin).
+ *
+ * Bun's `JSON.parse` errors carry no position, so a malformed file only
+ * reports "Unexpected token". On failure we re-scan the input with a small
+ * strict scanner to point at the offending line and column before any API
+ * call is made. The same scanner locates values by path, so schema errors
+ * (`json-schema.ts`) can point at the offending entry too.
+ */
+
+import { throwUsageError, ERROR_CODE } from "./errors.ts";
+
+export interface JsonLocation {
+  line: number;
+  column: number;
+  offset: number;
+}
+
+export interface JsonSyntaxLocation extends JsonLocation {
+  reason: string;
+}
+
+/** Property names and array indexes from the root to a value. */
+export type JsonPath = (string | number)[];
+
+class JsonScanError extends Error {
+  constructor(
+    readonly offset: number,
+    readonly reason: string,
+  ) {
+    super(reason);
+  }
+}
+
+const NUMBER_PATTERN = /-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?/y;
+const SIMPLE_ESCAPES = new Set(['"', "\\", "/", "b", "f", "n", "r", "t"]);
+const LITERALS = ["true", "false", "null"];
+
+function describeChar(ch: string | undefined): string {
+  return ch === undefined ? "end of input" : `character ${JSON.stringify(ch)}`;
+}
+
+/**
+ * Scan `text` as strict JSON, throwing {@link JsonScanError} at the first
+ * syntax error. `onValue` sees the path and offset of every value.
+ */
+function scan(text: string, onValue?: (path: JsonPath, offset: number) => void): void {
+  let pos = 0;
+  const path: JsonPath = [];
+
+  const fail = (reason: string): never => {
+    throw new JsonScanError(pos, reason);
+  };
+
+  const skipWhitespace = () => {
+    while (pos < text.length && " \t\n\r".includes(text[pos]!)) pos++;
+  };
+
+  const scanString = () => {
+    pos++;
+    while (pos < text.length) {
+      const ch = text[pos]!;
+      if (ch === '"') {
+        pos++;
+        return;
+      }
+      if (ch === "\\") {
+        const next = text[pos + 1];
+        if (next === "u") {
+          if (!/^[0-9a-fA-F]{4}$/.test(text.slice(pos + 2, pos + 6))) {
+            fail("invalid unicode escape");
+          }
+          pos += 6;
+        } else if (next !== undefined && SIMPLE_ESCAPES.has(next)) {
+          pos += 2;
+        } else {
+          fail("invalid escape sequence");
+        }
+        continue;
+      }
+      if (ch < " ") fail("unescaped control character in string");
+      pos++;
+    }
+    fail("unterminated string");
+  };
+
+  const scanNumber = () => {
+    NUMBER_PATTERN.lastIndex = pos;
+    const match = NUMBER_PATTERN.exec(text);
+    if (!match) fail("invalid number");
+    pos += match![0].length;
+  };
+
+  const scanObject = () => {
+    pos++;
+    skipWhitespace();
+    if (text[pos] === "}") {
+      pos++;
+      return;
+    }
+    while (true) {
+      skipWhitespace();
+      if (text[pos] === "}") fail("trailing comma before '}'");
+      if (text[pos] !== '"') fail("expected a double-quoted property name");
+      const keyStart = pos;
+      scanString();
+      const key = onValue ? (JSON.parse(text.slice(keyStart, pos)) as string) : "";
+      skipWhitespace();
+      if (text[pos] !== ":") fail("expected ':' after property name");
+      pos++;
+      path.push(key);
+      scanValue();
+      path.pop();
+      skipWhitespace();
+      if (text[pos] === ",") {
+        pos++;
+        continue;
+      }
+      if (text[pos] === "}") {
+        pos++;
+        return;
+      }
+      fail(`expected ',' or '}' but found ${describeChar(text[pos])}`);
+    }
+  };
+
+  const scanArray = () => {
+    pos++;
+    skipWhitespace();
+    if (text[pos] === "]") {
+      pos++;
+      return;
+    }
+    for (let index = 0; ; index++) {
+      skipWhitespace();
+      if (text[pos] === "]") fail("trailing comma before ']'");
+      path.push(index);
+      scanValue();
+      path.pop();
+      skipWhitespace();
+      if (text[pos] === ",") {
+        pos++;
+        continue;
+      }
+      if (text[pos] === "]") {
+        pos++;
+        return;
+      }
+      fail(`expected ',' or ']' but found ${describeChar(text[pos])}`);
+    }
+  };
+
+  function scanValue(): void {
+    skipWhitespace();
+    onValue?.(path, pos);
+    const ch = text[pos];
+    if (ch === "{") return scanObject();
+    if (ch === "[") return scanArray();
+    if (ch === '"') return scanString();
+    if (ch === "-" || (ch !== undefined && ch >= "0" && ch <= "9")) return scanNumber();
+    const literal = LITERALS.find((word) => text.startsWith(word, pos));
+    if (literal) {
+      pos += literal.length;
+      return;
+    }
+    fail(`unexpected ${describeChar(ch)}`);
+  }
+
+  scanValue();
+  skipWhitespace();
+  if (pos < text.length) fail("unexpected content after JSON value");
+}
+
+function locationAt(text: string, offset: number): JsonLocation {
+  const lines = text.slice(0, offset).split("\n");
+  return { line: lines.length, column: lines[lines.length - 1]!.length + 1, offset };
+}
+
+/**
+ * Locate the first syntax error in `text`, or `undefined` if it is valid JSON.
+ * Lines and columns are 1-based.
+ */
+export function locateJsonSyntaxError(text: string): JsonSyntaxLocation | undefined {
+  try {
+    scan(text);
+    return undefined;
+  } catch (error) {
+    if (!(error instanceof JsonScanError)) throw error;
+    return { ...locationAt(text, error.offset), reason: error.reason };
+  }
+}
+
+/**
+ * Locate the value at `path` in valid JSON `text`, or `undefined` if there is
+ * none. Lines and columns are 1-based. For a repeated key the last one wins,
+ * as in `JSON.parse`.
+ */
+export function locateJsonValue(text: string, path: JsonPath): JsonLocation | undefined {
+  let found: number | undefined;
+  scan(text, (current, offset) => {
+    if (current.length === path.length && current.every((part, i) => part === path[i])) {
+      found = offset;
+    }
+  });
+  return found === undefined ? undefined : locationAt(text, found);
+}
+
+/** Render the offending source line with a caret under the error column. */
+function formatCodeFrame(text: string, location: JsonLocation): string {
+  const source = (text.split("\n")[location.line - 1] ?? "").replace(/\r$/, "");
+  const gutter = `${location.line} | `;
+  return `  ${gutter}${source}\n  ${" ".repeat(gutter.length + location.column - 1)}^`;
+}
+
+/**
+ * Parse user-supplied JSON, throwing a usage error that names the input
+ * source and the line/column of the first syntax error.
+ *
+ * @param source - Human label for where the JSON came from (a file path,
+ *   `--json`, `stdin`, ...). Omit to produce a generic message.
+ */
+export function parseJsonInput(text: string, source?: string): unknown {
+  try {
+    return JSON.parse(text);
+  } catch {
+    const location = locateJsonSyntaxError(text);
+    const where = source ? ` in ${source}` : "";
+    if (!location) {
+      throwUsageError(
+        `Invalid JSON${where}. Please provide valid JSON.`,
+        undefined,
+        ERROR_CODE.INVALID_JSON,
+      );
+    }
+    throwUsageError(
+      `Invalid JSON${where} at line ${location.line}, column ${location.column}: ${location.reason}\n` +
+        formatCodeFrame(text, location),
+      undefined,
+      ERROR_CODE.INVALID_JSON,
+    );
+  }
+}
@@ -0,0 +1,81 @@
+import { test, expect, describe } from "bun:test";
+import { CliError, ERROR_CODE } from "./errors.ts";
+import {
+  assertMatchesSchema,
+  formatJsonPath,
+  validateSchema,
+  type JsonSchema,
+} from "./json-schema.ts";
+
+const ENTRY: JsonSchema = {
+  type: "object",
+  required: ["email"],
+  additionalProperties: false,
+  properties: {
+    email: { type: "string", minLength: 1 },
+    role: { enum: ["admin", "member"] },
+    days: { type: "integer", minimum: 1, maximum: 30 },
+  },
+};
+
+const LIST: JsonSchema = {
+  type: "array",
+  minItems: 1,
+  items: { anyOf: [{ type: "string" }, ENTRY] },
+};
+
+describe("validateSchema", () => {
+  test("accepts a matching value", () => {
+    const value = ["a@example.com", { email: "b@example.com", days: 7 }];
+    expect(validateSchema(value, LIST)).toEqual([]);
+  });
+
+  test("reports every error with its path", () => {
+    const value = [{ role: "owner", days: 0, extra: true }, { email: "" }];
+    expect(validateSchema(value, LIST)).toEqual([
+      { path: [0], message: 'missing "email"' },
+      { path: [0, "role"], message: 'must be one of "admin", "member"' },
+      { path: [0, "days"], message: "must be at least 1" },
+      { path: [0, "extra"], message: "unknown property (expected one of email, role, days)" },
+      { path: [1, "email"], message: "must not be empty" },
+    ]);
+  });
+
+  test("names the allowed types when no anyOf branch fits the value", () => {
+    expect(validateSchema([42], LIST)).toEqual([
+      { path: [0], message: "expected string or object, found integer" },
+    ]);
+  });
+
+  test("checks array length and item types", () => {
+    expect(validateSchema([], LIST)).toEqual([{ path: [], message: "needs at least 1 item(s)" }]);
+    expect(validateSchema({ days: 1.5, email: "x" }, ENTRY)).toEqual([
+      { path: ["days"], message: "expected integer, found number" },
+    ]);
+  });
+});
+
+describe("formatJsonPath", () => {
+  test("uses dots for identifiers and brackets for the rest", () => {
+    expect(formatJsonPath([])).toBe("(root)");
+    expect(formatJsonPath([2, "public_metadata", "a.b"])).toBe('[2].public_metadata["a.b"]');
+  });
+});
+
+describe("assertMatchesSchema", () => {
+  test("throws an invalid_json usage error with line and column", () => {
+    const text = '[\n  {"email": "a@example.com", "days": 90}\n]';
+    let error: unknown;
+    try {
+      assertMatchesSchema(text, JSON.parse(text), LIST, "invites.json");
+    } catch (caught) {
+      error = caught;
+    }
+    expect(error).toBeInstanceOf(CliError);
+    expect((error as CliError).code).toBe(ERROR_CODE.INVALID_JSON);
+    expect((error as CliError).message).toBe(
+      "1 schema error(s) in invites.json; nothing was sent.\n" +
+        "  invites.json line 2, column 38 ([0].days): must be at most 30",
+    );
+  });
+});
@@ -0,0 +1,177 @@
+/**
+ * Schema checks for user-supplied JSON input files, run before any API call
+ * so a bad entry fails the whole command instead of half-applying a bulk
+ * operation. Commands embed their schemas next to the parser that uses them.
+ *
+ * Only the JSON Schema keywords those schemas need are supported: `type`,
+ * `enum`, `properties`, `required`, `additionalProperties`, `items`,
+ * `minItems`, `minLength`, `minimum`, `maximum`, and `anyOf`.
+ */
+
+import { throwUsageError, ERROR_CODE } from "./errors.ts";
+import { locateJsonValue, type JsonPath } from "./json-parse.ts";
+import { isRecord } from "./objects.ts";
+
+type JsonType = "object" | "array" | "string" | "integer" | "number" | "boolean" | "null";
+
+export interface JsonSchema {
+  type?: JsonType | JsonType[];
+  enum?: unknown[];
+  properties?: Record<string, JsonSchema>;
+  required?: string[];
+  additionalProperties?: boolean | JsonSchema;
+  items?: JsonSchema;
+  minItems?: number;
+  minLength?: number;
+  minimum?: number;
+  maximum?: number;
+  anyOf?: JsonSchema[];
+}
+
+export interface SchemaError {
+  path: JsonPath;
+  message: string;
+}
+
+const MAX_REPORTED_ERRORS = 5;
+
+function typeOf(value: unknown): JsonType {
+  if (value === null) return "null";
+  if (Array.isArray(value)) return "array";
+  if (typeof value === "number") return Number.isInteger(value) ? "integer" : "number";
+  return typeof value as JsonType;
+}
+
+function schemaTypes(schema: JsonSchema): JsonType[] {
+  if (schema.type === undefined) return [];
+  return Array.isArray(schema.type) ? schema.type : [schema.type];
+}
+
+function matchesType(value: unknown, schema: JsonSchema): boolean {
+  const types = schemaTypes(schema);
+  const actual = typeOf(value);
+  return (
+    types.length === 0 ||
+    types.includes(actual) ||
+    (actual === "integer" && types.includes("number"))
+  );
+}
+
+const IDENTIFIER = /^[A-Za-z_]\w*$/;
+
+/** `[2].role` style path for messages; the root is `(root)`. */
+export function formatJsonPath(path: JsonPath): string {
+  if (path.length === 0) return "(root)";
+  return path
+    .map((part, i) => {
+      if (typeof part === "number") return `[${part}]`;
+      if (!IDENTIFIER.test(part)) return `[${JSON.stringify(part)}]`;
+      return i === 0 ? part : `.${part}`;
+    })
+    .join("");
+}
+
+function validateAnyOf(value: unknown, branches: JsonSchema[], path: JsonPath): SchemaError[] {
+  const results = branches.map((branch) => validateSchema(value, branch, path));
+  if (results.some((errors) => errors.length === 0)) return [];
+  // When the value's type picks out one branch, its errors are the useful ones.
+  const typed = branches.flatMap((branch, i) => (matchesType(value, branch) ? [i] : []));
+  if (typed.length === 1) return results[typed[0]!]!;
+  const expected = branches.flatMap(schemaTypes).join(" or ");
+  return [{ path, message: `expected ${expected}, found ${typeOf(value)}` }];
+}
+
+function validateObject(
+  value: Record<string, unknown>,
+  schema: JsonSchema,
+  path: JsonPath,
+): SchemaError[] {
+  const errors: SchemaError[] = [];
+  for (const key of schema.required ?? []) {
+    if (value[key] === undefined) errors.push({ path, message: `missing "${key}"` });
+  }
+  for (const [key, child] of Object.entries(value)) {
+    const childPath = [...path, key];
+    const property = schema.properties?.[key];
+    if (property) {
+      errors.push(...validateSchema(child, property, childPath));
+    } else if (schema.additionalProperties === false) {
+      const known = Object.keys(schema.properties ?? {}).join(", ");
+      errors.push({ path: childPath, message: `unknown property (expected one of ${known})` });
+    } else if (isRecord(schema.additionalProperties)) {
+      errors.push(...validateSchema(child, schema.additionalProperties, childPath));
+    }
+  }
+  return errors;
+}
+
+/** Every way `value` breaks `schema`, in document order. */
+export function validateSchema(
+  value: unknown,
+  schema: JsonSchema,
+  path: JsonPath = [],
+): SchemaError[] {
+  if (schema.anyOf) return validateAnyOf(value, schema.anyOf, path);
+  if (!matchesType(value, schema)) {
+    const expected = schemaTypes(schema).join(" or ");
+    return [{ path, message: `expected ${expected}, found ${typeOf(value)}` }];
+  }
+  if (schema.enum && !schema.enum.includes(value)) {
+    const allowed = schema.enum.map((option) => JSON.stringify(option)).join(", ");
+    return [{ path, message: `must be one of ${allowed}` }];
+  }
+  if (typeof value === "string" && schema.minLength !== undefined) {
+    if (value.length < schema.minLength) {
+      const message =
+        schema.minLength === 1
+          ? "must not be empty"
+          : `needs at least ${schema.minLength} characters`;
+      return [{ path, message }];
+    }
+  }
+  if (typeof value === "number") {
+    if (schema.minimum !== undefined && value < schema.minimum) {
+      return [{ path, message: `must be at least ${schema.minimum}` }];
+    }
+    if (schema.maximum !== undefined && value > schema.maximum) {
+      return [{ path, message: `must be at most ${schema.maximum}` }];
+    }
+  }
+  if (Array.isArray(value)) {
+    if (schema.minItems !== undefined && value.length < schema.minItems) {
+      return [{ path, message: `needs at least ${schema.minItems} item(s)` }];
+    }
+    const items = schema.items;
+    return items ? value.flatMap((item, i) => validateSchema(item, items, [...path, i])) : [];
+  }
+  return isRecord(value) ? validateObject(value, schema, path) : [];
+}
+
+/**
+ * Throw a usage error listing where parsed `value` breaks `schema`, each with
+ * the line and column of the offending value in `text`.
+ */
+export function assertMatchesSchema(
+  text: string,
+  value: unknown,
+  schema: JsonSchema,
+  source: string,
+): void {
+  const errors = validateSchema(value, schema);
+  if (errors.length === 0) return;
+  const shown = errors.slice(0, MAX_REPORTED_ERRORS).map(({ path, message }) => {
+    const location = locateJsonValue(text, path);
+    const at = location ? ` line ${location.line}, column ${location.column}` : "";
+    return `  ${source}${at} (${formatJsonPath(path)}): ${message}`;
+  });
+  const more = errors.length - shown.length;
+  throwUsageError(
+    [
+      `${errors.length} schema error(s) in ${source}; nothing was sent.`,
+      ...shown,
+      ...(more > 0 ? [`  ... and ${more} more`] : []),
+    ].join("\n"),
+    undefined,
+    ERROR_CODE.INVALID_JSON,
+  );
+}
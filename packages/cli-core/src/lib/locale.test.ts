@@ -0,0 +1,40 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import { formatAmount, formatDate, isValidLocale, setDisplayLocale } from "./locale.ts";
+
+describe("locale", () => {
+  afterEach(() => {
+    setDisplayLocale(undefined);
+  });
+
+  test("dates stay ISO 8601 without a locale", () => {
+    expect(formatDate(Date.UTC(2024, 0, 2, 3, 4, 5))).toBe("2024-01-02T03:04:05Z");
+  });
+
+  test("dates follow the configured locale", () => {
+    setDisplayLocale("de-DE");
+    expect(formatDate(Date.UTC(2024, 0, 2, 3, 4, 5))).toContain("2024");
+    expect(formatDate(Date.UTC(2024, 0, 2, 3, 4, 5))).not.toContain("T03:04");
+  });
+
+  test("amounts scale by the currency's minor unit, not always cents", () => {
+    expect(formatAmount({ amount: 1250, currency: "usd" })).toBe("$12.50");
+    expect(formatAmount({ amount: 1500, currency: "JPY" })).toBe("¥1,500");
+    expect(formatAmount({ amount: 1500, currency: "KWD" })).toBe("KWD 1.500");
+  });
+
+  test("amounts use the configured locale's separators", () => {
+    setDisplayLocale("de-DE");
+    expect(formatAmount({ amount: 1250, currency: "EUR" })).toBe("12,50 €");
+  });
+
+  test("unknown currencies fall back to raw minor units", () => {
+    expect(formatAmount({ amount: 42, currency: "??" })).toBe("42 ?? (minor units)");
+  });
+
+  test("rejects malformed locale tags", () => {
+    expect(isValidLocale("en-GB")).toBe(true);
+    expect(isValidLocale("not a locale")).toBe(false);
+    setDisplayLocale("not a locale");
+    expect(formatDate(Date.UTC(2024, 0, 2))).toBe("2024-01-02T00:00:00Z");
+  });
+});
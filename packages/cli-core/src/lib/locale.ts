@@ -0,0 +1,60 @@
+/**
+ * Locale-aware rendering of dates and money in human-mode output, driven by
+ * the `output.locale` setting. Without it, timestamps stay ISO 8601 (UTC) and
+ * amounts use `en-US` grouping, so existing output is unchanged. JSON output
+ * never goes through here.
+ */
+
+let displayLocale: string | undefined;
+
+/** True when `locale` is a BCP 47 tag the runtime's `Intl` accepts. */
+export function isValidLocale(locale: string): boolean {
+  try {
+    return Intl.DateTimeFormat.supportedLocalesOf(locale).length > 0;
+  } catch {
+    return false;
+  }
+}
+
+/** Set from the `output.locale` setting at the start of each invocation. */
+export function setDisplayLocale(locale: string | undefined): void {
+  displayLocale = locale && isValidLocale(locale) ? locale : undefined;
+}
+
+export function getDisplayLocale(): string | undefined {
+  return displayLocale;
+}
+
+/** Render a millisecond epoch timestamp; ISO 8601 unless a locale is configured. */
+export function formatDate(ms: number): string {
+  const date = new Date(ms);
+  if (!displayLocale) return date.toISOString().replace(/\.\d{3}Z$/, "Z");
+  return new Intl.DateTimeFormat(displayLocale, {
+    dateStyle: "medium",
+    timeStyle: "short",
+  }).format(date);
+}
+
+/** Money as the Backend API returns it: an integer count of the currency's minor unit. */
+export type MinorUnitAmount = {
+  amount: number;
+  currency: string;
+};
+
+/**
+ * Render a minor-unit amount in its own currency. The number of minor-unit
+ * digits comes from the currency (2 for USD, 0 for JPY, 3 for KWD) rather than
+ * assuming cents.
+ */
+export function formatAmount({ amount, currency }: MinorUnitAmount): string {
+  const code = currency.toUpperCase();
+  let format: Intl.NumberFormat;
+  try {
+    format = new Intl.NumberFormat(displayLocale ?? "en-US", { style: "currency", currency: code });
+  } catch {
+    // Unknown currency code: show the raw minor units rather than guess a scale.
+    return `${amount} ${code} (minor units)`;
+  }
+  const digits = format.resolvedOptions().maximumFractionDigits ?? 2;
+  return format.format(amount / 10 ** digits);
+}
@@ -1,5 +1,13 @@
 import { test, expect, describe, beforeEach, afterEach } from "bun:test";
-import { log, setLogLevel, getLogLevel, pushPrefix, popPrefix, type LogLevel } from "./log.ts";
+import {
+  log,
+  setLogLevel,
+  getLogLevel,
+  pushPrefix,
+  popPrefix,
+  setPlain,
+  type LogLevel,
+} from "./log.ts";
 import { useCaptureLog } from "../test/lib/stubs.ts";
 
 let savedLevel: LogLevel;
@@ -146,3 +154,29 @@ describe("raw", () => {
     expect(captured.stderr.every((line) => line === payload)).toBe(true);
   });
 });
+
+describe("plain output", () => {
+  const captured = useCaptureLog();
+
+  afterEach(() => {
+    setPlain(false);
+  });
+
+  test("strips ANSI styling from data", () => {
+    setPlain(true);
+    log.data("\x1b[2morg:\x1b[0m acme");
+    expect(captured.stdout).toEqual(["org: acme"]);
+  });
+
+  test("drops decorative stderr output", () => {
+    setPlain(true);
+    log.ui("◇  Done\n");
+    log.blank();
+    expect(captured.stderr).toEqual([]);
+  });
+
+  test("keeps styling when off", () => {
+    log.data("\x1b[2mdim\x1b[0m");
+    expect(captured.stdout).toEqual(["\x1b[2mdim\x1b[0m"]);
+  });
+});
@@ -1,4 +1,5 @@
-import { dim, green, red, yellow } from "./color.ts";
+import { accentSpan, dim, dimSpan, green, red, stripAnsi, yellow } from "./color.ts";
+import { redactSecrets } from "./redact.ts";
 
 // ── Log level ────────────────────────────────────────────────────────────
 
@@ -26,6 +27,25 @@ function isLevelEnabled(level: LogLevel): boolean {
   return LEVEL_VALUE[level] <= LEVEL_VALUE[currentLevel];
 }
 
+// ── Plain output ─────────────────────────────────────────────────────────
+
+let plain = false;
+
+/**
+ * Set from the `--plain` global flag. Plain output keeps stdout to data with
+ * ANSI styling stripped, and drops decorative stderr output (intro/outro
+ * brackets, spinners, blank lines); the `preAction` hook also lowers the log
+ * level so only errors remain on stderr. Tables and detail views go to stdout
+ * through `printOutput`, so they survive the lower level.
+ */
+export function setPlain(value: boolean) {
+  plain = value;
+}
+
+export function isPlain(): boolean {
+  return plain;
+}
+
 // ── Pipe prefix state (for intro/outro flow) ──────────────────────────────
 
 const S_BAR = "│";
@@ -117,12 +137,12 @@ function shouldWrite(channel: "stdout" | "stderr", msg: string): boolean {
 // ── Inline highlighting ──────────────────────────────────────────────────
 
 /**
- * Highlights `backtick` spans in cyan within a message.
+ * Highlights `backtick` spans in the theme's accent color within a message.
  */
 function highlight(msg: string): string {
-  // Use targeted foreground color set/reset (\x1b[39m = default fg) instead of
-  // cyan() which uses \x1b[0m (full reset) and kills surrounding styles.
-  return msg.replace(/`([^`]+)`/g, (_, content) => `\x1b[36m\`${content}\`\x1b[39m`);
+  // accentSpan resets only the foreground (\x1b[39m) instead of using cyan(),
+  // whose \x1b[0m full reset kills surrounding styles.
+  return msg.replace(/`([^`]+)`/g, (_, content) => accentSpan(`\`${content}\``));
 }
 
 // ── Capture context (for testing) ─────────────────────────────────────────
@@ -138,7 +158,9 @@ export function setActiveCapture(captured: CapturedLogs | null): void {
   activeCapture = captured;
 }
 
-function writeln(stream: NodeJS.WriteStream, channel: "stdout" | "stderr", msg: string) {
+function writeln(stream: NodeJS.WriteStream, channel: "stdout" | "stderr", rawMsg: string) {
+  const redacted = redactSecrets(rawMsg);
+  const msg = plain && channel === "stdout" ? stripAnsi(redacted) : redacted;
   if (activeCapture) {
     activeCapture[channel].push(msg);
     return;
@@ -165,9 +187,9 @@ export interface Logger {
 function createLogger(tag?: string): Logger {
   function formatTag(msg: string): string {
     if (!tag) return msg;
-    // Use targeted dim on/off (\x1b[22m = normal intensity) instead of dim()
-    // which uses \x1b[0m (full reset) and kills surrounding color styles.
-    return `\x1b[2m[${tag}]\x1b[22m ${msg}`;
+    // dimSpan turns dim off with \x1b[22m (normal intensity) instead of dim()'s
+    // \x1b[0m full reset, which kills surrounding color styles.
+    return `${dimSpan(`[${tag}]`)} ${msg}`;
   }
 
   const logger = {
@@ -198,6 +220,7 @@ function createLogger(tag?: string): Logger {
     },
     /** Blank line to stderr. Preserves pipe prefix inside intro/outro flow. */
     blank() {
+      if (plain) return;
       const prefix = applyPrefix("");
       if (activeCapture) {
         activeCapture.stderr.push(prefix);
@@ -206,7 +229,8 @@ function createLogger(tag?: string): Logger {
       }
     },
     /** Raw stderr — no color, no prefix, no throttle. For machine-readable output (agent JSON). */
-    raw(msg: string) {
+    raw(rawMsg: string) {
+      const msg = redactSecrets(rawMsg);
       if (activeCapture) {
         activeCapture.stderr.push(msg);
       } else {
@@ -224,6 +248,7 @@ function createLogger(tag?: string): Logger {
      * appended newline would break the redraw.
      */
     ui(msg: string) {
+      if (plain) return;
       if (activeCapture) {
         activeCapture.stderr.push(msg);
       } else {
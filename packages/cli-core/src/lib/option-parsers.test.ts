@@ -1,5 +1,10 @@
 import { test, expect, describe } from "bun:test";
-import { collectOptionValues, parseIntegerOption } from "./option-parsers.ts";
+import {
+  collectOptionValues,
+  parseIntegerOption,
+  parseTimeOption,
+  parseWhereOption,
+} from "./option-parsers.ts";
 
 describe("collectOptionValues", () => {
   test("returns the first value in an array when no previous array is supplied", () => {
@@ -70,3 +75,51 @@ describe("parseIntegerOption", () => {
     });
   });
 });
+
+describe("parseTimeOption", () => {
+  // Wednesday, 2024-03-13 15:30 local time.
+  const NOW = new Date(2024, 2, 13, 15, 30);
+  const DAY = 24 * 60 * 60 * 1000;
+
+  test.each([
+    { value: "30d", expected: NOW.getTime() - 30 * DAY },
+    { value: "12h", expected: NOW.getTime() - 12 * 60 * 60 * 1000 },
+    { value: "2w", expected: NOW.getTime() - 14 * DAY },
+    { value: "90 days ago", expected: NOW.getTime() - 90 * DAY },
+    { value: "1 Year", expected: NOW.getTime() - 365 * DAY },
+    { value: "now", expected: NOW.getTime() },
+    { value: "today", expected: new Date(2024, 2, 13).getTime() },
+    { value: "yesterday", expected: new Date(2024, 2, 12).getTime() },
+    { value: "last monday", expected: new Date(2024, 2, 11).getTime() },
+    { value: "last wednesday", expected: new Date(2024, 2, 6).getTime() },
+    { value: "2024-01-01", expected: Date.UTC(2024, 0, 1) },
+    { value: "2024-01-01T09:00:00Z", expected: Date.UTC(2024, 0, 1, 9) },
+    { value: "1704067200000", expected: 1_704_067_200_000 },
+  ])("parses $value", ({ value, expected }) => {
+    expect(parseTimeOption(value, "--since", NOW)).toBe(expected);
+  });
+
+  test.each(["soon", "30", "5 fortnights", "last funday", "2024-13-45"])(
+    "throws a usage error for %j",
+    (value) => {
+      expect(() => parseTimeOption(value, "--since", NOW)).toThrow(/Invalid --since value/);
+    },
+  );
+});
+
+describe("parseWhereOption", () => {
+  test("splits each clause on the first =", () => {
+    expect(parseWhereOption(["status=pending", " query = a=b "])).toEqual([
+      ["status", "pending"],
+      ["query", "a=b"],
+    ]);
+  });
+
+  test("returns no pairs when the flag is absent", () => {
+    expect(parseWhereOption()).toEqual([]);
+  });
+
+  test.each(["status", "=pending", " =x"])("throws a usage error for %j", (clause) => {
+    expect(() => parseWhereOption([clause])).toThrow(/Invalid --where value/);
+  });
+});
@@ -5,6 +5,21 @@ export function collectOptionValues(value: string, previous: string[] = []): str
   return [...previous, value];
 }
 
+/**
+ * Parse repeated `--where key=value` filters into query parameter pairs. The
+ * value may itself contain `=`; only the first one splits.
+ */
+export function parseWhereOption(values: string[] = []): [key: string, value: string][] {
+  return values.map((clause) => {
+    const index = clause.indexOf("=");
+    const key = index > 0 ? clause.slice(0, index).trim() : "";
+    if (!key) {
+      throwUsageError(`Invalid --where value "${clause}". Use key=value, e.g. --where query=acme.`);
+    }
+    return [key, clause.slice(index + 1).trim()];
+  });
+}
+
 /** Parse and range-validate an integer option value, throwing a usage error on bad input. */
 export function parseIntegerOption(
   value: string,
@@ -23,3 +38,67 @@ export function parseIntegerOption(
 
   return parsed;
 }
+
+const MINUTE_MS = 60_000;
+const DAY_MS = 24 * 60 * MINUTE_MS;
+
+const DURATION_UNITS_MS: Record<string, number> = {
+  m: MINUTE_MS,
+  min: MINUTE_MS,
+  minute: MINUTE_MS,
+  h: 60 * MINUTE_MS,
+  hour: 60 * MINUTE_MS,
+  d: DAY_MS,
+  day: DAY_MS,
+  w: 7 * DAY_MS,
+  week: 7 * DAY_MS,
+  mo: 30 * DAY_MS,
+  month: 30 * DAY_MS,
+  y: 365 * DAY_MS,
+  year: 365 * DAY_MS,
+};
+
+const WEEKDAYS = ["sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"];
+
+function startOfDay(date: Date, daysAgo = 0): number {
+  return new Date(date.getFullYear(), date.getMonth(), date.getDate() - daysAgo).getTime();
+}
+
+/**
+ * Parse a time flag into Unix milliseconds. Accepts:
+ *
+ * - durations before now: `30d`, `12h`, `2w`, `6mo`, `1y`, `90 days ago`
+ * - `now`, `today`, `yesterday`, and `last <weekday>` (local midnight)
+ * - ISO dates and date-times: `2024-01-01` (UTC midnight), `2024-01-01T09:00:00Z`
+ * - raw Unix milliseconds, for scripts written against the API
+ */
+export function parseTimeOption(value: string, flag: string, now = new Date()): number {
+  const input = value.trim().toLowerCase();
+
+  if (/^\d{11,}$/.test(input)) return Number(input);
+  if (input === "now") return now.getTime();
+  if (input === "today") return startOfDay(now);
+  if (input === "yesterday") return startOfDay(now, 1);
+
+  const duration = /^(\d+)\s*([a-z]+?)s?(?:\s+ago)?$/.exec(input);
+  const unitMs = duration ? DURATION_UNITS_MS[duration[2]!] : undefined;
+  if (duration && unitMs) return now.getTime() - Number(duration[1]) * unitMs;
+
+  const lastWeekday = /^last\s+([a-z]+)$/.exec(input);
+  const weekday = lastWeekday ? WEEKDAYS.indexOf(lastWeekday[1]!) : -1;
+  if (weekday >= 0) {
+    // Always strictly before today: on a Monday, "last monday" is a week ago.
+    const daysAgo = (now.getDay() - weekday + 7) % 7 || 7;
+    return startOfDay(now, daysAgo);
+  }
+
+  if (/^\d{4}-\d{2}-\d{2}/.test(input)) {
+    const parsed = Date.parse(value.trim());
+    if (!Number.isNaN(parsed)) return parsed;
+  }
+
+  throwUsageError(
+    `Invalid ${flag} value "${value}". Use a duration like 30d or 12h, a date like 2024-01-01, ` +
+      '"yesterday", "last monday", or Unix milliseconds.',
+  );
+}
@@ -0,0 +1,307 @@
+/**
+ * Backend API helpers for organizations, shared by the `orgs` commands and
+ * feature toggles that need organization data.
+ */
+
+import { bapiRequest } from "./bapi.ts";
+import { ApiError } from "./errors.ts";
+import { isRecord } from "./objects.ts";
+
+async function readTotalCount(secretKey: string, path: string): Promise<number> {
+  const response = await bapiRequest({ method: "GET", path, secretKey });
+  const body = response.body;
+  return isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+}
+
+/**
+ * Total number of organizations on the instance, optionally narrowed by list
+ * filters (e.g. `query`). Requests a single row and reads `total_count`, so it
+ * stays cheap on large instances.
+ */
+export function countOrganizations(
+  secretKey: string,
+  filters: [key: string, value: string][] = [],
+): Promise<number> {
+  const params = new URLSearchParams([["limit", "1"], ...filters]);
+  return readTotalCount(secretKey, `/organizations?${params}`);
+}
+
+export interface BapiOrganization {
+  id: string;
+  name: string;
+  slug?: string | null;
+  members_count?: number;
+  max_allowed_memberships?: number;
+  admin_delete_enabled?: boolean;
+  public_metadata?: Record<string, unknown>;
+  private_metadata?: Record<string, unknown>;
+  created_by?: string | null;
+  created_at?: number;
+  updated_at?: number;
+}
+
+/** Fetch one organization by ID or slug, including its member count. */
+export async function fetchOrganization(
+  secretKey: string,
+  idOrSlug: string,
+): Promise<BapiOrganization> {
+  const response = await bapiRequest({
+    method: "GET",
+    path: `/organizations/${encodeURIComponent(idOrSlug)}?include_members_count=true`,
+    secretKey,
+  });
+  return response.body as BapiOrganization;
+}
+
+/** Like {@link fetchOrganization}, but `undefined` when no organization matches. */
+export async function findOrganization(
+  secretKey: string,
+  idOrSlug: string,
+): Promise<BapiOrganization | undefined> {
+  try {
+    return await fetchOrganization(secretKey, idOrSlug);
+  } catch (error) {
+    if (error instanceof ApiError && error.status === 404) return undefined;
+    throw error;
+  }
+}
+
+/** Update an organization's `name`, `slug`, or other top-level fields. */
+export async function updateOrganization(
+  secretKey: string,
+  orgId: string,
+  params: Record<string, unknown>,
+): Promise<BapiOrganization> {
+  const response = await bapiRequest({
+    method: "PATCH",
+    path: `/organizations/${encodeURIComponent(orgId)}`,
+    secretKey,
+    body: JSON.stringify(params),
+  });
+  return response.body as BapiOrganization;
+}
+
+/** Organizations whose name, slug, or ID matches `query`, newest first. */
+export async function searchOrganizations(
+  secretKey: string,
+  query: string,
+  limit: number,
+): Promise<BapiOrganization[]> {
+  const params = new URLSearchParams({ limit: String(limit) });
+  if (query) params.set("query", query);
+  const response = await bapiRequest({
+    method: "GET",
+    path: `/organizations?${params}`,
+    secretKey,
+  });
+  const body = response.body;
+  return isRecord(body) && Array.isArray(body.data) ? (body.data as BapiOrganization[]) : [];
+}
+
+const ORGANIZATIONS_PAGE_SIZE = 100;
+
+/** Every organization on the instance with its member count, following pagination. */
+export async function listOrganizations(secretKey: string): Promise<BapiOrganization[]> {
+  const organizations: BapiOrganization[] = [];
+  for (let offset = 0; ; offset += ORGANIZATIONS_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      include_members_count: "true",
+      limit: String(ORGANIZATIONS_PAGE_SIZE),
+      offset: String(offset),
+    });
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/organizations?${params}`,
+      secretKey,
+    });
+    const body = response.body;
+    const page = isRecord(body) && Array.isArray(body.data) ? body.data : [];
+    organizations.push(...(page as BapiOrganization[]));
+    const total = isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+    if (page.length < ORGANIZATIONS_PAGE_SIZE || organizations.length >= total) {
+      return organizations;
+    }
+  }
+}
+
+/** Number of invitations to `orgId`, optionally only those with `status`. */
+export function countOrganizationInvitations(
+  secretKey: string,
+  orgId: string,
+  status?: string,
+): Promise<number> {
+  const params = new URLSearchParams(status ? { status, limit: "1" } : { limit: "1" });
+  const path = `/organizations/${encodeURIComponent(orgId)}/invitations?${params}`;
+  return readTotalCount(secretKey, path);
+}
+
+/** Number of invitations to `orgId` that have not been accepted or revoked yet. */
+export function countPendingInvitations(secretKey: string, orgId: string): Promise<number> {
+  return countOrganizationInvitations(secretKey, orgId, "pending");
+}
+
+/** Number of domains attached to `orgId`. */
+export function countOrganizationDomains(secretKey: string, orgId: string): Promise<number> {
+  return readTotalCount(secretKey, `/organizations/${encodeURIComponent(orgId)}/domains?limit=1`);
+}
+
+export interface BapiOrganizationInvitation {
+  id: string;
+  email_address: string;
+  role: string;
+  status?: string;
+  organization_id?: string;
+  created_at?: number;
+}
+
+const INVITATIONS_PAGE_SIZE = 100;
+
+/** Every invitation to `orgId` with the given status, following pagination. */
+export async function listOrganizationInvitations(
+  secretKey: string,
+  orgId: string,
+  status: string,
+): Promise<BapiOrganizationInvitation[]> {
+  const invitations: BapiOrganizationInvitation[] = [];
+  for (let offset = 0; ; offset += INVITATIONS_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      status,
+      limit: String(INVITATIONS_PAGE_SIZE),
+      offset: String(offset),
+    });
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/organizations/${encodeURIComponent(orgId)}/invitations?${params}`,
+      secretKey,
+    });
+    const body = response.body;
+    const page = isRecord(body) && Array.isArray(body.data) ? body.data : [];
+    invitations.push(...(page as BapiOrganizationInvitation[]));
+    const total = isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+    if (page.length < INVITATIONS_PAGE_SIZE || invitations.length >= total) return invitations;
+  }
+}
+
+/** Create several invitations to `orgId` in one request. The batch succeeds or fails as a whole. */
+export async function createOrganizationInvitations(
+  secretKey: string,
+  orgId: string,
+  invitations: Record<string, unknown>[],
+): Promise<BapiOrganizationInvitation[]> {
+  const response = await bapiRequest({
+    method: "POST",
+    path: `/organizations/${encodeURIComponent(orgId)}/invitations/bulk`,
+    secretKey,
+    body: JSON.stringify(invitations),
+  });
+  return Array.isArray(response.body) ? (response.body as BapiOrganizationInvitation[]) : [];
+}
+
+/** Revoke a pending invitation so its link stops working. */
+export async function revokeOrganizationInvitation(
+  secretKey: string,
+  orgId: string,
+  invitationId: string,
+): Promise<void> {
+  await bapiRequest({
+    method: "POST",
+    path:
+      `/organizations/${encodeURIComponent(orgId)}` +
+      `/invitations/${encodeURIComponent(invitationId)}/revoke`,
+    secretKey,
+  });
+}
+
+export interface BapiOrganizationMembership {
+  id: string;
+  role: string;
+  public_user_data?: { user_id: string; identifier?: string | null } | null;
+  created_at?: number;
+}
+
+const MEMBERSHIPS_PAGE_SIZE = 100;
+
+/** Every membership of `orgId`, following pagination. */
+export async function listOrganizationMemberships(
+  secretKey: string,
+  orgId: string,
+): Promise<BapiOrganizationMembership[]> {
+  const memberships: BapiOrganizationMembership[] = [];
+  for (let offset = 0; ; offset += MEMBERSHIPS_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      limit: String(MEMBERSHIPS_PAGE_SIZE),
+      offset: String(offset),
+    });
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/organizations/${encodeURIComponent(orgId)}/memberships?${params}`,
+      secretKey,
+    });
+    const body = response.body;
+    const page = isRecord(body) && Array.isArray(body.data) ? body.data : [];
+    memberships.push(...(page as BapiOrganizationMembership[]));
+    const total = isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+    if (page.length < MEMBERSHIPS_PAGE_SIZE || memberships.length >= total) return memberships;
+  }
+}
+
+/** Create an organization. `created_by` becomes its first admin. */
+export async function createOrganization(
+  secretKey: string,
+  params: Record<string, unknown>,
+): Promise<BapiOrganization> {
+  const response = await bapiRequest({
+    method: "POST",
+    path: "/organizations",
+    secretKey,
+    body: JSON.stringify(params),
+  });
+  return response.body as BapiOrganization;
+}
+
+/** True when no organization uses `slug` yet. */
+export async function isOrganizationSlugAvailable(
+  secretKey: string,
+  slug: string,
+): Promise<boolean> {
+  try {
+    await bapiRequest({
+      method: "GET",
+      path: `/organizations/${encodeURIComponent(slug)}`,
+      secretKey,
+    });
+    return false;
+  } catch (error) {
+    if (error instanceof ApiError && error.status === 404) return true;
+    throw error;
+  }
+}
+
+export interface BapiOrganizationRole {
+  id: string;
+  key: string;
+  name?: string;
+}
+
+const ROLES_PAGE_SIZE = 100;
+
+/** Every organization role defined on the instance (e.g. `org:admin`), following pagination. */
+export async function listOrganizationRoles(secretKey: string): Promise<BapiOrganizationRole[]> {
+  const roles: BapiOrganizationRole[] = [];
+  for (let offset = 0; ; offset += ROLES_PAGE_SIZE) {
+    const params = new URLSearchParams({
+      limit: String(ROLES_PAGE_SIZE),
+      offset: String(offset),
+    });
+    const response = await bapiRequest({
+      method: "GET",
+      path: `/organization_roles?${params}`,
+      secretKey,
+    });
+    const body = response.body;
+    const page = isRecord(body) && Array.isArray(body.data) ? body.data : [];
+    roles.push(...(page as BapiOrganizationRole[]));
+    const total = isRecord(body) && typeof body.total_count === "number" ? body.total_count : 0;
+    if (page.length < ROLES_PAGE_SIZE || roles.length >= total) return roles;
+  }
+}
@@ -0,0 +1,75 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import {
+  escapeHtml,
+  htmlTable,
+  jsonlRows,
+  markdownTable,
+  setOutputFormat,
+  toDocumentLines,
+} from "./output-format.ts";
+
+describe("markdownTable", () => {
+  test("escapes pipes and keeps multi-line cells on one row", () => {
+    expect(markdownTable(["A", "B"], [["x|y", "one\ntwo"]])).toEqual([
+      "| A | B |",
+      "| --- | --- |",
+      "| x\\|y | one<br>two |",
+    ]);
+  });
+});
+
+describe("htmlTable", () => {
+  test("escapes cell text", () => {
+    expect(htmlTable(["A"], [[`<b>"Tom" & 'Jerry'</b>`]])).toContain(
+      "    <tr><td>&lt;b&gt;&quot;Tom&quot; &amp; &#39;Jerry&#39;&lt;/b&gt;</td></tr>",
+    );
+  });
+
+  test("omits the header when none is given", () => {
+    expect(htmlTable(undefined, [["x"]])).not.toContain("  <thead>");
+  });
+});
+
+describe("jsonlRows", () => {
+  test("writes empty cells as null", () => {
+    const rows = [
+      ["a_1", ""],
+      ["a_2", "bravo"],
+    ];
+    expect(jsonlRows(["id", "name"], rows)).toEqual([
+      '{"id":"a_1","name":null}',
+      '{"id":"a_2","name":"bravo"}',
+    ]);
+  });
+});
+
+describe("escapeHtml", () => {
+  test("leaves plain text alone", () => {
+    expect(escapeHtml("user_123")).toBe("user_123");
+  });
+});
+
+describe("toDocumentLines", () => {
+  afterEach(() => {
+    setOutputFormat("table");
+  });
+
+  test("markdown strips colors and unindents table rows", () => {
+    setOutputFormat("markdown");
+    expect(toDocumentLines(["\x1b[1mUser\x1b[22m", "  | A |"])).toEqual(["User", "| A |"]);
+  });
+
+  test("html wraps loose lines in paragraphs", () => {
+    setOutputFormat("html");
+    expect(toDocumentLines(["\x1b[1mA & B\x1b[22m", "", "<table>"])).toEqual([
+      "<p>A &amp; B</p>",
+      "",
+      "<table>",
+    ]);
+  });
+
+  test("jsonl drops loose lines", () => {
+    setOutputFormat("jsonl");
+    expect(toDocumentLines(["User", "", '{"ID":"user_1"}'])).toEqual(['{"ID":"user_1"}']);
+  });
+});
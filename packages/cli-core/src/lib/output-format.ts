@@ -0,0 +1,104 @@
+/**
+ * Document formats for human-mode tables and detail views, selected with the
+ * global `-o, --output-format` flag. `markdown` and `html` render the same
+ * rows and fields as the terminal table, uncolored, so a listing can be pasted
+ * into a wiki, PR description, or incident doc as is. `jsonl` writes one JSON
+ * object per row instead, and list commands that can page through every
+ * result (`users list`) stream them as pages arrive.
+ *
+ * JSON output (`--json`, agent mode) is unaffected. Only commands that print
+ * through {@link renderTable} or {@link formatFields} in `table.ts` follow the
+ * flag.
+ */
+
+import { stripAnsi } from "./color.ts";
+
+export const OUTPUT_FORMATS = ["table", "markdown", "html", "jsonl"] as const;
+export type OutputFormat = (typeof OUTPUT_FORMATS)[number];
+
+let outputFormat: OutputFormat = "table";
+
+export function setOutputFormat(format: OutputFormat): void {
+  outputFormat = format;
+}
+
+export function getOutputFormat(): OutputFormat {
+  return outputFormat;
+}
+
+/** Whether output is a pasteable document, which belongs on stdout. */
+export function isDocumentFormat(): boolean {
+  return outputFormat !== "table";
+}
+
+function escapeMarkdownCell(text: string): string {
+  return text.replace(/\\/g, "\\\\").replace(/\|/g, "\\|").replace(/\r?\n/g, "<br>");
+}
+
+const HTML_ESCAPES: Record<string, string> = {
+  "&": "&amp;",
+  "<": "&lt;",
+  ">": "&gt;",
+  '"': "&quot;",
+  "'": "&#39;",
+};
+
+export function escapeHtml(text: string): string {
+  return text.replace(/[&<>"']/g, (char) => HTML_ESCAPES[char]!);
+}
+
+/** A GitHub-flavored Markdown table. */
+export function markdownTable(headers: string[], rows: string[][]): string[] {
+  const line = (cells: string[]) => `| ${cells.map(escapeMarkdownCell).join(" | ")} |`;
+  return [line(headers), line(headers.map(() => "---")), ...rows.map(line)];
+}
+
+/** JSON Lines: one object per row, keyed by column. Empty cells are `null`. */
+export function jsonlRows(keys: string[], rows: (string | undefined)[][]): string[] {
+  return rows.map((cells) =>
+    JSON.stringify(Object.fromEntries(keys.map((key, i) => [key, cells[i] || null]))),
+  );
+}
+
+/** An HTML `<table>`. Row headers (`<th scope="row">`) suit label/value detail views. */
+export function htmlTable(
+  headers: string[] | undefined,
+  rows: string[][],
+  options: { rowHeaders?: boolean } = {},
+): string[] {
+  const cell = (text: string, index: number) =>
+    options.rowHeaders && index === 0
+      ? `<th scope="row">${escapeHtml(text)}</th>`
+      : `<td>${escapeHtml(text)}</td>`;
+  return [
+    "<table>",
+    ...(headers
+      ? [
+          "  <thead>",
+          `    <tr>${headers.map((header) => `<th>${escapeHtml(header)}</th>`).join("")}</tr>`,
+          "  </thead>",
+        ]
+      : []),
+    "  <tbody>",
+    ...rows.map((cells) => `    <tr>${cells.map(cell).join("")}</tr>`),
+    "  </tbody>",
+    "</table>",
+  ];
+}
+
+/**
+ * Turn a detail view's loose lines (titles, blank separators, notes) into the
+ * document format. Lines the renderers already produced (Markdown table rows,
+ * HTML tags, JSON objects) pass through unchanged; JSON Lines has no place
+ * for loose lines, so they are dropped.
+ */
+export function toDocumentLines(lines: string[]): string[] {
+  if (outputFormat === "jsonl") return lines.filter((line) => line.startsWith("{"));
+  return lines.map((raw) => {
+    const line = stripAnsi(raw);
+    const trimmed = line.trim();
+    if (outputFormat === "markdown") return trimmed.startsWith("|") ? trimmed : line;
+    if (!trimmed || trimmed.startsWith("<")) return line;
+    return `<p>${escapeHtml(trimmed)}</p>`;
+  });
+}
@@ -0,0 +1,113 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { _setConfigDir } from "./config.ts";
+import { setSetting } from "./settings.ts";
+import { pageOutput, resolvePagerCommand, setPagerEnabled } from "./pager.ts";
+
+const ENV_KEYS = ["CLERK_PAGER", "PAGER", "CLERK_MODE"] as const;
+
+describe("pager", () => {
+  let tempDir: string;
+  let savedEnv: Record<string, string | undefined>;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-pager-test-"));
+    _setConfigDir(tempDir);
+    savedEnv = Object.fromEntries(ENV_KEYS.map((k) => [k, process.env[k]]));
+    for (const k of ENV_KEYS) delete process.env[k];
+  });
+
+  afterEach(async () => {
+    for (const k of ENV_KEYS) {
+      if (savedEnv[k] == null) delete process.env[k];
+      else process.env[k] = savedEnv[k];
+    }
+    setPagerEnabled(true);
+    _setConfigDir(undefined);
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  describe("resolvePagerCommand", () => {
+    test("defaults to less -FRX", async () => {
+      expect(await resolvePagerCommand()).toBe("less -FRX");
+    });
+
+    test("uses $PAGER when set", async () => {
+      process.env.PAGER = "more";
+      expect(await resolvePagerCommand()).toBe("more");
+    });
+
+    test("core.pager setting takes precedence over $PAGER", async () => {
+      process.env.PAGER = "more";
+      await setSetting("core.pager", "bat --plain");
+      expect(await resolvePagerCommand()).toBe("bat --plain");
+    });
+
+    test("CLERK_PAGER takes precedence over everything", async () => {
+      process.env.PAGER = "more";
+      await setSetting("core.pager", "bat --plain");
+      process.env.CLERK_PAGER = "most";
+      expect(await resolvePagerCommand()).toBe("most");
+    });
+
+    test.each(["", "  ", "cat"])("%p disables paging", async (value) => {
+      process.env.CLERK_PAGER = value;
+      expect(await resolvePagerCommand()).toBeUndefined();
+    });
+  });
+
+  describe("pageOutput", () => {
+    let savedStdoutTTY: boolean | undefined;
+    let savedStderrTTY: boolean | undefined;
+    let savedRows: number | undefined;
+
+    beforeEach(() => {
+      savedStdoutTTY = process.stdout.isTTY;
+      savedStderrTTY = process.stderr.isTTY;
+      savedRows = process.stdout.rows;
+      Object.defineProperty(process.stdout, "isTTY", { value: true, configurable: true });
+      Object.defineProperty(process.stderr, "isTTY", { value: true, configurable: true });
+      Object.defineProperty(process.stdout, "rows", { value: 5, configurable: true });
+      process.env.CLERK_MODE = "human";
+    });
+
+    afterEach(() => {
+      Object.defineProperty(process.stdout, "isTTY", { value: savedStdoutTTY, configurable: true });
+      Object.defineProperty(process.stderr, "isTTY", { value: savedStderrTTY, configurable: true });
+      Object.defineProperty(process.stdout, "rows", { value: savedRows, configurable: true });
+    });
+
+    const longOutput = Array.from({ length: 20 }, (_, i) => `line ${i}`);
+
+    test("leaves output that fits on screen to the caller", async () => {
+      expect(await pageOutput(["a", "b"])).toBe(false);
+    });
+
+    test("leaves output to the caller when --no-pager is set", async () => {
+      setPagerEnabled(false);
+      expect(await pageOutput(longOutput)).toBe(false);
+    });
+
+    test("leaves output to the caller when stdout is not a terminal", async () => {
+      Object.defineProperty(process.stdout, "isTTY", { value: false, configurable: true });
+      expect(await pageOutput(longOutput)).toBe(false);
+    });
+
+    test("leaves output to the caller when the pager is disabled", async () => {
+      process.env.CLERK_PAGER = "cat";
+      expect(await pageOutput(longOutput)).toBe(false);
+    });
+
+    test("falls back to printing when the pager does not exist", async () => {
+      process.env.CLERK_PAGER = "clerk-test-missing-pager";
+      expect(await pageOutput(longOutput)).toBe(false);
+    });
+
+    test("pipes long output through the pager", async () => {
+      process.env.CLERK_PAGER = "true";
+      expect(await pageOutput(longOutput)).toBe(true);
+    });
+  });
+});
@@ -0,0 +1,126 @@
+/**
+ * Pipe long human-mode output through a pager, the way `git` and `gh` do.
+ *
+ * Commands opt in by handing their rendered lines to {@link pageOutput}. When
+ * the output fits on screen, or paging is disabled, it returns `false` and the
+ * caller prints normally, so short listings keep their intro/outro framing.
+ *
+ * Pager command, first match wins:
+ *
+ * 1. `CLERK_PAGER` environment variable
+ * 2. `core.pager` setting
+ * 3. `PAGER` environment variable
+ * 4. `less -FRX`
+ *
+ * An empty value or `cat` disables paging.
+ */
+
+import { isHuman } from "../mode.ts";
+import { isPlain, log } from "./log.ts";
+import { isDocumentFormat, toDocumentLines } from "./output-format.ts";
+import { isQuiet } from "./quiet.ts";
+import { redactSecrets } from "./redact.ts";
+import { getSetting } from "./settings.ts";
+
+export const DEFAULT_PAGER = "less -FRX";
+const COMMAND_NOT_FOUND = 127;
+
+let enabled = true;
+
+/** Set from the `--no-pager` global flag at the start of each invocation. */
+export function setPagerEnabled(value: boolean): void {
+  enabled = value;
+}
+
+/** Resolve the pager command, or `undefined` when paging is turned off. */
+export async function resolvePagerCommand(): Promise<string | undefined> {
+  const command =
+    process.env.CLERK_PAGER ??
+    (await getSetting("core.pager")) ??
+    process.env.PAGER ??
+    DEFAULT_PAGER;
+  const trimmed = command.trim();
+  if (!trimmed || trimmed === "cat") return undefined;
+  return trimmed;
+}
+
+function spawnPager(command: string) {
+  const shell = process.platform === "win32" ? ["cmd.exe", "/c"] : ["sh", "-c"];
+  return Bun.spawn([...shell, command], {
+    stdin: "pipe",
+    stdout: "inherit",
+    stderr: "inherit",
+    // Match git: give `less` sane defaults when the user has not set $LESS.
+    env: { ...process.env, LESS: process.env.LESS ?? "FRX" },
+  });
+}
+
+function shouldPage(lineCount: number): boolean {
+  if (!enabled || !isHuman()) return false;
+  if (!process.stdout.isTTY || !process.stderr.isTTY) return false;
+  const rows = process.stdout.rows;
+  return typeof rows === "number" && rows > 0 && lineCount >= rows;
+}
+
+/**
+ * Show `lines` in the pager when they would scroll off screen.
+ *
+ * Returns `true` once the pager has exited, or `false` when the caller should
+ * print the lines itself (output fits, paging disabled, or the pager could not
+ * be started).
+ */
+export async function pageOutput(lines: string[]): Promise<boolean> {
+  if (!shouldPage(lines.length)) return false;
+
+  const command = await resolvePagerCommand();
+  if (!command) return false;
+
+  let proc: ReturnType<typeof spawnPager>;
+  try {
+    proc = spawnPager(command);
+  } catch (error) {
+    log.debug(`pager: failed to start "${command}": ${String(error)}`);
+    return false;
+  }
+
+  log.debug(`pager: paging ${lines.length} lines through "${command}"`);
+  try {
+    proc.stdin.write(redactSecrets(lines.join("\n")) + "\n");
+    await proc.stdin.end();
+  } catch {
+    // The user quit the pager before reading everything (EPIPE).
+  }
+
+  // The shell exits 127 when the pager binary does not exist; fall back to
+  // printing so the output is not lost.
+  const exitCode = await proc.exited;
+  if (exitCode === COMMAND_NOT_FOUND) {
+    log.debug(`pager: "${command}" not found, printing directly`);
+    return false;
+  }
+  return true;
+}
+
+/**
+ * Print a rendered table or detail view. Markdown and HTML documents
+ * (`--output-format`) go to stdout so they can be redirected to a file, and
+ * so does `--plain` and `-q` output, whose log level hides info lines;
+ * terminal output is logged as UI, through the pager when long unless
+ * `page` is false.
+ */
+export async function printOutput(
+  lines: string[],
+  { page = true }: { page?: boolean } = {},
+): Promise<void> {
+  if (isDocumentFormat()) {
+    log.data(toDocumentLines(lines).join("\n"));
+    return;
+  }
+  if (isPlain() || isQuiet()) {
+    for (const line of lines) log.data(line);
+    return;
+  }
+  if (!page || !(await pageOutput(lines))) {
+    for (const line of lines) log.info(line);
+  }
+}
@@ -0,0 +1,88 @@
+import { describe, expect, test } from "bun:test";
+import { BapiError, CliError, ERROR_CODE } from "./errors.ts";
+import { attemptBulk, BulkItemError, formatProgress } from "./progress.ts";
+
+const stripAnsi = (value: string): string => value.replace(/\x1b\[[0-9;]*m/g, "");
+
+describe("formatProgress", () => {
+  const startedAt = 1_000_000;
+
+  test("shows counts without an ETA until work has run for a moment", () => {
+    const state = { total: 10, done: 2, failed: 0, startedAt };
+    expect(stripAnsi(formatProgress(state, startedAt + 500, false))).toBe("2/10");
+  });
+
+  test("estimates the remaining time from the average pace", () => {
+    const state = { total: 10, done: 2, failed: 0, startedAt };
+    expect(stripAnsi(formatProgress(state, startedAt + 4_000, false))).toBe("2/10 · ETA 16s");
+  });
+
+  test("counts failures as processed and reports them", () => {
+    const state = { total: 4, done: 3, failed: 1, startedAt };
+    expect(stripAnsi(formatProgress(state, startedAt + 90_000, false))).toBe("4/4 · 1 failed");
+  });
+
+  test("fills the bar in proportion to processed items", () => {
+    const state = { total: 4, done: 1, failed: 1, startedAt };
+    expect(stripAnsi(formatProgress(state, startedAt))).toBe(
+      `${"█".repeat(10)}${"░".repeat(10)} 2/4`,
+    );
+  });
+
+  test("formats long estimates in minutes", () => {
+    const state = { total: 100, done: 10, failed: 0, startedAt };
+    expect(stripAnsi(formatProgress(state, startedAt + 10_000, false))).toBe("10/100 · ETA 1m30s");
+  });
+});
+
+describe("attemptBulk", () => {
+  const tally = () => {
+    const counts = { done: 0, failed: 0 };
+    return {
+      counts,
+      progress: {
+        advance: (count = 1) => void (counts.done += count),
+        fail: (count = 1) => void (counts.failed += count),
+      },
+    };
+  };
+
+  test("advances on success and returns the value", async () => {
+    const { counts, progress } = tally();
+
+    expect(await attemptBulk(progress, async () => "user_1", 3)).toEqual({
+      ok: true,
+      value: "user_1",
+    });
+    expect(counts).toEqual({ done: 3, failed: 0 });
+  });
+
+  test("fails just the unit on an API error or a BulkItemError", async () => {
+    const { counts, progress } = tally();
+    const apiError = new BapiError(422, '{"errors":[{"message":"taken"}]}', new Headers());
+
+    expect(
+      await attemptBulk(progress, async () => {
+        throw apiError;
+      }),
+    ).toEqual({ ok: false, error: apiError.message });
+    expect(
+      await attemptBulk(progress, async () => {
+        throw new BulkItemError("no ID");
+      }),
+    ).toEqual({ ok: false, error: "no ID" });
+    expect(counts).toEqual({ done: 0, failed: 2 });
+  });
+
+  test("rethrows anything else, such as read-only mode", async () => {
+    const { counts, progress } = tally();
+    const readOnly = new CliError("read-only", { code: ERROR_CODE.READ_ONLY });
+
+    await expect(
+      attemptBulk(progress, async () => {
+        throw readOnly;
+      }),
+    ).rejects.toBe(readOnly);
+    expect(counts).toEqual({ done: 0, failed: 0 });
+  });
+});
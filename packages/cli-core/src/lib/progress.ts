@@ -0,0 +1,138 @@
+/**
+ * Progress reporting for commands that work through a known number of items
+ * (bulk invitations, revocations, exports). On a terminal it renders a bar
+ * with counts, failures, and an ETA inside the usual spinner; when stderr is
+ * not a TTY it falls back to a plain status line every few seconds, so logs
+ * stay readable. Agent mode prints nothing, like {@link withSpinner}.
+ */
+
+import { isHuman } from "../mode.ts";
+import { dim, red } from "./color.ts";
+import { ApiError, errorMessage } from "./errors.ts";
+import { log } from "./log.ts";
+import { withSpinner } from "./spinner.ts";
+
+const BAR_WIDTH = 20;
+/** Elapsed time before an ETA is shown; earlier estimates jump around too much. */
+const ETA_AFTER_MS = 1_000;
+/** How often the plain-text fallback prints a status line. */
+const PLAIN_INTERVAL_MS = 5_000;
+
+export type ProgressControls = {
+  /** Record `count` items (default 1) as processed successfully. */
+  advance(count?: number): void;
+  /** Record `count` items (default 1) as processed but failed. */
+  fail(count?: number): void;
+};
+
+export type ProgressState = {
+  total: number;
+  done: number;
+  failed: number;
+  startedAt: number;
+};
+
+function formatDuration(ms: number): string {
+  const seconds = Math.max(1, Math.round(ms / 1000));
+  if (seconds < 60) return `${seconds}s`;
+  const minutes = Math.floor(seconds / 60);
+  return `${minutes}m${String(seconds % 60).padStart(2, "0")}s`;
+}
+
+/**
+ * One status line for `state`, e.g. `7/12 · 1 failed · ETA 4s`, led by a bar
+ * when `bar` is set. Failed items count as processed.
+ */
+export function formatProgress(state: ProgressState, now: number, bar = true): string {
+  const processed = state.done + state.failed;
+  const parts = [`${processed}/${state.total}`];
+  if (state.failed > 0) parts.push(red(`${state.failed} failed`));
+  const elapsed = now - state.startedAt;
+  if (processed > 0 && processed < state.total && elapsed >= ETA_AFTER_MS) {
+    parts.push(`ETA ${formatDuration((elapsed / processed) * (state.total - processed))}`);
+  }
+  const counts = parts.join(dim(" · "));
+  if (!bar) return counts;
+  // A total that was only an estimate can be overrun; keep the bar full rather than overflowing.
+  const ratio = state.total > 0 ? Math.min(1, processed / state.total) : 1;
+  const filled = Math.round(ratio * BAR_WIDTH);
+  return `${"█".repeat(filled)}${dim("░".repeat(BAR_WIDTH - filled))} ${counts}`;
+}
+
+/**
+ * Run `fn` while reporting progress through `total` items. `message` is the
+ * spinner label (e.g. "Creating invitations...").
+ */
+export async function withProgress<T>(
+  message: string,
+  total: number,
+  fn: (progress: ProgressControls) => Promise<T>,
+): Promise<T> {
+  const state: ProgressState = { total, done: 0, failed: 0, startedAt: Date.now() };
+  const label = message.replace(/\.{3}$/, "");
+  const controls = (render: () => void): ProgressControls => ({
+    advance(count = 1) {
+      state.done += count;
+      render();
+    },
+    fail(count = 1) {
+      state.failed += count;
+      render();
+    },
+  });
+
+  if (!isHuman()) return fn(controls(() => {}));
+
+  if (!process.stderr.isTTY) {
+    let lastPrinted = state.startedAt;
+    log.info(`${label}: 0/${total}`);
+    const result = await fn(
+      controls(() => {
+        const now = Date.now();
+        if (now - lastPrinted < PLAIN_INTERVAL_MS) return;
+        lastPrinted = now;
+        log.info(`${label}: ${formatProgress(state, now, false)}`);
+      }),
+    );
+    log.info(`${label}: ${formatProgress(state, Date.now(), false)}`);
+    return result;
+  }
+
+  return withSpinner(
+    `${message} ${formatProgress(state, state.startedAt)}`,
+    (spinner) =>
+      fn(controls(() => spinner.update(`${message} ${formatProgress(state, Date.now())}`))),
+    () => `${label} ${formatProgress(state, Date.now(), false)}`,
+  );
+}
+
+/**
+ * Thrown by a bulk task to fail just its own item when the API answered but
+ * the answer can't be used (a created record without an ID, say).
+ */
+export class BulkItemError extends Error {}
+
+/** How one unit of bulk work ended: with its result, or with why it failed. */
+export type BulkAttempt<T> = { ok: true; value: T } | { ok: false; error: string };
+
+/**
+ * Run one unit of bulk work (`count` items) and record it on `progress`. An
+ * API error or a {@link BulkItemError} fails just this unit, and the job goes
+ * on. Anything else (read-only mode, an open circuit breaker, a `--dry-run`
+ * stop) would fail every unit after it the same way, so it ends the job.
+ */
+export async function attemptBulk<T>(
+  progress: ProgressControls,
+  task: () => Promise<T>,
+  count = 1,
+): Promise<BulkAttempt<T>> {
+  try {
+    const value = await task();
+    progress.advance(count);
+    return { ok: true, value };
+  } catch (error) {
+    if (!(error instanceof ApiError || error instanceof BulkItemError)) throw error;
+    progress.fail(count);
+    return { ok: false, error: errorMessage(error) };
+  }
+}
@@ -0,0 +1,35 @@
+/**
+ * `-q/--quiet` output. List and create commands print only resource IDs on
+ * stdout, one per line, so they compose with `xargs` and shell loops:
+ *
+ * ```sh
+ * clerk users list -q --query foo | xargs -n1 clerk users get
+ * ```
+ *
+ * Quiet output takes precedence over `--json` and agent-mode JSON. Commands
+ * that do not list or create resources ignore the flag apart from the
+ * quieter log level set in the `preAction` hook.
+ */
+
+import { log } from "./log.ts";
+
+let quiet = false;
+
+/** Set from the `--quiet` global flag at the start of each invocation. */
+export function setQuiet(value: boolean): void {
+  quiet = value;
+}
+
+export function isQuiet(): boolean {
+  return quiet;
+}
+
+/**
+ * Print `ids` one per line when quiet output is on. Returns `false` otherwise
+ * so the caller renders its normal output.
+ */
+export function printQuietIds(ids: readonly string[]): boolean {
+  if (!quiet) return false;
+  for (const id of ids) log.data(id);
+  return true;
+}
@@ -0,0 +1,54 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import { assertRequestAllowed, isReadOnly, setReadOnly } from "./read-only.ts";
+
+describe("assertRequestAllowed", () => {
+  afterEach(() => {
+    setReadOnly(false);
+  });
+
+  test("allows everything when read-only mode is off", () => {
+    expect(isReadOnly()).toBe(false);
+    expect(() =>
+      assertRequestAllowed("bapi", "DELETE", "https://api.clerk.test/v1/users/u"),
+    ).not.toThrow();
+  });
+
+  test.each(["POST", "PUT", "PATCH", "DELETE", "delete"])("blocks %s to Clerk APIs", (method) => {
+    setReadOnly(true);
+    expect(() => assertRequestAllowed("plapi", method, "https://api.clerk.test/v1/x")).toThrow(
+      "read-only mode is enabled",
+    );
+  });
+
+  test.each(["GET", "HEAD", "OPTIONS"])("allows %s in read-only mode", (method) => {
+    setReadOnly(true);
+    expect(() => assertRequestAllowed("bapi", method, "https://api.clerk.test/v1/x")).not.toThrow();
+  });
+
+  test.each(["oauth", "mcp", "update-check", "relay"])(
+    "does not guard non-Clerk-API tag %s",
+    (tag) => {
+      setReadOnly(true);
+      expect(() => assertRequestAllowed(tag, "POST", "https://example.test/token")).not.toThrow();
+    },
+  );
+
+  test("allows server-side dry runs", () => {
+    setReadOnly(true);
+    expect(() =>
+      assertRequestAllowed("plapi", "PATCH", "https://api.clerk.test/v1/config?dry_run=true"),
+    ).not.toThrow();
+  });
+
+  test("error carries the read_only code and the request path", () => {
+    setReadOnly(true);
+    let caught: unknown;
+    try {
+      assertRequestAllowed("bapi", "POST", "https://api.clerk.test/v1/users?limit=1");
+    } catch (error) {
+      caught = error;
+    }
+    expect(caught).toMatchObject({ code: "read_only" });
+    expect((caught as Error).message).toContain("Refusing to send POST /v1/users");
+  });
+});
@@ -0,0 +1,56 @@
+/**
+ * Read-only mode: when enabled, every mutating request to a Clerk API is
+ * refused in `loggedFetch` before it leaves the process, so a profile used by
+ * dashboards or cron jobs is guaranteed not to change anything.
+ *
+ * Enabled by `--read-only`, `CLERK_READ_ONLY=1`, or `clerk settings set
+ * core.read-only true`. Resolved once per invocation in the preAction hook.
+ */
+
+import { CliError, ERROR_CODE } from "./errors.ts";
+import { log } from "./log.ts";
+
+const MUTATING_METHODS = new Set(["POST", "PUT", "PATCH", "DELETE"]);
+
+/** Fetch tags that talk to Clerk APIs. OAuth login, MCP and update checks stay usable. */
+const GUARDED_TAGS = new Set(["bapi", "plapi", "fapi"]);
+
+let readOnly = false;
+
+export function setReadOnly(enabled: boolean): void {
+  readOnly = enabled;
+}
+
+export function isReadOnly(): boolean {
+  return readOnly;
+}
+
+function isDryRun(url: string): boolean {
+  try {
+    return new URL(url).searchParams.get("dry_run") === "true";
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Whether a request would change state in a Clerk API. Server-side dry runs
+ * (`?dry_run=true`) don't count since they persist nothing.
+ */
+export function isMutatingApiRequest(tag: string, method: string, url: string): boolean {
+  return GUARDED_TAGS.has(tag) && MUTATING_METHODS.has(method.toUpperCase()) && !isDryRun(url);
+}
+
+/** Throw when read-only mode is on and the request would mutate state. */
+export function assertRequestAllowed(tag: string, method: string, url: string): void {
+  if (!readOnly || !isMutatingApiRequest(tag, method, url)) return;
+
+  const request = `${method.toUpperCase()} ${new URL(url).pathname}`;
+  log.debug(`${tag}: blocked ${method} ${url} (read-only mode)`);
+  throw new CliError(
+    `Refusing to send ${request}: read-only mode is enabled.\n` +
+      "Read-only mode comes from --read-only, CLERK_READ_ONLY, or the core.read-only setting.\n" +
+      "Run `clerk settings unset core.read-only` to turn off the setting.",
+    { code: ERROR_CODE.READ_ONLY },
+  );
+}
@@ -0,0 +1,105 @@
+/**
+ * Central secret redaction for everything the CLI prints.
+ *
+ * `log.ts` runs every line written to stdout/stderr through {@link redactSecrets},
+ * so tables, JSON payloads, debug request logs, and API error bodies all mask
+ * credentials the same way. `--show-secrets` turns the filter off for the
+ * current invocation.
+ *
+ * Personal data is handled separately: with the `display.redact-pii` setting,
+ * {@link redactPii} masks email addresses and phone numbers in table cells and
+ * detail views (not JSON), for screen sharing and recorded demos.
+ * `--show-pii` turns it off for one command.
+ *
+ * Must not import `log.ts` (it is imported by it).
+ */
+
+const MASK = "****";
+const VISIBLE_SUFFIX = 4;
+
+/**
+ * Each pattern captures the non-secret prefix in group 1 and the secret body
+ * in group 2. Publishable keys (`pk_`) are public by design and left alone.
+ */
+const SECRET_PATTERNS: RegExp[] = [
+  // Clerk secret keys
+  /\b(sk_(?:test|live)_)([A-Za-z0-9]{8,})/g,
+  // Clerk Platform API keys
+  /\b(ak_)([A-Za-z0-9_]{8,})/g,
+  // Svix / Clerk webhook signing secrets
+  /\b(whsec_)([A-Za-z0-9+/=]{8,})/g,
+  // Anthropic and OpenAI API keys
+  /\b(sk-ant-(?:[a-z0-9]+-)?)([A-Za-z0-9_-]{16,})/g,
+  /\b(sk-(?:proj-)?)([A-Za-z0-9_-]{20,})/g,
+  // Bearer tokens echoed in headers or error bodies
+  /\b(Bearer\s+)([A-Za-z0-9._~+/=-]{16,})/g,
+];
+
+let showSecrets = false;
+
+export function setShowSecrets(enabled: boolean): void {
+  showSecrets = enabled;
+}
+
+export function isShowingSecrets(): boolean {
+  return showSecrets;
+}
+
+function mask(prefix: string, secret: string): string {
+  return `${prefix}${MASK}${secret.slice(-VISIBLE_SUFFIX)}`;
+}
+
+/** Mask known credential formats in `text`, keeping the prefix and last four characters. */
+export function redactSecrets(text: string): string {
+  if (showSecrets) return text;
+  let result = text;
+  for (const pattern of SECRET_PATTERNS) {
+    result = result.replace(pattern, (_match, prefix: string, secret: string) =>
+      mask(prefix, secret),
+    );
+  }
+  return result;
+}
+
+let redactingPii = false;
+
+export function setRedactPii(enabled: boolean): void {
+  redactingPii = enabled;
+}
+
+export function isRedactingPii(): boolean {
+  return redactingPii;
+}
+
+const EMAIL_PATTERN = /([^\s@"'<>(),;:]+)@([^\s@"'<>(),;:]+\.[A-Za-z]{2,})/g;
+// E.164 numbers as Clerk stores them (`+15555550100`).
+const PHONE_PATTERN = /\+\d{7,15}\b/g;
+const ANSI_SEQUENCE = /(\x1b\[[0-9;]*m)/;
+
+/** `jane@example.com` → `j***@example.com`. The domain stays: it rarely identifies anyone. */
+function maskEmail(local: string, domain: string): string {
+  return `${local.slice(0, 1)}***@${domain}`;
+}
+
+/** `+15555550100` → `+1******0100`: the first digit and the last four stay. */
+function maskPhone(phone: string): string {
+  return `${phone.slice(0, 2)}${"*".repeat(phone.length - 6)}${phone.slice(-4)}`;
+}
+
+/** Mask email addresses and phone numbers in `text` when `display.redact-pii` is on. */
+export function redactPii(text: string): string {
+  if (!redactingPii) return text;
+  // Styled values arrive with color codes; mask only the text between them.
+  return text
+    .split(ANSI_SEQUENCE)
+    .map((part) =>
+      ANSI_SEQUENCE.test(part)
+        ? part
+        : part
+            .replace(EMAIL_PATTERN, (_match, local: string, domain: string) =>
+              maskEmail(local, domain),
+            )
+            .replace(PHONE_PATTERN, maskPhone),
+    )
+    .join("");
+}
@@ -0,0 +1,51 @@
+import { test, expect, describe, afterEach, mock } from "bun:test";
+
+const mockText = mock();
+mock.module("./prompts.ts", () => ({
+  text: (...args: unknown[]) => mockText(...args),
+}));
+
+const mockIsAgent = mock();
+mock.module("../mode.ts", () => ({
+  isAgent: (...args: unknown[]) => mockIsAgent(...args),
+  isHuman: (...args: unknown[]) => !mockIsAgent(...args),
+  setMode: () => {},
+  getMode: () => "human",
+}));
+
+const { requireArg } = await import("./require-arg.ts");
+
+describe("requireArg", () => {
+  afterEach(() => {
+    mockText.mockReset();
+    mockIsAgent.mockReset();
+  });
+
+  test("returns a passed value without prompting", async () => {
+    const prompt = mock(async () => "picked");
+    expect(await requireArg("org_1", { label: "Organization", prompt })).toBe("org_1");
+    expect(prompt).not.toHaveBeenCalled();
+  });
+
+  test("runs the picker in a terminal", async () => {
+    mockIsAgent.mockReturnValue(false);
+    const prompt = async () => "org_2";
+    expect(await requireArg(undefined, { label: "Organization", prompt })).toBe("org_2");
+  });
+
+  test("falls back to a text prompt without a picker", async () => {
+    mockIsAgent.mockReturnValue(false);
+    mockText.mockResolvedValue("  user_3 ");
+    expect(await requireArg("", { label: "User ID" })).toBe("user_3");
+    expect(mockText.mock.calls[0]![0]).toMatchObject({ message: "User ID" });
+  });
+
+  test("fails with a usage error in agent mode", async () => {
+    mockIsAgent.mockReturnValue(true);
+    const prompt = mock(async () => "picked");
+    await expect(requireArg(undefined, { label: "User ID", prompt })).rejects.toThrow(
+      "User ID is required in agent mode",
+    );
+    expect(prompt).not.toHaveBeenCalled();
+  });
+});
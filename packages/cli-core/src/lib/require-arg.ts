@@ -0,0 +1,37 @@
+/**
+ * Positional arguments that fall back to an interactive prompt.
+ *
+ * Commands declare such arguments as optional (`[org]`) and resolve them with
+ * {@link requireArg}: a value passed on the command line wins, a terminal user
+ * gets a targeted picker or prompt, and agent mode fails with a usage error
+ * instead of blocking on input nobody will type.
+ */
+
+import { throwUsageError } from "./errors.ts";
+import { text } from "./prompts.ts";
+import { isAgent } from "../mode.ts";
+
+export type RequireArgOptions = {
+  /** Subject of the agent-mode error, e.g. "User ID" → "User ID is required in agent mode." */
+  label: string;
+  /** Picker that yields the value. Defaults to a free-text prompt labelled with `label`. */
+  prompt?: () => Promise<string>;
+};
+
+export async function requireArg(
+  value: string | undefined,
+  options: RequireArgOptions,
+): Promise<string> {
+  if (value?.trim()) return value;
+  if (isAgent()) {
+    throwUsageError(
+      `${options.label} is required in agent mode. Pass it as a positional argument.`,
+    );
+  }
+  if (options.prompt) return options.prompt();
+  const answer = await text({
+    message: options.label,
+    validate: (input) => (input?.trim() ? undefined : `${options.label} is required`),
+  });
+  return answer.trim();
+}
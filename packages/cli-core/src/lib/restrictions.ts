@@ -0,0 +1,63 @@
+/**
+ * Backend API helpers for the instance's allowlist and blocklist identifiers
+ * (Dashboard → Restrictions). An identifier is an email address, a phone
+ * number, a Web3 wallet, or an email domain written as `*@example.com`.
+ */
+
+import { bapiRequest } from "./bapi.ts";
+import { isRecord } from "./objects.ts";
+
+export const RESTRICTION_LISTS = ["blocklist", "allowlist"] as const;
+export type RestrictionList = (typeof RESTRICTION_LISTS)[number];
+
+export interface BapiListIdentifier {
+  id: string;
+  identifier: string;
+  identifier_type?: string;
+  created_at?: number;
+  updated_at?: number;
+}
+
+function listPath(list: RestrictionList): string {
+  return `/${list}_identifiers`;
+}
+
+/**
+ * Every identifier on the list. The endpoint isn't paginated; it answers with
+ * `{ data, total_count }`, or a bare array on older API versions.
+ */
+export async function listIdentifiers(
+  secretKey: string,
+  list: RestrictionList,
+): Promise<BapiListIdentifier[]> {
+  const response = await bapiRequest({ method: "GET", path: listPath(list), secretKey });
+  const body = response.body;
+  const data = Array.isArray(body) ? body : isRecord(body) ? body.data : undefined;
+  return Array.isArray(data) ? (data as BapiListIdentifier[]) : [];
+}
+
+export async function createIdentifier(
+  secretKey: string,
+  list: RestrictionList,
+  identifier: string,
+): Promise<BapiListIdentifier> {
+  const response = await bapiRequest({
+    method: "POST",
+    path: listPath(list),
+    secretKey,
+    body: JSON.stringify({ identifier }),
+  });
+  return response.body as BapiListIdentifier;
+}
+
+export async function deleteIdentifier(
+  secretKey: string,
+  list: RestrictionList,
+  identifierId: string,
+): Promise<void> {
+  await bapiRequest({
+    method: "DELETE",
+    path: `${listPath(list)}/${encodeURIComponent(identifierId)}`,
+    secretKey,
+  });
+}
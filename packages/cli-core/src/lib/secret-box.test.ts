@@ -0,0 +1,30 @@
+import { test, expect, describe } from "bun:test";
+import { isSealed, seal, unseal } from "./secret-box.ts";
+import { CliError } from "./errors.ts";
+
+describe("secret-box", () => {
+  test("seal and unseal roundtrip with a fresh salt each time", async () => {
+    const first = await seal('{"accessToken":"abc"}', "correct horse");
+    const second = await seal('{"accessToken":"abc"}', "correct horse");
+
+    expect(isSealed(first)).toBe(true);
+    expect(first).not.toContain("abc");
+    expect(first).not.toBe(second);
+    expect(await unseal(first, "correct horse")).toBe('{"accessToken":"abc"}');
+  });
+
+  test("a wrong passphrase fails instead of returning garbage", async () => {
+    const sealed = await seal("secret", "right");
+    await expect(unseal(sealed, "wrong")).rejects.toThrow(/is wrong or the file is damaged/);
+  });
+
+  test("a missing passphrase explains how to unlock", async () => {
+    const sealed = await seal("secret", "right");
+    await expect(unseal(sealed, undefined)).rejects.toBeInstanceOf(CliError);
+    await expect(unseal(sealed, undefined)).rejects.toThrow(/CLERK_CREDENTIALS_PASSPHRASE/);
+  });
+
+  test("plain values are not mistaken for sealed ones", () => {
+    expect(isSealed('{"accessToken":"abc"}')).toBe(false);
+  });
+});
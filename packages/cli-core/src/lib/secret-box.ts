@@ -0,0 +1,85 @@
+/**
+ * Passphrase-based encryption for secrets the CLI keeps on disk: the
+ * credentials fallback file used when no OS keyring is available, and the
+ * secret-bearing values in the config file (the webhook relay token).
+ *
+ * The key is derived with scrypt from `CLERK_CREDENTIALS_PASSPHRASE` and a
+ * per-write random salt; the value is sealed with AES-256-GCM, so a wrong
+ * passphrase or a tampered file fails to open instead of yielding garbage.
+ * Sealed values are a single line:
+ *
+ *   clerk-sealed:v1:<salt>:<iv>:<tag>:<ciphertext>   (base64 fields)
+ */
+
+import { createCipheriv, createDecipheriv, randomBytes, scrypt } from "node:crypto";
+import { promisify } from "node:util";
+import { CliError, ERROR_CODE } from "./errors.ts";
+
+export const PASSPHRASE_ENV = "CLERK_CREDENTIALS_PASSPHRASE";
+
+const PREFIX = "clerk-sealed:v1:";
+const KEY_BYTES = 32;
+const SALT_BYTES = 16;
+const IV_BYTES = 12;
+// N=2^15 costs a fraction of a second: expensive per guess, unnoticeable per command.
+const SCRYPT_OPTIONS = { N: 2 ** 15, r: 8, p: 1, maxmem: 64 * 1024 * 1024 };
+
+const deriveKey = promisify(scrypt) as (
+  passphrase: string,
+  salt: Buffer,
+  keylen: number,
+  options: typeof SCRYPT_OPTIONS,
+) => Promise<Buffer>;
+
+/** The configured passphrase, or `undefined` when encryption at rest is off. */
+export function getPassphrase(): string | undefined {
+  return process.env[PASSPHRASE_ENV] || undefined;
+}
+
+export function isSealed(value: string): boolean {
+  return value.startsWith(PREFIX);
+}
+
+export async function seal(plaintext: string, passphrase: string): Promise<string> {
+  const salt = randomBytes(SALT_BYTES);
+  const iv = randomBytes(IV_BYTES);
+  const key = await deriveKey(passphrase, salt, KEY_BYTES, SCRYPT_OPTIONS);
+  const cipher = createCipheriv("aes-256-gcm", key, iv);
+  const ciphertext = Buffer.concat([cipher.update(plaintext, "utf8"), cipher.final()]);
+  const fields = [salt, iv, cipher.getAuthTag(), ciphertext].map((b) => b.toString("base64"));
+  return PREFIX + fields.join(":");
+}
+
+/**
+ * Open a value produced by {@link seal}. Throws a {@link CliError} when the
+ * passphrase is missing or wrong, since the caller can't continue without it;
+ * `recovery` tells the user how to start over without the old value.
+ */
+export async function unseal(
+  sealed: string,
+  passphrase: string | undefined,
+  recovery = "run `clerk auth login` to sign in again",
+): Promise<string> {
+  if (!passphrase) {
+    throw new CliError(
+      `Stored credentials are encrypted. Set ${PASSPHRASE_ENV} to unlock them, or ${recovery}.`,
+      { code: ERROR_CODE.AUTH_REQUIRED },
+    );
+  }
+  const [salt, iv, tag, ciphertext] = sealed
+    .slice(PREFIX.length)
+    .split(":")
+    .map((field) => Buffer.from(field, "base64"));
+  try {
+    const key = await deriveKey(passphrase, salt!, KEY_BYTES, SCRYPT_OPTIONS);
+    const decipher = createDecipheriv("aes-256-gcm", key, iv!);
+    decipher.setAuthTag(tag!);
+    return Buffer.concat([decipher.update(ciphertext!), decipher.final()]).toString("utf8");
+  } catch {
+    throw new CliError(
+      `Could not decrypt stored credentials: ${PASSPHRASE_ENV} is wrong or the file is damaged. ` +
+        `Fix the passphrase, or ${recovery}.`,
+      { code: ERROR_CODE.AUTH_REQUIRED },
+    );
+  }
+}
@@ -8,6 +8,7 @@
  */
 
 import { bapiRequest } from "./bapi.ts";
+import { isRecord } from "./objects.ts";
 
 /** The actor claim stamped onto sessions created from an actor token. */
 export type SessionActor = {
@@ -15,11 +16,44 @@ export type SessionActor = {
   iss?: string;
 };
 
+/** Where a session was last used from, as recorded by Clerk. */
+export type SessionActivity = {
+  ip_address?: string | null;
+  city?: string | null;
+  country?: string | null;
+  browser_name?: string | null;
+  browser_version?: string | null;
+  device_type?: string | null;
+  is_mobile?: boolean;
+};
+
 /** The subset of BAPI's Session object the CLI consumes. */
 export type Session = {
   id: string;
   status?: string;
+  user_id?: string;
+  client_id?: string;
   actor?: SessionActor | null;
+  latest_activity?: SessionActivity | null;
+  last_active_organization_id?: string | null;
+  last_active_at?: number;
+  expire_at?: number;
+  abandon_at?: number;
+  created_at?: number;
+  updated_at?: number;
+};
+
+/** The subset of BAPI's Client object (a browser or device) the CLI consumes. */
+export type SessionClient = {
+  id: string;
+  session_ids?: string[];
+  last_active_session_id?: string | null;
+  sign_in_id?: string | null;
+  sign_up_id?: string | null;
+  /** The client's sessions, embedded by BAPI. */
+  sessions?: Session[];
+  created_at?: number;
+  updated_at?: number;
 };
 
 /** Result of revoking a session. Fields are optional — BAPI may echo them. */
@@ -49,6 +83,62 @@ export async function listUserSessions(
   return Array.isArray(body) ? (body as Session[]) : [];
 }
 
+export async function fetchSession(secretKey: string, sessionId: string): Promise<Session> {
+  const response = await bapiRequest({
+    method: "GET",
+    path: `/sessions/${encodeURIComponent(sessionId)}`,
+    secretKey,
+  });
+  return response.body as Session;
+}
+
+/** One page of the instance's clients. */
+export async function listClients(
+  secretKey: string,
+  page: { limit: number; offset?: number },
+): Promise<SessionClient[]> {
+  const params = new URLSearchParams({ limit: String(page.limit) });
+  if (page.offset) params.set("offset", String(page.offset));
+  const response = await bapiRequest({ method: "GET", path: `/clients?${params}`, secretKey });
+  return Array.isArray(response.body) ? (response.body as SessionClient[]) : [];
+}
+
+export async function fetchClient(secretKey: string, clientId: string): Promise<SessionClient> {
+  const response = await bapiRequest({
+    method: "GET",
+    path: `/clients/${encodeURIComponent(clientId)}`,
+    secretKey,
+  });
+  return response.body as SessionClient;
+}
+
+/**
+ * Mint a session token for an active session. BAPI doesn't return tokens it
+ * issued earlier, so this is the way to see the claims the session currently
+ * gets; the token is short-lived and nothing else uses it.
+ */
+export async function createSessionToken(secretKey: string, sessionId: string): Promise<string> {
+  const response = await bapiRequest({
+    method: "POST",
+    path: `/sessions/${encodeURIComponent(sessionId)}/tokens`,
+    secretKey,
+  });
+  const body = response.body as { jwt?: string };
+  return body.jwt ?? "";
+}
+
+/** The payload of a JWT, without verifying its signature. */
+export function decodeJwtClaims(jwt: string): Record<string, unknown> | undefined {
+  const [, payload] = jwt.split(".");
+  if (!payload) return undefined;
+  try {
+    const parsed: unknown = JSON.parse(Buffer.from(payload, "base64url").toString("utf8"));
+    return isRecord(parsed) ? parsed : undefined;
+  } catch {
+    return undefined;
+  }
+}
+
 export async function revokeSession(secretKey: string, sessionId: string): Promise<RevokedSession> {
   const response = await bapiRequest({
     method: "POST",
@@ -0,0 +1,107 @@
+import { test, expect, describe, beforeEach, afterEach } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { _setConfigDir, readConfig, writeConfig } from "./config.ts";
+import {
+  getBooleanSetting,
+  getIntegerSetting,
+  getSetting,
+  listSettings,
+  normalizeSettingValue,
+  parseBoolean,
+  setSetting,
+  unsetSetting,
+} from "./settings.ts";
+
+describe("settings", () => {
+  let tempDir: string;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-settings-test-"));
+    _setConfigDir(tempDir);
+  });
+
+  afterEach(async () => {
+    _setConfigDir(undefined);
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test.each([
+    ["true", true],
+    ["YES", true],
+    ["1", true],
+    ["on", true],
+    ["false", false],
+    ["0", false],
+    ["off", false],
+    ["maybe", undefined],
+  ] as const)("parseBoolean(%p) is %p", (input, expected) => {
+    expect(parseBoolean(input)).toBe(expected);
+  });
+
+  test("normalizes boolean values on write", async () => {
+    expect(await setSetting("core.read-only", "yes")).toBe("true");
+    expect(await getSetting("core.read-only")).toBe("true");
+    expect(await getBooleanSetting("core.read-only")).toBe(true);
+  });
+
+  test("rejects invalid values", () => {
+    expect(() => normalizeSettingValue("core.read-only", "sometimes")).toThrow(
+      "core.read-only must be true or false",
+    );
+  });
+
+  test("validates output.theme and output.colors", () => {
+    expect(normalizeSettingValue("output.theme", " Light ")).toBe("light");
+    expect(() => normalizeSettingValue("output.theme", "solarized")).toThrow(
+      "output.theme must be one of dark, light, mono",
+    );
+    expect(normalizeSettingValue("output.colors", "Accent = Magenta, warning=208")).toBe(
+      "accent=magenta,warning=208",
+    );
+    expect(() => normalizeSettingValue("output.colors", "links=blue")).toThrow("role one of");
+  });
+
+  test("canonicalizes output.locale and rejects malformed tags", () => {
+    expect(normalizeSettingValue("output.locale", " de-de ")).toBe("de-DE");
+    expect(() => normalizeSettingValue("output.locale", "not a locale")).toThrow(
+      "output.locale must be a BCP 47 locale tag",
+    );
+  });
+
+  test("caps http.retries and http.max-delay", () => {
+    expect(normalizeSettingValue("http.retries", "10")).toBe("10");
+    expect(() => normalizeSettingValue("http.retries", "11")).toThrow(
+      "http.retries must be at most 10",
+    );
+    expect(() => normalizeSettingValue("http.max-delay", "3600")).toThrow("at most 300");
+  });
+
+  test("getIntegerSetting falls back on a hand-edited value that isn't valid", async () => {
+    const config = await readConfig();
+    config.settings = { "http.retries": "lots", "http.max-delay": "30" };
+    await writeConfig(config);
+
+    expect(await getIntegerSetting("http.retries", 2)).toBe(2);
+    expect(await getIntegerSetting("http.max-delay", 10)).toBe(30);
+  });
+
+  test("unset removes the key and drops an empty settings table", async () => {
+    await setSetting("core.read-only", "true");
+    await unsetSetting("core.read-only");
+
+    expect(await getSetting("core.read-only")).toBeUndefined();
+    expect(await getBooleanSetting("core.read-only")).toBe(false);
+    expect((await readConfig()).settings).toBeUndefined();
+  });
+
+  test("listSettings ignores unknown keys left in the config file", async () => {
+    await setSetting("core.read-only", "false");
+    const config = await readConfig();
+    config.settings = { ...config.settings, "legacy.key": "x" };
+    await writeConfig(config);
+
+    expect(await listSettings()).toEqual({ "core.read-only": "false" });
+  });
+});
@@ -0,0 +1,191 @@
+/**
+ * CLI preferences stored under the `settings` key of the CLI config file and
+ * managed with `clerk settings`. Distinct from `clerk config`, which manages
+ * Clerk *instance* configuration over the Platform API.
+ *
+ * Every key must be declared in {@link SETTINGS}; values are normalized on
+ * write so readers never have to re-validate them.
+ */
+
+import { parseColorOverrides, THEMES } from "./color.ts";
+import { readConfig, updateConfig } from "./config.ts";
+import { errorMessage, throwUsageError } from "./errors.ts";
+import { isValidLocale } from "./locale.ts";
+import { log } from "./log.ts";
+
+type SettingType = "boolean" | "integer" | "string" | "choice" | "colors" | "locale";
+
+interface SettingDefinition {
+  type: SettingType;
+  description: string;
+  /** Allowed values for `choice` settings. */
+  choices?: readonly string[];
+  /** Largest allowed value for `integer` settings. */
+  max?: number;
+}
+
+export const SETTINGS = {
+  "core.read-only": {
+    type: "boolean",
+    description: "Block mutating API requests (POST, PUT, PATCH, DELETE)",
+  },
+  "core.telemetry": {
+    type: "boolean",
+    description: "Send anonymous usage telemetry (set with `clerk telemetry on|off`)",
+  },
+  "core.pager": {
+    type: "string",
+    description: "Pager for long human-mode output (empty or `cat` disables paging)",
+  },
+  "http.retries": {
+    type: "integer",
+    max: 10,
+    description: "Retries for rate-limited or unavailable Clerk API requests (default 2, max 10)",
+  },
+  "http.max-delay": {
+    type: "integer",
+    max: 300,
+    description: "Longest wait between retries, in seconds (default 10, max 300)",
+  },
+  "output.theme": {
+    type: "choice",
+    choices: THEMES,
+    description: "Color palette: dark (default), light, or mono",
+  },
+  "output.colors": {
+    type: "colors",
+    description: "Per-role color overrides, e.g. accent=magenta,warning=208",
+  },
+  "output.locale": {
+    type: "locale",
+    description: "Locale for dates and amounts in human output, e.g. de-DE (default: ISO dates)",
+  },
+  "display.redact-pii": {
+    type: "boolean",
+    description: "Mask emails and phone numbers in tables, e.g. j***@example.com (--show-pii)",
+  },
+} as const satisfies Record<string, SettingDefinition>;
+
+export type SettingKey = keyof typeof SETTINGS;
+
+export const SETTING_KEYS = Object.keys(SETTINGS) as SettingKey[];
+
+const TRUE_VALUES = new Set(["true", "1", "yes", "on"]);
+const FALSE_VALUES = new Set(["false", "0", "no", "off"]);
+
+export function isSettingKey(key: string): key is SettingKey {
+  return Object.hasOwn(SETTINGS, key);
+}
+
+/** Parse a boolean-ish string (`true`/`1`/`yes`/`on` and their negatives). */
+export function parseBoolean(value: string): boolean | undefined {
+  const normalized = value.trim().toLowerCase();
+  if (TRUE_VALUES.has(normalized)) return true;
+  if (FALSE_VALUES.has(normalized)) return false;
+  return undefined;
+}
+
+/** Validate and normalize a raw value for `key`, throwing a usage error when invalid. */
+export function normalizeSettingValue(key: SettingKey, value: string): string {
+  const definition: SettingDefinition = SETTINGS[key];
+  switch (definition.type) {
+    case "boolean": {
+      const parsed = parseBoolean(value);
+      if (parsed === undefined) {
+        throwUsageError(`${key} must be true or false (got "${value}").`);
+      }
+      return String(parsed);
+    }
+    case "integer": {
+      if (!/^\d+$/.test(value.trim())) {
+        throwUsageError(`${key} must be a non-negative integer (got "${value}").`);
+      }
+      const parsed = Number(value.trim());
+      if (definition.max !== undefined && parsed > definition.max) {
+        throwUsageError(`${key} must be at most ${definition.max} (got "${value}").`);
+      }
+      return String(parsed);
+    }
+    case "string":
+      return value;
+    case "choice": {
+      const normalized = value.trim().toLowerCase();
+      if (!definition.choices?.includes(normalized)) {
+        throwUsageError(
+          `${key} must be one of ${definition.choices?.join(", ")} (got "${value}").`,
+        );
+      }
+      return normalized;
+    }
+    case "colors": {
+      const parsed = parseColorOverrides(value);
+      if ("error" in parsed) throwUsageError(`${key}: ${parsed.error}.`);
+      return value
+        .split(",")
+        .map((pair) => pair.replace(/\s+/g, "").toLowerCase())
+        .filter(Boolean)
+        .join(",");
+    }
+    case "locale": {
+      const trimmed = value.trim();
+      if (!trimmed) return "";
+      if (!isValidLocale(trimmed)) {
+        throwUsageError(
+          `${key} must be a BCP 47 locale tag such as en-US or de-DE (got "${value}").`,
+        );
+      }
+      return Intl.getCanonicalLocales(trimmed)[0] ?? trimmed;
+    }
+  }
+}
+
+export async function listSettings(): Promise<Partial<Record<SettingKey, string>>> {
+  const config = await readConfig();
+  const result: Partial<Record<SettingKey, string>> = {};
+  for (const [key, value] of Object.entries(config.settings ?? {})) {
+    if (isSettingKey(key)) result[key] = value;
+  }
+  return result;
+}
+
+export async function getSetting(key: SettingKey): Promise<string | undefined> {
+  const config = await readConfig();
+  return config.settings?.[key];
+}
+
+export async function getBooleanSetting(key: SettingKey): Promise<boolean> {
+  const value = await getSetting(key);
+  return value !== undefined && parseBoolean(value) === true;
+}
+
+/**
+ * An integer setting, or `fallback` when it's unset. A stored value that
+ * isn't valid (the config file can be edited by hand) is ignored with a
+ * warning rather than trusted.
+ */
+export async function getIntegerSetting(key: SettingKey, fallback: number): Promise<number> {
+  const value = await getSetting(key);
+  if (value === undefined) return fallback;
+  try {
+    return Number(normalizeSettingValue(key, value));
+  } catch (error) {
+    log.warn(`Ignoring the ${key} setting: ${errorMessage(error)} Using ${fallback}.`);
+    return fallback;
+  }
+}
+
+export async function setSetting(key: SettingKey, value: string): Promise<string> {
+  const normalized = normalizeSettingValue(key, value);
+  await updateConfig((config) => {
+    config.settings = { ...config.settings, [key]: normalized };
+  });
+  return normalized;
+}
+
+export async function unsetSetting(key: SettingKey): Promise<void> {
+  await updateConfig((config) => {
+    if (!config.settings) return;
+    delete config.settings[key];
+    if (Object.keys(config.settings).length === 0) delete config.settings;
+  });
+}
@@ -3,6 +3,7 @@ import { intro as clackIntro, outro as clackOutro, spinner as clackSpinner } fro
 import { isHuman } from "../mode.ts";
 import { dim, cyan } from "./color.ts";
 import { animateHeader } from "./gradient.ts";
+import { DryRunRequest } from "./dry-run.ts";
 import { UserAbortError, isPromptExitError } from "./errors.ts";
 import { log, pushPrefix, popPrefix } from "./log.ts";
 import { getUiOutput } from "./ui.ts";
@@ -121,6 +122,8 @@ export async function withGutter<T>(
   } catch (error) {
     if (error instanceof UserAbortError || isPromptExitError(error)) {
       pausedOutro();
+    } else if (error instanceof DryRunRequest) {
+      await outro("Dry run: request not sent");
     } else {
       await outro("Failed");
     }
@@ -131,7 +134,7 @@ export async function withGutter<T>(
 export async function withSpinner<T>(
   message: string,
   fn: (controls: SpinnerControls) => Promise<T>,
-  doneMessage?: string,
+  doneMessage?: string | (() => string),
 ): Promise<T> {
   if (!isHuman()) return fn({ update: () => {} });
 
@@ -139,10 +142,12 @@ export async function withSpinner<T>(
   s.start(message);
   try {
     const result = await fn({ update: (nextMessage) => s.message(nextMessage) });
-    s.stop(doneMessage ?? message.replace(/\.{3}$/, ""));
+    const done = typeof doneMessage === "function" ? doneMessage() : doneMessage;
+    s.stop(done ?? message.replace(/\.{3}$/, ""));
     return result;
   } catch (error) {
-    s.error("Failed");
+    if (error instanceof DryRunRequest) s.stop(message.replace(/\.{3}$/, ""));
+    else s.error("Failed");
     throw error;
   }
 }
@@ -0,0 +1,219 @@
+import { test, expect, describe, afterEach } from "bun:test";
+import { setOutputFormat } from "./output-format.ts";
+import { setRedactPii } from "./redact.ts";
+import {
+  formatFields,
+  formatTimestamp,
+  renderTable,
+  selectColumns,
+  sortRows,
+  type TableColumn,
+} from "./table.ts";
+
+type Row = { id: string; name?: string; seen?: number };
+
+const COLUMNS: TableColumn<Row>[] = [
+  { key: "id", header: "ID", value: (row) => row.id },
+  { key: "name", header: "NAME", value: (row) => row.name },
+  {
+    key: "seen",
+    header: "SEEN",
+    value: (row) => formatTimestamp(row.seen),
+    sortValue: (row) => row.seen,
+    wide: true,
+  },
+];
+
+const ROWS: Row[] = [
+  { id: "a_2", name: "bravo", seen: 2_000 },
+  { id: "a_10", name: "alpha" },
+  { id: "a_1", name: "charlie", seen: 1_000 },
+];
+
+const ANSI_ESCAPE_PATTERN = new RegExp(String.raw`\x1b\[[0-9;]*m`, "g");
+const stripAnsi = (value: string): string => value.replace(ANSI_ESCAPE_PATTERN, "");
+
+describe("selectColumns", () => {
+  test("hides wide columns by default", () => {
+    expect(selectColumns(COLUMNS).map((c) => c.key)).toEqual(["id", "name"]);
+  });
+
+  test("--wide shows every column", () => {
+    expect(selectColumns(COLUMNS, { wide: true }).map((c) => c.key)).toEqual([
+      "id",
+      "name",
+      "seen",
+    ]);
+  });
+
+  test("--columns picks and orders columns, including wide ones", () => {
+    expect(selectColumns(COLUMNS, { columns: "seen, id" }).map((c) => c.key)).toEqual([
+      "seen",
+      "id",
+    ]);
+  });
+
+  test("rejects unknown columns and lists the available ones", () => {
+    expect(() => selectColumns(COLUMNS, { columns: "id,email" })).toThrow(
+      'Unknown column "email" for --columns. Available: id, name, seen.',
+    );
+  });
+
+  test("rejects an empty --columns", () => {
+    expect(() => selectColumns(COLUMNS, { columns: " , " })).toThrow("at least one column");
+  });
+});
+
+describe("sortRows", () => {
+  test("sorts ascending with natural number ordering", () => {
+    expect(sortRows(ROWS, COLUMNS, "id").map((r) => r.id)).toEqual(["a_1", "a_2", "a_10"]);
+  });
+
+  test("sorts descending", () => {
+    expect(sortRows(ROWS, COLUMNS, "name:desc").map((r) => r.name)).toEqual([
+      "charlie",
+      "bravo",
+      "alpha",
+    ]);
+  });
+
+  test.each(["seen", "seen:desc"])("keeps missing values last for %p", (spec) => {
+    expect(sortRows(ROWS, COLUMNS, spec).at(-1)?.id).toBe("a_10");
+  });
+
+  test("does not mutate the input", () => {
+    const copy = [...ROWS];
+    sortRows(ROWS, COLUMNS, "id:desc");
+    expect(ROWS).toEqual(copy);
+  });
+
+  test.each(["id:up", "id:asc:extra"])("rejects malformed spec %p", (spec) => {
+    expect(() => sortRows(ROWS, COLUMNS, spec)).toThrow(`Invalid --sort "${spec}"`);
+  });
+
+  test("rejects unknown sort columns", () => {
+    expect(() => sortRows(ROWS, COLUMNS, "email")).toThrow('Unknown column "email" for --sort');
+  });
+});
+
+describe("renderTable", () => {
+  test("pads every column but the last and fills missing cells", () => {
+    const lines = renderTable(ROWS, COLUMNS, { columns: "name,seen" }).map(stripAnsi);
+    expect(lines).toEqual([
+      "NAME     SEEN",
+      "bravo    1970-01-01T00:00:02Z",
+      "alpha    -",
+      "charlie  1970-01-01T00:00:01Z",
+    ]);
+  });
+
+  test("applies --sort before rendering", () => {
+    const lines = renderTable(ROWS, COLUMNS, { columns: "id", sort: "id:desc" }).map(stripAnsi);
+    expect(lines).toEqual(["ID", "a_10", "a_2", "a_1"]);
+  });
+
+  describe("with display.redact-pii", () => {
+    afterEach(() => {
+      setRedactPii(false);
+    });
+
+    test("masks emails and phone numbers and sizes columns to the masked text", () => {
+      setRedactPii(true);
+      const rows = [
+        { id: "user_1", name: "jane@example.com" },
+        { id: "user_2", name: "+15555550100" },
+      ];
+      const lines = renderTable(rows, COLUMNS, { columns: "name,id" }).map(stripAnsi);
+      expect(lines).toEqual([
+        "NAME              ID",
+        "j***@example.com  user_1",
+        "+1******0100      user_2",
+      ]);
+    });
+  });
+
+  describe("with --output-format", () => {
+    afterEach(() => {
+      setOutputFormat("table");
+    });
+
+    test("markdown renders a pipe table with every cell", () => {
+      setOutputFormat("markdown");
+      expect(renderTable(ROWS, COLUMNS, { columns: "id,name" })).toEqual([
+        "| ID | NAME |",
+        "| --- | --- |",
+        "| a_2 | bravo |",
+        "| a_10 | alpha |",
+        "| a_1 | charlie |",
+      ]);
+    });
+
+    test("html renders a table with a header row", () => {
+      setOutputFormat("html");
+      const lines = renderTable([{ id: "a<1>" }], COLUMNS, { columns: "id,name" });
+      expect(lines).toEqual([
+        "<table>",
+        "  <thead>",
+        "    <tr><th>ID</th><th>NAME</th></tr>",
+        "  </thead>",
+        "  <tbody>",
+        "    <tr><td>a&lt;1&gt;</td><td>-</td></tr>",
+        "  </tbody>",
+        "</table>",
+      ]);
+    });
+
+    test("jsonl renders one object per row keyed by column", () => {
+      setOutputFormat("jsonl");
+      expect(renderTable([{ id: "a_1" }], COLUMNS, { columns: "id,name" })).toEqual([
+        '{"id":"a_1","name":null}',
+      ]);
+    });
+  });
+});
+
+describe("formatFields", () => {
+  afterEach(() => {
+    setOutputFormat("table");
+  });
+
+  test("pads labels in a terminal", () => {
+    const lines = formatFields([
+      ["ID", "user_1"],
+      ["Name", undefined],
+    ]).map(stripAnsi);
+    expect(lines).toEqual([`  ${"ID".padEnd(20)}user_1`, `  ${"Name".padEnd(20)}-`]);
+  });
+
+  test("markdown renders a two-column table without colors", () => {
+    setOutputFormat("markdown");
+    expect(formatFields([["Status", "\x1b[32mactive\x1b[39m"]])).toEqual([
+      "",
+      "| Field | Value |",
+      "| --- | --- |",
+      "| Status | active |",
+      "",
+    ]);
+  });
+
+  test("jsonl renders a single object without colors", () => {
+    setOutputFormat("jsonl");
+    expect(
+      formatFields([
+        ["Status", "\x1b[32mactive\x1b[39m"],
+        ["Name", undefined],
+      ]),
+    ).toEqual(['{"Status":"active","Name":null}']);
+  });
+
+  test("html marks labels as row headers", () => {
+    setOutputFormat("html");
+    expect(formatFields([["Name", undefined]])).toEqual([
+      "<table>",
+      "  <tbody>",
+      '    <tr><th scope="row">Name</th><td>-</td></tr>',
+      "  </tbody>",
+      "</table>",
+    ]);
+  });
+});
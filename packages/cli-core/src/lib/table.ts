@@ -0,0 +1,196 @@
+/**
+ * Human-mode tables for list commands, with the shared `--columns`, `--sort`,
+ * and `--wide` flags so users can tailor a listing without switching to
+ * `--json | jq`.
+ *
+ * Commands describe their columns once; {@link renderTable} picks, orders,
+ * sorts, and pads them and returns plain lines for the caller to print (or
+ * hand to the pager).
+ */
+
+import { dim, stripAnsi } from "./color.ts";
+import { throwUsageError } from "./errors.ts";
+import { formatDate } from "./locale.ts";
+import { getOutputFormat, htmlTable, jsonlRows, markdownTable } from "./output-format.ts";
+import { redactPii } from "./redact.ts";
+
+const COLUMN_PADDING = 2;
+const EMPTY_CELL = "-";
+
+type SortValue = string | number | undefined;
+
+export interface TableColumn<T> {
+  /** Name used by `--columns` and `--sort` (snake_case, e.g. `last_sign_in`). */
+  key: string;
+  header: string;
+  value: (row: T) => string | undefined;
+  /** Sort key when it differs from the displayed value (e.g. a raw timestamp). */
+  sortValue?: (row: T) => SortValue;
+  /** Style applied after padding, so colors don't skew column widths. */
+  style?: (text: string) => string;
+  /** Only shown with `--wide` (or when named in `--columns`). */
+  wide?: boolean;
+}
+
+export interface TableOptions {
+  /** Comma-separated column keys, in display order. */
+  columns?: string;
+  /** `<column>` or `<column>:asc|desc`. */
+  sort?: string;
+  wide?: boolean;
+}
+
+function availableKeys<T>(columns: TableColumn<T>[]): string {
+  return columns.map((column) => column.key).join(", ");
+}
+
+function findColumn<T>(columns: TableColumn<T>[], key: string, flag: string): TableColumn<T> {
+  const column = columns.find((c) => c.key === key);
+  if (!column) {
+    throwUsageError(`Unknown column "${key}" for ${flag}. Available: ${availableKeys(columns)}.`);
+  }
+  return column;
+}
+
+/** Resolve which columns to show, honoring `--columns` and `--wide`. */
+export function selectColumns<T>(
+  columns: TableColumn<T>[],
+  options: TableOptions = {},
+): TableColumn<T>[] {
+  if (options.columns !== undefined) {
+    const keys = options.columns
+      .split(",")
+      .map((key) => key.trim())
+      .filter(Boolean);
+    if (keys.length === 0) {
+      throwUsageError(`--columns needs at least one column. Available: ${availableKeys(columns)}.`);
+    }
+    return keys.map((key) => findColumn(columns, key, "--columns"));
+  }
+  return options.wide ? columns : columns.filter((column) => !column.wide);
+}
+
+function isMissing(value: SortValue): boolean {
+  return value === undefined || value === "";
+}
+
+function compareValues(a: SortValue, b: SortValue): number {
+  if (typeof a === "number" && typeof b === "number") return a - b;
+  return String(a).localeCompare(String(b), undefined, { numeric: true, sensitivity: "base" });
+}
+
+/** Sort rows by a `--sort` spec. Returns a new array; the input is untouched. */
+export function sortRows<T>(rows: T[], columns: TableColumn<T>[], spec: string): T[] {
+  const [key = "", direction = "asc", ...rest] = spec.split(":").map((part) => part.trim());
+  if (rest.length > 0 || (direction !== "asc" && direction !== "desc")) {
+    throwUsageError(`Invalid --sort "${spec}". Use <column> or <column>:asc|desc.`);
+  }
+  const column = findColumn(columns, key, "--sort");
+  const valueOf = column.sortValue ?? column.value;
+  const sign = direction === "desc" ? -1 : 1;
+
+  return [...rows].sort((a, b) => {
+    const av = valueOf(a);
+    const bv = valueOf(b);
+    // Missing values sort last regardless of direction.
+    if (isMissing(av) || isMissing(bv)) return Number(isMissing(av)) - Number(isMissing(bv));
+    return sign * compareValues(av, bv);
+  });
+}
+
+/** Fail fast on a bad `--columns` or `--sort` before any API call is made. */
+export function validateTableOptions<T>(columns: TableColumn<T>[], options: TableOptions): void {
+  selectColumns(columns, options);
+  if (options.sort) sortRows([], columns, options.sort);
+}
+
+/**
+ * Render `rows` as aligned lines: a dimmed header followed by one line per
+ * row. Every column but the last is padded to its widest cell. Emails and
+ * phone numbers are masked when `display.redact-pii` is on. With
+ * `--output-format markdown` or `html`, the same cells come back as a table in
+ * that format instead, and with `jsonl` as one object per row keyed by column.
+ */
+export function renderTable<T>(
+  rows: T[],
+  columns: TableColumn<T>[],
+  options: TableOptions = {},
+): string[] {
+  const selected = selectColumns(columns, options);
+  const sorted = options.sort ? sortRows(rows, columns, options.sort) : rows;
+  const format = getOutputFormat();
+  if (format === "jsonl") {
+    const values = sorted.map((row) =>
+      selected.map((column) => {
+        const value = column.value(row);
+        return value && redactPii(value);
+      }),
+    );
+    return jsonlRows(selected.map((column) => column.key), values);
+  }
+  const cells = sorted.map((row) =>
+    selected.map((column) => redactPii(column.value(row) || EMPTY_CELL)),
+  );
+  if (format !== "table") {
+    const headers = selected.map((column) => column.header);
+    return format === "markdown" ? markdownTable(headers, cells) : htmlTable(headers, cells);
+  }
+  const widths = selected.map(
+    (column, i) =>
+      Math.max(column.header.length, ...cells.map((rowCells) => rowCells[i]!.length)) +
+      COLUMN_PADDING,
+  );
+  const last = selected.length - 1;
+  const pad = (text: string, i: number) => (i === last ? text : text.padEnd(widths[i]!));
+
+  const header = selected.map((column, i) => dim(pad(column.header, i))).join("");
+  const lines = cells.map((rowCells) =>
+    rowCells
+      .map((text, i) => {
+        const style = selected[i]!.style;
+        const padded = pad(text, i);
+        return style ? style(padded) : padded;
+      })
+      .join(""),
+  );
+
+  return [header, ...lines];
+}
+
+/**
+ * Format a millisecond epoch timestamp for a table cell: ISO 8601 by default,
+ * or localized when `output.locale` is set.
+ */
+export function formatTimestamp(ms: number | null | undefined): string | undefined {
+  if (typeof ms !== "number" || !Number.isFinite(ms) || ms <= 0) return undefined;
+  return formatDate(ms);
+}
+
+const FIELD_LABEL_WIDTH = 20;
+
+/**
+ * Render a detail view's label/value pairs as indented, aligned lines. Missing
+ * values print as a dimmed `-` so every field keeps its row; with
+ * `--output-format jsonl` they are `null` in a single object.
+ */
+export function formatFields(fields: [label: string, value: string | undefined][]): string[] {
+  const format = getOutputFormat();
+  if (format === "jsonl") {
+    const values = fields.map(([, value]) => value && stripAnsi(redactPii(value)));
+    return jsonlRows(fields.map(([label]) => label), [values]);
+  }
+  if (format !== "table") {
+    const rows = fields.map(([label, value]) => [
+      label,
+      value === undefined ? EMPTY_CELL : stripAnsi(redactPii(value)),
+    ]);
+    // Blank lines keep a Markdown table apart from the title above it.
+    return format === "markdown"
+      ? ["", ...markdownTable(["Field", "Value"], rows), ""]
+      : htmlTable(undefined, rows, { rowHeaders: true });
+  }
+  return fields.map(([label, value]) => {
+    const shown = value === undefined ? dim(EMPTY_CELL) : redactPii(value);
+    return `  ${dim(label.padEnd(FIELD_LABEL_WIDTH))}${shown}`;
+  });
+}
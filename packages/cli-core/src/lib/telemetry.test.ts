@@ -0,0 +1,88 @@
+import { test, expect, describe, beforeEach, afterEach, mock } from "bun:test";
+import { join } from "node:path";
+import { mkdtemp, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+
+const mockLoggedFetch = mock();
+mock.module("./fetch.ts", () => ({
+  loggedFetch: (...args: unknown[]) => mockLoggedFetch(...args),
+}));
+
+const { _setConfigDir } = await import("./config.ts");
+const { setSetting } = await import("./settings.ts");
+const { finishCommandTelemetry, startCommandTelemetry, telemetryEnvOverride, TELEMETRY_URL } =
+  await import("./telemetry.ts");
+
+const ENV_KEYS = ["CLERK_TELEMETRY_DISABLED", "DO_NOT_TRACK"] as const;
+
+describe("telemetry", () => {
+  let tempDir: string;
+  let savedEnv: Record<string, string | undefined>;
+
+  beforeEach(async () => {
+    tempDir = await mkdtemp(join(tmpdir(), "clerk-telemetry-test-"));
+    _setConfigDir(tempDir);
+    savedEnv = Object.fromEntries(ENV_KEYS.map((k) => [k, process.env[k]]));
+    for (const k of ENV_KEYS) delete process.env[k];
+    mockLoggedFetch.mockReset();
+    mockLoggedFetch.mockResolvedValue(new Response(null, { status: 202 }));
+  });
+
+  afterEach(async () => {
+    for (const k of ENV_KEYS) {
+      if (savedEnv[k] == null) delete process.env[k];
+      else process.env[k] = savedEnv[k];
+    }
+    _setConfigDir(undefined);
+    await rm(tempDir, { recursive: true, force: true });
+  });
+
+  test("sends nothing unless opted in", async () => {
+    startCommandTelemetry("users list");
+    await finishCommandTelemetry(true);
+    expect(mockLoggedFetch).not.toHaveBeenCalled();
+  });
+
+  test("sends only the command name, duration, and outcome", async () => {
+    await setSetting("core.telemetry", "true");
+    startCommandTelemetry("users list");
+    await finishCommandTelemetry(false);
+
+    expect(mockLoggedFetch).toHaveBeenCalledTimes(1);
+    const [url, init] = mockLoggedFetch.mock.calls[0] as [string, RequestInit];
+    expect(url).toBe(TELEMETRY_URL);
+    const { events } = JSON.parse(init.body as string);
+    expect(events).toHaveLength(1);
+    expect(Object.keys(events[0].payload).sort()).toEqual(["command", "duration_ms", "success"]);
+    expect(events[0].payload).toMatchObject({ command: "users list", success: false });
+  });
+
+  test("sends at most one event per command", async () => {
+    await setSetting("core.telemetry", "true");
+    startCommandTelemetry("apps list");
+    await finishCommandTelemetry(true);
+    await finishCommandTelemetry(true);
+    expect(mockLoggedFetch).toHaveBeenCalledTimes(1);
+  });
+
+  test("DO_NOT_TRACK overrides the setting", async () => {
+    await setSetting("core.telemetry", "true");
+    process.env.DO_NOT_TRACK = "1";
+    expect(telemetryEnvOverride()).toBe("DO_NOT_TRACK");
+    startCommandTelemetry("users list");
+    await finishCommandTelemetry(true);
+    expect(mockLoggedFetch).not.toHaveBeenCalled();
+  });
+
+  test("CLERK_TELEMETRY_DISABLED=0 does not disable it", () => {
+    process.env.CLERK_TELEMETRY_DISABLED = "0";
+    expect(telemetryEnvOverride()).toBeUndefined();
+  });
+
+  test("a failing endpoint never throws", async () => {
+    await setSetting("core.telemetry", "true");
+    mockLoggedFetch.mockRejectedValue(new Error("timed out"));
+    startCommandTelemetry("users list");
+    await expect(finishCommandTelemetry(true)).resolves.toBeUndefined();
+  });
+});
@@ -0,0 +1,75 @@
+/**
+ * Opt-in usage telemetry. Off unless turned on with `clerk telemetry on` (the
+ * `core.telemetry` setting), and `CLERK_TELEMETRY_DISABLED=1` or
+ * `DO_NOT_TRACK=1` keep it off regardless.
+ *
+ * One event per command run, with only the command's name (e.g. `users list`,
+ * never its arguments or options), how long it took, and whether it exited
+ * 0. No user, application, or machine identifier is attached. Sending is
+ * best effort: a slow or failing endpoint never delays or fails a command
+ * by more than {@link SEND_TIMEOUT_MS}.
+ */
+
+import { loggedFetch } from "./fetch.ts";
+import { log } from "./log.ts";
+import { getBooleanSetting, parseBoolean } from "./settings.ts";
+import { getCurrentVersion } from "./update-check.ts";
+
+export const TELEMETRY_URL = "https://clerk-telemetry.com/v1/event";
+const SEND_TIMEOUT_MS = 1_000;
+
+export type CommandEvent = {
+  command: string;
+  duration_ms: number;
+  success: boolean;
+};
+
+/** The environment variable that turned telemetry off, if any. */
+export function telemetryEnvOverride(): string | undefined {
+  for (const name of ["CLERK_TELEMETRY_DISABLED", "DO_NOT_TRACK"]) {
+    const value = process.env[name];
+    if (value && parseBoolean(value) !== false) return name;
+  }
+  return undefined;
+}
+
+export async function isTelemetryEnabled(): Promise<boolean> {
+  if (telemetryEnvOverride()) return false;
+  return getBooleanSetting("core.telemetry");
+}
+
+let pending: { command: string; startedAt: number } | undefined;
+
+/** Note the command about to run. Called from the preAction hook. */
+export function startCommandTelemetry(command: string): void {
+  pending = { command, startedAt: performance.now() };
+}
+
+/**
+ * Send the event for the command started with {@link startCommandTelemetry},
+ * if telemetry is on. The setting is read now rather than at start, so
+ * `clerk telemetry off` isn't itself reported.
+ */
+export async function finishCommandTelemetry(success: boolean): Promise<void> {
+  const started = pending;
+  pending = undefined;
+  if (!started) return;
+  try {
+    if (!(await isTelemetryEnabled())) return;
+    const payload: CommandEvent = {
+      command: started.command,
+      duration_ms: Math.round(performance.now() - started.startedAt),
+      success,
+    };
+    const event = { event: "COMMAND_RUN", sdk: "clerk-cli", sdkv: getCurrentVersion(), payload };
+    await loggedFetch(TELEMETRY_URL, {
+      tag: "telemetry",
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ events: [event] }),
+      signal: AbortSignal.timeout(SEND_TIMEOUT_MS),
+    });
+  } catch (error) {
+    log.debug(`telemetry: ${error}`);
+  }
+}
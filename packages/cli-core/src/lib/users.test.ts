@@ -5,6 +5,8 @@ import {
   buildUpdateUserPayload,
   mergeUsersPayload,
   parseUsersPayload,
+  primaryEmail,
+  primaryFirst,
   redactUsersDisplayPayload,
 } from "./users.ts";
 
@@ -56,6 +58,15 @@ describe("users helpers", () => {
     expect((error as CliError).exitCode).toBe(EXIT_CODE.USAGE);
   });
 
+  test("parseUsersPayload points at fields of the wrong shape before anything is sent", () => {
+    const text = '{\n  "email_address": "alice@example.com",\n  "public_metadata": []\n}';
+    expect(() => parseUsersPayload(text, "user.json")).toThrow(
+      "2 schema error(s) in user.json; nothing was sent.\n" +
+        "  user.json line 2, column 20 (email_address): expected array, found string\n" +
+        "  user.json line 3, column 22 (public_metadata): expected object, found array",
+    );
+  });
+
   test.each(['["email@example.com"]', '"just a string"', "42", "null"])(
     "parseUsersPayload rejects non-object JSON: %s",
     (input) => {
@@ -99,4 +110,26 @@ describe("users helpers", () => {
       users: [{ password: "[REDACTED]" }, { password: "[REDACTED]" }],
     });
   });
+
+  test("primaryEmail prefers the primary address and falls back to the first one", () => {
+    const email_addresses = [
+      { id: "idn_1", email_address: "first@example.com" },
+      { id: "idn_2", email_address: "primary@example.com" },
+    ];
+    expect(primaryEmail({ primary_email_address_id: "idn_2", email_addresses })).toBe(
+      "primary@example.com",
+    );
+    expect(primaryEmail({ primary_email_address_id: null, email_addresses })).toBe(
+      "first@example.com",
+    );
+    expect(primaryEmail({})).toBeUndefined();
+  });
+
+  test("primaryFirst moves the primary identifier to the front", () => {
+    const phones = [
+      { id: "idn_1", phone_number: "+15550100" },
+      { id: "idn_2", phone_number: "+15550101" },
+    ];
+    expect(primaryFirst(phones, "idn_2", "phone_number")).toEqual(["+15550101", "+15550100"]);
+  });
 });
@@ -1,19 +1,80 @@
 import { bapiRequest } from "./bapi.ts";
 import { ERROR_CODE, throwUsageError } from "./errors.ts";
+import { parseJsonInput } from "./json-parse.ts";
+import { assertMatchesSchema, type JsonSchema } from "./json-schema.ts";
 
 const USERS_INVALID_JSON_MESSAGE = "User payload must be a JSON object.";
 const REDACTED = "[REDACTED]";
 const DIRECT_REDACT_KEYS = new Set(["password", "code"]);
 const OBJECT_REDACT_KEYS = new Set(["private_metadata", "unsafe_metadata"]);
 
+const STRINGS: JsonSchema = { type: "array", items: { type: "string", minLength: 1 } };
+
+/**
+ * The `POST /users` body fields whose shape is easy to get wrong by hand
+ * (identifiers are arrays, metadata are objects). Other fields pass through
+ * for the API to check, so new ones don't need a CLI release.
+ */
+const CREATE_USER_SCHEMA: JsonSchema = {
+  type: "object",
+  properties: {
+    email_address: STRINGS,
+    phone_number: STRINGS,
+    web3_wallet: STRINGS,
+    backup_codes: STRINGS,
+    username: { type: "string" },
+    password: { type: "string" },
+    password_digest: { type: "string" },
+    password_hasher: { type: "string" },
+    first_name: { type: "string" },
+    last_name: { type: "string" },
+    external_id: { type: "string" },
+    totp_secret: { type: "string" },
+    public_metadata: { type: "object" },
+    private_metadata: { type: "object" },
+    unsafe_metadata: { type: "object" },
+    skip_password_checks: { type: "boolean" },
+    skip_password_requirement: { type: "boolean" },
+    skip_legal_checks: { type: "boolean" },
+    delete_self_enabled: { type: "boolean" },
+    create_organization_enabled: { type: "boolean" },
+    create_organizations_limit: { type: "integer", minimum: 0 },
+  },
+};
+
+export type UserIdentifier = { id?: string; email_address?: string; phone_number?: string };
+
 export type BapiUserSummary = {
   id: string;
   first_name?: string | null;
   last_name?: string | null;
   username?: string | null;
-  email_addresses?: Array<{ email_address?: string }> | null;
+  primary_email_address_id?: string | null;
+  email_addresses?: UserIdentifier[] | null;
 };
 
+/**
+ * Identifier values with the primary one first, the order `POST /users`
+ * expects (it makes the first one primary).
+ */
+export function primaryFirst(
+  identifiers: UserIdentifier[] | null | undefined,
+  primaryId: string | null | undefined,
+  field: "email_address" | "phone_number",
+): string[] {
+  const isPrimary = (identifier: UserIdentifier) =>
+    Number(Boolean(identifier.id) && identifier.id === primaryId);
+  const sorted = [...(identifiers ?? [])].sort((a, b) => isPrimary(b) - isPrimary(a));
+  return sorted.flatMap((identifier) => identifier[field] || []);
+}
+
+/** The user's primary email address, or their first one if none is marked primary. */
+export function primaryEmail(
+  user: Pick<BapiUserSummary, "primary_email_address_id" | "email_addresses">,
+): string | undefined {
+  return primaryFirst(user.email_addresses, user.primary_email_address_id, "email_address")[0];
+}
+
 /**
  * How to filter the user search: an exact email match or a fuzzy query. An
  * empty `query` returns the unfiltered first page (used by the interactive
@@ -94,22 +155,13 @@ export function mergeUsersPayload(
   return { ...basePayload, ...flagPayload };
 }
 
-export function parseUsersPayload(rawInput: string): Record<string, unknown> {
-  let payload: unknown;
-
-  try {
-    payload = JSON.parse(rawInput);
-  } catch {
-    throwUsageError(
-      "Invalid JSON input. Please provide valid JSON.",
-      undefined,
-      ERROR_CODE.INVALID_JSON,
-    );
-  }
+export function parseUsersPayload(rawInput: string, source?: string): Record<string, unknown> {
+  const payload = parseJsonInput(rawInput, source);
 
   if (typeof payload !== "object" || payload === null || Array.isArray(payload)) {
     throwUsageError(USERS_INVALID_JSON_MESSAGE, undefined, ERROR_CODE.INVALID_JSON);
   }
+  assertMatchesSchema(rawInput, payload, CREATE_USER_SCHEMA, source ?? "the user payload");
 
   return payload as Record<string, unknown>;
 }
@@ -0,0 +1,41 @@
+import { describe, expect, test } from "bun:test";
+import { validateEmail, validatePhoneNumber, validateSlugFormat } from "./validate.ts";
+
+describe("validateEmail", () => {
+  test("accepts ordinary and plus-addressed emails", () => {
+    expect(validateEmail("alice@example.com")).toBeUndefined();
+    expect(validateEmail("alice+ops@mail.example.co.uk")).toBeUndefined();
+  });
+
+  test("rejects addresses without a domain or with spaces", () => {
+    for (const value of ["alice", "alice@", "alice@localhost", "al ice@example.com", "a@b..com"]) {
+      expect(validateEmail(value)).toBe(`"${value}" is not an email address`);
+    }
+  });
+});
+
+describe("validatePhoneNumber", () => {
+  test("accepts E.164 numbers", () => {
+    expect(validatePhoneNumber("+15555550100")).toBeUndefined();
+    expect(validatePhoneNumber("+447700900123")).toBeUndefined();
+  });
+
+  test("suggests the compact form when only separators are wrong", () => {
+    expect(validatePhoneNumber("+1 (555) 555-0100")).toBe(
+      '"+1 (555) 555-0100" must be in E.164 format without separators (+15555550100)',
+    );
+  });
+
+  test("rejects numbers without a country code", () => {
+    expect(validatePhoneNumber("555-0100")).toContain("is not an E.164 phone number");
+    expect(validatePhoneNumber("+0123456")).toContain("is not an E.164 phone number");
+  });
+});
+
+describe("validateSlugFormat", () => {
+  test("accepts lowercase hyphenated slugs and rejects everything else", () => {
+    expect(validateSlugFormat("acme-inc")).toBeUndefined();
+    expect(validateSlugFormat("Acme_Inc")).toContain("lowercase letters");
+    expect(validateSlugFormat("acme--inc")).toContain("lowercase letters");
+  });
+});
@@ -0,0 +1,30 @@
+/**
+ * Client-side checks for values the Backend API would otherwise reject with
+ * an opaque 422. Each returns an error message, or `undefined` when the value
+ * is acceptable, so flag handlers can name the offending flag and prompts can
+ * use them as `validate` callbacks.
+ */
+
+const EMAIL_PATTERN = /^[^\s@]+@[^\s@.]+(?:\.[^\s@.]+)+$/;
+const E164_PATTERN = /^\+[1-9]\d{1,14}$/;
+const SLUG_PATTERN = /^[a-z0-9]+(?:-[a-z0-9]+)*$/;
+
+export function validateEmail(value: string): string | undefined {
+  return EMAIL_PATTERN.test(value) ? undefined : `"${value}" is not an email address`;
+}
+
+/** Phone numbers must be E.164: a `+`, the country code, and up to 15 digits in total. */
+export function validatePhoneNumber(value: string): string | undefined {
+  if (E164_PATTERN.test(value)) return undefined;
+  const compact = value.replace(/[\s().-]/g, "");
+  if (compact !== value && E164_PATTERN.test(compact)) {
+    return `"${value}" must be in E.164 format without separators (${compact})`;
+  }
+  return `"${value}" is not an E.164 phone number (e.g. +15555550100)`;
+}
+
+export function validateSlugFormat(slug: string): string | undefined {
+  return SLUG_PATTERN.test(slug)
+    ? undefined
+    : "Use lowercase letters, numbers, and single hyphens (e.g. acme-inc)";
+}
@@ -130,6 +130,7 @@ export const configStubs = {
   _setConfigDir: () => {},
   readConfig: noop,
   writeConfig: noop,
+  updateConfig: noop,
   getAuth: noop,
   setAuth: noop,
   clearAuth: noop,
@@ -142,6 +143,16 @@ export const configStubs = {
   resolveProfileOrAutolink: noop,
   resolveInstanceId: () => ({ id: "", label: "" }),
   resolveAppContext: async () => ({ appId: "", appLabel: "", instanceId: "", instanceLabel: "" }),
+  getDefaultContext: noop,
+  setDefaultContext: noop,
+  clearDefaultContext: noop,
+  getDefaultOrg: noop,
+  setDefaultOrg: noop,
+  clearDefaultOrg: noop,
+  listInvitationPresets: async () => ({}),
+  getInvitationPreset: noop,
+  setInvitationPreset: noop,
+  removeInvitationPreset: async () => false,
   profileLabel: (profile: { appName?: string; appId: string }) =>
     profile.appName ? `${profile.appName} (${profile.appId})` : profile.appId,
 };
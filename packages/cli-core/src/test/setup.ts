@@ -0,0 +1,15 @@
+/**
+ * Preloaded before every test file (see bunfig.toml). The retry policy and
+ * circuit breaker in lib/http-retry.ts are module-global, so without this a
+ * test stubbing 429/5xx responses would wait through real backoff, and
+ * breaker state from one file would fail requests in the next. Tests of the
+ * retry behavior set their own policy in their own `beforeEach`.
+ */
+
+import { beforeEach } from "bun:test";
+import { resetHttpRetryState, setRetryPolicy } from "../lib/http-retry.ts";
+
+beforeEach(() => {
+  resetHttpRetryState();
+  setRetryPolicy({ retries: 0, maxDelayMs: 0 });
+});